@@ -0,0 +1,242 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: terminus.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Terminus_GetSubnet_FullMethodName      = "/terminus.v1.Terminus/GetSubnet"
+	Terminus_Split_FullMethodName          = "/terminus.v1.Terminus/Split"
+	Terminus_Aggregate_FullMethodName      = "/terminus.v1.Terminus/Aggregate"
+	Terminus_ListInterfaces_FullMethodName = "/terminus.v1.Terminus/ListInterfaces"
+)
+
+// TerminusClient is the client API for Terminus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TerminusClient interface {
+	// GetSubnet returns the properties of an IP address or CIDR.
+	GetSubnet(ctx context.Context, in *GetSubnetRequest, opts ...grpc.CallOption) (*Subnet, error)
+	// Split divides a subnet into smaller subnets.
+	Split(ctx context.Context, in *SplitRequest, opts ...grpc.CallOption) (*SplitResponse, error)
+	// Aggregate returns the minimal list of prefixes covering all CIDRs.
+	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error)
+	// ListInterfaces returns the subnets of the network interfaces of the server.
+	ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error)
+}
+
+type terminusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTerminusClient(cc grpc.ClientConnInterface) TerminusClient {
+	return &terminusClient{cc}
+}
+
+func (c *terminusClient) GetSubnet(ctx context.Context, in *GetSubnetRequest, opts ...grpc.CallOption) (*Subnet, error) {
+	out := new(Subnet)
+	err := c.cc.Invoke(ctx, Terminus_GetSubnet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminusClient) Split(ctx context.Context, in *SplitRequest, opts ...grpc.CallOption) (*SplitResponse, error) {
+	out := new(SplitResponse)
+	err := c.cc.Invoke(ctx, Terminus_Split_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminusClient) Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error) {
+	out := new(AggregateResponse)
+	err := c.cc.Invoke(ctx, Terminus_Aggregate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminusClient) ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error) {
+	out := new(ListInterfacesResponse)
+	err := c.cc.Invoke(ctx, Terminus_ListInterfaces_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TerminusServer is the server API for Terminus service.
+// All implementations must embed UnimplementedTerminusServer
+// for forward compatibility
+type TerminusServer interface {
+	// GetSubnet returns the properties of an IP address or CIDR.
+	GetSubnet(context.Context, *GetSubnetRequest) (*Subnet, error)
+	// Split divides a subnet into smaller subnets.
+	Split(context.Context, *SplitRequest) (*SplitResponse, error)
+	// Aggregate returns the minimal list of prefixes covering all CIDRs.
+	Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error)
+	// ListInterfaces returns the subnets of the network interfaces of the server.
+	ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error)
+	mustEmbedUnimplementedTerminusServer()
+}
+
+// UnimplementedTerminusServer must be embedded to have forward compatible implementations.
+type UnimplementedTerminusServer struct {
+}
+
+func (UnimplementedTerminusServer) GetSubnet(context.Context, *GetSubnetRequest) (*Subnet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubnet not implemented")
+}
+func (UnimplementedTerminusServer) Split(context.Context, *SplitRequest) (*SplitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Split not implemented")
+}
+func (UnimplementedTerminusServer) Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
+}
+func (UnimplementedTerminusServer) ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInterfaces not implemented")
+}
+func (UnimplementedTerminusServer) mustEmbedUnimplementedTerminusServer() {}
+
+// UnsafeTerminusServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TerminusServer will
+// result in compilation errors.
+type UnsafeTerminusServer interface {
+	mustEmbedUnimplementedTerminusServer()
+}
+
+func RegisterTerminusServer(s grpc.ServiceRegistrar, srv TerminusServer) {
+	s.RegisterService(&Terminus_ServiceDesc, srv)
+}
+
+func _Terminus_GetSubnet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubnetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminusServer).GetSubnet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Terminus_GetSubnet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminusServer).GetSubnet(ctx, req.(*GetSubnetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Terminus_Split_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminusServer).Split(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Terminus_Split_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminusServer).Split(ctx, req.(*SplitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Terminus_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminusServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Terminus_Aggregate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminusServer).Aggregate(ctx, req.(*AggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Terminus_ListInterfaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInterfacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminusServer).ListInterfaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Terminus_ListInterfaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminusServer).ListInterfaces(ctx, req.(*ListInterfacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Terminus_ServiceDesc is the grpc.ServiceDesc for Terminus service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Terminus_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "terminus.v1.Terminus",
+	HandlerType: (*TerminusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSubnet",
+			Handler:    _Terminus_GetSubnet_Handler,
+		},
+		{
+			MethodName: "Split",
+			Handler:    _Terminus_Split_Handler,
+		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _Terminus_Aggregate_Handler,
+		},
+		{
+			MethodName: "ListInterfaces",
+			Handler:    _Terminus_ListInterfaces_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "terminus.proto",
+}
@@ -0,0 +1,352 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package asn resolves the origin autonomous system (AS) of IP addresses.
+// Origins are looked up via the Team Cymru IP to ASN mapping service (DNS) or in an offline database,
+// which is read from an MRT routing table dump (RFC 6396) or a CSV file.
+package asn
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/terminus/ipset"
+)
+
+// Origin describes the autonomous system, which originates the route to an IP address.
+type Origin struct {
+	// ASN is the number of the autonomous system.
+	ASN uint32 `json:"asn"`
+	// Name is the name of the autonomous system (if known).
+	Name string `json:"name,omitempty"`
+	// Prefix is the announced network, which contains the IP address.
+	Prefix netip.Prefix `json:"prefix"`
+	// Country is the ISO 3166 code of the country, which the network is registered in (if known).
+	Country string `json:"country,omitempty"`
+}
+
+// DB is an offline database mapping announced networks to their origin.
+type DB struct {
+	origins map[netip.Prefix]Origin
+}
+
+// Lookup returns the origin of the most specific network, which contains ip.
+func (db *DB) Lookup(ip netip.Addr) (Origin, bool) {
+	ip = ip.Unmap()
+	for bits := ip.BitLen(); bits >= 0; bits-- {
+		p, _ := ip.Prefix(bits)
+		if o, ok := db.origins[p]; ok {
+			return o, true
+		}
+	}
+	return Origin{}, false
+}
+
+// Len returns the number of networks in the database.
+func (db *DB) Len() int {
+	return len(db.origins)
+}
+
+// Read reads a database from an MRT routing table dump (TABLE_DUMP_V2) or a CSV file.
+// The format is detected automatically and the input may be compressed with gzip or bzip2
+// (as provided by RouteViews and RIPE RIS).
+func Read(r io.Reader) (*DB, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(zr)
+	case bytes.Equal(magic, []byte("BZh")):
+		br = bufio.NewReader(bzip2.NewReader(br))
+	}
+
+	if hdr, _ := br.Peek(mrtHeaderLen); len(hdr) == mrtHeaderLen && binary.BigEndian.Uint16(hdr[4:]) == mrtTableDumpV2 {
+		return ReadMRT(br)
+	}
+	return ReadCSV(br)
+}
+
+const (
+	mrtHeaderLen   = 12
+	mrtTableDumpV2 = 13
+	ribIPv4Unicast = 2
+	ribIPv6Unicast = 4
+	attrASPath     = 2
+	attrExtLen     = 0x10
+)
+
+// ReadMRT reads a database from an MRT routing table dump in the TABLE_DUMP_V2 format.
+// The origin of a network is the last AS in the AS_PATH of its first RIB entry.
+func ReadMRT(r io.Reader) (*DB, error) {
+	db := &DB{origins: map[netip.Prefix]Origin{}}
+	hdr := make([]byte, mrtHeaderLen)
+	for {
+		if _, err := io.ReadFull(r, hdr); errors.Is(err, io.EOF) {
+			return db, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid MRT record: %w", err)
+		}
+
+		msg := make([]byte, binary.BigEndian.Uint32(hdr[8:]))
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return nil, fmt.Errorf("invalid MRT record: %w", err)
+		}
+		if binary.BigEndian.Uint16(hdr[4:]) != mrtTableDumpV2 {
+			continue
+		}
+
+		switch binary.BigEndian.Uint16(hdr[6:]) {
+		case ribIPv4Unicast:
+			if err := db.addRIB(msg, 4); err != nil {
+				return nil, err
+			}
+		case ribIPv6Unicast:
+			if err := db.addRIB(msg, 16); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+var errRIB = errors.New("invalid MRT RIB entry")
+
+// addRIB adds the network of a RIB_IPV4_UNICAST or RIB_IPV6_UNICAST record to the database.
+func (db *DB) addRIB(msg []byte, addrLen int) error {
+	// sequence number (4), prefix length (1), prefix (variable), entry count (2)
+	if len(msg) < 5 {
+		return errRIB
+	}
+	bits := int(msg[4])
+	n := (bits + 7) / 8
+	if bits > addrLen*8 || len(msg) < 5+n+2 {
+		return errRIB
+	}
+	a := make([]byte, addrLen)
+	copy(a, msg[5:5+n])
+	ip, _ := netip.AddrFromSlice(a)
+	p := netip.PrefixFrom(ip, bits)
+
+	msg = msg[5+n:]
+	if binary.BigEndian.Uint16(msg) == 0 {
+		return nil
+	}
+	// peer index (2), originated time (4), attribute length (2), attributes (variable)
+	if len(msg) < 10 || len(msg) < 10+int(binary.BigEndian.Uint16(msg[8:])) {
+		return errRIB
+	}
+	if asn, ok := originAS(msg[10 : 10+int(binary.BigEndian.Uint16(msg[8:]))]); ok {
+		db.origins[p] = Origin{ASN: asn, Prefix: p}
+	}
+	return nil
+}
+
+// originAS returns the last AS in the AS_PATH attribute (TABLE_DUMP_V2 always uses 4-byte AS numbers).
+func originAS(attrs []byte) (asn uint32, ok bool) {
+	for len(attrs) >= 3 {
+		flags, typ, hl, l := attrs[0], attrs[1], 3, int(attrs[2])
+		if flags&attrExtLen != 0 {
+			if len(attrs) < 4 {
+				return 0, false
+			}
+			hl, l = 4, int(binary.BigEndian.Uint16(attrs[2:]))
+		}
+		if len(attrs) < hl+l {
+			return 0, false
+		}
+		val := attrs[hl : hl+l]
+		attrs = attrs[hl+l:]
+		if typ != attrASPath {
+			continue
+		}
+
+		// segments: type (1), count (1), AS numbers (4 each)
+		for len(val) >= 2 && len(val) >= 2+4*int(val[1]) {
+			if cnt := int(val[1]); cnt > 0 {
+				asn, ok = binary.BigEndian.Uint32(val[2+4*(cnt-1):]), true
+			}
+			val = val[2+4*int(val[1]):]
+		}
+		return asn, ok
+	}
+	return 0, false
+}
+
+// ReadCSV reads a database from a CSV (or TSV) file with one of the following record formats:
+//
+//	PREFIX,ASN[,NAME]
+//	START,END,ASN[,COUNTRY[,NAME]]
+//
+// The latter is used by iptoasn.com. AS numbers may be prefixed with "AS" and AS 0 (not routed) is ignored.
+// Empty lines and comments (#) are skipped.
+func ReadCSV(r io.Reader) (*DB, error) {
+	db := &DB{origins: map[netip.Prefix]Origin{}}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		l := strings.TrimSpace(sc.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		cr := csv.NewReader(strings.NewReader(l))
+		if !strings.Contains(l, ",") {
+			cr.Comma = '\t'
+		}
+		rec, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := db.addCSV(rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return db, sc.Err()
+}
+
+func (db *DB) addCSV(rec []string) error {
+	for i := range rec {
+		rec[i] = strings.TrimSpace(rec[i])
+	}
+	if len(rec) < 2 {
+		return errors.New("missing AS number")
+	}
+
+	var ps []netip.Prefix
+	if p, err := netip.ParsePrefix(rec[0]); err == nil {
+		ps, rec = []netip.Prefix{p.Masked()}, rec[1:]
+	} else if len(rec) >= 3 {
+		rg, err := ipset.ParseRange(rec[0] + "-" + rec[1])
+		if err != nil {
+			return err
+		}
+		ps, rec = rg.Prefixes(), rec[2:]
+	} else {
+		return err
+	}
+
+	asn, err := ParseASN(rec[0])
+	if err != nil || asn == 0 {
+		return err
+	}
+	o := Origin{ASN: asn}
+	switch len(rec) {
+	case 1:
+	case 2:
+		o.Name = rec[1]
+	default:
+		o.Country, o.Name = rec[1], rec[2]
+	}
+	if o.Country == "None" {
+		o.Country = ""
+	}
+
+	for _, p := range ps {
+		o.Prefix = p
+		db.origins[p] = o
+	}
+	return nil
+}
+
+// ParseASN parses an AS number like "64496" or "AS64496".
+func ParseASN(s string) (uint32, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "AS"), "as")
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid AS number: %q", s)
+	}
+	return uint32(n), nil
+}
+
+// TXTResolver looks up DNS TXT records (e.g., net.DefaultResolver).
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// LookupCymru resolves the origin of ip via the Team Cymru IP to ASN mapping service.
+// If the AS name cannot be resolved, the Origin is returned without name.
+func LookupCymru(ctx context.Context, r TXTResolver, ip netip.Addr) (Origin, error) {
+	txts, err := r.LookupTXT(ctx, cymruOriginName(ip))
+	if err != nil {
+		return Origin{}, err
+	} else if len(txts) == 0 {
+		return Origin{}, errors.New("no origin found for " + ip.String())
+	}
+
+	// "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11" (multiple origins are separated by space)
+	fs := splitCymru(txts[0])
+	if len(fs) < 3 {
+		return Origin{}, errors.New("invalid Team Cymru response: " + txts[0])
+	}
+	asn, err := ParseASN(strings.Fields(fs[0] + " ")[0])
+	if err != nil {
+		return Origin{}, err
+	}
+	p, err := netip.ParsePrefix(fs[1])
+	if err != nil {
+		return Origin{}, err
+	}
+	o := Origin{ASN: asn, Prefix: p, Country: fs[2]}
+
+	// "13335 | US | arin | 2010-07-14 | CLOUDFLARENET, US"
+	name := "AS" + strconv.FormatUint(uint64(asn), 10) + ".asn.cymru.com"
+	if txts, err = r.LookupTXT(ctx, name); err == nil && len(txts) > 0 {
+		if fs = splitCymru(txts[0]); len(fs) >= 5 {
+			o.Name = fs[4]
+		}
+	}
+	return o, nil
+}
+
+// cymruOriginName returns the DNS name of the origin query (reversed octets and nibbles, respectively).
+func cymruOriginName(ip netip.Addr) string {
+	ip = ip.Unmap()
+	sb := strings.Builder{}
+	if ip.Is4() {
+		a := ip.As4()
+		for i := len(a) - 1; i >= 0; i-- {
+			sb.WriteString(strconv.Itoa(int(a[i])) + ".")
+		}
+		return sb.String() + "origin.asn.cymru.com"
+	}
+
+	const hex = "0123456789abcdef"
+	a := ip.As16()
+	for i := len(a) - 1; i >= 0; i-- {
+		sb.Write([]byte{hex[a[i]&0xf], '.', hex[a[i]>>4], '.'})
+	}
+	return sb.String() + "origin6.asn.cymru.com"
+}
+
+func splitCymru(txt string) []string {
+	fs := strings.Split(txt, "|")
+	for i := range fs {
+		fs[i] = strings.TrimSpace(fs[i])
+	}
+	return fs
+}
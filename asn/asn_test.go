@@ -0,0 +1,142 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asn_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/asn"
+	. "github.com/stretchr/testify/require"
+)
+
+// rib returns a TABLE_DUMP_V2 RIB record of p with a single entry, whose AS_PATH is path.
+func rib(p netip.Prefix, path ...uint32) []byte {
+	seg := []byte{2, byte(len(path))}
+	for _, a := range path {
+		seg = binary.BigEndian.AppendUint32(seg, a)
+	}
+	// ORIGIN (IGP) and AS_PATH
+	attrs := append([]byte{0x40, 1, 1, 0, 0x50, 2}, binary.BigEndian.AppendUint16(nil, uint16(len(seg)))...)
+	attrs = append(attrs, seg...)
+
+	msg := []byte{0, 0, 0, 1, byte(p.Bits())}
+	msg = append(msg, p.Addr().AsSlice()[:(p.Bits()+7)/8]...)
+	msg = append(msg, 0, 1, 0, 0, 0, 0, 0, 0)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(attrs)))
+	msg = append(msg, attrs...)
+
+	subtype := uint16(2)
+	if p.Addr().Is6() {
+		subtype = 4
+	}
+	hdr := []byte{0, 0, 0, 0, 0, 13}
+	hdr = binary.BigEndian.AppendUint16(hdr, subtype)
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(len(msg)))
+	return append(hdr, msg...)
+}
+
+func TestReadMRT(t *testing.T) {
+	// PEER_INDEX_TABLE records are skipped
+	dump := []byte{0, 0, 0, 0, 0, 13, 0, 1, 0, 0, 0, 2, 0xc0, 0}
+	dump = append(dump, rib(netip.MustParsePrefix("192.0.2.0/24"), 64500, 64496)...)
+	dump = append(dump, rib(netip.MustParsePrefix("192.0.2.128/25"), 64500, 64497)...)
+	dump = append(dump, rib(netip.MustParsePrefix("2001:db8::/32"), 64511)...)
+
+	zdump := &bytes.Buffer{}
+	zw := gzip.NewWriter(zdump)
+	_, _ = zw.Write(dump)
+	NoError(t, zw.Close())
+
+	for _, b := range [][]byte{dump, zdump.Bytes()} {
+		db, err := asn.Read(bytes.NewReader(b))
+		NoError(t, err)
+		Equal(t, 3, db.Len())
+
+		o, ok := db.Lookup(netip.MustParseAddr("192.0.2.1"))
+		True(t, ok)
+		Equal(t, asn.Origin{ASN: 64496, Prefix: netip.MustParsePrefix("192.0.2.0/24")}, o)
+
+		o, _ = db.Lookup(netip.MustParseAddr("::ffff:192.0.2.129"))
+		Equal(t, uint32(64497), o.ASN)
+
+		o, _ = db.Lookup(netip.MustParseAddr("2001:db8::1"))
+		Equal(t, uint32(64511), o.ASN)
+
+		_, ok = db.Lookup(netip.MustParseAddr("198.51.100.1"))
+		False(t, ok)
+	}
+
+	_, err := asn.ReadMRT(bytes.NewReader(dump[:len(dump)-1]))
+	ErrorContains(t, err, "invalid MRT record")
+}
+
+func TestReadCSV(t *testing.T) {
+	db, err := asn.Read(strings.NewReader(`# prefix,asn,name
+192.0.2.0/24,AS64496,"EXAMPLE, US"
+2001:db8::/32,64511
+
+198.51.100.0	198.51.100.255	64497	NL	EXAMPLE-NL
+203.0.113.0	203.0.113.255	0	None	Not routed
+`))
+	NoError(t, err)
+	Equal(t, 3, db.Len())
+
+	o, _ := db.Lookup(netip.MustParseAddr("192.0.2.1"))
+	Equal(t, asn.Origin{ASN: 64496, Name: "EXAMPLE, US", Prefix: netip.MustParsePrefix("192.0.2.0/24")}, o)
+
+	o, _ = db.Lookup(netip.MustParseAddr("198.51.100.200"))
+	Equal(t, asn.Origin{
+		ASN: 64497, Name: "EXAMPLE-NL", Prefix: netip.MustParsePrefix("198.51.100.0/24"), Country: "NL",
+	}, o)
+
+	_, err = asn.ReadCSV(strings.NewReader("192.0.2.0/24,ASX\n"))
+	EqualError(t, err, `line 1: invalid AS number: "X"`)
+}
+
+// resolver is a TXTResolver returning fixed records.
+type resolver map[string]string
+
+func (r resolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if txt, ok := r[name]; ok {
+		return []string{txt}, nil
+	}
+	return nil, errors.New("no such host: " + name)
+}
+
+func TestLookupCymru(t *testing.T) {
+	r := resolver{
+		"1.2.0.192.origin.asn.cymru.com": "64496 64497 | 192.0.2.0/24 | US | arin | 2010-07-14",
+		"AS64496.asn.cymru.com":          "64496 | US | arin | 2010-07-14 | EXAMPLE, US",
+		"1." + strings.Repeat("0.", 23) + "8.b.d.0.1.0.0.2.origin6.asn.cymru.com": "64511 | 2001:db8::/32 | NL | ripencc |",
+	}
+
+	o, err := asn.LookupCymru(context.Background(), r, netip.MustParseAddr("192.0.2.1"))
+	NoError(t, err)
+	Equal(t, asn.Origin{ASN: 64496, Name: "EXAMPLE, US", Prefix: netip.MustParsePrefix("192.0.2.0/24"), Country: "US"}, o)
+
+	o, err = asn.LookupCymru(context.Background(), r, netip.MustParseAddr("2001:db8::1"))
+	NoError(t, err)
+	Equal(t, asn.Origin{ASN: 64511, Prefix: netip.MustParsePrefix("2001:db8::/32"), Country: "NL"}, o)
+
+	_, err = asn.LookupCymru(context.Background(), r, netip.MustParseAddr("198.51.100.1"))
+	EqualError(t, err, "no such host: 1.100.51.198.origin.asn.cymru.com")
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bogon checks whether IP addresses are bogons i.e., addresses, which must not appear on the Internet.
+// Besides the special-purpose address blocks (RFC 6890), team-maintained lists like the Team Cymru full bogons
+// (which include unallocated address space) can be checked.
+package bogon
+
+import (
+	"net/netip"
+)
+
+// Category classifies bogon address blocks.
+type Category string
+
+// Categories of bogon address blocks.
+const (
+	ThisNetwork   Category = "this-network"
+	Private       Category = "private"
+	Shared        Category = "shared"
+	Loopback      Category = "loopback"
+	LinkLocal     Category = "link-local"
+	Protocol      Category = "protocol-assignment"
+	Documentation Category = "documentation"
+	Benchmarking  Category = "benchmarking"
+	Multicast     Category = "multicast"
+	Reserved      Category = "reserved"
+	Unallocated   Category = "unallocated"
+)
+
+// Block is a bogon address block.
+type Block struct {
+	Prefix   netip.Prefix `json:"prefix"`
+	Category Category     `json:"category"`
+	// RFC is the specification of the block (empty for team-maintained lists).
+	RFC string `json:"rfc,omitempty"`
+}
+
+func block(p string, c Category, rfc string) Block {
+	return Block{netip.MustParsePrefix(p), c, rfc}
+}
+
+// Blocks lists the special-purpose address blocks, which are not globally routable.
+// Unallocated address space is not included (but Check reports IPv6 addresses outside of 2000::/3).
+var Blocks = []Block{
+	block("0.0.0.0/8", ThisNetwork, "RFC 791"),
+	block("10.0.0.0/8", Private, "RFC 1918"),
+	block("100.64.0.0/10", Shared, "RFC 6598"),
+	block("127.0.0.0/8", Loopback, "RFC 1122"),
+	block("169.254.0.0/16", LinkLocal, "RFC 3927"),
+	block("172.16.0.0/12", Private, "RFC 1918"),
+	block("192.0.0.0/24", Protocol, "RFC 6890"),
+	block("192.0.2.0/24", Documentation, "RFC 5737"),
+	block("192.168.0.0/16", Private, "RFC 1918"),
+	block("198.18.0.0/15", Benchmarking, "RFC 2544"),
+	block("198.51.100.0/24", Documentation, "RFC 5737"),
+	block("203.0.113.0/24", Documentation, "RFC 5737"),
+	block("224.0.0.0/4", Multicast, "RFC 5771"),
+	block("240.0.0.0/4", Reserved, "RFC 1112"),
+	block("::/128", ThisNetwork, "RFC 4291"),
+	block("::1/128", Loopback, "RFC 4291"),
+	block("64:ff9b:1::/48", Reserved, "RFC 8215"),
+	block("100::/64", Reserved, "RFC 6666"),
+	block("2001:2::/48", Benchmarking, "RFC 5180"),
+	block("2001:10::/28", Reserved, "RFC 4843"),
+	block("2001:db8::/32", Documentation, "RFC 3849"),
+	block("3fff::/20", Documentation, "RFC 9637"),
+	block("fc00::/7", Private, "RFC 4193"),
+	block("fe80::/10", LinkLocal, "RFC 4291"),
+	block("fec0::/10", Reserved, "RFC 3879"),
+	block("ff00::/8", Multicast, "RFC 4291"),
+}
+
+// globalUnicast is the IPv6 address space, which is (being) allocated by IANA.
+var globalUnicast = netip.MustParsePrefix("2000::/3")
+
+// Checker checks IP addresses against the bogon blocks and an optional full bogon list.
+type Checker struct {
+	full []Block
+}
+
+// NewChecker returns a Checker, which additionally checks the networks of a full bogon list
+// (e.g., https://www.team-cymru.org/Services/Bogons/fullbogons-ipv4.txt).
+// They are reported as Unallocated unless they are contained in one of the Blocks.
+func NewChecker(full []netip.Prefix) *Checker {
+	c := &Checker{}
+	for _, p := range full {
+		c.full = append(c.full, Block{Prefix: p.Masked(), Category: Unallocated})
+	}
+	return c
+}
+
+// Check returns the most specific bogon block, which contains ip.
+func (c *Checker) Check(ip netip.Addr) (Block, bool) {
+	ip = ip.Unmap()
+	if b, ok := lookup(Blocks, ip); ok {
+		return b, true
+	}
+	if ip.Is6() && !globalUnicast.Contains(ip) {
+		p, _ := ip.Prefix(3)
+		return Block{Prefix: p, Category: Unallocated, RFC: "RFC 4291"}, true
+	}
+	return lookup(c.full, ip)
+}
+
+func lookup(bs []Block, ip netip.Addr) (best Block, ok bool) {
+	for _, b := range bs {
+		if b.Prefix.Contains(ip) && (!ok || b.Prefix.Bits() > best.Prefix.Bits()) {
+			best, ok = b, true
+		}
+	}
+	return best, ok
+}
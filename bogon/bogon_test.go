@@ -0,0 +1,65 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bogon_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/bogon"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	c := bogon.NewChecker([]netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("45.61.40.1/22"),
+	})
+
+	tests := []struct {
+		ip   string
+		want bogon.Block
+	}{
+		{"0.1.2.3", bogon.Block{netip.MustParsePrefix("0.0.0.0/8"), bogon.ThisNetwork, "RFC 791"}},
+		{"10.1.2.3", bogon.Block{netip.MustParsePrefix("10.0.0.0/8"), bogon.Private, "RFC 1918"}},
+		{"100.100.0.1", bogon.Block{netip.MustParsePrefix("100.64.0.0/10"), bogon.Shared, "RFC 6598"}},
+		{"169.254.0.1", bogon.Block{netip.MustParsePrefix("169.254.0.0/16"), bogon.LinkLocal, "RFC 3927"}},
+		{"::ffff:192.0.2.1", bogon.Block{netip.MustParsePrefix("192.0.2.0/24"), bogon.Documentation, "RFC 5737"}},
+		{"255.255.255.255", bogon.Block{netip.MustParsePrefix("240.0.0.0/4"), bogon.Reserved, "RFC 1112"}},
+		{"45.61.42.7", bogon.Block{netip.MustParsePrefix("45.61.40.0/22"), bogon.Unallocated, ""}},
+		{"::1", bogon.Block{netip.MustParsePrefix("::1/128"), bogon.Loopback, "RFC 4291"}},
+		{"2001:db8::1", bogon.Block{netip.MustParsePrefix("2001:db8::/32"), bogon.Documentation, "RFC 3849"}},
+		{"fd00::1", bogon.Block{netip.MustParsePrefix("fc00::/7"), bogon.Private, "RFC 4193"}},
+		{"4000::1", bogon.Block{netip.MustParsePrefix("4000::/3"), bogon.Unallocated, "RFC 4291"}},
+		{"8.8.8.8", bogon.Block{}},
+		{"2a00:1450::1", bogon.Block{}},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			b, ok := c.Check(netip.MustParseAddr(tt.ip))
+			Equal(t, tt.want.Prefix.IsValid(), ok)
+			Equal(t, tt.want, b)
+		})
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	for _, b := range bogon.Blocks {
+		Equal(t, b.Prefix.Masked(), b.Prefix, b.Prefix.String())
+		NotEmpty(t, b.RFC)
+	}
+}
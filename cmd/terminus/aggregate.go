@@ -0,0 +1,75 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+)
+
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate [flags] [CIDR...]",
+	Short: "Aggregate subnets into the minimal list of prefixes",
+	Long: `Aggregate IP addresses and subnets into the minimal list of prefixes, which covers exactly the same addresses.
+Adjacent and overlapping subnets are merged. IPv4 prefixes are listed before IPv6 prefixes.
+If no CIDR is given (or "-"), CIDRs are read from stdin (one per line).`,
+	Example: `  terminus aggregate 192.0.2.0/25 192.0.2.128/25 192.0.2.1 198.51.100.0/24
+  # 192.0.2.0/24
+  # 198.51.100.0/24`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runAggregateCmd,
+}
+
+func init() {
+	aggregateCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(aggregateCmd)
+}
+
+func runAggregateCmd(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+
+	ps, err := readPrefixArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+	out := newOutput(cmd)
+	if err := writeAggregate(out, setOf(ps).Prefixes(), output); err != nil {
+		fatal(err)
+	}
+	_ = out.Flush()
+}
+
+func writeAggregate(w io.Writer, ps []netip.Prefix, output string) error {
+	switch output {
+	case "text":
+		for _, p := range ps {
+			_, _ = fmt.Fprintln(w, p)
+		}
+		return nil
+	case "json":
+		a := &jsonArray{w: w}
+		for _, p := range ps {
+			if err := a.add(p); err != nil {
+				return err
+			}
+		}
+		return a.close()
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteAggregate(t *testing.T) {
+	ps, err := parsePrefixes([]string{"192.0.2.128/25", "2001:db8::/33", "192.0.2.0/25", "2001:db8:8000::/33", "10.0.0.1"})
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, writeAggregate(s, setOf(ps).Prefixes(), "text"))
+	Equal(t, "10.0.0.1/32\n192.0.2.0/24\n2001:db8::/32\n", s.String())
+
+	s.Reset()
+	NoError(t, writeAggregate(s, []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}, "json"))
+	Equal(t, `["192.0.2.0/24"]`+"\n", s.String())
+
+	s.Reset()
+	NoError(t, writeAggregate(s, nil, "json"))
+	Equal(t, "[]\n", s.String())
+
+	EqualError(t, writeAggregate(s, nil, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// builtinAliases maps friendly names to their well-known IP addresses and networks.
+var builtinAliases = map[string]string{
+	"localhost":  "127.0.0.1/8",
+	"localhost6": "::1/128",
+	"any":        "0.0.0.0/0",
+	"any6":       "::/0",
+	"default":    "0.0.0.0/0",
+	"default6":   "::/0",
+	"linklocal":  "169.254.0.0/16",
+	"linklocal6": "fe80::/10",
+	"multicast":  "224.0.0.0/4",
+	"multicast6": "ff00::/8",
+}
+
+var aliasesCmd = &cobra.Command{
+	Use:   "aliases [flags]",
+	Short: "List the names, which can be used instead of IP addresses and subnets",
+	Long: `List the names, which can be used instead of IP addresses and subnets (e.g., "terminus -b linklocal").
+Besides the built-in aliases, the named subnets of the registry file given by the environment variable
+TERMINUS_ALIASES are listed. The registry contains one alias per line (NAME IP or NAME IP/PREFIX_LEN),
+empty lines and comments (#) are ignored. Aliases of the registry take precedence over built-in aliases.
+Aliases take precedence over network interfaces of the same name.`,
+	Example: `  echo "dmz 192.0.2.0/26  # web servers" > ~/.terminus-aliases
+  export TERMINUS_ALIASES=~/.terminus-aliases
+  terminus -l dmz
+  # 192.0.2.62`,
+	Args: cobra.NoArgs,
+	Run:  runAliasesCmd,
+}
+
+func init() {
+	aliasesCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(aliasesCmd)
+}
+
+func runAliasesCmd(cmd *cobra.Command, _ []string) {
+	output, _ := cmd.Flags().GetString("output")
+	as, err := loadAliases()
+	if err != nil {
+		fatal(err)
+	}
+	if err := writeAliases(os.Stdout, as, output); err != nil {
+		fatal(err)
+	}
+}
+
+// alias is a friendly name of an IP address or network.
+type alias struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Source is "builtin" or the registry file.
+	Source string `json:"source"`
+}
+
+var (
+	aliasesOnce sync.Once
+	aliases     map[string]alias
+	errAliases  error
+)
+
+// lookupAlias returns the IP address or network, which is registered under the name (case-insensitive).
+func lookupAlias(name string) (string, bool, error) {
+	aliasesOnce.Do(func() {
+		var as []alias
+		if as, errAliases = loadAliases(); errAliases == nil {
+			aliases = map[string]alias{}
+			for _, a := range as {
+				aliases[a.Name] = a
+			}
+		}
+	})
+	a, ok := aliases[strings.ToLower(name)]
+	return a.Value, ok, errAliases
+}
+
+// loadAliases returns the built-in aliases and the aliases of the registry file in TERMINUS_ALIASES (if set),
+// ordered by name. Aliases of the registry replace built-in aliases of the same name.
+func loadAliases() ([]alias, error) {
+	m := map[string]alias{}
+	for n, v := range builtinAliases {
+		m[n] = alias{n, v, "builtin"}
+	}
+	if name := os.Getenv("TERMINUS_ALIASES"); name != "" {
+		b, err := readInputFile(name)
+		if err != nil {
+			return nil, err
+		}
+		as, err := parseAliases(b, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range as {
+			m[a.Name] = a
+		}
+	}
+
+	as := make([]alias, 0, len(m))
+	for _, a := range m {
+		as = append(as, a)
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i].Name < as[j].Name })
+	return as, nil
+}
+
+// parseAliases parses a registry file containing one alias per line (NAME IP or NAME IP/PREFIX_LEN).
+func parseAliases(b []byte, source string) (as []alias, err error) {
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; sc.Scan(); line++ {
+		l := sc.Text()
+		if i := strings.IndexByte(l, '#'); i >= 0 {
+			l = l[:i]
+		}
+		fs := strings.Fields(l)
+		if len(fs) == 0 {
+			continue
+		} else if len(fs) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected NAME IP or NAME IP/PREFIX_LEN", source, line)
+		}
+
+		if _, errAddr := netip.ParseAddr(fs[1]); errAddr != nil {
+			if _, err := netip.ParsePrefix(fs[1]); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w: %s", source, line, ErrInvalidCIDR, fs[1])
+			}
+		}
+		as = append(as, alias{strings.ToLower(fs[0]), fs[1], source})
+	}
+	return as, sc.Err()
+}
+
+func writeAliases(w io.Writer, as []alias, output string) error {
+	switch output {
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, a := range as {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Name, a.Value, a.Source)
+		}
+		return tw.Flush()
+	case "json":
+		return json.NewEncoder(w).Encode(as)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDetermineIPAlias(t *testing.T) {
+	tests := []struct {
+		arg  string
+		ip   string
+		want string
+	}{
+		{"localhost", "127.0.0.1", "127.0.0.0/8"},
+		{"LinkLocal", "169.254.0.0", "169.254.0.0/16"},
+		{"default", "0.0.0.0", "0.0.0.0/0"},
+		{"any6", "::", "::/0"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.arg, func(t *testing.T) {
+			ip, n, err := determineIP(tt.arg)
+			NoError(t, err)
+			Equal(t, tt.ip, ip.String())
+			Equal(t, tt.want, n.String())
+		})
+	}
+}
+
+func TestParsePrefixesAlias(t *testing.T) {
+	ps, err := parsePrefixes([]string{"linklocal", "localhost6"})
+	NoError(t, err)
+	Equal(t, "169.254.0.0/16", ps[0].String())
+	Equal(t, "::1/128", ps[1].String())
+}
+
+func TestLoadAliases(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "aliases")
+	NoError(t, os.WriteFile(name, []byte("# named subnets\nDMZ 192.0.2.0/26  # web servers\n\nany 10.0.0.0/8\n"), 0o600))
+	t.Setenv("TERMINUS_ALIASES", name)
+
+	as, err := loadAliases()
+	NoError(t, err)
+	m := map[string]alias{}
+	for _, a := range as {
+		m[a.Name] = a
+	}
+	Equal(t, alias{"dmz", "192.0.2.0/26", name}, m["dmz"])
+	Equal(t, alias{"any", "10.0.0.0/8", name}, m["any"])
+	Equal(t, alias{"localhost", "127.0.0.1/8", "builtin"}, m["localhost"])
+}
+
+func TestParseAliasesInvalid(t *testing.T) {
+	_, err := parseAliases([]byte("dmz 192.0.2.0/26\nlan\n"), "aliases")
+	EqualError(t, err, "aliases:2: expected NAME IP or NAME IP/PREFIX_LEN")
+
+	_, err = parseAliases([]byte("lan 10.0.0.0/33"), "aliases")
+	EqualError(t, err, "aliases:1: invalid IP address or CIDR: 10.0.0.0/33")
+	ErrorIs(t, err, ErrInvalidCIDR)
+}
+
+func TestWriteAliases(t *testing.T) {
+	as := []alias{{"any", "0.0.0.0/0", "builtin"}, {"dmz", "192.0.2.0/26", "aliases"}}
+	s := &strings.Builder{}
+	NoError(t, writeAliases(s, as, "text"))
+	Equal(t, "any  0.0.0.0/0     builtin\ndmz  192.0.2.0/26  aliases\n", s.String())
+
+	s.Reset()
+	NoError(t, writeAliases(s, as[:1], "json"))
+	Equal(t, `[{"name":"any","value":"0.0.0.0/0","source":"builtin"}]`+"\n", s.String())
+
+	EqualError(t, writeAliases(s, as, "yaml"), "unsupported output format: yaml")
+}
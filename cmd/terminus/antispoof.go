@@ -0,0 +1,285 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/abc-inc/terminus/bogon"
+	"github.com/spf13/cobra"
+)
+
+var antispoofCmd = &cobra.Command{
+	Use:   "antispoof [flags] PREFIX...",
+	Short: "Generate anti-spoofing filters",
+	Long: `Generate anti-spoofing filters for the given prefixes of the site.
+For ingress (traffic from the Internet), packets with martian source addresses (see terminus bogon) or with a source
+address of the own prefixes are dropped. The IPv6 unspecified and link-local addresses are permitted, since the
+neighbor discovery relies on them.
+For egress (traffic to the Internet), only packets with a source address of the own prefixes are permitted.
+
+Supported formats: nftables, iptables, cisco (IOS access lists), junos (firewall filters).`,
+	Example: `  terminus antispoof --interface eth0 198.51.100.0/24 2001:db8::/32
+  # table inet antispoof {
+  # 	set martians4 {
+  # 	...
+
+  terminus antispoof --format cisco --direction egress 198.51.100.0/24
+  # ip access-list extended ANTISPOOF-OUT
+  #  permit ip 198.51.100.0 0.0.0.255 any
+  #  deny ip any any`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runAntispoofCmd,
+}
+
+func init() {
+	antispoofCmd.Flags().String("format", "nftables", "Filter format (nftables, iptables, cisco, junos)")
+	antispoofCmd.Flags().String("direction", "ingress", "Direction of the traffic to be filtered (ingress, egress)")
+	antispoofCmd.Flags().String("interface", "", "External interface (for nftables and iptables)")
+	antispoofCmd.Flags().String("name", "", "Name of the filter (default: ANTISPOOF-IN or ANTISPOOF-OUT)")
+	rootCmd.AddCommand(antispoofCmd)
+}
+
+func runAntispoofCmd(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	direction, _ := cmd.Flags().GetString("direction")
+	ifName, _ := cmd.Flags().GetString("interface")
+	name, _ := cmd.Flags().GetString("name")
+
+	own, err := parsePrefixes(args)
+	if err != nil {
+		fatal(err)
+	}
+	f, err := newSpoofFilter(own, direction, name, ifName)
+	if err != nil {
+		fatal(err)
+	}
+	if err := f.write(os.Stdout, format); err != nil {
+		fatal(err)
+	}
+}
+
+// filterRule matches the source address of packets.
+type filterRule struct {
+	prefix  netip.Prefix
+	comment string
+}
+
+// spoofFilter drops (ingress) or permits (egress) packets with matching source addresses.
+type spoofFilter struct {
+	name   string
+	ifName string
+	egress bool
+	rules  []filterRule
+}
+
+// newSpoofFilter returns the filter rules for the own prefixes.
+func newSpoofFilter(own []netip.Prefix, direction, name, ifName string) (*spoofFilter, error) {
+	f := &spoofFilter{name: name, ifName: ifName}
+	switch direction {
+	case "ingress":
+		if f.name == "" {
+			f.name = "ANTISPOOF-IN"
+		}
+		for _, b := range bogon.Blocks {
+			if b.Prefix.Addr().Is6() && (b.Category == bogon.ThisNetwork || b.Category == bogon.LinkLocal) {
+				// required by the neighbor discovery (e.g., duplicate address detection)
+				continue
+			}
+			f.rules = append(f.rules, filterRule{b.Prefix, b.RFC + " " + string(b.Category)})
+		}
+	case "egress":
+		if f.name == "" {
+			f.name = "ANTISPOOF-OUT"
+		}
+		f.egress = true
+	default:
+		return nil, usageError("unsupported direction: " + direction)
+	}
+
+	for _, p := range own {
+		f.rules = append(f.rules, filterRule{p, "own prefix"})
+	}
+	return f, nil
+}
+
+// family returns the rules of the given IP version.
+func (f *spoofFilter) family(v4 bool) (rs []filterRule) {
+	for _, r := range f.rules {
+		if r.prefix.Addr().Is4() == v4 {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+func (f *spoofFilter) write(w io.Writer, format string) error {
+	switch format {
+	case "nftables":
+		f.writeNftables(w)
+	case "iptables":
+		f.writeIptables(w)
+	case "cisco":
+		f.writeCisco(w)
+	case "junos":
+		f.writeJunos(w)
+	default:
+		return usageError("unsupported filter format: " + format)
+	}
+	return nil
+}
+
+func (f *spoofFilter) writeNftables(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "table inet antispoof {")
+	for _, v4 := range []bool{true, false} {
+		set, typ := "sources6", "ipv6_addr"
+		if v4 {
+			set, typ = "sources4", "ipv4_addr"
+		}
+		_, _ = fmt.Fprintf(w, "\tset %s {\n\t\ttype %s\n\t\tflags interval\n\t\tauto-merge\n", set, typ)
+		if rs := f.family(v4); len(rs) > 0 {
+			_, _ = fmt.Fprintln(w, "\t\telements = {")
+			for _, r := range rs {
+				_, _ = fmt.Fprintf(w, "\t\t\t%s, # %s\n", r.prefix, r.comment)
+			}
+			_, _ = fmt.Fprintln(w, "\t\t}")
+		}
+		_, _ = fmt.Fprintln(w, "\t}")
+	}
+
+	hook, iif, op, verdict := "prerouting", "iifname", "", "drop"
+	if f.egress {
+		hook, iif, op = "postrouting", "oifname", "!= "
+	}
+	match := ""
+	if f.ifName != "" {
+		match = fmt.Sprintf("%s %q ", iif, f.ifName)
+	}
+	chain := strings.ReplaceAll(f.name, "-", "_")
+	_, _ = fmt.Fprintf(w, "\tchain %s {\n\t\ttype filter hook %s priority raw; policy accept;\n", chain, hook)
+	for _, v4 := range []bool{true, false} {
+		if v4 {
+			_, _ = fmt.Fprintf(w, "\t\t%sip saddr %s@sources4 %s\n", match, op, verdict)
+		} else {
+			_, _ = fmt.Fprintf(w, "\t\t%sip6 saddr %s@sources6 %s\n", match, op, verdict)
+		}
+	}
+	_, _ = fmt.Fprintln(w, "\t}\n}")
+}
+
+func (f *spoofFilter) writeIptables(w io.Writer) {
+	// the raw table does not have a POSTROUTING chain
+	iif, table, hook := "-i", "raw", "PREROUTING"
+	if f.egress {
+		iif, table, hook = "-o", "mangle", "POSTROUTING"
+	}
+	match := ""
+	if f.ifName != "" {
+		match = fmt.Sprintf(" %s %s", iif, f.ifName)
+	}
+
+	for _, v4 := range []bool{true, false} {
+		cmd := "ip6tables"
+		if v4 {
+			cmd = "iptables"
+		}
+		chain := fmt.Sprintf("%s -t %s -%%s %s", cmd, table, f.name)
+		_, _ = fmt.Fprintf(w, chain+"\n", "N")
+		for _, r := range f.family(v4) {
+			verdict := "DROP"
+			if f.egress {
+				verdict = "RETURN"
+			}
+			_, _ = fmt.Fprintf(w, chain+" -s %s -m comment --comment %q -j %s\n", "A", r.prefix, r.comment, verdict)
+		}
+		if f.egress {
+			_, _ = fmt.Fprintf(w, chain+" -j DROP\n", "A")
+		}
+		_, _ = fmt.Fprintf(w, "%s -t %s -A %s%s -j %s\n", cmd, table, hook, match, f.name)
+	}
+}
+
+func (f *spoofFilter) writeCisco(w io.Writer) {
+	deny, permit := "deny", "permit"
+	if f.egress {
+		deny, permit = permit, deny
+	}
+	for _, v4 := range []bool{true, false} {
+		if v4 {
+			_, _ = fmt.Fprintf(w, "ip access-list extended %s\n", f.name)
+		} else {
+			_, _ = fmt.Fprintf(w, "ipv6 access-list %s-V6\n", f.name)
+		}
+		comment := ""
+		for _, r := range f.family(v4) {
+			if r.comment != comment {
+				comment = r.comment
+				_, _ = fmt.Fprintf(w, " remark %s\n", comment)
+			}
+			if v4 {
+				_, _ = fmt.Fprintf(w, " %s ip %s any\n", deny, ciscoWildcard(r.prefix))
+			} else {
+				_, _ = fmt.Fprintf(w, " %s ipv6 %s any\n", deny, r.prefix)
+			}
+		}
+		if v4 {
+			_, _ = fmt.Fprintf(w, " %s ip any any\n", permit)
+		} else {
+			_, _ = fmt.Fprintf(w, " %s ipv6 any any\n", permit)
+		}
+	}
+}
+
+// ciscoWildcard returns the address and wildcard mask of an IPv4 prefix (or "host ADDR" for host prefixes).
+func ciscoWildcard(p netip.Prefix) string {
+	if p.IsSingleIP() {
+		return "host " + p.Addr().String()
+	}
+	m := net.CIDRMask(p.Bits(), 32)
+	for i := range m {
+		m[i] = ^m[i]
+	}
+	return p.Addr().String() + " " + net.IP(m).String()
+}
+
+func (f *spoofFilter) writeJunos(w io.Writer) {
+	then, other := "discard", "accept"
+	if f.egress {
+		then, other = other, then
+	}
+	_, _ = fmt.Fprintln(w, "firewall {")
+	for _, v4 := range []bool{true, false} {
+		family := "inet6"
+		if v4 {
+			family = "inet"
+		}
+		_, _ = fmt.Fprintf(w, "    family %s {\n        filter %s {\n", family, f.name)
+		if rs := f.family(v4); len(rs) > 0 {
+			_, _ = fmt.Fprintln(w, "            term sources {\n                from {\n                    source-address {")
+			for _, r := range rs {
+				_, _ = fmt.Fprintf(w, "                        %s; /* %s */\n", r.prefix, r.comment)
+			}
+			_, _ = fmt.Fprintf(w, "                    }\n                }\n                then %s;\n            }\n", then)
+		}
+		_, _ = fmt.Fprintf(w, "            term default {\n                then %s;\n            }\n", other)
+		_, _ = fmt.Fprintln(w, "        }\n    }")
+	}
+	_, _ = fmt.Fprintln(w, "}")
+}
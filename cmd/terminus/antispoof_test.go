@@ -0,0 +1,82 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSpoofFilterEgress(t *testing.T) {
+	own := []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24"), netip.MustParsePrefix("203.0.113.7/32")}
+	f, err := newSpoofFilter(own, "egress", "", "eth0")
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, f.write(s, "cisco"))
+	Equal(t, `ip access-list extended ANTISPOOF-OUT
+ remark own prefix
+ permit ip 198.51.100.0 0.0.0.255 any
+ permit ip host 203.0.113.7 any
+ deny ip any any
+ipv6 access-list ANTISPOOF-OUT-V6
+ deny ipv6 any any
+`, s.String())
+
+	s.Reset()
+	NoError(t, f.write(s, "iptables"))
+	Equal(t, `iptables -t mangle -N ANTISPOOF-OUT
+iptables -t mangle -A ANTISPOOF-OUT -s 198.51.100.0/24 -m comment --comment "own prefix" -j RETURN
+iptables -t mangle -A ANTISPOOF-OUT -s 203.0.113.7/32 -m comment --comment "own prefix" -j RETURN
+iptables -t mangle -A ANTISPOOF-OUT -j DROP
+iptables -t mangle -A POSTROUTING -o eth0 -j ANTISPOOF-OUT
+ip6tables -t mangle -N ANTISPOOF-OUT
+ip6tables -t mangle -A ANTISPOOF-OUT -j DROP
+ip6tables -t mangle -A POSTROUTING -o eth0 -j ANTISPOOF-OUT
+`, s.String())
+}
+
+func TestSpoofFilterIngress(t *testing.T) {
+	f, err := newSpoofFilter([]netip.Prefix{netip.MustParsePrefix("2001:db8:1000::/36")}, "ingress", "EDGE", "")
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, f.write(s, "nftables"))
+	Contains(t, s.String(), "\t\t\t10.0.0.0/8, # RFC 1918 private\n")
+	Contains(t, s.String(), "\t\t\t2001:db8:1000::/36, # own prefix\n")
+	Contains(t, s.String(), "\tchain EDGE {\n\t\ttype filter hook prerouting priority raw; policy accept;\n"+
+		"\t\tip saddr @sources4 drop\n\t\tip6 saddr @sources6 drop\n\t}\n}\n")
+	NotContains(t, s.String(), "fe80::/10")
+	NotContains(t, s.String(), "::/128")
+
+	s.Reset()
+	NoError(t, f.write(s, "junos"))
+	Contains(t, s.String(), "        filter EDGE {\n            term sources {\n")
+	Contains(t, s.String(), "                        2001:db8:1000::/36; /* own prefix */\n"+
+		"                    }\n                }\n                then discard;\n            }\n"+
+		"            term default {\n                then accept;\n            }\n")
+
+	EqualError(t, f.write(s, "pf"), "unsupported filter format: pf")
+	_, err = newSpoofFilter(nil, "both", "", "")
+	EqualError(t, err, "unsupported direction: both")
+}
+
+func TestCiscoWildcard(t *testing.T) {
+	Equal(t, "100.64.0.0 0.63.255.255", ciscoWildcard(netip.MustParsePrefix("100.64.0.0/10")))
+	Equal(t, "host 192.0.2.1", ciscoWildcard(netip.MustParsePrefix("192.0.2.1/32")))
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/asn"
+	"github.com/spf13/cobra"
+)
+
+var asnCmd = &cobra.Command{
+	Use:   "asn [flags] [IP...]",
+	Short: "Resolve the origin AS of IP addresses",
+	Long: `Resolve the origin autonomous system (AS) number and name of IP addresses.
+By default, the Team Cymru IP to ASN mapping service is queried via DNS.
+Alternatively, --db reads an offline database from an MRT routing table dump (TABLE_DUMP_V2, optionally compressed
+with gzip or bzip2) or a CSV file with PREFIX,ASN[,NAME] or START,END,ASN[,COUNTRY[,NAME]] records.
+If no IP address is given (or "-"), IP addresses are read from stdin (one per line).`,
+	Example: `  terminus asn 192.0.2.1
+  # 192.0.2.1	AS64496	192.0.2.0/24	US	EXAMPLE, US
+
+  terminus asn --db rib.20240101.0000.bz2 -o json < ips.txt`,
+	Args:        cobra.ArbitraryArgs,
+	Run:         runASNCmd,
+	Annotations: map[string]string{sandboxNetwork: "always", sandboxOffline: "db"},
+}
+
+func init() {
+	asnCmd.Flags().String("db", "", "Offline database (MRT or CSV file, or URL)")
+	asnCmd.Flags().Duration("timeout", 5*time.Second, "Timeout per DNS lookup")
+	asnCmd.Flags().Int("parallel", defaultParallel, "Number of concurrent lookups")
+	asnCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(asnCmd)
+}
+
+func runASNCmd(cmd *cobra.Command, args []string) {
+	dbName, _ := cmd.Flags().GetString("db")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	output, _ := cmd.Flags().GetString("output")
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+
+	ips, err := readAddrArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	lookup := func(ip netip.Addr) (asn.Origin, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return asn.LookupCymru(ctx, net.DefaultResolver, ip)
+	}
+	if dbName != "" {
+		b, err := readInputFile(dbName)
+		if err != nil {
+			fatal(err)
+		}
+		db, err := asn.Read(bytes.NewReader(b))
+		if err != nil {
+			fatal(err)
+		}
+		lookup = func(ip netip.Addr) (asn.Origin, error) {
+			if o, ok := db.Lookup(ip); ok {
+				return o, nil
+			}
+			return asn.Origin{}, errors.New("no origin found for " + ip.String())
+		}
+	}
+
+	rs := make([]asnResult, len(ips))
+	failed := false
+	forEachOrdered(par, len(ips), func(i int) interface{} {
+		var err error
+		rs[i].IP = ips[i]
+		rs[i].Origin, err = lookup(ips[i])
+		return err
+	}, func(i int, v interface{}) {
+		if v != nil {
+			log.Println(v)
+			failed = true
+		}
+	})
+
+	if err := writeASN(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// asnResult is the origin of an IP address (ASN 0 if unknown).
+type asnResult struct {
+	IP netip.Addr `json:"ip"`
+	asn.Origin
+}
+
+func writeASN(w io.Writer, rs []asnResult, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			if r.ASN == 0 {
+				_, _ = fmt.Fprintf(w, "%s\t-\n", r.IP)
+				continue
+			}
+			fs := []string{r.IP.String(), fmt.Sprintf("AS%d", r.ASN), r.Prefix.String(), r.Country, r.Name}
+			_, _ = fmt.Fprintln(w, strings.TrimRight(strings.Join(fs, "\t"), "\t"))
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
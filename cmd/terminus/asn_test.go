@@ -0,0 +1,46 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/asn"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteASN(t *testing.T) {
+	rs := []asnResult{
+		{netip.MustParseAddr("192.0.2.1"), asn.Origin{
+			ASN: 64496, Name: "EXAMPLE, US", Prefix: netip.MustParsePrefix("192.0.2.0/24"), Country: "US",
+		}},
+		{netip.MustParseAddr("2001:db8::1"), asn.Origin{ASN: 64511, Prefix: netip.MustParsePrefix("2001:db8::/32")}},
+		{IP: netip.MustParseAddr("198.51.100.1")},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeASN(s, rs, "text"))
+	Equal(t, "192.0.2.1\tAS64496\t192.0.2.0/24\tUS\tEXAMPLE, US\n"+
+		"2001:db8::1\tAS64511\t2001:db8::/32\n"+
+		"198.51.100.1\t-\n", s.String())
+
+	s.Reset()
+	NoError(t, writeASN(s, rs[1:2], "json"))
+	Equal(t, `[{"ip":"2001:db8::1","asn":64511,"prefix":"2001:db8::/32"}]`+"\n", s.String())
+
+	EqualError(t, writeASN(s, rs, "xml"), "unsupported output format: xml")
+}
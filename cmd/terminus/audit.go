@@ -0,0 +1,264 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/abc-inc/terminus/devconf"
+	"github.com/abc-inc/terminus/plan"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit --plan FILE --configs DIR",
+	Short: "Compare the interface addresses of device configs against a plan",
+	Long: `Compare the interface addresses in saved device configs against an address plan and report deviations.
+The addresses are extracted from Cisco IOS and Junos configs as well as the output of "ip address show".
+The device name is the file name without extension (e.g., rtr1 for configs/rtr1.cfg).
+Loopback and link-local addresses are ignored unless they are planned.
+
+The plan is an address plan in YAML (see "Address Plans" in the README), whose devices section lists the addresses
+per device and interface:
+
+  devices:
+    rtr1:
+      GigabitEthernet0/0: 192.0.2.1/24
+      Loopback0: [198.51.100.1/32, 2001:db8::1/128]
+
+Deviations are addresses, which are missing, unexpected, configured with a different prefix length or on
+a different interface, and devices without a config. The exit status is 1 if there is any deviation.
+
+With --watch-file, the audit is re-run whenever one of the files is modified, which gives a live preview
+while editing the plan in another window.`,
+	Example: `  terminus audit --plan plan.yaml --configs configs/
+  # rtr1	GigabitEthernet0/0	192.0.2.1/24	prefix-length	configured as 192.0.2.1/25
+  # rtr2			no-config
+
+  terminus audit --plan plan.yaml --configs configs/ --watch-file plan.yaml`,
+	Args: cobra.NoArgs,
+	Run:  runAuditCmd,
+}
+
+func init() {
+	auditCmd.Flags().String("plan", "", "Address plan (YAML file)")
+	auditCmd.Flags().String("configs", "", "Directory (or file) containing the device configs")
+	auditCmd.Flags().String("format", "auto", "Format of the device configs (auto, ios, junos, linux)")
+	auditCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	auditCmd.Flags().Bool("strict", false, "Reject address plans with unknown keys (e.g., typos)")
+	auditCmd.Flags().StringArray("watch-file", nil, "Re-run the audit whenever the file is modified (e.g., the plan)")
+	_ = auditCmd.MarkFlagRequired("plan")
+	_ = auditCmd.MarkFlagRequired("configs")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditCmd(cmd *cobra.Command, _ []string) {
+	planFile, _ := cmd.Flags().GetString("plan")
+	configs, _ := cmd.Flags().GetString("configs")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	watched, _ := cmd.Flags().GetStringArray("watch-file")
+	strict, _ := cmd.Flags().GetBool("strict")
+	if format == "auto" {
+		format = ""
+	}
+
+	if len(watched) == 0 {
+		n, err := runAudit(os.Stdout, planFile, configs, strict, devconf.Format(format), output)
+		if err != nil {
+			fatal(err)
+		}
+		if n > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	watchOutput(cmd.Context(), watched, func() error {
+		_, err := runAudit(os.Stdout, planFile, configs, strict, devconf.Format(format), output)
+		return err
+	})
+}
+
+// readPlan reads and validates the address plan. If strict is true, unknown keys are rejected.
+func readPlan(name string, strict bool) (*plan.Plan, error) {
+	b, err := readInputFile(name)
+	if err != nil {
+		return nil, err
+	}
+	parse := plan.Parse
+	if strict {
+		parse = plan.ParseStrict
+	}
+	p, err := parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return p, nil
+}
+
+// runAudit compares the device configs against the plan and writes the deviations.
+// It returns the number of deviations. If strict is true, unknown keys in the plan are rejected.
+func runAudit(w io.Writer, planFile, configs string, strict bool, format devconf.Format, output string) (int, error) {
+	p, err := readPlan(planFile, strict)
+	if err != nil {
+		return 0, err
+	}
+	devs, err := readConfigs(configs, format)
+	if err != nil {
+		return 0, err
+	}
+
+	ds := audit(p.Devices, devs)
+	return len(ds), writeAudit(w, ds, output)
+}
+
+// readConfigs extracts the interface addresses of all device configs (regular files, which are not hidden)
+// in the directory. The device name is the file name without extension.
+func readConfigs(dir string, format devconf.Format) (map[string][]devconf.Address, error) {
+	names := []string{dir}
+	if fi, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if fi.IsDir() {
+		es, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		names = nil
+		for _, e := range es {
+			if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+				names = append(names, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+
+	devs := map[string][]devconf.Address{}
+	for _, name := range names {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		as, err := devconf.Extract(format, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		base := filepath.Base(name)
+		devs[strings.TrimSuffix(base, filepath.Ext(base))] = as
+	}
+	return devs, nil
+}
+
+// deviation is a difference between the plan and the config of a device.
+type deviation struct {
+	Device    string `json:"device"`
+	Interface string `json:"interface,omitempty"`
+	// Address is the planned (or configured) address in CIDR notation (empty for no-config).
+	Address string `json:"address,omitempty"`
+	// Kind is missing, unexpected, prefix-length, interface or no-config.
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// audit compares the configured addresses of the devices against the planned ones.
+// Interface names are compared case-insensitively.
+func audit(planned plan.Devices, devs map[string][]devconf.Address) (ds []deviation) {
+	names := make([]string, 0, len(planned)+len(devs))
+	for dev := range planned {
+		names = append(names, dev)
+	}
+	for dev := range devs {
+		if _, ok := planned[dev]; !ok {
+			names = append(names, dev)
+		}
+	}
+	sort.Strings(names)
+
+	for _, dev := range names {
+		as, ok := devs[dev]
+		if !ok {
+			ds = append(ds, deviation{Device: dev, Kind: "no-config"})
+			continue
+		}
+
+		ifs := make([]string, 0, len(planned[dev]))
+		for name := range planned[dev] {
+			ifs = append(ifs, name)
+		}
+		sort.Strings(ifs)
+
+		known := map[netip.Addr]bool{}
+		for _, name := range ifs {
+			for _, p := range planned[dev][name] {
+				known[p.Addr()] = true
+				if d, ok := checkPlanned(dev, name, p, as); ok {
+					ds = append(ds, d)
+				}
+			}
+		}
+		for _, a := range as {
+			if !known[a.Prefix.Addr()] && !a.Prefix.Addr().IsLoopback() && !a.Prefix.Addr().IsLinkLocalUnicast() {
+				ds = append(ds, deviation{dev, a.Interface, a.Prefix.String(), "unexpected", ""})
+			}
+		}
+	}
+	return ds
+}
+
+// checkPlanned returns the deviation of the configured addresses from the planned address p of the interface.
+func checkPlanned(dev, name string, p netip.Prefix, as []devconf.Address) (deviation, bool) {
+	var other *devconf.Address
+	for i, a := range as {
+		if a.Prefix.Addr() != p.Addr() {
+			continue
+		}
+		if !strings.EqualFold(a.Interface, name) {
+			other = &as[i]
+		} else if a.Prefix.Bits() != p.Bits() {
+			return deviation{dev, name, p.String(), "prefix-length", "configured as " + a.Prefix.String()}, true
+		} else {
+			return deviation{}, false
+		}
+	}
+
+	if other != nil {
+		return deviation{dev, name, p.String(), "interface", "configured on " + other.Interface}, true
+	}
+	return deviation{dev, name, p.String(), "missing", ""}, true
+}
+
+func writeAudit(w io.Writer, ds []deviation, output string) error {
+	switch output {
+	case "text":
+		for _, d := range ds {
+			l := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", d.Device, d.Interface, d.Address, d.Kind, d.Detail)
+			_, _ = fmt.Fprintln(w, strings.TrimRight(l, "\t"))
+		}
+		return nil
+	case "json":
+		if ds == nil {
+			ds = []deviation{}
+		}
+		return json.NewEncoder(w).Encode(ds)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/devconf"
+	"github.com/abc-inc/terminus/plan"
+	. "github.com/stretchr/testify/require"
+)
+
+const auditPlanYAML = `devices:
+  rtr1:
+    GigabitEthernet0/0: 192.0.2.1/24
+    GigabitEthernet0/1: 198.51.100.1/24
+    Loopback0: [203.0.113.1/32, 2001:db8::1/128]
+    Loopback1: 203.0.113.2/32
+  rtr2:
+    eth0: 192.0.2.2/24
+`
+
+func TestReadConfigs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := []byte("interface Gi0/0\n ip address 192.0.2.1 255.255.255.0\n")
+	NoError(t, os.WriteFile(filepath.Join(dir, "rtr1.cfg"), cfg, 0o600))
+	NoError(t, os.WriteFile(filepath.Join(dir, ".rtr1.cfg.swp"), []byte("garbage"), 0o600))
+	NoError(t, os.Mkdir(filepath.Join(dir, "old"), 0o700))
+
+	devs, err := readConfigs(dir, "")
+	NoError(t, err)
+	Equal(t, map[string][]devconf.Address{
+		"rtr1": {{Interface: "Gi0/0", Prefix: netip.MustParsePrefix("192.0.2.1/24")}},
+	}, devs)
+
+	NoError(t, os.WriteFile(filepath.Join(dir, "rtr2.txt"), []byte("hostname rtr2\n"), 0o600))
+	_, err = readConfigs(dir, "")
+	EqualError(t, err, filepath.Join(dir, "rtr2.txt")+": unknown configuration format")
+}
+
+func TestAudit(t *testing.T) {
+	p, err := plan.Parse([]byte(auditPlanYAML))
+	NoError(t, err)
+	addr := func(name, p string) devconf.Address {
+		return devconf.Address{Interface: name, Prefix: netip.MustParsePrefix(p)}
+	}
+	devs := map[string][]devconf.Address{
+		"rtr1": {
+			addr("gigabitethernet0/0", "192.0.2.1/24"),
+			addr("GigabitEthernet0/1", "198.51.100.1/25"),
+			addr("Loopback0", "203.0.113.1/32"),
+			addr("Loopback0", "203.0.113.2/32"),
+			addr("GigabitEthernet0/2", "10.0.0.1/30"),
+			addr("GigabitEthernet0/2", "fe80::1/64"),
+		},
+		"sw1": {addr("Vlan1", "10.1.0.1/24"), addr("lo", "127.0.0.1/8")},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeAudit(s, audit(p.Devices, devs), "text"))
+	Equal(t, "rtr1\tGigabitEthernet0/1\t198.51.100.1/24\tprefix-length\tconfigured as 198.51.100.1/25\n"+
+		"rtr1\tLoopback0\t2001:db8::1/128\tmissing\n"+
+		"rtr1\tLoopback1\t203.0.113.2/32\tinterface\tconfigured on Loopback0\n"+
+		"rtr1\tGigabitEthernet0/2\t10.0.0.1/30\tunexpected\n"+
+		"rtr2\t\t\tno-config\n"+
+		"sw1\tVlan1\t10.1.0.1/24\tunexpected\n", s.String())
+}
+
+func TestWriteAudit(t *testing.T) {
+	s := &strings.Builder{}
+	NoError(t, writeAudit(s, nil, "json"))
+	Equal(t, "[]\n", s.String())
+
+	s.Reset()
+	NoError(t, writeAudit(s, []deviation{{Device: "rtr1", Interface: "eth0", Address: "192.0.2.1/24", Kind: "missing"},
+		{Device: "rtr2", Kind: "no-config"}}, "json"))
+	Equal(t, `[{"device":"rtr1","interface":"eth0","address":"192.0.2.1/24","kind":"missing"},`+
+		`{"device":"rtr2","kind":"no-config"}]`+"\n", s.String())
+
+	EqualError(t, writeAudit(s, nil, "xml"), "unsupported output format: xml")
+}
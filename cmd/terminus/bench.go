@@ -0,0 +1,247 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	mrand "math/rand"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [flags]",
+	Short: "Measure the throughput of representative workloads",
+	Long: `Measure the throughput of representative workloads on this machine:
+
+  parse      parse IP addresses and subnets in CIDR notation (1M inputs)
+  aggregate  aggregate random IPv4 prefixes (1M prefixes)
+  template   calculate the properties of subnets and render a template (100k records)
+
+The inputs are generated from a fixed seed, so that the results of different machines and versions are comparable.
+--scale changes the number of operations. With --min-rate, the exit status is 1 if the throughput of a workload
+is lower than the given number of operations per second, which can be used to catch performance regressions.`,
+	Example: `  terminus bench --scale 0.1
+  # parse	100000	0.093s	1075268/s
+  # aggregate	100000	0.052s	1923076/s
+  # template	10000	0.098s	102040/s
+
+  terminus bench --workload aggregate --min-rate aggregate=1000000`,
+	Args: cobra.NoArgs,
+	Run:  runBenchCmd,
+}
+
+func init() {
+	benchCmd.Flags().Float64("scale", 1, "Scale the number of operations (e.g., 0.1 for a quick run)")
+	benchCmd.Flags().StringSlice("workload", nil, "Run the given workloads only (parse, aggregate, template)")
+	benchCmd.Flags().StringArray("min-rate", nil, "Fail if a workload is slower than the rate (WORKLOAD=OPS_PER_SEC)")
+	benchCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBenchCmd(cmd *cobra.Command, _ []string) {
+	scale, _ := cmd.Flags().GetFloat64("scale")
+	names, _ := cmd.Flags().GetStringSlice("workload")
+	minRates, _ := cmd.Flags().GetStringArray("min-rate")
+	output, _ := cmd.Flags().GetString("output")
+
+	ws, err := selectWorkloads(names)
+	if err != nil {
+		fatal(err)
+	}
+	limits, err := parseMinRates(minRates)
+	if err != nil {
+		fatal(err)
+	}
+
+	rs := make([]benchResult, 0, len(ws))
+	for _, w := range ws {
+		r, err := w.run(scale)
+		if err != nil {
+			fatal(err)
+		}
+		rs = append(rs, r)
+	}
+	if err := writeBench(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+
+	failed := false
+	for _, r := range rs {
+		if limit, ok := limits[r.Workload]; ok && r.Rate < limit {
+			log.Printf("%s: %.0f/s is below the minimum rate of %.0f/s", r.Workload, r.Rate, limit)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// benchWorkload is a workload, which consists of ops operations.
+type benchWorkload struct {
+	name string
+	ops  int
+	// setup prepares the inputs of n operations and returns the function, which performs them.
+	setup func(rnd *mrand.Rand, n int) func() error
+}
+
+// benchResult is the throughput of a workload.
+type benchResult struct {
+	Workload string  `json:"workload"`
+	Ops      int     `json:"ops"`
+	Seconds  float64 `json:"seconds"`
+	Rate     float64 `json:"opsPerSecond"`
+}
+
+var benchWorkloads = []benchWorkload{
+	{"parse", 1000000, setupParse},
+	{"aggregate", 1000000, setupAggregate},
+	{"template", 100000, setupTemplate},
+}
+
+// run prepares and measures the workload, whose number of operations is multiplied by scale.
+func (w benchWorkload) run(scale float64) (benchResult, error) {
+	n := int(float64(w.ops) * scale)
+	if n < 1 {
+		n = 1
+	}
+	rnd := mrand.New(mrand.NewSource(deterministicSeed)) //nolint:gosec // reproducible on purpose
+	f := w.setup(rnd, n)
+
+	start := time.Now()
+	if err := f(); err != nil {
+		return benchResult{}, fmt.Errorf("%s: %w", w.name, err)
+	}
+	d := time.Since(start)
+	return benchResult{Workload: w.name, Ops: n, Seconds: d.Seconds(), Rate: float64(n) / d.Seconds()}, nil
+}
+
+// selectWorkloads returns the workloads with the given names (or all if there is no name).
+func selectWorkloads(names []string) ([]benchWorkload, error) {
+	if len(names) == 0 {
+		return benchWorkloads, nil
+	}
+
+	var ws []benchWorkload
+	for _, name := range names {
+		found := false
+		for _, w := range benchWorkloads {
+			if w.name == name {
+				ws, found = append(ws, w), true
+			}
+		}
+		if !found {
+			return nil, errors.New("unknown workload: " + name)
+		}
+	}
+	return ws, nil
+}
+
+// parseMinRates parses the minimum rates per workload (WORKLOAD=OPS_PER_SEC).
+func parseMinRates(ss []string) (map[string]float64, error) {
+	m := map[string]float64{}
+	for _, s := range ss {
+		name, rate, _ := strings.Cut(s, "=")
+		if _, err := selectWorkloads([]string{name}); err != nil {
+			return nil, err
+		}
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil || r < 0 {
+			return nil, errors.New("invalid minimum rate: " + s)
+		}
+		m[name] = r
+	}
+	return m, nil
+}
+
+// randomPrefix returns a random IPv4 prefix with a length between /16 and /32.
+func randomPrefix(rnd *mrand.Rand) netip.Prefix {
+	var b [4]byte
+	v := rnd.Uint32()
+	b[0], b[1], b[2], b[3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+	return netip.PrefixFrom(netip.AddrFrom4(b), 16+rnd.Intn(17))
+}
+
+func setupParse(rnd *mrand.Rand, n int) func() error {
+	args := make([]string, n)
+	for i := range args {
+		if p := randomPrefix(rnd); i%2 == 0 {
+			args[i] = p.String()
+		} else {
+			args[i] = p.Addr().String()
+		}
+	}
+	return func() error {
+		_, err := parseInputs(args, "auto", false)
+		return err
+	}
+}
+
+func setupAggregate(rnd *mrand.Rand, n int) func() error {
+	ps := make([]netip.Prefix, n)
+	for i := range ps {
+		ps[i] = randomPrefix(rnd).Masked()
+	}
+	return func() error {
+		_ = setOf(ps).Prefixes()
+		return nil
+	}
+}
+
+func setupTemplate(rnd *mrand.Rand, n int) func() error {
+	ps := make([]netip.Prefix, n)
+	for i := range ps {
+		ps[i] = randomPrefix(rnd)
+	}
+	return func() error {
+		for _, p := range ps {
+			ip := net.IP(p.Addr().AsSlice())
+			data := iface.GetParams("", ip, net.CIDRMask(p.Bits(), 32))
+			if err := printTemplate("{{.ip}}/{{.prefix}} {{.network}}-{{.broadcast}}", io.Discard, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func writeBench(w io.Writer, rs []benchResult, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%.3fs\t%.0f/s\n", r.Workload, r.Ops, r.Seconds, r.Rate)
+		}
+		return nil
+	case "json":
+		if rs == nil {
+			rs = []benchResult{}
+		}
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestBenchWorkloads(t *testing.T) {
+	for _, w := range benchWorkloads {
+		r, err := w.run(0.0001)
+		NoError(t, err)
+		Equal(t, w.name, r.Workload)
+		Equal(t, w.ops/10000, r.Ops)
+		Greater(t, r.Rate, 0.0)
+	}
+}
+
+func TestSelectWorkloads(t *testing.T) {
+	ws, err := selectWorkloads(nil)
+	NoError(t, err)
+	Len(t, ws, len(benchWorkloads))
+
+	ws, err = selectWorkloads([]string{"template", "parse"})
+	NoError(t, err)
+	Equal(t, "template", ws[0].name)
+	Equal(t, "parse", ws[1].name)
+
+	_, err = selectWorkloads([]string{"sort"})
+	EqualError(t, err, "unknown workload: sort")
+}
+
+func TestParseMinRates(t *testing.T) {
+	m, err := parseMinRates([]string{"parse=500000", "template=1e4"})
+	NoError(t, err)
+	Equal(t, map[string]float64{"parse": 500000, "template": 10000}, m)
+
+	_, err = parseMinRates([]string{"parse"})
+	EqualError(t, err, "invalid minimum rate: parse")
+	_, err = parseMinRates([]string{"sort=1"})
+	EqualError(t, err, "unknown workload: sort")
+}
+
+func TestWriteBench(t *testing.T) {
+	rs := []benchResult{{Workload: "parse", Ops: 1000, Seconds: 0.5, Rate: 2000}}
+	s := &strings.Builder{}
+	NoError(t, writeBench(s, rs, "text"))
+	Equal(t, "parse\t1000\t0.500s\t2000/s\n", s.String())
+
+	s.Reset()
+	NoError(t, writeBench(s, rs, "json"))
+	Equal(t, `[{"workload":"parse","ops":1000,"seconds":0.5,"opsPerSecond":2000}]`+"\n", s.String())
+
+	EqualError(t, writeBench(s, rs, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+
+	"github.com/abc-inc/terminus/bogon"
+	"github.com/spf13/cobra"
+)
+
+var bogonCmd = &cobra.Command{
+	Use:   "bogon [flags] [IP...]",
+	Short: "Check whether IP addresses are bogons",
+	Long: `Check whether IP addresses are bogons i.e., addresses, which must not appear on the Internet.
+Bogons are private (RFC 1918), shared (CGN), loopback, link-local, documentation, benchmarking, multicast and
+reserved addresses as well as unallocated IPv6 addresses (outside of 2000::/3).
+Team-maintained lists like the Team Cymru full bogons (including unallocated IPv4 space) can be added with
+--fullbogons. If no IP address is given (or "-"), IP addresses are read from stdin (one per line).
+Bogons are printed along with the block containing them. The exit status is 1 if any address is a bogon.`,
+	Example: `  terminus bogon 10.1.2.3 192.0.2.1 8.8.8.8
+  # 10.1.2.3	10.0.0.0/8	private	RFC 1918
+  # 192.0.2.1	192.0.2.0/24	documentation	RFC 5737
+
+  terminus bogon -q --fullbogons https://www.team-cymru.org/Services/Bogons/fullbogons-ipv4.txt "${SRC_IP}"`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runBogonCmd,
+}
+
+func init() {
+	bogonCmd.Flags().StringArray("fullbogons", nil, "Additional list of bogon networks (file, stdin (-) or URL)")
+	bogonCmd.Flags().BoolP("quiet", "q", false, "Do not print anything (only set the exit status)")
+	bogonCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(bogonCmd)
+}
+
+func runBogonCmd(cmd *cobra.Command, args []string) {
+	files, _ := cmd.Flags().GetStringArray("fullbogons")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	output, _ := cmd.Flags().GetString("output")
+
+	full, err := readFullBogons(files)
+	if err != nil {
+		fatal(err)
+	}
+	ips, err := readAddrArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	rs := checkBogons(bogon.NewChecker(full), ips)
+	if !quiet {
+		if err := writeBogons(os.Stdout, rs, output); err != nil {
+			fatal(err)
+		}
+	}
+	if len(rs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// readFullBogons reads the networks of team-maintained bogon lists.
+func readFullBogons(names []string) ([]netip.Prefix, error) {
+	var ps []netip.Prefix
+	for _, name := range names {
+		b, err := readInputFile(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range parseTargets(b) {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			ps = append(ps, p)
+		}
+	}
+	return ps, nil
+}
+
+// bogonResult is an IP address along with the bogon block containing it.
+type bogonResult struct {
+	IP netip.Addr `json:"ip"`
+	bogon.Block
+}
+
+// checkBogons returns the IP addresses, which are bogons.
+func checkBogons(c *bogon.Checker, ips []netip.Addr) []bogonResult {
+	var rs []bogonResult
+	for _, ip := range ips {
+		if b, ok := c.Check(ip); ok {
+			rs = append(rs, bogonResult{ip, b})
+		}
+	}
+	return rs
+}
+
+func writeBogons(w io.Writer, rs []bogonResult, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			rfc := ""
+			if r.RFC != "" {
+				rfc = "\t" + r.RFC
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s%s\n", r.IP, r.Prefix, r.Category, rfc)
+		}
+		return nil
+	case "json":
+		if rs == nil {
+			rs = []bogonResult{}
+		}
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
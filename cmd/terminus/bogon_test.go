@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/bogon"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCheckBogons(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "fullbogons-ipv4.txt")
+	NoError(t, os.WriteFile(name, []byte("# last updated 1700000000\n45.61.40.0/22\n"), 0o600))
+	full, err := readFullBogons([]string{name})
+	NoError(t, err)
+
+	ips := []netip.Addr{
+		netip.MustParseAddr("10.1.2.3"), netip.MustParseAddr("8.8.8.8"), netip.MustParseAddr("45.61.41.1"),
+	}
+	rs := checkBogons(bogon.NewChecker(full), ips)
+
+	s := &strings.Builder{}
+	NoError(t, writeBogons(s, rs, "text"))
+	Equal(t, "10.1.2.3\t10.0.0.0/8\tprivate\tRFC 1918\n45.61.41.1\t45.61.40.0/22\tunallocated\n", s.String())
+
+	s.Reset()
+	NoError(t, writeBogons(s, rs[:1], "json"))
+	Equal(t, `[{"ip":"10.1.2.3","prefix":"10.0.0.0/8","category":"private","rfc":"RFC 1918"}]`+"\n", s.String())
+
+	s.Reset()
+	NoError(t, writeBogons(s, checkBogons(bogon.NewChecker(nil), ips[1:2]), "json"))
+	Equal(t, "[]\n", s.String())
+
+	EqualError(t, writeBogons(s, rs, "xml"), "unsupported output format: xml")
+}
+
+func TestReadFullBogonsInvalid(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "bogons.txt")
+	NoError(t, os.WriteFile(name, []byte("45.61.40.1\n"), 0o600))
+	_, err := readFullBogons([]string{name})
+	ErrorContains(t, err, name)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+// Optional features of the terminus command, which complement the features of the iface package.
+const (
+	featureSandbox iface.Feature = "sandbox"
+	featureWatch   iface.Feature = "watch"
+)
+
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities [flags]",
+	Short: "Print the optional features, which are available on the current platform",
+	Long: `Print the optional features, which are available on the current platform (operating system, architecture
+and build).
+Commands, which depend on an unavailable feature, fail with a "not supported on this platform" error.`,
+	Example: `  terminus capabilities
+  # FEATURE      SUPPORTED  DETAIL
+  # routes       yes        /proc/net/route
+  # ipv6-routes  yes        /proc/net/ipv6_route`,
+	Args: cobra.NoArgs,
+	Run:  runCapabilitiesCmd,
+}
+
+func init() {
+	capabilitiesCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(capabilitiesCmd)
+}
+
+func runCapabilitiesCmd(cmd *cobra.Command, _ []string) {
+	output, _ := cmd.Flags().GetString("output")
+	if err := writeCapabilities(os.Stdout, capabilities(), output); err != nil {
+		fatal(err)
+	}
+}
+
+// capabilities returns the registry of optional features and whether they are available on the current platform.
+func capabilities() []iface.Capability {
+	return append(iface.Capabilities(),
+		sandboxCapability,
+		tuiCapability,
+		iface.Capability{
+			Feature: featureWatch, Description: "file watching", Supported: true, Detail: "polling of modification times",
+		},
+	)
+}
+
+// supported returns nil if the feature is available or an error wrapping iface.ErrNotSupported otherwise.
+func supported(f iface.Feature) error {
+	for _, c := range capabilities() {
+		if c.Feature == f {
+			return c.Err()
+		}
+	}
+	return iface.Supported(f)
+}
+
+func writeCapabilities(w io.Writer, cs []iface.Capability, output string) error {
+	switch output {
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "FEATURE\tSUPPORTED\tDETAIL")
+		for _, c := range cs {
+			s := "no"
+			if c.Supported {
+				s = "yes"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Feature, s, c.Detail)
+		}
+		return tw.Flush()
+	case "json":
+		return json.NewEncoder(w).Encode(cs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
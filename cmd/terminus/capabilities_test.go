@@ -0,0 +1,54 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSupported(t *testing.T) {
+	NoError(t, supported(featureWatch))
+	Equal(t, sandboxCapability.Supported, supported(featureSandbox) == nil)
+	ErrorIs(t, supported("teleport"), iface.ErrNotSupported)
+	if !sandboxCapability.Supported {
+		ErrorIs(t, sandbox(sandboxPolicy{}), iface.ErrNotSupported)
+	}
+}
+
+func TestWriteCapabilities(t *testing.T) {
+	cs := []iface.Capability{
+		{Feature: iface.FeatureRoutes, Description: "routing table lookup", Supported: true, Detail: "/proc/net/route"},
+		{Feature: iface.FeatureIPv6Routes, Description: "IPv6 routing table lookup"},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeCapabilities(s, cs, "text"))
+	Equal(t, "FEATURE      SUPPORTED  DETAIL\n"+
+		"routes       yes        /proc/net/route\n"+
+		"ipv6-routes  no         \n", s.String())
+
+	s.Reset()
+	NoError(t, writeCapabilities(s, cs, "json"))
+	var got []iface.Capability
+	NoError(t, json.Unmarshal([]byte(s.String()), &got))
+	Equal(t, cs, got)
+
+	EqualError(t, writeCapabilities(s, cs, "yaml"), "unsupported output format: yaml")
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var capacityCmd = &cobra.Command{
+	Use: `capacity [flags] PARENT_CIDR CHILD_PREFIX
+  terminus capacity [flags] --hosts N [PARENT_CIDR]`,
+	Short: "Calculate how many subnets fit into a network or which prefix fits a number of hosts",
+	Long: `Calculate how many subnets of the given prefix length fit into the parent network.
+With --hosts, the smallest subnet (i.e., the longest prefix), which has at least N usable addresses, is calculated
+instead. If the parent network is given as well, the number of such subnets, which fit into it, is reported.
+Point-to-point subnets (/31 and /127) have two usable addresses (RFC 3021).`,
+	Example: `  terminus capacity 10.0.0.0/22 26
+  # prefix:  26
+  # hosts:   62
+  # subnets: 16
+
+  terminus capacity --hosts 500
+  # prefix:  23
+  # hosts:   510`,
+	Args: cobra.RangeArgs(0, 2),
+	Run:  runCapacityCmd,
+}
+
+func init() {
+	capacityCmd.Flags().String("hosts", "", "Calculate the smallest subnet with at least N usable addresses")
+	capacityCmd.Flags().String("family", "4", "Address family of the subnet (with --hosts and no parent) (4, 6)")
+	capacityCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(capacityCmd)
+}
+
+func runCapacityCmd(cmd *cobra.Command, args []string) {
+	var c capacityResult
+	var err error
+	if hosts, _ := cmd.Flags().GetString("hosts"); cmd.Flags().Changed("hosts") {
+		if len(args) > 1 {
+			fatal(usageError("either CHILD_PREFIX or --hosts can be given"))
+		}
+		family, _ := cmd.Flags().GetString("family")
+		c, err = capacityForHosts(hosts, family, args)
+	} else if len(args) == 2 {
+		c, err = capacity(args[0], args[1])
+	} else {
+		_ = cmd.Usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if err := writeCapacity(os.Stdout, c, output); err != nil {
+		fatal(err)
+	}
+}
+
+// capacityResult describes the subnets of a prefix length and how many of them fit into the parent network.
+type capacityResult struct {
+	Parent string `json:"parent,omitempty"`
+	Prefix int    `json:"prefix"`
+	// Hosts is the number of usable addresses of every subnet.
+	Hosts *big.Int `json:"hosts"`
+	// Subnets is the number of subnets, which fit into the parent network (nil if there is no parent network).
+	Subnets *big.Int `json:"subnets,omitempty"`
+}
+
+// capacity returns the number of subnets with the given prefix length (e.g., 26 or /26), which fit into parent.
+func capacity(parent, child string) (capacityResult, error) {
+	p, err := netip.ParsePrefix(parent)
+	if err != nil {
+		return capacityResult{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, parent)
+	}
+	size, err := strconv.Atoi(strings.TrimPrefix(child, "/"))
+	if err != nil || size < 0 || size > p.Addr().BitLen() {
+		return capacityResult{}, usageError(fmt.Sprintf("invalid prefix length (must be 0-%d): %s", p.Addr().BitLen(), child))
+	} else if size < p.Bits() {
+		return capacityResult{}, usageError(fmt.Sprintf("prefix length %d is shorter than the prefix of %s", size, p.Masked()))
+	}
+
+	c := capacityResult{Parent: p.Masked().String(), Prefix: size, Hosts: usableHosts(size, p.Addr().BitLen())}
+	c.Subnets = new(big.Int).Lsh(big.NewInt(1), uint(size-p.Bits()))
+	return c, nil
+}
+
+// capacityForHosts returns the longest prefix length of the family (4 or 6), whose subnets have at least the given
+// number of usable addresses. If the parent network is given (as only element of args), it determines the family and
+// the number of such subnets, which fit into it, is calculated as well.
+func capacityForHosts(hosts, family string, args []string) (capacityResult, error) {
+	n, ok := new(big.Int).SetString(hosts, 10)
+	if !ok || n.Sign() <= 0 {
+		return capacityResult{}, usageError(fmt.Sprintf("invalid number of hosts (must be positive): %s", hosts))
+	}
+
+	var parent netip.Prefix
+	if len(args) == 1 {
+		var err error
+		if parent, err = netip.ParsePrefix(args[0]); err != nil {
+			return capacityResult{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, args[0])
+		}
+		family = "4"
+		if parent.Addr().Is6() {
+			family = "6"
+		}
+	}
+	bits := 32
+	switch family {
+	case "4":
+	case "6":
+		bits = 128
+	default:
+		return capacityResult{}, usageError(fmt.Sprintf("invalid family (must be 4 or 6): %s", family))
+	}
+
+	size := bits
+	for size >= 0 && usableHosts(size, bits).Cmp(n) < 0 {
+		size--
+	}
+	if size < 0 {
+		return capacityResult{}, usageError(fmt.Sprintf("no IPv%s subnet has %s usable addresses", family, hosts))
+	}
+
+	c := capacityResult{Prefix: size, Hosts: usableHosts(size, bits)}
+	if parent.IsValid() {
+		c.Parent, c.Subnets = parent.Masked().String(), new(big.Int)
+		if size >= parent.Bits() {
+			c.Subnets.Lsh(big.NewInt(1), uint(size-parent.Bits()))
+		}
+	}
+	return c, nil
+}
+
+// usableHosts returns the number of usable addresses of a subnet with the given prefix length.
+// IPv4 subnets do not use the network and broadcast address, except for /31 (RFC 3021) and /32.
+func usableHosts(size, bits int) *big.Int {
+	n := new(big.Int).Lsh(big.NewInt(1), uint(bits-size))
+	if bits == 32 && size < 31 {
+		n.Sub(n, big.NewInt(2))
+	}
+	return n
+}
+
+// writeCapacity writes the prefix length, the number of usable addresses and the number of subnets (if any).
+func writeCapacity(w io.Writer, c capacityResult, output string) error {
+	switch output {
+	case "text":
+		_, _ = fmt.Fprintf(w, "prefix:  %d\n", c.Prefix)
+		_, _ = fmt.Fprintf(w, "hosts:   %s\n", loc.format(c.Hosts))
+		if c.Subnets != nil {
+			_, _ = fmt.Fprintf(w, "subnets: %s\n", loc.format(c.Subnets))
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(c)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
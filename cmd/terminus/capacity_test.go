@@ -0,0 +1,116 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCapacity(t *testing.T) {
+	tests := []struct {
+		parent, child string
+		hosts         string
+		subnets       string
+	}{
+		{"10.0.0.0/22", "26", "62", "16"},
+		{"10.0.0.0/22", "/22", "1022", "1"},
+		{"10.0.0.0/24", "31", "2", "128"},
+		{"10.0.0.0/24", "32", "1", "256"},
+		{"10.1.2.3/16", "24", "254", "256"},
+		{"2001:db8::/48", "64", "18446744073709551616", "65536"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.parent+" "+tt.child, func(t *testing.T) {
+			c, err := capacity(tt.parent, tt.child)
+			NoError(t, err)
+			Equal(t, tt.hosts, c.Hosts.String())
+			Equal(t, tt.subnets, c.Subnets.String())
+		})
+	}
+
+	_, err := capacity("10.0.0.0/22", "20")
+	EqualError(t, err, "prefix length 20 is shorter than the prefix of 10.0.0.0/22")
+	_, err = capacity("10.0.0.0/22", "33")
+	EqualError(t, err, "invalid prefix length (must be 0-32): 33")
+	Equal(t, exitParse, exitCode(err))
+	_, err = capacity("10.0.0.0/22", "x")
+	Equal(t, exitParse, exitCode(err))
+	_, err = capacity("10.0.0.0", "24")
+	ErrorIs(t, err, ErrInvalidCIDR)
+}
+
+func TestCapacityForHosts(t *testing.T) {
+	tests := []struct {
+		hosts, family string
+		args          []string
+		prefix        int
+		subnets       string
+	}{
+		{"500", "4", nil, 23, ""},
+		{"62", "4", nil, 26, ""},
+		{"63", "4", nil, 25, ""},
+		{"2", "4", nil, 31, ""},
+		{"1", "4", nil, 32, ""},
+		{"3", "6", nil, 126, ""},
+		{"62", "4", []string{"10.0.0.0/22"}, 26, "16"},
+		{"5000", "4", []string{"192.168.0.0/24"}, 19, "0"},
+		{"4", "4", []string{"2001:db8::/120"}, 126, "64"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.hosts+" "+tt.family, func(t *testing.T) {
+			c, err := capacityForHosts(tt.hosts, tt.family, tt.args)
+			NoError(t, err)
+			Equal(t, tt.prefix, c.Prefix)
+			if tt.subnets == "" {
+				Nil(t, c.Subnets)
+			} else {
+				Equal(t, tt.subnets, c.Subnets.String())
+			}
+		})
+	}
+
+	_, err := capacityForHosts("0", "4", nil)
+	EqualError(t, err, "invalid number of hosts (must be positive): 0")
+	Equal(t, exitParse, exitCode(err))
+	_, err = capacityForHosts("5000000000", "4", nil)
+	EqualError(t, err, "no IPv4 subnet has 5000000000 usable addresses")
+	_, err = capacityForHosts("1", "5", nil)
+	EqualError(t, err, "invalid family (must be 4 or 6): 5")
+	Equal(t, exitParse, exitCode(err))
+}
+
+func TestWriteCapacity(t *testing.T) {
+	c, err := capacity("10.0.0.0/22", "26")
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, writeCapacity(s, c, "text"))
+	Equal(t, "prefix:  26\nhosts:   62\nsubnets: 16\n", s.String())
+
+	s.Reset()
+	NoError(t, writeCapacity(s, c, "json"))
+	Equal(t, `{"parent":"10.0.0.0/22","prefix":26,"hosts":62,"subnets":16}`+"\n", s.String())
+
+	c, err = capacityForHosts("500", "4", nil)
+	NoError(t, err)
+	s.Reset()
+	NoError(t, writeCapacity(s, c, "json"))
+	Equal(t, `{"prefix":23,"hosts":510}`+"\n", s.String())
+}
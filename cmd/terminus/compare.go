@@ -0,0 +1,147 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [flags] A B",
+	Short: "Compare two IP addresses, networks or IP ranges",
+	Long: `Compare two IP addresses, networks or IP ranges (FROM-TO).
+The relation of A to B is one of:
+  equal      A and B consist of the same addresses
+  contains   A contains all addresses of B
+  contained  A is contained in B
+  overlap    A and B have some, but not all addresses in common
+  adjacent   A and B have no addresses in common, but one directly follows the other
+  disjoint   A and B have no addresses in common
+The distance is the number of addresses from the first address of A to the first address of B
+(negative if B starts before A).`,
+	Example: `  terminus compare 10.0.0.0/16 10.0.4.0/24
+  # relation: contains
+  # distance: 1024
+
+  terminus compare -o json 192.0.2.0/25 192.0.2.128/25
+  # {"a":"192.0.2.0/25","b":"192.0.2.128/25","relation":"adjacent","distance":128}`,
+	Args: cobra.ExactArgs(2),
+	Run:  runCompareCmd,
+}
+
+func init() {
+	compareCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompareCmd(cmd *cobra.Command, args []string) {
+	c, err := compare(args[0], args[1])
+	if err != nil {
+		fatal(err)
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if err := writeComparison(os.Stdout, c, output); err != nil {
+		fatal(err)
+	}
+}
+
+// comparison is the result of comparing two IP addresses, networks or IP ranges.
+type comparison struct {
+	A        string   `json:"a"`
+	B        string   `json:"b"`
+	Relation string   `json:"relation"`
+	Distance *big.Int `json:"distance"`
+}
+
+// compare determines the relation of a to b and the distance between their first addresses.
+// Both must be of the same address family.
+func compare(a, b string) (comparison, error) {
+	ra, err := parseCompareRange(a)
+	if err != nil {
+		return comparison{}, err
+	}
+	rb, err := parseCompareRange(b)
+	if err != nil {
+		return comparison{}, err
+	}
+	if ra.From.Is4() != rb.From.Is4() {
+		return comparison{}, errors.New("cannot compare IPv4 and IPv6: " + a + " " + b)
+	}
+
+	c := comparison{A: a, B: b, Relation: relation(ra, rb)}
+	c.Distance = new(big.Int).Sub(addrInt(rb.From), addrInt(ra.From))
+	return c, nil
+}
+
+// relation returns the relation of the range a to the range b.
+func relation(a, b ipset.Range) string {
+	switch {
+	case a == b:
+		return "equal"
+	case !b.From.Less(a.From) && !a.To.Less(b.To):
+		return "contains"
+	case !a.From.Less(b.From) && !b.To.Less(a.To):
+		return "contained"
+	case !b.To.Less(a.From) && !a.To.Less(b.From):
+		return "overlap"
+	case a.To.Next() == b.From || b.To.Next() == a.From:
+		return "adjacent"
+	default:
+		return "disjoint"
+	}
+}
+
+// parseCompareRange parses an IP address, a network, an IP range (FROM-TO) or an alias.
+func parseCompareRange(s string) (ipset.Range, error) {
+	v, ok, err := lookupAlias(s)
+	if err != nil {
+		return ipset.Range{}, err
+	} else if !ok {
+		v = s
+	}
+	r, err := ipset.ParseRange(v)
+	if err != nil {
+		return ipset.Range{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+	}
+	return r, nil
+}
+
+// addrInt returns the IP address as integer.
+func addrInt(a netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(a.AsSlice())
+}
+
+// writeComparison writes the relation and the distance.
+func writeComparison(w io.Writer, c comparison, output string) error {
+	switch output {
+	case "text":
+		_, _ = fmt.Fprintf(w, "relation: %s\n", c.Relation)
+		_, _ = fmt.Fprintf(w, "distance: %s\n", loc.format(c.Distance))
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(c)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		relation string
+		distance string
+	}{
+		{"10.0.0.0/24", "10.0.0.0/24", "equal", "0"},
+		{"10.0.0.1", "10.0.0.1/32", "equal", "0"},
+		{"10.0.0.0/16", "10.0.4.0/24", "contains", "1024"},
+		{"10.0.0.5", "10.0.0.0/24", "contained", "-5"},
+		{"10.0.0.0-10.0.0.10", "10.0.0.5-10.0.0.20", "overlap", "5"},
+		{"192.0.2.0/25", "192.0.2.128/25", "adjacent", "128"},
+		{"192.0.2.128/25", "192.0.2.0/25", "adjacent", "-128"},
+		{"10.2.0.0/16", "10.0.0.0/16", "disjoint", "-131072"},
+		{"2001:db8::/32", "2001:db9::", "adjacent", "79228162514264337593543950336"},
+		{"::/0", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "contains", "340282366920938463463374607431768211455"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			c, err := compare(tt.a, tt.b)
+			NoError(t, err)
+			Equal(t, tt.relation, c.Relation)
+			Equal(t, tt.distance, c.Distance.String())
+		})
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	_, err := compare("10.0.0.0/33", "10.0.0.1")
+	EqualError(t, err, "invalid IP address or CIDR: 10.0.0.0/33")
+	ErrorIs(t, err, ErrInvalidCIDR)
+
+	_, err = compare("10.0.0.1", "::1")
+	EqualError(t, err, "cannot compare IPv4 and IPv6: 10.0.0.1 ::1")
+}
+
+func TestWriteComparison(t *testing.T) {
+	c, err := compare("10.0.0.0/16", "10.0.4.0/24")
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, writeComparison(s, c, "text"))
+	Equal(t, "relation: contains\ndistance: 1024\n", s.String())
+
+	s.Reset()
+	NoError(t, writeComparison(s, c, "json"))
+	Equal(t, `{"a":"10.0.0.0/16","b":"10.0.4.0/24","relation":"contains","distance":1024}`+"\n", s.String())
+
+	EqualError(t, writeComparison(s, c, "yaml"), "unsupported output format: yaml")
+}
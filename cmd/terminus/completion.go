@@ -0,0 +1,105 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/mtu"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// enumUsage matches the list of supported values in the usage of a flag e.g., "Output format (text, json)".
+var enumUsage = regexp.MustCompile(`\(([a-z0-9-]+(?:, [a-z0-9-]+)+)\)$`)
+
+// registerCompletions registers the functions, which dynamically complete interface names and flag values
+// in the shell completion scripts (terminus completion bash|zsh|fish|powershell).
+// It must be called after the flags of all commands are defined.
+func registerCompletions(root *cobra.Command) {
+	for _, c := range []*cobra.Command{root, hostsCmd, infoCmd, mtuCmd, splitCmd} {
+		c.ValidArgsFunction = completeInterfaces
+	}
+
+	cmds := []*cobra.Command{root}
+	for len(cmds) > 0 {
+		c := cmds[0]
+		cmds = append(cmds[1:], c.Commands()...)
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if m := enumUsage.FindStringSubmatch(f.Usage); m != nil {
+				_ = c.RegisterFlagCompletionFunc(f.Name, completeValues(strings.Split(m[1], ", ")...))
+			}
+		})
+	}
+
+	names := make([]string, len(mtu.Encapsulations))
+	for i, e := range mtu.Encapsulations {
+		names[i] = e.Name + "\t" + e.Description
+	}
+	_ = mtuCmd.RegisterFlagCompletionFunc("encap", completeValues(names...))
+	_ = antispoofCmd.RegisterFlagCompletionFunc("interface", completeInterfaces)
+	for _, c := range []*cobra.Command{root, infoCmd} {
+		_ = c.RegisterFlagCompletionFunc("missingkey", completeValues("error", "zero", "default="))
+		_ = c.RegisterFlagCompletionFunc("fields", completeFields)
+	}
+}
+
+// completeValues returns a completion function, which suggests the given values (VALUE or VALUE\tDESCRIPTION).
+func completeValues(vals ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return vals, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFields suggests the property names for the last entry of a comma-separated list.
+func completeFields(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+	var names []string
+	for _, p := range templateProperties {
+		if !isContextProperty(p.Name) {
+			names = append(names, prefix+p.Name+"\t"+p.Description)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeInterfaces suggests the names of the network interfaces along with their IP addresses.
+// Commands, which accept a single argument only, are completed once.
+func completeInterfaces(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 && cmd.HasParent() && cmd != infoCmd {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	is, err := iface.Interfaces()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+
+	names := make([]string, 0, len(is))
+	for _, i := range is {
+		if ip, n, err := iface.GetAddr(i.Name); err == nil {
+			size, _ := n.Mask.Size()
+			names = append(names, i.Name+"\t"+ip.String()+"/"+strconv.Itoa(size))
+		} else {
+			names = append(names, i.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func complete(t *testing.T, args ...string) []string {
+	registerCompletions(rootCmd)
+	s := &strings.Builder{}
+	rootCmd.SetOut(s)
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+	NoError(t, rootCmd.Execute())
+	return strings.Split(strings.TrimSpace(s.String()), "\n")
+}
+
+func TestCompleteFlagValues(t *testing.T) {
+	Equal(t, []string{"text", "json", ":4"}, complete(t, "whois", "-o", ""))
+	Equal(t, []string{"nftables", "iptables", "cisco", "junos", ":4"}, complete(t, "antispoof", "--format", ""))
+	Contains(t, complete(t, "mtu", "--encap", ""), "wireguard\tWireGuard (UDP 8 + WireGuard 16 + tag 16, "+
+		"padding never exceeds the MTU)")
+}
+
+func TestCompleteInterfaces(t *testing.T) {
+	names := complete(t, "split", "")
+	True(t, contains(names, "lo\t127.0.0.1/8") || contains(names, "lo0\t127.0.0.1/8"), names)
+	Equal(t, []string{":4"}, complete(t, "split", "lo", ""))
+	Equal(t, names, complete(t, "info", "lo", ""))
+}
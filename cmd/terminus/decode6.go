@@ -0,0 +1,69 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/abc-inc/terminus/ipv6"
+	"github.com/spf13/cobra"
+)
+
+var decode6Cmd = &cobra.Command{
+	Use:   "decode6 IPv6",
+	Short: "Decode the IPv4 address embedded in an IPv6 transition address",
+	Long: `Decode the IPv4 address embedded in an IPv6 transition address.
+Supported are 6to4 (2002::/16), Teredo (2001::/32), IPv4-mapped (::ffff:0:0/96), ` +
+		`NAT64 well-known prefix (64:ff9b::/96) and ISATAP addresses.`,
+	Example: `  terminus decode6 2001:0:4136:e378:8000:63bf:3fff:fdd2
+  # type:   teredo
+  # ipv4:   192.0.2.45
+  # server: 65.54.227.120
+  # port:   40000
+  # flags:  0x8000`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDecode6Cmd,
+}
+
+func init() {
+	rootCmd.AddCommand(decode6Cmd)
+}
+
+func runDecode6Cmd(_ *cobra.Command, args []string) {
+	ip := net.ParseIP(args[0])
+	if ip == nil || !strings.Contains(args[0], ":") {
+		fatal(&net.ParseError{Type: "IPv6 address", Text: args[0]})
+	}
+
+	ta, err := ipv6.DecodeTransition(ip)
+	if err != nil {
+		fatal(err)
+	}
+	printTransition(os.Stdout, ta)
+}
+
+func printTransition(w io.Writer, ta *ipv6.TransitionAddr) {
+	_, _ = fmt.Fprintf(w, "type:   %s\n", ta.Type)
+	_, _ = fmt.Fprintf(w, "ipv4:   %v\n", ta.IPv4)
+	if ta.Type == ipv6.Teredo {
+		_, _ = fmt.Fprintf(w, "server: %v\n", ta.Server)
+		_, _ = fmt.Fprintf(w, "port:   %d\n", ta.Port)
+		_, _ = fmt.Fprintf(w, "flags:  %#04x\n", ta.Flags)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipv6"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrintTransition(t *testing.T) {
+	ta, _ := ipv6.DecodeTransition(net.ParseIP("2001:0:4136:e378:8000:63bf:3fff:fdd2"))
+	s := &strings.Builder{}
+	printTransition(s, ta)
+	Equal(t, "type:   teredo\nipv4:   192.0.2.45\nserver: 65.54.227.120\nport:   40000\nflags:  0x8000\n", s.String())
+
+	ta, _ = ipv6.DecodeTransition(net.ParseIP("2002:c000:0204::1"))
+	s.Reset()
+	printTransition(s, ta)
+	Equal(t, "type:   6to4\nipv4:   192.0.2.4\n", s.String())
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+// deterministicSeed is the seed of the random number generator in deterministic mode.
+const deterministicSeed = 1
+
+var (
+	// randReader is the source of random numbers (e.g., for generating MAC addresses).
+	randReader io.Reader = rand.Reader
+	// now returns the current time (e.g., for generating ULA prefixes).
+	now = time.Now
+	// deterministic reports whether --deterministic is set, i.e., sets of inputs are sorted.
+	deterministic bool
+)
+
+// applyDeterministic replaces the sources of random numbers and time if --deterministic is set.
+// Random numbers are generated from a fixed seed (or --seed), and the time is taken from SOURCE_DATE_EPOCH
+// (see https://reproducible-builds.org/specs/source-date-epoch/), which defaults to the Unix epoch.
+// Without --deterministic, --seed replaces the source of random numbers only.
+func applyDeterministic(cmd *cobra.Command) error {
+	seed, seeded := int64(deterministicSeed), false
+	if f := cmd.Flags().Lookup("seed"); f != nil && f.Changed {
+		seed, _ = cmd.Flags().GetInt64("seed")
+		seeded = true
+	}
+
+	deterministic, _ = cmd.Flags().GetBool("deterministic")
+	if deterministic {
+		t := time.Unix(0, 0).UTC()
+		if sde := os.Getenv("SOURCE_DATE_EPOCH"); sde != "" {
+			sec, err := strconv.ParseInt(sde, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid SOURCE_DATE_EPOCH: %s", sde)
+			}
+			t = time.Unix(sec, 0).UTC()
+		}
+		now = func() time.Time { return t }
+		seeded = true
+	}
+
+	if seeded {
+		randReader = mrand.New(mrand.NewSource(seed)) //nolint:gosec // reproducible on purpose
+	}
+	return nil
+}
+
+// sortIPs orders the IP addresses read by subcommands in deterministic mode.
+func sortIPs(ips []netip.Addr) {
+	sort.SliceStable(ips, func(i, j int) bool { return ips[i].Less(ips[j]) })
+}
+
+// sortPrefixes orders the networks read by subcommands by IP address and prefix length in deterministic mode.
+func sortPrefixes(ps []netip.Prefix) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		if c := ps[i].Addr().Compare(ps[j].Addr()); c != 0 {
+			return c < 0
+		}
+		return ps[i].Bits() < ps[j].Bits()
+	})
+}
+
+// sortInputs orders the inputs by IP address and prefix length.
+func sortInputs(ins []*input) {
+	sort.SliceStable(ins, func(i, j int) bool {
+		if c := iplib.CompareIPs(ins[i].ip, ins[j].ip); c != 0 {
+			return c < 0
+		}
+		si, _ := ins[i].n.Mask.Size()
+		sj, _ := ins[j].n.Mask.Size()
+		return si < sj
+	})
+}
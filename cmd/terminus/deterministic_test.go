@@ -0,0 +1,103 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"io"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestApplyDeterministic(t *testing.T) {
+	defer func() { randReader, now, deterministic = rand.Reader, time.Now, false }()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("deterministic", false, "")
+	NoError(t, applyDeterministic(cmd))
+	Equal(t, rand.Reader, randReader)
+
+	NoError(t, cmd.ParseFlags([]string{"--deterministic"}))
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	NoError(t, applyDeterministic(cmd))
+	Equal(t, time.Unix(1700000000, 0).UTC(), now())
+
+	b1 := make([]byte, 8)
+	_, _ = io.ReadFull(randReader, b1)
+	NoError(t, applyDeterministic(cmd))
+	b2 := make([]byte, 8)
+	_, _ = io.ReadFull(randReader, b2)
+	Equal(t, b1, b2)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	EqualError(t, applyDeterministic(cmd), "invalid SOURCE_DATE_EPOCH: yesterday")
+}
+
+func TestDeterministicArgs(t *testing.T) {
+	defer func() { deterministic = false }()
+
+	args := []string{"192.0.2.1", "10.0.0.1", "2001:db8::1", "10.0.0.0"}
+	ips, err := readAddrArgs(args)
+	NoError(t, err)
+	Equal(t, "192.0.2.1", ips[0].String())
+
+	deterministic = true
+	ips, err = readAddrArgs(args)
+	NoError(t, err)
+	Equal(t, []netip.Addr{netip.MustParseAddr("10.0.0.0"), netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("2001:db8::1")}, ips)
+
+	ps, err := readPrefixArgs([]string{"10.0.0.0/16", "192.0.2.0/24", "10.0.0.0/8"})
+	NoError(t, err)
+	Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("10.0.0.0/16"),
+		netip.MustParsePrefix("192.0.2.0/24")}, ps)
+}
+
+func TestApplyDeterministicSeed(t *testing.T) {
+	defer func() { randReader, now, deterministic = rand.Reader, time.Now, false }()
+
+	read := func(args ...string) []byte {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("deterministic", false, "")
+		cmd.Flags().Int64("seed", 0, "")
+		NoError(t, cmd.ParseFlags(args))
+		NoError(t, applyDeterministic(cmd))
+		b := make([]byte, 8)
+		_, _ = io.ReadFull(randReader, b)
+		return b
+	}
+
+	Equal(t, read("--seed", "42"), read("--seed", "42"))
+	Equal(t, read("--seed", "42"), read("--seed", "42", "--deterministic"))
+	NotEqual(t, read("--seed", "42"), read("--seed", "43"))
+	NotEqual(t, read("--seed", "42"), read("--deterministic"))
+	Equal(t, read("--seed", "1"), read("--deterministic"))
+}
+
+func TestSortInputs(t *testing.T) {
+	ins, err := parseInputs([]string{"10.0.0.2", "10.0.0.1/24", "10.0.0.1/16", "9.1.1.1"}, "auto", false)
+	NoError(t, err)
+	sortInputs(ins)
+
+	var keys []string
+	for _, in := range ins {
+		keys = append(keys, in.key())
+	}
+	Equal(t, []string{"9.1.1.1/8", "10.0.0.1/16", "10.0.0.1/24", "10.0.0.2/8"}, keys)
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+var dhcpCmd = &cobra.Command{
+	Use:   "dhcp [flags] IP/PREFIX_LEN",
+	Short: "Generate a DHCP server configuration snippet for a subnet",
+	Long: `Generate a DHCP server configuration snippet for a subnet.
+The address pool starts at the given offset from the network address and ` +
+		`spans the given number of addresses (or up to the last usable address).`,
+	Example: `  terminus dhcp 192.168.100.0/24
+  # dhcp-range=192.168.100.10,192.168.100.254,255.255.255.0
+  # dhcp-option=option:router,192.168.100.1
+
+  terminus dhcp --format isc --pool-start-offset 100 --pool-size 50 192.168.100.0/24`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDHCPCmd,
+}
+
+func init() {
+	dhcpCmd.Flags().SortFlags = false
+	dhcpCmd.Flags().String("format", "dnsmasq", "Configuration format (dnsmasq, isc, kea)")
+	dhcpCmd.Flags().String("gateway", "", "Default gateway (defaults to the first usable IP address of the subnet)")
+	dhcpCmd.Flags().Uint32("pool-start-offset", 10, "Offset of the first pool address from the network address")
+	dhcpCmd.Flags().Uint32("pool-size", 0, "Number of addresses in the pool (0 means up to the last usable IP address)")
+	rootCmd.AddCommand(dhcpCmd)
+}
+
+func runDHCPCmd(cmd *cobra.Command, args []string) {
+	_, n, err := determineIP(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	gw, _ := cmd.Flags().GetString("gateway")
+	offset, _ := cmd.Flags().GetUint32("pool-start-offset")
+	size, _ := cmd.Flags().GetUint32("pool-size")
+	scope, err := newDHCPScope(n, gw, offset, size)
+	if err != nil {
+		fatal(err)
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	s, err := scope.render(format)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Print(s)
+}
+
+// dhcpScope holds the parameters of a DHCP address pool.
+type dhcpScope struct {
+	n         iplib.Net
+	gateway   net.IP
+	poolStart net.IP
+	poolEnd   net.IP
+}
+
+// newDHCPScope calculates the address pool of the subnet n.
+func newDHCPScope(n iplib.Net, gateway string, offset, size uint32) (*dhcpScope, error) {
+	if n.Version() != 4 {
+		return nil, errors.New("DHCP configuration requires an IPv4 network: " + n.String())
+	}
+
+	s := &dhcpScope{n: n, gateway: n.FirstAddress()}
+	if gateway != "" {
+		if s.gateway = net.ParseIP(gateway).To4(); s.gateway == nil || !n.Contains(s.gateway) {
+			return nil, fmt.Errorf("gateway is not within the subnet %s: %s", n.String(), gateway)
+		}
+	}
+
+	first := iplib.IP4ToUint32(n.FirstAddress())
+	last := iplib.IP4ToUint32(n.LastAddress())
+	start := iplib.IP4ToUint32(n.NetworkAddress()) + offset
+	if offset == 0 || start < first || start > last {
+		return nil, fmt.Errorf("pool start offset %d is out of the usable range of the subnet %s", offset, n.String())
+	}
+
+	end := last
+	if size > 0 {
+		if end = start + size - 1; end > last || end < start {
+			return nil, fmt.Errorf("pool size %d exceeds the usable range of the subnet %s", size, n.String())
+		}
+	}
+
+	s.poolStart, s.poolEnd = iplib.Uint32ToIP4(start), iplib.Uint32ToIP4(end)
+	if gw := iplib.IP4ToUint32(s.gateway); gw >= start && gw <= end {
+		return nil, fmt.Errorf("gateway %s is part of the pool %s - %s", s.gateway, s.poolStart, s.poolEnd)
+	}
+	return s, nil
+}
+
+// render formats the scope as configuration snippet for the given DHCP server.
+func (s *dhcpScope) render(format string) (string, error) {
+	b := &strings.Builder{}
+	mask := net.IP(s.n.Mask)
+
+	switch format {
+	case "dnsmasq":
+		_, _ = fmt.Fprintf(b, "dhcp-range=%v,%v,%v\n", s.poolStart, s.poolEnd, mask)
+		_, _ = fmt.Fprintf(b, "dhcp-option=option:router,%v\n", s.gateway)
+	case "isc":
+		_, _ = fmt.Fprintf(b, "subnet %v netmask %v {\n", s.n.NetworkAddress(), mask)
+		_, _ = fmt.Fprintf(b, "  range %v %v;\n", s.poolStart, s.poolEnd)
+		_, _ = fmt.Fprintf(b, "  option routers %v;\n", s.gateway)
+		_, _ = fmt.Fprintf(b, "  option subnet-mask %v;\n", mask)
+		_, _ = fmt.Fprintf(b, "  option broadcast-address %v;\n", s.n.BroadcastAddress())
+		b.WriteString("}\n")
+	case "kea":
+		type option struct {
+			Name string `json:"name"`
+			Data string `json:"data"`
+		}
+		type pool struct {
+			Pool string `json:"pool"`
+		}
+		j, err := json.MarshalIndent(struct {
+			Subnet     string   `json:"subnet"`
+			Pools      []pool   `json:"pools"`
+			OptionData []option `json:"option-data"`
+		}{
+			Subnet:     s.n.String(),
+			Pools:      []pool{{fmt.Sprintf("%v - %v", s.poolStart, s.poolEnd)}},
+			OptionData: []option{{"routers", s.gateway.String()}},
+		}, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(j)
+		b.WriteString("\n")
+	default:
+		return "", usageError("unsupported DHCP configuration format: " + format)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDHCPScope(t *testing.T) {
+	_, n, _ := determineIP("192.168.100.1/24")
+	s, err := newDHCPScope(n, "", 10, 0)
+	NoError(t, err)
+	Equal(t, "192.168.100.1", s.gateway.String())
+	Equal(t, "192.168.100.10", s.poolStart.String())
+	Equal(t, "192.168.100.254", s.poolEnd.String())
+
+	s, err = newDHCPScope(n, "192.168.100.254", 100, 50)
+	NoError(t, err)
+	Equal(t, "192.168.100.254", s.gateway.String())
+	Equal(t, "192.168.100.100", s.poolStart.String())
+	Equal(t, "192.168.100.149", s.poolEnd.String())
+}
+
+func TestDHCPScopeInvalid(t *testing.T) {
+	_, n, _ := determineIP("192.168.100.1/24")
+	_, err := newDHCPScope(n, "10.0.0.1", 10, 0)
+	EqualError(t, err, "gateway is not within the subnet 192.168.100.0/24: 10.0.0.1")
+	_, err = newDHCPScope(n, "", 255, 0)
+	EqualError(t, err, "pool start offset 255 is out of the usable range of the subnet 192.168.100.0/24")
+	_, err = newDHCPScope(n, "", 200, 100)
+	EqualError(t, err, "pool size 100 exceeds the usable range of the subnet 192.168.100.0/24")
+	_, err = newDHCPScope(n, "192.168.100.20", 10, 0)
+	EqualError(t, err, "gateway 192.168.100.20 is part of the pool 192.168.100.10 - 192.168.100.254")
+}
+
+func TestDHCPScopeRender(t *testing.T) {
+	_, n, _ := determineIP("10.0.0.0/24")
+	s, err := newDHCPScope(n, "", 100, 51)
+	NoError(t, err)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"dnsmasq", "dhcp-range=10.0.0.100,10.0.0.150,255.255.255.0\ndhcp-option=option:router,10.0.0.1\n"},
+		{"isc", `subnet 10.0.0.0 netmask 255.255.255.0 {
+  range 10.0.0.100 10.0.0.150;
+  option routers 10.0.0.1;
+  option subnet-mask 255.255.255.0;
+  option broadcast-address 10.0.0.255;
+}
+`},
+		{"kea", `{
+  "subnet": "10.0.0.0/24",
+  "pools": [
+    {
+      "pool": "10.0.0.100 - 10.0.0.150"
+    }
+  ],
+  "option-data": [
+    {
+      "name": "routers",
+      "data": "10.0.0.1"
+    }
+  ]
+}
+`},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.format, func(t *testing.T) {
+			out, err := s.render(tt.format)
+			NoError(t, err)
+			Equal(t, tt.want, out)
+		})
+	}
+
+	_, err = s.render("dhcpd")
+	EqualError(t, err, "unsupported DHCP configuration format: dhcpd")
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+)
+
+var distanceCmd = &cobra.Command{
+	Use:   "distance [flags] IP1 IP2",
+	Short: "Print the number of addresses from one IP address to another",
+	Long: `Print the number of addresses from IP1 to IP2 (negative if IP2 is before IP1).
+Both IP addresses must be of the same address family.`,
+	Example: `  terminus distance 10.0.0.1 10.0.1.0
+  # 255
+
+  terminus distance 2001:db8:: 2001:db8:1::
+  # 1208925819614629174706176`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDistanceCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(distanceCmd)
+}
+
+func runDistanceCmd(_ *cobra.Command, args []string) {
+	d, err := distance(args[0], args[1])
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Println(loc.format(d))
+}
+
+// distance returns the number of addresses from a to b.
+func distance(a, b string) (*big.Int, error) {
+	ipA, err := parseAddr(a)
+	if err != nil {
+		return nil, err
+	}
+	ipB, err := parseAddr(b)
+	if err != nil {
+		return nil, err
+	}
+	if ipA.Is4() != ipB.Is4() {
+		return nil, errors.New("cannot compare IPv4 and IPv6: " + a + " " + b)
+	}
+	return new(big.Int).Sub(addrInt(ipB), addrInt(ipA)), nil
+}
+
+// parseAddr parses an IP address, returning IPv4-mapped IPv6 addresses as IPv4 addresses.
+func parseAddr(s string) (netip.Addr, error) {
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+	}
+	return a.Unmap(), nil
+}
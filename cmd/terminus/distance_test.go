@@ -0,0 +1,51 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"10.0.0.1", "10.0.1.0", "255"},
+		{"10.0.1.0", "10.0.0.1", "-255"},
+		{"192.0.2.1", "::ffff:192.0.2.3", "2"},
+		{"0.0.0.0", "255.255.255.255", "4294967295"},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "340282366920938463463374607431768211455"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			d, err := distance(tt.a, tt.b)
+			NoError(t, err)
+			Equal(t, tt.want, d.String())
+		})
+	}
+}
+
+func TestDistanceInvalid(t *testing.T) {
+	_, err := distance("10.0.0.1", "10.0.0.0/24")
+	EqualError(t, err, "invalid IP address or CIDR: 10.0.0.0/24")
+	ErrorIs(t, err, ErrInvalidCIDR)
+
+	_, err = distance("::1", "10.0.0.1")
+	EqualError(t, err, "cannot compare IPv4 and IPv6: ::1 10.0.0.1")
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs [flags]",
+	Short: "Generate man pages or Markdown docs",
+	Long: `Generate man pages or Markdown docs for all commands from the actual flag definitions.
+One file per command is written to the directory, which is created if necessary.
+The date of the man pages is the current time or SOURCE_DATE_EPOCH in deterministic mode.`,
+	Example: `  terminus docs --format man --dir ./man/man1
+  terminus docs --deterministic --format markdown --dir ./docs`,
+	Args:        cobra.NoArgs,
+	Run:         runDocsCmd,
+	Annotations: map[string]string{sandboxWrite: "dir"},
+}
+
+func init() {
+	docsCmd.Flags().String("format", "man", "Documentation format (man, markdown)")
+	docsCmd.Flags().String("dir", "./docs", "Output directory")
+	rootCmd.AddCommand(docsCmd)
+}
+
+func runDocsCmd(cmd *cobra.Command, _ []string) {
+	format, _ := cmd.Flags().GetString("format")
+	dir, _ := cmd.Flags().GetString("dir")
+	if err := genDocs(cmd.Root(), format, dir); err != nil {
+		fatal(err)
+	}
+}
+
+// genDocs writes the docs of root and all its subcommands to dir.
+func genDocs(root *cobra.Command, format, dir string) error {
+	// the "Auto generated by spf13/cobra on DATE" footer would prevent reproducible builds
+	root.DisableAutoGenTag = true
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	switch format {
+	case "man":
+		t := now()
+		return doc.GenManTree(root, &doc.GenManHeader{
+			Title:   "TERMINUS",
+			Section: "1",
+			Date:    &t,
+			Source:  "terminus " + version,
+			Manual:  "Terminus Manual",
+		}, dir)
+	case "markdown":
+		return doc.GenMarkdownTree(root, dir)
+	default:
+		return usageError("unsupported documentation format: " + format)
+	}
+}
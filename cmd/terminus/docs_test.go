@@ -0,0 +1,51 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestGenDocs(t *testing.T) {
+	root := &cobra.Command{Use: "terminus", Run: func(*cobra.Command, []string) {}}
+	root.Flags().BoolP("broadcast", "b", false, "Show the broadcast address of the subnet")
+	root.AddCommand(&cobra.Command{Use: "split", Short: "Split a subnet", Run: func(*cobra.Command, []string) {}})
+
+	now = func() time.Time { return time.Unix(0, 0).UTC() }
+	t.Cleanup(func() { now = time.Now })
+
+	dir := t.TempDir()
+	NoError(t, genDocs(root, "man", dir))
+	b, err := os.ReadFile(filepath.Join(dir, "terminus.1"))
+	NoError(t, err)
+	Contains(t, string(b), `.TH "TERMINUS" "1" "Jan 1970" "terminus 0" "Terminus Manual"`)
+	Contains(t, string(b), `\fB-b\fP, \fB--broadcast\fP[=false]`)
+	FileExists(t, filepath.Join(dir, "terminus-split.1"))
+
+	dir = filepath.Join(t.TempDir(), "docs")
+	NoError(t, genDocs(root, "markdown", dir))
+	b, err = os.ReadFile(filepath.Join(dir, "terminus_split.md"))
+	NoError(t, err)
+	Contains(t, string(b), "## terminus split\n\nSplit a subnet\n")
+	NotContains(t, string(b), "Auto generated")
+
+	EqualError(t, genDocs(root, "html", dir), "unsupported documentation format: html")
+}
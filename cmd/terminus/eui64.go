@@ -0,0 +1,86 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/abc-inc/terminus/mac"
+	"github.com/spf13/cobra"
+)
+
+var eui64Cmd = &cobra.Command{
+	Use: `eui64 MAC [IPv6_PREFIX/64]
+  terminus eui64 IPv6`,
+	Short: "Derive an IPv6 address from a MAC address using modified EUI-64",
+	Long: `Derive an IPv6 address from a MAC address using modified EUI-64.
+The address is link-local (fe80::/64) unless another /64 prefix is given.
+If an IPv6 address is given instead, the MAC address is extracted from its interface identifier.`,
+	Example: `  terminus eui64 00:11:22:33:44:55
+  # interface-id: 0211:22ff:fe33:4455
+  # address:      fe80::211:22ff:fe33:4455
+
+  terminus eui64 00:11:22:33:44:55 2001:db8:1:2::/64
+
+  terminus eui64 fe80::211:22ff:fe33:4455
+  # 00:11:22:33:44:55`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runEUI64Cmd,
+}
+
+func init() {
+	rootCmd.AddCommand(eui64Cmd)
+}
+
+func runEUI64Cmd(_ *cobra.Command, args []string) {
+	if ip := net.ParseIP(args[0]); ip != nil && len(args) == 1 {
+		hw, err := mac.FromEUI64(ip)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(hw)
+		return
+	}
+
+	hw, err := net.ParseMAC(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	n := mac.LinkLocal
+	if len(args) > 1 {
+		if _, n, err = net.ParseCIDR(args[1]); err != nil {
+			fatal(err)
+		}
+	}
+
+	if err := printEUI64(os.Stdout, n, hw); err != nil {
+		fatal(err)
+	}
+}
+
+func printEUI64(w io.Writer, n *net.IPNet, hw net.HardwareAddr) error {
+	ip, err := mac.EUI64Addr(n, hw)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "interface-id: %s\n", formatInterfaceID(ip[8:]))
+	_, _ = fmt.Fprintf(w, "address:      %v\n", ip)
+	return nil
+}
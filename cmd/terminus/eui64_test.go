@@ -0,0 +1,31 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrintEUI64(t *testing.T) {
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	s := &strings.Builder{}
+	NoError(t, printEUI64(s, mac.LinkLocal, hw))
+	Equal(t, "interface-id: 0211:22ff:fe33:4455\naddress:      fe80::211:22ff:fe33:4455\n", s.String())
+}
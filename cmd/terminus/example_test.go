@@ -29,3 +29,69 @@ func ExampleExecute_templateAndArgs() {
 	// 127.0.0.0 - 127.0.0.255
 	// 256
 }
+
+func ExampleExecute_dedupe() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "-n", "--count", "10.0.0.1/8", "10.1.1.1/16", "10.0.0.1"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// 2	10.0.0.0
+	// 1	10.1.0.0
+}
+
+func ExampleExecute_hosts() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "hosts", "--limit", "2", "127.0.0.0/8"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// 127.0.0.1
+	// 127.0.0.2
+}
+
+func ExampleExecute_export() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "--export", "-n", "-p", "10.0.0.1/24"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// TERMINUS_NETWORK=10.0.0.0
+	// TERMINUS_PREFIX=24
+}
+
+func ExampleExecute_csv() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "-o", "csv", "-i", "-p", "10.0.0.1/24", "192.168.0.1/16"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// ip,prefix
+	// 10.0.0.1,24
+	// 192.168.0.1,16
+}
+
+func ExampleExecute_info() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "info", "-n", "-p", "10.0.0.1/24"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// 10.0.0.0
+	// 24
+}
+
+func ExampleExecute_aggregate() {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"test", "aggregate", "10.0.0.0/25", "10.0.0.128/25"}
+	rootCmd.ResetFlags()
+	Execute()
+	// Output:
+	// 10.0.0.0/24
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"log"
+	"net"
+	"os"
+
+	"github.com/abc-inc/terminus/iface"
+)
+
+// Exit statuses of terminus.
+const (
+	// exitFailure indicates a runtime error or a check, which failed.
+	exitFailure = 1
+	// exitParse indicates invalid arguments, flags, inputs or templates.
+	exitParse = 2
+	// exitNoInterface indicates that a network interface does not exist.
+	exitNoInterface = 3
+	// exitNoAddress indicates that a network interface has no IP address.
+	exitNoAddress = 4
+)
+
+var (
+	// ErrInvalidCIDR is returned if an argument is neither an IP address nor a CIDR.
+	ErrInvalidCIDR = errors.New("invalid IP address or CIDR")
+	// ErrInvalidTemplate is returned if a template cannot be parsed.
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrUnknownField is returned if --fields lists an unknown property.
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// usageError is returned if required flags or arguments are missing or cannot be combined.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// exitCode returns the exit status, which corresponds to the error.
+func exitCode(err error) int {
+	var pe *net.ParseError
+	var ue usageError
+	switch {
+	case errors.Is(err, ErrInvalidCIDR), errors.Is(err, ErrInvalidTemplate), errors.Is(err, ErrUnknownField),
+		errors.As(err, &pe), errors.As(err, &ue):
+		return exitParse
+	case errors.Is(err, iface.ErrNoSuchInterface):
+		return exitNoInterface
+	case errors.Is(err, iface.ErrNoAddress):
+		return exitNoAddress
+	}
+	return exitFailure
+}
+
+// fatal logs the error and exits with the corresponding exit status.
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("connection refused"), exitFailure},
+		{fmt.Errorf("%w: 10.0.0.1/33", ErrInvalidCIDR), exitParse},
+		{fmt.Errorf("%w: unexpected EOF", ErrInvalidTemplate), exitParse},
+		{&net.ParseError{Type: "CIDR address", Text: "10.0.0.1/33"}, exitParse},
+		{usageError("one of --rtr, --routinator or --vrps is required"), exitParse},
+		{fmt.Errorf("%w: xyz0", iface.ErrNoSuchInterface), exitNoInterface},
+		{fmt.Errorf("hosts: %w", fmt.Errorf("%w: tun0", iface.ErrNoAddress)), exitNoAddress},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func TestExitCodeUnsupportedFormat(t *testing.T) {
+	w := io.Discard
+	tests := map[string]func() error{
+		"aggregate":     func() error { return writeAggregate(w, nil, "xml") },
+		"alias":         func() error { return writeAliases(w, nil, "xml") },
+		"antispoof":     func() error { return (&spoofFilter{}).write(w, "xml") },
+		"asn":           func() error { return writeASN(w, nil, "xml") },
+		"audit":         func() error { return writeAudit(w, nil, "xml") },
+		"bench":         func() error { return writeBench(w, nil, "xml") },
+		"bogon":         func() error { return writeBogons(w, nil, "xml") },
+		"capabilities":  func() error { return writeCapabilities(w, nil, "xml") },
+		"capacity":      func() error { return writeCapacity(w, capacityResult{}, "xml") },
+		"compare":       func() error { return writeComparison(w, comparison{}, "xml") },
+		"dhcp":          func() error { _, err := (&dhcpScope{}).render("xml"); return err },
+		"funcs":         func() error { return writeFuncs(w, "xml") },
+		"geo":           func() error { return writeGeo(w, nil, "xml") },
+		"hilbert":       func() error { return writeHilbert(w, "xml", nil, 1) },
+		"info":          func() error { return checkIPv6Format("xml") },
+		"inventory":     func() error { _, err := newInventoryWriter(w, "xml", "all"); return err },
+		"ipsec":         func() error { _, err := formatIPsec("vpn", nil, nil, "xml"); return err },
+		"irr":           func() error { return writeIRR(w, nil, "xml") },
+		"mask":          func() error { return writeMask(w, maskInfo{}, "xml") },
+		"mtu":           func() error { return writeMTUs(w, nil, false, "xml") },
+		"prefix-report": func() error { return writePrefixReport(w, prefixReport{}, "xml") },
+		"public-ip":     func() error { return publicIPs{}.write(w, "xml") },
+		"reverse-zones": func() error { return writeReverseZones(w, nil, "xml") },
+		"rpki":          func() error { return writeRPKI(w, nil, "xml") },
+		"sweep":         func() error { _, err := newSweeper("xml", nil, 0); return err },
+		"to-range":      func() error { return writeRanges(w, nil, "xml") },
+		"tree":          func() error { return writeTree(w, nil, "xml") },
+		"validate":      func() error { return writeDiagnostics(w, nil, "xml") },
+	}
+
+	for name, fn := range tests {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			err := fn()
+			Error(t, err)
+			Equal(t, exitParse, exitCode(err))
+		})
+	}
+}
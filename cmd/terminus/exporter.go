@@ -0,0 +1,205 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter [flags]",
+	Short: "Export network interface metrics for Prometheus",
+	Long: `Export network interface metrics for Prometheus.
+The metrics are served at /metrics in the Prometheus text format and gathered on every scrape:
+
+  terminus_interface_up                   whether the interface is up (1) or down (0)
+  terminus_interface_mtu                  MTU of the interface
+  terminus_interface_addresses            number of IP addresses by family (ipv4, ipv6)
+  terminus_interface_prefix_length        prefix length of every IP address
+  terminus_interface_*_bytes_total        received and transmitted bytes (where available)
+  terminus_interface_*_packets_total      received and transmitted packets (where available)
+  terminus_interface_*_errors_total       receive and transmit errors (where available)
+  terminus_interface_*_dropped_total      dropped packets (where available)`,
+	Example: `  terminus exporter --listen :9123
+  curl -s localhost:9123/metrics
+  # HELP terminus_interface_up Whether the network interface is up (1) or down (0).
+  # TYPE terminus_interface_up gauge
+  # terminus_interface_up{interface="eth0"} 1
+  # ...`,
+	Args:        cobra.NoArgs,
+	Run:         runExporterCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	exporterCmd.Flags().String("listen", ":9123", "Address (HOST:PORT) to listen on")
+	rootCmd.AddCommand(exporterCmd)
+}
+
+func runExporterCmd(cmd *cobra.Command, _ []string) {
+	addr, _ := cmd.Flags().GetString("listen")
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", serveMetrics)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<html><body><a href="/metrics">Metrics</a></body></html>`)
+	})
+
+	log.Print("listening on ", l.Addr())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	fatal(srv.Serve(l))
+}
+
+func serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	ms, err := collectMetrics()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, ms)
+}
+
+// ifaceMetrics holds the metrics of a network interface.
+type ifaceMetrics struct {
+	name  string
+	up    bool
+	mtu   int
+	addrs []netip.Prefix
+	// stats is nil if the counters are not available.
+	stats *iface.Stats
+}
+
+// collectMetrics gathers the metrics of all network interfaces.
+func collectMetrics() ([]ifaceMetrics, error) {
+	is, err := iface.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	ms := make([]ifaceMetrics, len(is))
+	for n, i := range is {
+		ms[n] = ifaceMetrics{name: i.Name, up: i.Flags&net.FlagUp != 0, mtu: i.MTU}
+		if addrs, err := iface.Addrs(&is[n]); err == nil {
+			for _, a := range addrs {
+				if p, err := netip.ParsePrefix(a.String()); err == nil {
+					ms[n].addrs = append(ms[n].addrs, p)
+				}
+			}
+		}
+		if s, err := iface.GetStats(i.Name); err == nil {
+			ms[n].stats = &s
+		}
+	}
+	return ms, nil
+}
+
+// metricFamily describes a metric and how to obtain its samples from the metrics of an interface.
+type metricFamily struct {
+	name, typ, help string
+	samples         func(m ifaceMetrics, add func(labels string, v uint64))
+}
+
+var metricFamilies = []metricFamily{
+	{"terminus_interface_up", "gauge", "Whether the network interface is up (1) or down (0).",
+		func(m ifaceMetrics, add func(string, uint64)) {
+			v := uint64(0)
+			if m.up {
+				v = 1
+			}
+			add("", v)
+		}},
+	{"terminus_interface_mtu", "gauge", "MTU of the network interface in bytes.",
+		func(m ifaceMetrics, add func(string, uint64)) { add("", uint64(m.mtu)) }},
+	{"terminus_interface_addresses", "gauge", "Number of IP addresses of the network interface.",
+		func(m ifaceMetrics, add func(string, uint64)) {
+			v4 := uint64(0)
+			for _, p := range m.addrs {
+				if p.Addr().Is4() {
+					v4++
+				}
+			}
+			add(`,family="ipv4"`, v4)
+			add(`,family="ipv6"`, uint64(len(m.addrs))-v4)
+		}},
+	{"terminus_interface_prefix_length", "gauge", "Prefix length of the IP address of the network interface.",
+		func(m ifaceMetrics, add func(string, uint64)) {
+			for _, p := range m.addrs {
+				add(`,address="`+p.Addr().String()+`"`, uint64(p.Bits()))
+			}
+		}},
+	counterFamily("receive_bytes", "Number of bytes received by the network interface.",
+		func(s *iface.Stats) uint64 { return s.RxBytes }),
+	counterFamily("transmit_bytes", "Number of bytes transmitted by the network interface.",
+		func(s *iface.Stats) uint64 { return s.TxBytes }),
+	counterFamily("receive_packets", "Number of packets received by the network interface.",
+		func(s *iface.Stats) uint64 { return s.RxPackets }),
+	counterFamily("transmit_packets", "Number of packets transmitted by the network interface.",
+		func(s *iface.Stats) uint64 { return s.TxPackets }),
+	counterFamily("receive_errors", "Number of receive errors of the network interface.",
+		func(s *iface.Stats) uint64 { return s.RxErrors }),
+	counterFamily("transmit_errors", "Number of transmit errors of the network interface.",
+		func(s *iface.Stats) uint64 { return s.TxErrors }),
+	counterFamily("receive_dropped", "Number of received packets dropped by the network interface.",
+		func(s *iface.Stats) uint64 { return s.RxDropped }),
+	counterFamily("transmit_dropped", "Number of transmitted packets dropped by the network interface.",
+		func(s *iface.Stats) uint64 { return s.TxDropped }),
+}
+
+// counterFamily returns a counter, which is present only if the interface statistics are available.
+func counterFamily(name, help string, value func(s *iface.Stats) uint64) metricFamily {
+	return metricFamily{"terminus_interface_" + name + "_total", "counter", help,
+		func(m ifaceMetrics, add func(string, uint64)) {
+			if m.stats != nil {
+				add("", value(m.stats))
+			}
+		}}
+}
+
+// writeMetrics renders the metrics in the Prometheus text format.
+func writeMetrics(w io.Writer, ms []ifaceMetrics) {
+	esc := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for _, f := range metricFamilies {
+		s := &strings.Builder{}
+		for _, m := range ms {
+			f.samples(m, func(labels string, v uint64) {
+				_, _ = fmt.Fprintf(s, "%s{interface=\"%s\"%s} %d\n", f.name, esc.Replace(m.name), labels, v)
+			})
+		}
+		if s.Len() > 0 {
+			_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s", f.name, f.help, f.name, f.typ, s)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	ms := []ifaceMetrics{
+		{name: "eth0", up: true, mtu: 1500, stats: &iface.Stats{RxBytes: 42, TxBytes: 23},
+			addrs: []netip.Prefix{netip.MustParsePrefix("10.0.0.42/24"), netip.MustParsePrefix("fe80::1/64")}},
+		{name: `we"ird`, mtu: 1280},
+	}
+
+	s := &strings.Builder{}
+	writeMetrics(s, ms)
+	Equal(t, `# HELP terminus_interface_up Whether the network interface is up (1) or down (0).
+# TYPE terminus_interface_up gauge
+terminus_interface_up{interface="eth0"} 1
+terminus_interface_up{interface="we\"ird"} 0
+# HELP terminus_interface_mtu MTU of the network interface in bytes.
+# TYPE terminus_interface_mtu gauge
+terminus_interface_mtu{interface="eth0"} 1500
+terminus_interface_mtu{interface="we\"ird"} 1280
+# HELP terminus_interface_addresses Number of IP addresses of the network interface.
+# TYPE terminus_interface_addresses gauge
+terminus_interface_addresses{interface="eth0",family="ipv4"} 1
+terminus_interface_addresses{interface="eth0",family="ipv6"} 1
+terminus_interface_addresses{interface="we\"ird",family="ipv4"} 0
+terminus_interface_addresses{interface="we\"ird",family="ipv6"} 0
+# HELP terminus_interface_prefix_length Prefix length of the IP address of the network interface.
+# TYPE terminus_interface_prefix_length gauge
+terminus_interface_prefix_length{interface="eth0",address="10.0.0.42"} 24
+terminus_interface_prefix_length{interface="eth0",address="fe80::1"} 64
+# HELP terminus_interface_receive_bytes_total Number of bytes received by the network interface.
+# TYPE terminus_interface_receive_bytes_total counter
+terminus_interface_receive_bytes_total{interface="eth0"} 42
+# HELP terminus_interface_transmit_bytes_total Number of bytes transmitted by the network interface.
+# TYPE terminus_interface_transmit_bytes_total counter
+terminus_interface_transmit_bytes_total{interface="eth0"} 23
+`, strings.Join(strings.SplitAfter(s.String(), "\n")[:24], ""))
+}
+
+func TestServeMetrics(t *testing.T) {
+	w := httptest.NewRecorder()
+	serveMetrics(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	Equal(t, http.StatusOK, w.Code)
+	Contains(t, w.Body.String(), "# TYPE terminus_interface_up gauge\n")
+	Contains(t, w.Body.String(), `address="127.0.0.1"} 8`)
+}
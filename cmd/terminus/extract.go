@@ -0,0 +1,129 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var extractCmd = &cobra.Command{
+	Use:   "extract [flags] [FILE...]",
+	Short: "Print the IP addresses and CIDRs, which are mentioned in arbitrary text",
+	Long: `Print the IPv4 and IPv6 addresses and networks in CIDR notation, which are mentioned in arbitrary text
+(e.g., log files or device configs), one per line. Thus, the output can be fed into other commands.
+Ports (192.0.2.1:443, [2001:db8::1]:443) are stripped, and IPv4-mapped IPv6 addresses are unmapped.
+If no file is given (or "-"), the text is read from stdin.
+The exit status is 0 if an IP address is found, 1 otherwise.`,
+	Example: `  terminus extract --unique --with-count /var/log/auth.log
+  #       3 10.1.2.3
+  #       1 2001:db8::7
+
+  terminus extract --cidr-only router.cfg | terminus aggregate`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runExtractCmd,
+}
+
+func init() {
+	extractCmd.Flags().BoolP("unique", "u", false, "Print every IP address only once (in order of appearance)")
+	extractCmd.Flags().BoolP("with-count", "c", false, "Prefix unique IP addresses by the number of occurrences")
+	extractCmd.Flags().Bool("cidr-only", false, "Print networks in CIDR notation only (no bare IP addresses)")
+	rootCmd.AddCommand(extractCmd)
+}
+
+func runExtractCmd(cmd *cobra.Command, args []string) {
+	e := extractor{}
+	e.unique, _ = cmd.Flags().GetBool("unique")
+	e.withCount, _ = cmd.Flags().GetBool("with-count")
+	e.cidrOnly, _ = cmd.Flags().GetBool("cidr-only")
+
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	out := newOutput(cmd)
+	for _, name := range args {
+		if err := e.extractFile(out, name); err != nil {
+			_ = out.Flush()
+			fatal(err)
+		}
+	}
+	e.writeCounts(out)
+	_ = out.Flush()
+	if e.found == 0 {
+		os.Exit(1)
+	}
+}
+
+// extractor prints the IP addresses and CIDRs, which are mentioned in text.
+type extractor struct {
+	unique, withCount, cidrOnly bool
+	// found is the number of matches (including duplicates).
+	found int
+	// counts holds the number of occurrences (unique, with-count only), and order the matches in order of appearance.
+	counts map[string]int
+	order  []string
+}
+
+// extractFile extracts the IP addresses and CIDRs from the file (or stdin).
+func (e *extractor) extractFile(w io.Writer, name string) error {
+	r, err := openInput(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	return e.extract(w, r)
+}
+
+// extract writes the IP addresses and CIDRs, which are mentioned in the text r.
+// If counts are requested, they are written by writeCounts after all inputs have been processed.
+func (e *extractor) extract(w io.Writer, r io.Reader) error {
+	if e.counts == nil {
+		e.counts = map[string]int{}
+	}
+	return scanLines(r, func(line string) error {
+		for _, m := range findAddrs(line) {
+			if e.cidrOnly && !m.cidr {
+				continue
+			}
+			e.found++
+			s := m.String()
+			if e.unique || e.withCount {
+				if e.counts[s]++; e.counts[s] == 1 && e.withCount {
+					e.order = append(e.order, s)
+				}
+				if e.counts[s] > 1 || e.withCount {
+					continue
+				}
+			}
+			if _, err := io.WriteString(w, s+"\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeCounts writes the unique matches along with their number of occurrences (like "uniq -c") if requested.
+func (e *extractor) writeCounts(w io.Writer) {
+	if !e.withCount {
+		return
+	}
+	for _, s := range e.order {
+		_, _ = fmt.Fprintf(w, "%7d %s\n", e.counts[s], s)
+	}
+}
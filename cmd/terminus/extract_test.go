@@ -0,0 +1,52 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	text := `sshd: Failed password for root from 10.1.2.3 port 22 ssh2
+nginx: 2001:db8::7 - - "GET / HTTP/1.1" via [::ffff:10.1.2.3]:443
+ip route 10.0.0.0/8 192.0.2.1
+`
+	tests := []struct {
+		name string
+		e    extractor
+		want string
+	}{
+		{"all", extractor{}, "10.1.2.3\n2001:db8::7\n10.1.2.3\n10.0.0.0/8\n192.0.2.1\n"},
+		{"unique", extractor{unique: true}, "10.1.2.3\n2001:db8::7\n10.0.0.0/8\n192.0.2.1\n"},
+		{"with-count", extractor{withCount: true},
+			"      2 10.1.2.3\n      1 2001:db8::7\n      1 10.0.0.0/8\n      1 192.0.2.1\n"},
+		{"cidr-only", extractor{cidrOnly: true}, "10.0.0.0/8\n"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			s := &strings.Builder{}
+			NoError(t, tt.e.extract(s, strings.NewReader(text)))
+			NoError(t, tt.e.extract(s, strings.NewReader("no address\n")))
+			tt.e.writeCounts(s)
+			Equal(t, tt.want, s.String())
+			Equal(t, strings.Count(tt.want, "\n") > 0, tt.e.found > 0)
+		})
+	}
+}
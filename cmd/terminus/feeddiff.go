@@ -0,0 +1,77 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var feedDiffCmd = &cobra.Command{
+	Use:   "feed-diff [flags] OLD NEW",
+	Short: "Compare the address coverage of two CIDR feeds",
+	Long: `Compare the address coverage of two CIDR feeds (files, stdin or URLs).
+Rather than comparing lines, the networks, which were added to or removed from the feed, are reported.
+The exit status is 0 if the coverage is the same, 1 if it differs.`,
+	Example: `  terminus feed-diff drop-old.txt https://www.spamhaus.org/drop/drop.txt
+  # + 198.51.100.0/24
+  # - 192.0.2.128/25`,
+	Args: cobra.ExactArgs(2),
+	Run:  runFeedDiffCmd,
+}
+
+func init() {
+	feedDiffCmd.Flags().Bool("summary", false, "Print the number of added and removed addresses")
+	rootCmd.AddCommand(feedDiffCmd)
+}
+
+func runFeedDiffCmd(cmd *cobra.Command, args []string) {
+	feeds := make([]ipset.Set, len(args))
+	for i, name := range args {
+		b, err := readInputFile(name)
+		if err != nil {
+			fatal(err)
+		}
+		if feeds[i], err = ipset.Parse(parseTargets(b)); err != nil {
+			fatal(err)
+		}
+	}
+
+	summary, _ := cmd.Flags().GetBool("summary")
+	if feedDiff(os.Stdout, feeds[0], feeds[1], summary) {
+		os.Exit(1)
+	}
+}
+
+// feedDiff prints the networks, which were added to or removed from the old feed, and reports whether there are any.
+func feedDiff(w io.Writer, old, cur ipset.Set, summary bool) bool {
+	added, removed := cur.Difference(old), old.Difference(cur)
+	for _, p := range added.Prefixes() {
+		_, _ = fmt.Fprintln(w, "+", p)
+	}
+	for _, p := range removed.Prefixes() {
+		_, _ = fmt.Fprintln(w, "-", p)
+	}
+
+	if summary {
+		_, _ = fmt.Fprintf(w, "%s addresses added, %s addresses removed\n",
+			loc.format(added.Size()), loc.format(removed.Size()))
+	}
+	return len(added.Ranges())+len(removed.Ranges()) > 0
+}
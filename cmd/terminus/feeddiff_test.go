@@ -0,0 +1,40 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFeedDiff(t *testing.T) {
+	old, _ := ipset.Parse(parseTargets([]byte("192.0.2.0/24 ; SBL1\n203.0.113.0/25\n")))
+	cur, _ := ipset.Parse(parseTargets([]byte("192.0.2.0/25 ; SBL1\n203.0.113.0/24\n198.51.100.0/24\n")))
+
+	s := &strings.Builder{}
+	True(t, feedDiff(s, old, cur, true))
+	Equal(t, `+ 198.51.100.0/24
++ 203.0.113.128/25
+- 192.0.2.128/25
+384 addresses added, 128 addresses removed
+`, s.String())
+
+	s.Reset()
+	False(t, feedDiff(s, old, old, false))
+	Empty(t, s.String())
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var filterCmd = &cobra.Command{
+	Use:   "filter [flags] [FILE...]",
+	Short: "Print the lines, which mention IP addresses inside (or outside) of the given subnets",
+	Long: `Print the lines, which mention IP addresses inside (or outside) of the given subnets, like grep.
+All IPv4 and IPv6 addresses of a line are extracted (CIDRs count as their network address).
+A line is printed if any of them is part of a subnet given by --in (if any) and not part of a subnet given by
+--not-in. Subnets can be given in CIDR notation or as IP ranges (FROM-TO).
+If no file is given (or "-"), the lines are read from stdin.
+The exit status is 0 if a line is printed, 1 otherwise.`,
+	Example: `  terminus filter --in 10.0.0.0/8 --not-in 10.5.0.0/16 /var/log/auth.log
+  # Failed password for root from 10.1.2.3 port 22 ssh2`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runFilterCmd,
+}
+
+func init() {
+	filterCmd.Flags().StringArray("in", nil, "Print lines with IP addresses inside the subnet")
+	filterCmd.Flags().StringArray("not-in", nil, "Print lines with IP addresses outside the subnet")
+	rootCmd.AddCommand(filterCmd)
+}
+
+func runFilterCmd(cmd *cobra.Command, args []string) {
+	in, _ := cmd.Flags().GetStringArray("in")
+	notIn, _ := cmd.Flags().GetStringArray("not-in")
+	f, err := newAddrFilter(in, notIn)
+	if err != nil {
+		fatal(err)
+	}
+
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	out := newOutput(cmd)
+	matched := false
+	for _, name := range args {
+		m, err := filterFile(out, name, f)
+		if err != nil {
+			_ = out.Flush()
+			fatal(err)
+		}
+		matched = matched || m
+	}
+	_ = out.Flush()
+	if !matched {
+		os.Exit(1)
+	}
+}
+
+// addrFilter selects IP addresses inside of the subnets in and outside of the subnets notIn.
+type addrFilter struct {
+	in, notIn ipset.Set
+	// all is true if no subnets are given by --in i.e., every IP address is inside.
+	all bool
+}
+
+func newAddrFilter(in, notIn []string) (f addrFilter, err error) {
+	if f.in, err = ipset.Parse(in); err != nil {
+		return f, err
+	}
+	if f.notIn, err = ipset.Parse(notIn); err != nil {
+		return f, err
+	}
+	f.all = len(in) == 0
+	return f, nil
+}
+
+// match reports whether the line mentions an IP address, which is selected by the filter.
+func (f addrFilter) match(line string) bool {
+	for _, p := range findAddrs(line) {
+		a := p.Masked().Addr()
+		if (f.all || f.in.Contains(a)) && !f.notIn.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFile writes the lines of the file (or stdin), which match the filter, and reports whether there are any.
+func filterFile(w io.Writer, name string, f addrFilter) (bool, error) {
+	r, err := openInput(name)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = r.Close() }()
+	return filterLines(w, r, f)
+}
+
+// filterLines writes the lines, which match the filter, and reports whether there are any.
+func filterLines(w io.Writer, r io.Reader, f addrFilter) (matched bool, err error) {
+	err = scanLines(r, func(line string) error {
+		if !f.match(line) {
+			return nil
+		}
+		matched = true
+		_, err := io.WriteString(w, line+"\n")
+		return err
+	})
+	return matched, err
+}
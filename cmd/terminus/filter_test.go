@@ -0,0 +1,71 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFilterLines(t *testing.T) {
+	lines := `Failed password for root from 10.1.2.3 port 22 ssh2
+Accepted publickey for ci from 10.5.0.7 port 40022 ssh2
+src=192.0.2.1:443 dst=10.200.0.1:80
+Server listening on :: port 22
+no address at all
+`
+	tests := []struct {
+		name      string
+		in, notIn []string
+		want      string
+	}{
+		{"in", []string{"10.0.0.0/8"}, nil,
+			"Failed password for root from 10.1.2.3 port 22 ssh2\n" +
+				"Accepted publickey for ci from 10.5.0.7 port 40022 ssh2\n" +
+				"src=192.0.2.1:443 dst=10.200.0.1:80\n"},
+		{"in and not-in", []string{"10.0.0.0/8"}, []string{"10.5.0.0/16"},
+			"Failed password for root from 10.1.2.3 port 22 ssh2\n" +
+				"src=192.0.2.1:443 dst=10.200.0.1:80\n"},
+		{"not-in", nil, []string{"10.0.0.0/8"},
+			"src=192.0.2.1:443 dst=10.200.0.1:80\n" +
+				"Server listening on :: port 22\n"},
+		{"range", []string{"10.1.2.0-10.5.0.7"}, nil,
+			"Failed password for root from 10.1.2.3 port 22 ssh2\n" +
+				"Accepted publickey for ci from 10.5.0.7 port 40022 ssh2\n"},
+		{"none", []string{"2001:db8::/32"}, nil, ""},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newAddrFilter(tt.in, tt.notIn)
+			NoError(t, err)
+			s := &strings.Builder{}
+			matched, err := filterLines(s, strings.NewReader(lines), f)
+			NoError(t, err)
+			Equal(t, tt.want != "", matched)
+			Equal(t, tt.want, s.String())
+		})
+	}
+}
+
+func TestNewAddrFilterInvalid(t *testing.T) {
+	_, err := newAddrFilter([]string{"10.0.0.0/33"}, nil)
+	Error(t, err)
+	_, err = newAddrFilter(nil, []string{"x"})
+	Error(t, err)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipv6"
+)
+
+// ipv6Formats maps the values of --ipv6-format to the functions, which format IPv6 addresses.
+var ipv6Formats = map[string]func(net.IP) (string, error){
+	"arpa":       ipv6.Arpa,
+	"canonical":  ipv6.Canonical,
+	"compressed": ipv6.Compress,
+	"expanded":   ipv6.Expand,
+}
+
+// checkIPv6Format returns an error if format is neither empty nor a value of --ipv6-format.
+func checkIPv6Format(format string) error {
+	if _, ok := ipv6Formats[format]; !ok && format != "" {
+		return usageError("unsupported IPv6 format: " + format)
+	}
+	return nil
+}
+
+// ipv6AddrKeys are the properties, which are IPv6 addresses (as opposed to masks like netmask and wildcard, and the
+// broadcast, which does not exist in IPv6).
+var ipv6AddrKeys = []string{iface.First, iface.IP, iface.Last, iface.Network}
+
+// formatIPv6Addrs replaces the IPv6 addresses in data by their text representation in the given format.
+// IPv4 addresses and masks are left as they are.
+func formatIPv6Addrs(data map[string]interface{}, format string) {
+	fn := ipv6Formats[format]
+	for _, k := range ipv6AddrKeys {
+		if ip, ok := data[k].(net.IP); ok {
+			if s, err := fn(ip); err == nil {
+				data[k] = s
+			}
+		}
+	}
+}
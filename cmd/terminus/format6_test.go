@@ -0,0 +1,57 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFormatIPv6Addrs(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("2001:db8::1/64")
+	data := iface.GetParamsOf("", ip, n.Mask, iface.IP, iface.Network, iface.Last, iface.NetMask, iface.Broadcast,
+		iface.Prefix)
+	formatIPv6Addrs(data, "expanded")
+	Equal(t, "2001:0db8:0000:0000:0000:0000:0000:0001", data[iface.IP])
+	Equal(t, "2001:0db8:0000:0000:0000:0000:0000:0000", data[iface.Network])
+	Equal(t, "2001:0db8:0000:0000:ffff:ffff:ffff:ffff", data[iface.Last])
+	Equal(t, net.IP(net.CIDRMask(64, 128)), data[iface.NetMask])
+	IsType(t, net.IP{}, data[iface.Broadcast])
+	Equal(t, 64, data[iface.Prefix])
+
+	ip, n, _ = net.ParseCIDR("192.0.2.1/24")
+	data = iface.GetParamsOf("", ip, n.Mask, iface.IP)
+	formatIPv6Addrs(data, "arpa")
+	Equal(t, ip, data[iface.IP])
+}
+
+func TestCheckIPv6Format(t *testing.T) {
+	for f := range ipv6Formats {
+		NoError(t, checkIPv6Format(f))
+	}
+	NoError(t, checkIPv6Format(""))
+	EqualError(t, checkIPv6Format("short"), "unsupported IPv6 format: short")
+}
+
+func TestPrintTemplateIPv6(t *testing.T) {
+	s := &strings.Builder{}
+	data := map[string]interface{}{iface.IP: net.ParseIP("2001:db8:0:1:1:1:1:1")}
+	NoError(t, printTemplate("{{.ip | canonical6}} {{.ip | compress6}} {{.ip | expand6}}", s, data))
+	Equal(t, "2001:db8:0:1:1:1:1:1 2001:db8::1:1:1:1:1 2001:0db8:0000:0001:0001:0001:0001:0001\n", s.String())
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipv6"
+	"github.com/spf13/cobra"
+)
+
+// templateFunc is a function, which is available in templates.
+type templateFunc struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Example     string      `json:"example"`
+	fn          interface{} // implementation
+}
+
+// templateFuncs lists the functions, which are available in templates.
+var templateFuncs = []templateFunc{
+	{"arpa6", "converts an IPv6 address to its reverse DNS name in nibble format (ip6.arpa)",
+		`{{.ip | arpa6}}`, ipv6.Arpa},
+	{"canonical6", "converts an IPv6 address to its canonical representation (RFC 5952)",
+		`{{.ip | canonical6}}`, ipv6.Canonical},
+	{"compress6", "converts an IPv6 address to its shortest representation (compressing single zero groups as well)",
+		`{{.ip | compress6}}`, ipv6.Compress},
+	{"expand6", "converts an IPv6 address to its full representation with 8 groups of 4 hex digits",
+		`{{.ip | expand6}}`, ipv6.Expand},
+	{"geo", "looks up the geolocation (country, city) of an IP address in the MMDB file given by --geo-db",
+		`{{(.ip | geo).Country}}`, geoLookup},
+	{"multicastMAC", "calculates the Ethernet multicast MAC address of an IPv4 or IPv6 multicast group",
+		`{{.ip | multicastMAC}}`, multicastMAC},
+	{"solicitedNode", "calculates the solicited-node multicast address of an IPv6 unicast address",
+		`{{.ip | solicitedNode}}`, solicitedNode},
+	{"toBinary", "converts an IP address (or netmask) to binary dot-decimal notation",
+		`{{.ip | toBinary}}`, toBinary},
+	{"toHex", "converts a netmask (or IP address) to hexadecimal notation",
+		`{{.netmask | toHex}}`, toHex},
+	{"toJson", "converts the input to a valid JSON object/array/string (if possible)",
+		`{{. | toJson}}`, toJSON},
+}
+
+// templateProperties lists the properties of the data passed to templates.
+var templateProperties = append(append([]iface.Property{}, iface.Properties...),
+	iface.Property{Name: "count", Type: "int", Description: "number of occurrences of the input (with --count)", Example: "42"},
+	iface.Property{Name: "port", Type: "int", Description: "port of the input (HOST:PORT or URL)", Example: "443"},
+	iface.Property{Name: "interfaces", Type: "map", Description: "properties of all network interfaces by name",
+		Example: `{{.interfaces.eth0.ip}}`},
+	iface.Property{Name: "env", Type: "map", Description: "environment variables by name", Example: `{{.env.USER}}`},
+	iface.Property{Name: "hostname", Type: "string", Description: "host name of the machine (PTR name of the IP address with --resolve)",
+		Example: "build01"},
+	iface.Property{Name: "now", Type: "time", Description: "current time in RFC 3339 format (or any layout)",
+		Example: `{{.now.Format "15:04"}}`},
+)
+
+// isContextProperty reports whether the property is available in templates only, as opposed to a property of
+// an IP address or subnet.
+func isContextProperty(name string) bool {
+	return name == "interfaces" || name == "env" || name == "hostname" || name == "now"
+}
+
+var funcsCmd = &cobra.Command{
+	Use:   "funcs [flags]",
+	Short: "List the functions and properties available in templates",
+	Example: `  terminus funcs
+  terminus funcs -o json | jq -r '.functions[].name'`,
+	Args: cobra.NoArgs,
+	Run:  runFuncsCmd,
+}
+
+func init() {
+	funcsCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(funcsCmd)
+}
+
+func runFuncsCmd(cmd *cobra.Command, _ []string) {
+	output, _ := cmd.Flags().GetString("output")
+	if err := writeFuncs(os.Stdout, output); err != nil {
+		fatal(err)
+	}
+}
+
+// funcMap returns the functions, which are available in templates.
+func funcMap() template.FuncMap {
+	m := template.FuncMap{}
+	for _, f := range templateFuncs {
+		m[f.Name] = f.fn
+	}
+	return m
+}
+
+// propertyExpr returns the template expression of a property.
+// Names, which are not valid identifiers (e.g., classful-network), have to be accessed by index.
+func propertyExpr(name string) string {
+	if strings.Contains(name, "-") {
+		return fmt.Sprintf("{{index . %q}}", name)
+	}
+	return "{{." + name + "}}"
+}
+
+// writeFuncs writes the documentation of all template functions and properties.
+func writeFuncs(w io.Writer, output string) error {
+	switch output {
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "FUNCTION\tEXAMPLE\tDESCRIPTION")
+		for _, f := range templateFuncs {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Name, f.Example, f.Description)
+		}
+		_, _ = fmt.Fprintln(tw, "\nPROPERTY\tEXAMPLE\tTYPE\tDESCRIPTION")
+		for _, p := range templateProperties {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", propertyExpr(p.Name), p.Example, p.Type, p.Description)
+		}
+		return tw.Flush()
+	case "json":
+		return json.NewEncoder(w).Encode(struct {
+			Functions  []templateFunc   `json:"functions"`
+			Properties []iface.Property `json:"properties"`
+		}{templateFuncs, templateProperties})
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFuncMap(t *testing.T) {
+	m := funcMap()
+	Len(t, m, len(templateFuncs))
+	for _, f := range templateFuncs {
+		NotNil(t, m[f.Name], f.Name)
+		Contains(t, f.Example, f.Name)
+	}
+}
+
+func TestWriteFuncs(t *testing.T) {
+	s := &strings.Builder{}
+	NoError(t, writeFuncs(s, "text"))
+	Contains(t, s.String(), "toHex          {{.netmask | toHex}}")
+	Contains(t, s.String(), "{{.interfaces}}                     {{.interfaces.eth0.ip}}  map")
+	Contains(t, s.String(), `{{index . "classful-network"}}      10.0.0.0/8`)
+
+	s.Reset()
+	NoError(t, writeFuncs(s, "json"))
+	var doc struct {
+		Functions  []map[string]string `json:"functions"`
+		Properties []map[string]string `json:"properties"`
+	}
+	NoError(t, json.Unmarshal([]byte(s.String()), &doc))
+	Len(t, doc.Functions, len(templateFuncs))
+	Len(t, doc.Properties, len(templateProperties))
+	Equal(t, "toJson", doc.Functions[len(doc.Functions)-1]["name"])
+
+	EqualError(t, writeFuncs(s, "yaml"), "unsupported output format: yaml")
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+
+	"github.com/abc-inc/terminus/geo"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/language"
+)
+
+var geoCmd = &cobra.Command{
+	Use:   "geo [flags] IP...",
+	Short: "Look up the geolocation of IP addresses",
+	Long: `Look up the geolocation (country, city and coordinates) of IP addresses in a MaxMind DB file
+like GeoLite2-City.mmdb or GeoLite2-Country.mmdb. Names are localized according to --locale (if available).
+The exit status is 1 if any address is not contained in the database.`,
+	Example: `  terminus geo --db GeoLite2-City.mmdb 192.0.2.1
+  # ip:          192.0.2.1
+  # network:     192.0.2.0/24
+  # country:     AT (Austria)
+  # city:        Vienna
+  # coordinates: 48.2, 16.3667
+
+  terminus -t '{{.ip}} {{.ip | geo}}' --geo-db GeoLite2-City.mmdb 192.0.2.1
+  # 192.0.2.1 AT, Vienna`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runGeoCmd,
+}
+
+func init() {
+	geoCmd.Flags().String("db", "", "MaxMind DB file (.mmdb)")
+	_ = geoCmd.MarkFlagRequired("db")
+	geoCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(geoCmd)
+}
+
+func runGeoCmd(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("db")
+	output, _ := cmd.Flags().GetString("output")
+
+	rs := make([]geoResult, len(args))
+	for i, arg := range args {
+		ip, err := netip.ParseAddr(arg)
+		if err != nil {
+			fatal(&net.ParseError{Type: "IP address", Text: arg})
+		}
+		rs[i].IP = ip.Unmap()
+	}
+
+	db, err := geo.Open(name)
+	if err != nil {
+		fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	failed := false
+	for i := range rs {
+		ip := rs[i].IP
+		var ok bool
+		if rs[i].Location, ok, err = db.Lookup(ip, geoLang()); err != nil {
+			fatal(err)
+		} else if !ok {
+			log.Println("no location found for " + ip.String())
+			failed = true
+		}
+	}
+
+	if err := writeGeo(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// geoResult is the location of an IP address.
+type geoResult struct {
+	IP netip.Addr `json:"ip"`
+	geo.Location
+}
+
+func writeGeo(w io.Writer, rs []geoResult, output string) error {
+	switch output {
+	case "text":
+		for i, r := range rs {
+			if i > 0 {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = fmt.Fprintf(w, "ip:          %s\n", r.IP)
+			if r.Network.IsValid() {
+				_, _ = fmt.Fprintf(w, "network:     %s\n", r.Network)
+			}
+			if r.Country != "" {
+				_, _ = fmt.Fprintf(w, "country:     %s (%s)\n", r.Country, r.CountryName)
+			}
+			if r.City != "" {
+				_, _ = fmt.Fprintf(w, "city:        %s\n", r.City)
+			}
+			if r.Latitude != nil && r.Longitude != nil {
+				_, _ = fmt.Fprintf(w, "coordinates: %s, %s\n",
+					strconv.FormatFloat(*r.Latitude, 'f', -1, 64), strconv.FormatFloat(*r.Longitude, 'f', -1, 64))
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
+
+// geoDBName is the MMDB file used by the geo template function (--geo-db).
+var geoDBName string
+
+// geoDB is opened on first use of the geo template function.
+var geoDB *geo.DB
+
+// geoLookup returns the location of ip (an empty Location if the database does not contain it).
+func geoLookup(ip interface{}) (geo.Location, error) {
+	if geoDB == nil {
+		if geoDBName == "" {
+			return geo.Location{}, errors.New("the geo function requires --geo-db")
+		}
+		db, err := geo.Open(geoDBName)
+		if err != nil {
+			return geo.Location{}, err
+		}
+		geoDB = db
+	}
+
+	a, err := netip.ParseAddr(fmt.Sprint(ip))
+	if err != nil {
+		return geo.Location{}, err
+	}
+	l, _, err := geoDB.Lookup(a, geoLang())
+	return l, err
+}
+
+// geoLang returns the language of localized names according to --locale.
+func geoLang() string {
+	if loc.tag == language.Und {
+		return "en"
+	}
+	b, _ := loc.tag.Base()
+	return b.String()
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/geo"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteGeo(t *testing.T) {
+	lat, lon := 48.2, 16.3667
+	rs := []geoResult{
+		{netip.MustParseAddr("192.0.2.1"), geo.Location{
+			Network: netip.MustParsePrefix("192.0.2.0/24"), Country: "AT", CountryName: "Austria", City: "Vienna",
+			Latitude: &lat, Longitude: &lon,
+		}},
+		{IP: netip.MustParseAddr("2001:db8::1")},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeGeo(s, rs, "text"))
+	Equal(t, `ip:          192.0.2.1
+network:     192.0.2.0/24
+country:     AT (Austria)
+city:        Vienna
+coordinates: 48.2, 16.3667
+
+ip:          2001:db8::1
+`, s.String())
+
+	s.Reset()
+	NoError(t, writeGeo(s, rs[1:], "json"))
+	Equal(t, `[{"ip":"2001:db8::1","network":""}]`+"\n", s.String())
+
+	EqualError(t, writeGeo(s, rs, "xml"), "unsupported output format: xml")
+}
+
+func TestGeoLookupNoDB(t *testing.T) {
+	s := &strings.Builder{}
+	err := printTemplate("{{.ip | geo}}", s, map[string]interface{}{"ip": "192.0.2.1"})
+	ErrorContains(t, err, "the geo function requires --geo-db")
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/abc-inc/terminus/api"
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipset"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer implements the gRPC service defined in api/terminus.proto.
+type grpcServer struct {
+	api.UnimplementedTerminusServer
+}
+
+// newGRPCServer returns a gRPC server, which provides the Terminus service.
+func newGRPCServer() *grpc.Server {
+	s := grpc.NewServer()
+	api.RegisterTerminusServer(s, grpcServer{})
+	return s
+}
+
+func (grpcServer) GetSubnet(_ context.Context, req *api.GetSubnetRequest) (*api.Subnet, error) {
+	ip, n, err := parseSubnet(req.GetCidr())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return toSubnet(iface.GetParamsOf(req.GetCidr(), ip, n.Mask, subnetKeys...)), nil
+}
+
+func (grpcServer) Split(_ context.Context, req *api.SplitRequest) (*api.SplitResponse, error) {
+	_, n, err := parseSubnet(req.GetCidr())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sns, err := subnets(n, int(req.GetNewPrefix()), maxSubnets)
+	if errors.Is(err, errTooManySubnets) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &api.SplitResponse{Subnets: make([]string, len(sns))}
+	for i, sn := range sns {
+		resp.Subnets[i] = sn.String()
+	}
+	return resp, nil
+}
+
+func (grpcServer) Aggregate(_ context.Context, req *api.AggregateRequest) (*api.AggregateResponse, error) {
+	s, err := ipset.Parse(req.GetCidrs())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &api.AggregateResponse{}
+	for _, p := range s.Prefixes() {
+		resp.Prefixes = append(resp.Prefixes, p.String())
+	}
+	return resp, nil
+}
+
+func (grpcServer) ListInterfaces(context.Context, *api.ListInterfacesRequest) (*api.ListInterfacesResponse, error) {
+	is, err := iface.Interfaces()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	resp := &api.ListInterfacesResponse{}
+	for _, i := range is {
+		if ip, n, err := iface.GetAddr(i.Name); err == nil {
+			resp.Interfaces = append(resp.Interfaces, toSubnet(iface.GetParams(i.Name, ip, n.Mask)))
+		}
+	}
+	return resp, nil
+}
+
+// toSubnet converts the properties returned by iface.GetParams (absent properties are empty).
+func toSubnet(data map[string]interface{}) *api.Subnet {
+	str := func(k string) string {
+		if v, ok := data[k]; ok {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	return &api.Subnet{
+		Name:      str(iface.Name),
+		Ip:        str(iface.IP),
+		Network:   str(iface.Network),
+		Broadcast: str(iface.Broadcast),
+		Netmask:   str(iface.NetMask),
+		Wildcard:  str(iface.Wildcard),
+		First:     str(iface.First),
+		Last:      str(iface.Last),
+		Prefix:    int32(data[iface.Prefix].(int)),
+		Version:   int32(data[iface.Version].(int)),
+		Size:      str(iface.Size),
+		Usable:    str(iface.UsableSize),
+		Gateway:   str(iface.Gateway),
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/api"
+	. "github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newGRPCClient(t *testing.T) api.TerminusClient {
+	l := bufconn.Listen(1 << 20)
+	s := newGRPCServer()
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }))
+	NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return api.NewTerminusClient(conn)
+}
+
+func TestGRPC(t *testing.T) {
+	c := newGRPCClient(t)
+	ctx := context.Background()
+
+	sn, err := c.GetSubnet(ctx, &api.GetSubnetRequest{Cidr: "192.168.100.1/24"})
+	NoError(t, err)
+	Equal(t, "192.168.100.0", sn.Network)
+	Equal(t, "192.168.100.255", sn.Broadcast)
+	Equal(t, "255.255.255.0", sn.Netmask)
+	EqualValues(t, 24, sn.Prefix)
+	EqualValues(t, 4, sn.Version)
+	Equal(t, "254", sn.Usable)
+
+	sp, err := c.Split(ctx, &api.SplitRequest{Cidr: "192.168.100.0/24", NewPrefix: 25})
+	NoError(t, err)
+	Equal(t, []string{"192.168.100.0/25", "192.168.100.128/25"}, sp.Subnets)
+
+	ag, err := c.Aggregate(ctx, &api.AggregateRequest{Cidrs: []string{"10.0.0.0/25", "10.0.0.128/25"}})
+	NoError(t, err)
+	Equal(t, []string{"10.0.0.0/24"}, ag.Prefixes)
+
+	is, err := c.ListInterfaces(ctx, &api.ListInterfacesRequest{})
+	NoError(t, err)
+	NotEmpty(t, is.Interfaces)
+
+	_, err = c.GetSubnet(ctx, &api.GetSubnetRequest{Cidr: "lo"})
+	Equal(t, codes.InvalidArgument, status.Code(err))
+	Equal(t, "invalid IP address or CIDR: lo", status.Convert(err).Message())
+
+	_, err = c.Split(ctx, &api.SplitRequest{Cidr: "::/0", NewPrefix: 128})
+	Equal(t, codes.ResourceExhausted, status.Code(err))
+	Equal(t, "too many subnets (limit 65536): ::/0 into /128", status.Convert(err).Message())
+
+	_, err = c.Split(ctx, &api.SplitRequest{Cidr: "10.0.0.0/8", NewPrefix: 8})
+	Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCGetSubnetHostConfig(t *testing.T) {
+	sn, err := newGRPCClient(t).GetSubnet(context.Background(), &api.GetSubnetRequest{Cidr: "127.0.0.1/8"})
+	NoError(t, err)
+	Equal(t, "127.0.0.1", sn.Ip)
+	Empty(t, sn.Name)
+	Empty(t, sn.Gateway)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+// maxHeatmapCells is the maximum number of cells of a heatmap.
+const maxHeatmapCells = 1 << 16
+
+// heatLevels are the glyphs and colors (ANSI 256) of the utilization levels 0%, < 25%, < 50%, < 75% and >= 75%.
+var heatLevels = []struct {
+	glyph string
+	color int
+}{
+	{"·", 240},
+	{"░", 34},
+	{"▒", 184},
+	{"▓", 208},
+	{"█", 196},
+}
+
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap [flags] IP/PREFIX_LEN",
+	Short: "Visualize the utilization of an address block as a grid",
+	Long: `Visualize the utilization of an address block as a grid.
+Every cell represents a subnet (/24 by default), which is shaded by the share of used addresses.`,
+	Example: `  terminus heatmap --used used.txt 10.0.0.0/16`,
+	Args:    cobra.ExactArgs(1),
+	Run:     runHeatmapCmd,
+}
+
+func init() {
+	heatmapCmd.Flags().String("used", "", "File containing the used IP addresses, networks or ranges")
+	heatmapCmd.Flags().Int("cell-prefix", 24, "Prefix length of the subnet represented by a cell")
+	heatmapCmd.Flags().String("color", "auto", "Colorize the output (auto, always, never)")
+	rootCmd.AddCommand(heatmapCmd)
+}
+
+func runHeatmapCmd(cmd *cobra.Command, args []string) {
+	_, n, err := determineIP(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	used := ipset.Set{}
+	if name, _ := cmd.Flags().GetString("used"); name != "" {
+		b, err := readInputFile(name)
+		if err != nil {
+			fatal(err)
+		}
+		if used, err = ipset.Parse(parseTargets(b)); err != nil {
+			fatal(err)
+		}
+	}
+
+	cell, _ := cmd.Flags().GetInt("cell-prefix")
+	color, _ := cmd.Flags().GetString("color")
+	if err := renderHeatmap(os.Stdout, toPrefix(n), cell, used, useColor(color)); err != nil {
+		fatal(err)
+	}
+}
+
+// renderHeatmap prints a grid of the subnets with the prefix length cell within p, shaded by their utilization.
+func renderHeatmap(w io.Writer, p netip.Prefix, cell int, used ipset.Set, color bool) error {
+	k := cell - p.Bits()
+	if k < 0 || cell > p.Addr().BitLen() || 1<<k > maxHeatmapCells {
+		return fmt.Errorf("invalid cell prefix length for a heatmap of %v: %d", p, cell)
+	}
+
+	cols := 1 << ((k + 1) / 2)
+	width := len(ipset.LastAddr(p).String())
+	s := &strings.Builder{}
+	a := p.Masked().Addr()
+	for i := 0; i < 1<<k; i++ {
+		if i%cols == 0 {
+			if i > 0 {
+				s.WriteString("\n")
+			}
+			_, _ = fmt.Fprintf(s, "%-*v ", width, a)
+		}
+
+		r := ipset.PrefixRange(netip.PrefixFrom(a, cell))
+		writeHeatCell(s, heatLevel(used.CountIn(r), r.Size()), color)
+		a = r.To.Next()
+	}
+
+	s.WriteString("\n\n")
+	for i, l := range []string{"0%", "< 25%", "< 50%", "< 75%", ">= 75%"} {
+		writeHeatCell(s, i, color)
+		_, _ = fmt.Fprintf(s, " %s  ", l)
+	}
+	_, _ = fmt.Fprintln(w, strings.TrimRight(s.String(), " "))
+	return nil
+}
+
+// heatLevel returns the utilization level (0-4) of a cell.
+func heatLevel(used, size *big.Int) int {
+	if used.Sign() == 0 {
+		return 0
+	}
+	ratio, _ := new(big.Rat).SetFrac(used, size).Float64()
+	if l := 1 + int(ratio*4); l < len(heatLevels) {
+		return l
+	}
+	return len(heatLevels) - 1
+}
+
+func writeHeatCell(s *strings.Builder, level int, color bool) {
+	g := strings.Repeat(heatLevels[level].glyph, 2)
+	if color {
+		_, _ = fmt.Fprintf(s, "\x1b[38;5;%dm%s\x1b[0m", heatLevels[level].color, g)
+	} else {
+		s.WriteString(g)
+	}
+}
+
+// useColor reports whether the output should be colorized.
+// In auto mode, the output is colorized if stdout is a terminal and NO_COLOR is not set.
+func useColor(mode string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
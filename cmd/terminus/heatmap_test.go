@@ -0,0 +1,64 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/big"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestRenderHeatmap(t *testing.T) {
+	used, _ := ipset.Parse([]string{"10.0.0.0/25", "10.0.3.0/24", "10.0.5.0-10.0.5.70", "10.0.9.1"})
+	s := &strings.Builder{}
+	NoError(t, renderHeatmap(s, netip.MustParsePrefix("10.0.0.0/20"), 24, used, false))
+	Equal(t, `10.0.0.0    ▓▓····██
+10.0.4.0    ··▒▒····
+10.0.8.0    ··░░····
+10.0.12.0   ········
+
+·· 0%  ░░ < 25%  ▒▒ < 50%  ▓▓ < 75%  ██ >= 75%
+`, s.String())
+
+	s.Reset()
+	NoError(t, renderHeatmap(s, netip.MustParsePrefix("10.0.0.0/23"), 24, used, true))
+	True(t, strings.HasPrefix(s.String(), "10.0.0.0   \x1b[38;5;208m▓▓\x1b[0m\x1b[38;5;240m··\x1b[0m\n"))
+
+	EqualError(t, renderHeatmap(s, netip.MustParsePrefix("10.0.0.0/24"), 16, used, false),
+		"invalid cell prefix length for a heatmap of 10.0.0.0/24: 16")
+	EqualError(t, renderHeatmap(s, netip.MustParsePrefix("10.0.0.0/8"), 32, used, false),
+		"invalid cell prefix length for a heatmap of 10.0.0.0/8: 32")
+}
+
+func TestHeatLevel(t *testing.T) {
+	size := big.NewInt(256)
+	Equal(t, 0, heatLevel(big.NewInt(0), size))
+	Equal(t, 1, heatLevel(big.NewInt(1), size))
+	Equal(t, 2, heatLevel(big.NewInt(64), size))
+	Equal(t, 3, heatLevel(big.NewInt(128), size))
+	Equal(t, 4, heatLevel(big.NewInt(192), size))
+	Equal(t, 4, heatLevel(size, size))
+}
+
+func TestUseColor(t *testing.T) {
+	True(t, useColor("always"))
+	False(t, useColor("never"))
+	t.Setenv("NO_COLOR", "1")
+	False(t, useColor("auto"))
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+// maxHilbertOrder is the maximum order of a Hilbert curve i.e., the image is at most 4096x4096 cells.
+const maxHilbertOrder = 12
+
+var hilbertCmd = &cobra.Command{
+	Use:   "hilbert [flags] IP/PREFIX_LEN",
+	Short: "Render an address block onto a Hilbert curve",
+	Long: `Render an address block onto a Hilbert curve (PNG or SVG).
+Adjacent addresses remain adjacent in the image, thus, subnets appear as squares or rectangles.
+Every cell represents a subnet, which is colored by the share of used addresses (black if unused).`,
+	Example: `  terminus hilbert --used access.log --out scans.png 0.0.0.0/0
+  terminus hilbert --used used.txt --format svg --out plan.svg 10.0.0.0/16`,
+	Args:        cobra.ExactArgs(1),
+	Run:         runHilbertCmd,
+	Annotations: map[string]string{sandboxWrite: "out"},
+}
+
+func init() {
+	hilbertCmd.Flags().SortFlags = false
+	hilbertCmd.Flags().String("used", "", "File containing the used IP addresses, networks or ranges")
+	hilbertCmd.Flags().String("out", "-", "Output file (- for stdout)")
+	hilbertCmd.Flags().String("format", "png", "Image format (png, svg)")
+	hilbertCmd.Flags().Int("order", 8, "Order of the Hilbert curve i.e., the image has 2^order x 2^order cells")
+	hilbertCmd.Flags().Int("scale", 2, "Size of a cell in pixels")
+	rootCmd.AddCommand(hilbertCmd)
+}
+
+func runHilbertCmd(cmd *cobra.Command, args []string) {
+	_, n, err := determineIP(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	used := ipset.Set{}
+	if name, _ := cmd.Flags().GetString("used"); name != "" {
+		b, err := readInputFile(name)
+		if err != nil {
+			fatal(err)
+		}
+		if used, err = ipset.Parse(parseTargets(b)); err != nil {
+			fatal(err)
+		}
+	}
+
+	order, _ := cmd.Flags().GetInt("order")
+	scale, _ := cmd.Flags().GetInt("scale")
+	grid, err := hilbertGrid(toPrefix(n), order, used)
+	if err != nil {
+		fatal(err)
+	}
+
+	out, _ := cmd.Flags().GetString("out")
+	w := os.Stdout
+	if out != "-" {
+		if w, err = os.Create(out); err != nil {
+			fatal(err)
+		}
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if err = writeHilbert(w, format, grid, scale); err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// hilbertGrid maps the subnets of p onto a Hilbert curve and returns the share of used addresses per cell.
+// The order of the curve is reduced if p is too small.
+func hilbertGrid(p netip.Prefix, order int, used ipset.Set) ([][]float64, error) {
+	if order < 1 || order > maxHilbertOrder {
+		return nil, fmt.Errorf("invalid order of a Hilbert curve: %d", order)
+	}
+	if hostBits := p.Addr().BitLen() - p.Bits(); 2*order > hostBits {
+		if order = hostBits / 2; order == 0 {
+			return nil, errors.New("network is too small for a Hilbert curve: " + p.String())
+		}
+	}
+
+	side := 1 << order
+	grid := make([][]float64, side)
+	for y := range grid {
+		grid[y] = make([]float64, side)
+	}
+
+	a := p.Masked().Addr()
+	for d := 0; d < side*side; d++ {
+		r := ipset.PrefixRange(netip.PrefixFrom(a, p.Bits()+2*order))
+		x, y := hilbertXY(order, d)
+		grid[y][x], _ = new(big.Rat).SetFrac(used.CountIn(r), r.Size()).Float64()
+		a = r.To.Next()
+	}
+	return grid, nil
+}
+
+// hilbertXY converts the distance d along a Hilbert curve of the given order to coordinates.
+func hilbertXY(order, d int) (x, y int) {
+	for s := 1; s < 1<<order; s *= 2 {
+		rx := 1 & (d / 2)
+		ry := 1 & (d ^ rx)
+		if ry == 0 {
+			if rx == 1 {
+				x, y = s-1-x, s-1-y
+			}
+			x, y = y, x
+		}
+		x += s * rx
+		y += s * ry
+		d /= 4
+	}
+	return x, y
+}
+
+// heatColor returns the color of a cell with the given share of used addresses.
+func heatColor(v float64) color.RGBA {
+	if v <= 0 {
+		return color.RGBA{A: 0xff}
+	}
+
+	stops := []color.RGBA{{32, 32, 160, 0xff}, {0, 200, 0, 0xff}, {255, 255, 0, 0xff}, {255, 0, 0, 0xff}}
+	pos := v * float64(len(stops)-1)
+	i := int(pos)
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+
+	f := pos - float64(i)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + f*(float64(b)-float64(a)) + 0.5) }
+	return color.RGBA{mix(stops[i].R, stops[i+1].R), mix(stops[i].G, stops[i+1].G), mix(stops[i].B, stops[i+1].B), 0xff}
+}
+
+// writeHilbert renders the grid as PNG or SVG image.
+func writeHilbert(w io.Writer, format string, grid [][]float64, scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("invalid scale: %d", scale)
+	}
+
+	side := len(grid) * scale
+	switch format {
+	case "png":
+		img := image.NewRGBA(image.Rect(0, 0, side, side))
+		for y := 0; y < side; y++ {
+			for x := 0; x < side; x++ {
+				img.SetRGBA(x, y, heatColor(grid[y/scale][x/scale]))
+			}
+		}
+		return png.Encode(w, img)
+	case "svg":
+		bw := bufio.NewWriter(w)
+		_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+			`shape-rendering="crispEdges">`+"\n", side, side)
+		_, _ = fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#000000"/>`+"\n", side, side)
+		for y, row := range grid {
+			for x, v := range row {
+				if v > 0 {
+					c := heatColor(v)
+					_, _ = fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"/>`+"\n",
+						x*scale, y*scale, scale, scale, c.R, c.G, c.B)
+				}
+			}
+		}
+		_, _ = fmt.Fprintln(bw, "</svg>")
+		return bw.Flush()
+	default:
+		return usageError("unsupported image format: " + format)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestHilbertXY(t *testing.T) {
+	var pts [][2]int
+	for d := 0; d < 16; d++ {
+		x, y := hilbertXY(2, d)
+		pts = append(pts, [2]int{x, y})
+	}
+	Equal(t, [][2]int{
+		{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 2}, {0, 3}, {1, 3}, {1, 2},
+		{2, 2}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {2, 1}, {2, 0}, {3, 0},
+	}, pts)
+}
+
+func TestHilbertGrid(t *testing.T) {
+	used, _ := ipset.Parse([]string{"10.0.0.0/26", "10.0.0.255"})
+	grid, err := hilbertGrid(netip.MustParsePrefix("10.0.0.0/24"), 8, used)
+	NoError(t, err)
+	// the order is reduced to 4 i.e., a cell represents a single address
+	Len(t, grid, 16)
+	Equal(t, 1.0, grid[0][0])
+	Equal(t, 1.0, grid[7][7])
+	Equal(t, 0.0, grid[8][8])
+	Equal(t, 1.0, grid[0][15])
+
+	grid, err = hilbertGrid(netip.MustParsePrefix("10.0.0.0/24"), 1, used)
+	NoError(t, err)
+	Equal(t, [][]float64{{1, 1.0 / 64}, {0, 0}}, grid)
+
+	_, err = hilbertGrid(netip.MustParsePrefix("10.0.0.0/32"), 8, used)
+	EqualError(t, err, "network is too small for a Hilbert curve: 10.0.0.0/32")
+	_, err = hilbertGrid(netip.MustParsePrefix("10.0.0.0/8"), 13, used)
+	EqualError(t, err, "invalid order of a Hilbert curve: 13")
+}
+
+func TestHeatColor(t *testing.T) {
+	Equal(t, color.RGBA{A: 0xff}, heatColor(0))
+	Equal(t, color.RGBA{32, 32, 160, 0xff}, heatColor(0.0000001))
+	Equal(t, color.RGBA{255, 0, 0, 0xff}, heatColor(1))
+}
+
+func TestWriteHilbert(t *testing.T) {
+	grid := [][]float64{{1, 0}, {0, 0.5}}
+	b := &bytes.Buffer{}
+	NoError(t, writeHilbert(b, "png", grid, 3))
+	img, err := png.Decode(b)
+	NoError(t, err)
+	Equal(t, 6, img.Bounds().Dx())
+	Equal(t, color.RGBA{255, 0, 0, 0xff}, img.At(2, 2))
+
+	s := &strings.Builder{}
+	NoError(t, writeHilbert(s, "svg", grid, 1))
+	Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="2" height="2" shape-rendering="crispEdges">
+<rect width="2" height="2" fill="#000000"/>
+<rect x="0" y="0" width="1" height="1" fill="#ff0000"/>
+<rect x="1" y="1" width="1" height="1" fill="#80e400"/>
+</svg>
+`, s.String())
+
+	EqualError(t, writeHilbert(s, "gif", grid, 1), "unsupported image format: gif")
+}
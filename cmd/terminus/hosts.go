@@ -0,0 +1,65 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+)
+
+var hostsCmd = &cobra.Command{
+	Use: `hosts [flags] IP/PREFIX_LEN
+  terminus hosts [flags] INTERFACE
+  terminus hosts [flags] START-END`,
+	Short: "List the usable IP addresses of a subnet",
+	Long: `List the usable IP addresses of a subnet.
+If an interface is given, the hosts of its subnet are listed.`,
+	Example: `  terminus hosts 192.168.100.0/30
+  # 192.168.100.1
+  # 192.168.100.2
+
+  terminus hosts --limit 20 eth0`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHostsCmd,
+}
+
+func init() {
+	hostsCmd.Flags().Uint32("limit", 0, "Maximum number of IP addresses to list (0 means unlimited)")
+	hostsCmd.Flags().Bool("progress", false, "Report the number of listed IP addresses and the rate on stderr")
+	rootCmd.AddCommand(hostsCmd)
+}
+
+func runHostsCmd(cmd *cobra.Command, args []string) {
+	start, end, err := hostRange(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	limit, _ := cmd.Flags().GetUint32("limit")
+	out := newOutput(cmd)
+	p := newProgress(cmd, countHosts(start, end, limit))
+	err = visitHosts(start, end, limit, func(ip net.IP) error {
+		_, err := fmt.Fprintln(out, ip)
+		p.add(1)
+		return err
+	})
+	_ = out.Flush()
+	p.finish()
+	if err != nil {
+		fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"net"
+	"os"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+var inferCmd = &cobra.Command{
+	Use:   "infer [flags] IP IP",
+	Short: "Infer the smallest subnet containing two IP addresses",
+	Long: `Infer the smallest subnet containing two IP addresses of the same family (IPv4 or IPv6).
+This is useful for reconstructing a configuration from partial information.`,
+	Example: `  terminus infer 10.0.4.17 10.0.7.200
+  # network:   10.0.4.0/22
+  # netmask:   255.255.252.0
+  # ...`,
+	Args: cobra.ExactArgs(2),
+	Run:  runInferCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(inferCmd)
+}
+
+func runInferCmd(_ *cobra.Command, args []string) {
+	a, b := net.ParseIP(args[0]), net.ParseIP(args[1])
+	if a == nil {
+		fatal(&net.ParseError{Type: "IP address", Text: args[0]})
+	} else if b == nil {
+		fatal(&net.ParseError{Type: "IP address", Text: args[1]})
+	}
+
+	size, err := commonPrefix(a, b)
+	if err != nil {
+		fatal(err)
+	}
+	if a4 := a.To4(); a4 != nil {
+		printInferred(os.Stdout, iface.GetParams(a.String(), a4, net.CIDRMask(size, 8*net.IPv4len)))
+	} else {
+		printInferred(os.Stdout, iface.GetParams(a.String(), a, net.CIDRMask(size, 8*net.IPv6len)))
+	}
+}
+
+// commonPrefix returns the length of the longest prefix a and b have in common.
+// Both must be either IPv4 or IPv6 addresses.
+func commonPrefix(a, b net.IP) (int, error) {
+	if (a.To4() == nil) != (b.To4() == nil) {
+		return 0, usageError("IPv4 and IPv6 addresses cannot be mixed: " + a.String() + " " + b.String())
+	}
+
+	if a.To4() != nil {
+		a, b = a.To4(), b.To4()
+	} else {
+		a, b = a.To16(), b.To16()
+	}
+	size := 0
+	for i := range a {
+		n := bits.LeadingZeros8(a[i] ^ b[i])
+		if size += n; n < 8 {
+			break
+		}
+	}
+	return size, nil
+}
+
+func printInferred(w io.Writer, data map[string]interface{}) {
+	_, _ = fmt.Fprintf(w, "network:   %v/%v\n", data[iface.Network], data[iface.Prefix])
+	_, _ = fmt.Fprintf(w, "netmask:   %v\n", data[iface.NetMask])
+	_, _ = fmt.Fprintf(w, "broadcast: %v\n", data[iface.Broadcast])
+	_, _ = fmt.Fprintf(w, "first:     %v\n", data[iface.First])
+	_, _ = fmt.Fprintf(w, "last:      %v\n", data[iface.Last])
+	_, _ = fmt.Fprintf(w, "size:      %v\n", data[iface.Size])
+	_, _ = fmt.Fprintf(w, "usable:    %v\n", data[iface.UsableSize])
+}
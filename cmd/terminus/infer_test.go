@@ -0,0 +1,68 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"10.0.4.17", "10.0.7.200", 22},
+		{"10.0.4.17", "10.0.4.17", 32},
+		{"10.0.4.16", "10.0.4.17", 31},
+		{"0.0.0.0", "255.255.255.255", 0},
+		{"192.168.0.1", "192.168.1.1", 23},
+		{"2001:db8::1", "2001:db8::2", 126},
+		{"2001:db8::1", "2001:db8::1", 128},
+		{"2001:db8::1", "2001:db9::1", 31},
+		{"::", "ffff::", 0},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			size, err := commonPrefix(net.ParseIP(tt.a), net.ParseIP(tt.b))
+			NoError(t, err)
+			Equal(t, tt.want, size)
+		})
+	}
+
+	_, err := commonPrefix(net.ParseIP("10.0.0.1"), net.ParseIP("::1"))
+	EqualError(t, err, "IPv4 and IPv6 addresses cannot be mixed: 10.0.0.1 ::1")
+	Equal(t, exitParse, exitCode(err))
+}
+
+func TestPrintInferred(t *testing.T) {
+	ip := net.ParseIP("10.0.4.17")
+	s := &strings.Builder{}
+	printInferred(s, iface.GetParams(ip.String(), ip, net.CIDRMask(22, 32)))
+	Equal(t, `network:   10.0.4.0/22
+netmask:   255.255.252.0
+broadcast: 10.0.7.255
+first:     10.0.4.1
+last:      10.0.7.254
+size:      1024
+usable:    1022
+`, s.String())
+}
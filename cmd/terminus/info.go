@@ -0,0 +1,432 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var infoCmd = &cobra.Command{
+	Use: `info [flags] IP...
+  terminus info [flags] IP/PREFIX_LEN...
+  terminus info [flags] INTERFACE...`,
+	Short: "Calculate the properties of IP addresses and subnets",
+	Long: `Calculate the properties (network address, broadcast address, number of hosts, etc.) of IP addresses,
+subnets and network interfaces. The properties are selected by flags or rendered by a template expression.
+The info command is the default command i.e., "terminus info -n 10.0.0.1/8" is equivalent to "terminus -n 10.0.0.1/8".`,
+	Example: `  terminus info -b 192.168.100.1/24
+  # 192.168.100.255
+
+  terminus info -t '{{.ip}}/{{.prefix}} ({{.network}} - {{.broadcast}})' tun0
+  # 10.197.63.254/11 (10.192.0.0 - 10.223.255.255)`,
+	Args:        cobra.ArbitraryArgs,
+	Run:         runInfoCmd,
+	Annotations: map[string]string{sandboxNetwork: "hosts,resolve"},
+}
+
+func init() {
+	infoCmd.Flags().SortFlags = false
+	addInfoFlags(infoCmd.Flags())
+	rootCmd.AddCommand(infoCmd)
+}
+
+// addInfoFlags defines the flags of the info command, which are supported by the root command as well.
+func addInfoFlags(fs *pflag.FlagSet) {
+	fs.BoolP("all", "a", false, "Show all properties as labeled lines (key: value)")
+	fs.BoolP(iface.Broadcast, "b", false, "Show the broadcast address of the subnet")
+	fs.Bool(iface.Class, false, "Show the address class (A, B, C, D, E) of the IPv4 address")
+	fs.Bool(iface.DHCP, false, "Show the DHCP lease of the network interface")
+	fs.Bool(iface.DNS, false, "Show the DNS servers of the network interface")
+	fs.BoolP(iface.First, "f", false, "Show the first usable IP address of the subnet")
+	fs.Bool(iface.Flags, false, "Show the flags (up, broadcast, multicast, ...) of the network interface")
+	fs.BoolP(iface.Gateway, "g", false, "Show the default gateway of the network interface")
+	fs.Bool(iface.HardwareAddr, false, "Show the MAC address of the network interface")
+	fs.BoolP(iface.IP, "i", false, "Show the IP address")
+	fs.BoolP(iface.Last, "l", false, "Show the last usable IP address of the subnet")
+	fs.BoolP(iface.NetMask, "m", false, "Show the subnet mask in dot-decimal notation")
+	fs.Bool(iface.MTU, false, "Show the MTU of the network interface")
+	fs.Bool(iface.Name, false, "Show the name of the network interface (if possible)")
+	fs.BoolP(iface.Network, "n", false, "Show the network address")
+	fs.BoolP(iface.Prefix, "p", false, "Show the prefix length")
+	fs.BoolP("range", "r", false, "Show the IP range of the subnet")
+	fs.BoolP("resolve", "R", false, "Show the host name of the IP address (PTR record)")
+	fs.String("resolver", "", "DNS server (IP or IP:PORT) queried for host names and --resolve")
+	fs.String("doh", "",
+		"DNS over HTTPS endpoint queried for host names and --resolve (e.g., https://dns.google/dns-query)")
+	fs.Duration("timeout", 5*time.Second, "Timeout per DNS lookup (with --resolve, --resolver or --doh)")
+	fs.Bool("progress", false, "Report the number of processed inputs and the rate on stderr")
+	fs.Int("parallel", defaultParallel, "Number of inputs processed concurrently (output keeps the order of the inputs)")
+	fs.Bool(iface.Search, false, "Show the DNS search domains of the network interface")
+	fs.BoolP(iface.Size, "s", false, "Count the total number of IPs of the subnet")
+	fs.Bool(iface.Speed, false, "Show the link speed of the network interface in Mbit/s (if available)")
+	fs.StringSlice("fields", nil, "Show the given properties in this order (e.g., ip,network,prefix)")
+	fs.StringP("template", "t", "", "Format the output with the given template expression")
+	fs.String("ipv6-format", "", "Format of IPv6 addresses except in templates (canonical, compressed, expanded, arpa)")
+	fs.String("geo-db", "", "MaxMind DB file (.mmdb) used by the geo template function")
+	fs.String("missingkey", "zero", "Handling of missing template properties (error, zero, default=VALUE)")
+	fs.BoolP(iface.UsableSize, "u", false, "Count the number of hosts of the subnet")
+	fs.BoolP(iface.Wildcard, "w", false, "Show the wildcard mask of the subnet")
+	fs.Bool("primary", false, "Use the primary network interface (of the default route) as input")
+	fs.Int("index", 0, "Select the n-th IP address (starting at 0) of network interfaces")
+	fs.String("family", "", "Select the IP addresses of network interfaces by family (4, 6)")
+	fs.Bool("dedupe", false, "Skip inputs, which are equal to a previous input after canonicalization")
+	fs.Bool("count", false, "Prefix every output with the number of occurrences (implies --dedupe)")
+	fs.StringArray("input-file", nil, "Read additional inputs from a file, stdin (-) or URL")
+	fs.String("input-type", "auto", "Type of all inputs (auto, ip, cidr, range, int, host, interface)")
+	fs.StringArray("input-checksum", nil, "Verify the checksum (sha256:HEX, sha512:HEX) of the input file")
+	fs.String("input-minisign-key", "", "Verify the minisign signatures (FILE.minisig) of all input files")
+	fs.Bool("warn-special", false, "Warn about network and broadcast addresses")
+	fs.StringP("output", "o", "text", "Output format (text, csv, ipsec, json, shell, yaml)")
+	fs.StringArray("ipsec-remote", nil, "Right traffic selector (CIDR) of the IPsec tunnel (with -o ipsec)")
+	fs.String("ipsec-syntax", "strongswan", "Syntax of the IPsec config (strongswan, libreswan)")
+	fs.String("ipsec-conn", "tunnel", "Name of the IPsec connection")
+	fs.Bool("export", false, "Print shell variable assignments (shorthand for --output shell)")
+}
+
+func runInfoCmd(cmd *cobra.Command, args []string) {
+	if primary, _ := cmd.Flags().GetBool("primary"); primary {
+		name, err := iface.Primary()
+		if err != nil {
+			fatal(err)
+		}
+		args = append(args, name)
+	}
+
+	switch {
+	case strings.Contains(cmd.Flag("template").Value.String(), ".interfaces"):
+		// if the template refers to interfaces by name, the positional argument is optional
+	case len(args) == 0 && !cmd.Flag("input-file").Changed:
+		_ = cmd.Usage()
+		os.Exit(1)
+	}
+
+	v, err := newVerifier(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if missingKey, err = parseMissingKey(cmd.Flag("missingkey").Value.String()); err != nil {
+		fatal(err)
+	}
+	if err := checkFields(cmd); err != nil {
+		fatal(err)
+	}
+	ipv6Format, _ := cmd.Flags().GetString("ipv6-format")
+	if err := checkIPv6Format(ipv6Format); err != nil {
+		fatal(err)
+	}
+	geoDBName = cmd.Flag("geo-db").Value.String()
+	if err := applyAddrSelection(cmd); err != nil {
+		fatal(err)
+	}
+	r, err := newResolver(cmd.Flag("resolver").Value.String(), cmd.Flag("doh").Value.String())
+	if err != nil {
+		fatal(err)
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if cmd.Flag("resolver").Changed || cmd.Flag("doh").Changed {
+		// host names are resolved by the given server as well
+		lookupIP = func(host string) ([]net.IP, error) { return lookupHost(r, host, timeout) }
+	}
+	files, _ := cmd.Flags().GetStringArray("input-file")
+	fileArgs, err := readInputFiles(files, v)
+	if err != nil {
+		fatal(err)
+	}
+
+	typ, _ := cmd.Flags().GetString("input-type")
+	ins, err := parseInputs(append(args, fileArgs...), typ, cmd.Flag("dedupe").Changed || cmd.Flag("count").Changed)
+	if err != nil {
+		fatal(err)
+	}
+	if deterministic {
+		sortInputs(ins)
+	}
+	f, err := newFormatter(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if f.output == "ipsec" {
+		printIPsec(cmd, ins)
+		return
+	}
+	if len(ins) == 0 {
+		s, err := f.format(map[string]interface{}{})
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Print(s)
+		return
+	}
+
+	// every record is written to the buffer as soon as it is formatted
+	out := newOutput(cmd)
+	defer func() { _ = out.Flush() }()
+	keys := requestedKeys(cmd)
+	resolve, _ := cmd.Flags().GetBool("resolve")
+	warned := false
+
+	// the properties are determined concurrently, whereas the records are formatted in the order of the inputs
+	p := newProgress(cmd, int64(len(ins)))
+	type record struct {
+		data map[string]interface{}
+		err  error
+	}
+	forEachOrdered(par, len(ins), func(i int) interface{} {
+		in := ins[i]
+		rec := record{data: iface.GetParamsOf(in.arg, in.ip, in.n.Mask, keys...)}
+		if cmd.Flag("count").Changed {
+			rec.data["count"] = in.count
+		}
+		if in.port != 0 {
+			rec.data["port"] = in.port
+		}
+		if resolve {
+			rec.data["hostname"], rec.err = lookupPTR(r, in.ip, timeout)
+		}
+		if ipv6Format != "" && !cmd.Flag("template").Changed {
+			formatIPv6Addrs(rec.data, ipv6Format)
+		}
+		return rec
+	}, func(i int, v interface{}) {
+		in, rec := ins[i], v.(record)
+		if msg := in.special(); msg != "" && cmd.Flag("warn-special").Changed {
+			// keep the order of warnings and output
+			_ = out.Flush()
+			log.Print("warning: ", msg)
+			warned = true
+		}
+		if rec.err != nil {
+			_ = out.Flush()
+			log.Print("warning: ", rec.err)
+			warned = true
+		}
+
+		s, err := f.format(rec.data)
+		if err != nil {
+			_ = out.Flush()
+			fatal(err)
+		}
+		if cmd.Flag("count").Changed && f.output == "text" {
+			s = prefixLines(loc.format(in.count)+"\t", s)
+		}
+		_, _ = out.WriteString(s)
+		p.add(1)
+	})
+	p.finish()
+
+	if warned {
+		_ = out.Flush()
+		os.Exit(1)
+	}
+}
+
+// applyAddrSelection sets the selection of the IP addresses of network interfaces according to --index and --family.
+func applyAddrSelection(cmd *cobra.Command) error {
+	if cmd.Flag("index").Changed {
+		if addrIndex, _ = cmd.Flags().GetInt("index"); addrIndex < 0 {
+			return fmt.Errorf("invalid index (must not be negative): %d", addrIndex)
+		}
+	}
+	switch f, _ := cmd.Flags().GetString("family"); f {
+	case "":
+	case "4", "6":
+		addrFamily, _ = strconv.Atoi(f)
+	default:
+		return errors.New("invalid family (must be 4 or 6): " + f)
+	}
+	return nil
+}
+
+// input is a positional argument along with the IP address and network it refers to.
+type input struct {
+	arg   string
+	ip    net.IP
+	n     iplib.Net
+	count int
+	// port is the port of HOST:PORT inputs and URLs (or 0).
+	port int
+}
+
+// key returns the canonical representation of the input i.e., IP/PREFIX_LEN (or IP%ZONE/PREFIX_LEN).
+func (in input) key() string {
+	size, _ := in.n.Mask.Size()
+	ip := in.ip.String()
+	if _, zone, ok := strings.Cut(strings.SplitN(in.arg, "/", 2)[0], "%"); ok {
+		ip += "%" + zone
+	}
+	return ip + "/" + strconv.Itoa(size)
+}
+
+// special reports whether the input is the network or broadcast address of its subnet i.e.,
+// it cannot be assigned to a host.
+// Inputs without an explicit prefix length (or netmask) are never reported, because their subnet is only implied.
+func (in input) special() string {
+	if !strings.Contains(in.arg, "/") {
+		return ""
+	}
+	if size, bits := in.n.Mask.Size(); size >= bits-1 {
+		// /31 and /32 do not have a network or broadcast address
+		return ""
+	}
+
+	switch {
+	case in.ip.Equal(in.n.NetworkAddress()):
+		return in.key() + " is the network address of " + in.n.String()
+	case in.ip.Equal(in.n.BroadcastAddress()):
+		return in.key() + " is the broadcast address of " + in.n.String()
+	}
+	return ""
+}
+
+// parseInputs determines the IP address and network of every argument. Ports and URLs are stripped (see splitPort).
+// IP ranges (FROM-TO and octet ranges like 10.0.0-3.1-254) are expanded into the IP addresses they contain,
+// integers and host names are converted into IP addresses. Unless typ is "auto", all arguments must be of that type.
+// If dedupe is true, inputs with the same canonical representation are reported only once.
+func parseInputs(args []string, typ string, dedupe bool) ([]*input, error) {
+	args, err := convertInputs(args, typ)
+	if err != nil {
+		return nil, err
+	}
+	if args, err = expandRanges(args); err != nil {
+		return nil, err
+	}
+
+	ins := make([]*input, 0, len(args))
+	seen := map[string]*input{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if typ == "auto" && i+1 < len(args) && isNetmaskPair(arg, args[i+1]) {
+			// legacy "IP NETMASK" notation, as used by many router configs
+			arg += "/" + args[i+1]
+			i++
+		}
+
+		arg, port := splitPort(arg)
+		ip, n, err := determineIP(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		in := &input{arg: arg, ip: ip, n: n, count: 1}
+		in.port, _ = strconv.Atoi(port)
+		if dedupe {
+			if prev, ok := seen[in.key()]; ok {
+				prev.count++
+				continue
+			}
+			seen[in.key()] = in
+		}
+		ins = append(ins, in)
+	}
+	return ins, nil
+}
+
+// isNetmaskPair reports whether ip is an IPv4 address followed by a subnet mask in dot-decimal notation.
+// Masks without any network bits (0.0.0.0) are considered IP addresses.
+func isNetmaskPair(ip, mask string) bool {
+	if net.ParseIP(ip).To4() == nil || !strings.Contains(mask, ".") {
+		return false
+	}
+	size, ok := parseMask(mask)
+	return ok && size > 0
+}
+
+// formatText renders the properties selected by --fields followed by the other flags of cmd (in the order of the
+// command line) as plain text.
+func formatText(cmd *cobra.Command, data map[string]interface{}) (string, error) {
+	s := &strings.Builder{}
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	for _, k := range fields {
+		if v, ok := data[k]; ok {
+			_, _ = fmt.Fprintln(s, loc.format(v))
+		}
+	}
+
+	var err error
+	visitFlags(cmd, func(f *pflag.Flag) {
+		switch f.Name {
+		case "range":
+			_, _ = fmt.Fprintf(s, "%v - %v\n", data[iface.Network], data[iface.Broadcast])
+		case "template":
+			if text, _ := cmd.Flags().GetString("template"); err == nil {
+				err = printTemplate(text, s, data)
+			}
+		default:
+			// flags, which do not refer to a property, are processing options without output
+			if k := flagProperty(f.Name); k != "count" && !contains(fields, k) {
+				if v, ok := data[k]; ok {
+					_, _ = fmt.Fprintln(s, loc.format(v))
+				}
+			}
+		}
+	})
+	return s.String(), err
+}
+
+// requestedKeys returns the properties, which are selected by --fields and other flags, so that only these have to
+// be determined for every input. If all properties are required (e.g., by --all, --template or if no property is
+// selected), nil is returned.
+func requestedKeys(cmd *cobra.Command) []string {
+	if all, _ := cmd.Flags().GetBool("all"); all || cmd.Flag("template").Changed {
+		return nil
+	}
+
+	keys, _ := cmd.Flags().GetStringSlice("fields")
+	keys = append([]string{}, keys...)
+	visitFlags(cmd, func(f *pflag.Flag) {
+		switch f.Name {
+		case "range":
+			keys = append(keys, iface.Network, iface.Broadcast)
+		case "resolve":
+			keys = append(keys, "hostname")
+		default:
+			for _, p := range iface.Properties {
+				if p.Name == f.Name {
+					keys = append(keys, f.Name)
+				}
+			}
+		}
+	})
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys
+}
+
+func prefixLines(prefix, s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseInputs(t *testing.T) {
+	ins, err := parseInputs([]string{"127.0.0.1/8", "127.0.0.1", "127.0.0.2/24"}, "auto", false)
+	NoError(t, err)
+	Len(t, ins, 3)
+	Equal(t, "127.0.0.1/8", ins[1].key())
+	Equal(t, "127.0.0.2/24", ins[2].key())
+}
+
+func TestParseInputsNetmask(t *testing.T) {
+	ins, err := parseInputs([]string{"192.168.1.10", "255.255.255.0", "10.0.0.1", "0.0.0.0", "127.0.0.1"}, "auto", false)
+	NoError(t, err)
+	Len(t, ins, 4)
+	Equal(t, "192.168.1.10/24", ins[0].key())
+	Equal(t, "10.0.0.1/8", ins[1].key())
+	Equal(t, "0.0.0.0/8", ins[2].key())
+	Equal(t, "127.0.0.1/8", ins[3].key())
+}
+
+func TestParseInputsDedupe(t *testing.T) {
+	ins, err := parseInputs([]string{"127.0.0.1/8", "127.0.0.2/24", "127.0.0.1", "127.0.0.1/8"}, "auto", true)
+	NoError(t, err)
+	Len(t, ins, 2)
+	Equal(t, "127.0.0.1/8", ins[0].arg)
+	Equal(t, 3, ins[0].count)
+	Equal(t, "127.0.0.2/24", ins[1].arg)
+	Equal(t, 1, ins[1].count)
+}
+
+func TestParseInputsDedupeZone(t *testing.T) {
+	ins, err := parseInputs([]string{"fe80::1%eth0/64", "fe80::1%eth1/64", "fe80::1%eth0/64"}, "auto", true)
+	NoError(t, err)
+	Len(t, ins, 2)
+	Equal(t, "fe80::1%eth0/64", ins[0].key())
+	Equal(t, 2, ins[0].count)
+	Equal(t, "fe80::1%eth1/64", ins[1].key())
+}
+
+func TestParseInputsPort(t *testing.T) {
+	ins, err := parseInputs([]string{"203.0.113.10:8443", "https://[2001:db8::1]/", "192.0.2.1"}, "auto", false)
+	NoError(t, err)
+	Len(t, ins, 3)
+	Equal(t, "203.0.113.10", ins[0].arg)
+	Equal(t, 8443, ins[0].port)
+	Equal(t, "2001:db8::1", ins[1].arg)
+	Equal(t, 443, ins[1].port)
+	Equal(t, 0, ins[2].port)
+}
+
+func TestRequestedKeys(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-i", "--dedupe", "-p"}, []string{iface.IP, iface.Prefix}},
+		{[]string{"--fields", "name,ip", "-r"}, []string{iface.Name, iface.IP, iface.Network, iface.Broadcast}},
+		{[]string{"-o", "json", "--count"}, nil},
+		{[]string{"-a", "-i"}, nil},
+		{[]string{"-i", "-t", "{{.ip}}"}, nil},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addInfoFlags(cmd.Flags())
+			NoError(t, cmd.ParseFlags(tt.args))
+			Equal(t, tt.want, requestedKeys(cmd))
+		})
+	}
+}
+
+func TestPrefixLines(t *testing.T) {
+	Equal(t, "3\ta\n3\tb\n", prefixLines("3\t", "a\nb\n"))
+	Equal(t, "", prefixLines("3\t", ""))
+}
+
+func TestInputSpecial(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"10.0.0.0/24", "10.0.0.0/24 is the network address of 10.0.0.0/24"},
+		{"10.0.0.255/24", "10.0.0.255/24 is the broadcast address of 10.0.0.0/24"},
+		{"10.0.0.1/24", ""},
+		{"10.0.0.0/31", ""},
+		{"10.0.0.0/32", ""},
+		{"10.0.0.0", ""},
+		{"10.0.0.0 255.255.255.0", "10.0.0.0/24 is the network address of 10.0.0.0/24"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.arg, func(t *testing.T) {
+			ins, err := parseInputs(strings.Fields(tt.arg), "auto", false)
+			NoError(t, err)
+			Equal(t, tt.want, ins[0].special())
+		})
+	}
+}
@@ -0,0 +1,507 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipset"
+)
+
+// httpClient is used for fetching remote input files.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// readInputFiles returns the targets listed in the given files after verifying their integrity.
+func readInputFiles(names []string, v verifier) ([]string, error) {
+	if len(v.checksums) > len(names) {
+		return nil, usageError(fmt.Sprintf("%d --input-checksum values given for %d input files",
+			len(v.checksums), len(names)))
+	}
+
+	var args []string
+	for i, name := range names {
+		b, err := readInputFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.verify(i, name, b); err != nil {
+			return nil, err
+		}
+		args = append(args, parseTargets(b)...)
+	}
+	return args, nil
+}
+
+// readInputFile returns the content of a local file, stdin ("-") or a remote file (http, https).
+func readInputFile(name string) ([]byte, error) {
+	u, err := url.Parse(name)
+	if err != nil || len(u.Scheme) < 2 {
+		// not a URL (or a Windows drive letter)
+		if name == "-" {
+			return io.ReadAll(os.Stdin)
+		}
+		return os.ReadFile(name)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return fetch(u.String())
+	case "file":
+		return os.ReadFile(u.Path)
+	case "s3":
+		return nil, errors.New("s3 input files are not supported by this build: " + name)
+	default:
+		return nil, usageError("unsupported input file scheme: " + u.Scheme)
+	}
+}
+
+// parseTargets returns the first field of every line, skipping empty lines and comments (# or ;).
+// Thus, common CIDR feeds like "192.0.2.0/24 ; SBL123456" can be read as they are.
+// Fields containing spaces (e.g., Windows interface names like "Ethernet 2") must be enclosed in double quotes.
+func parseTargets(b []byte) (args []string) {
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		l := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(l, `"`) {
+			if i := strings.IndexByte(l[1:], '"'); i > 0 {
+				args = append(args, l[1:i+1])
+				continue
+			}
+		}
+		if i := strings.IndexAny(l, "#;"); i >= 0 {
+			l = l[:i]
+		}
+		if fs := strings.FieldsFunc(l, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }); len(fs) > 0 {
+			args = append(args, fs[0])
+		}
+	}
+	return args
+}
+
+// readArgs returns the arguments, reading them from stdin if there are no args or an arg is "-".
+func readArgs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	var ss []string
+	for _, a := range args {
+		if a != "-" {
+			ss = append(ss, a)
+			continue
+		}
+		b, err := readInputFile(a)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, parseTargets(b)...)
+	}
+	return ss, nil
+}
+
+// readAddrArgs parses the IP addresses, reading them from stdin if there are no args or an arg is "-".
+func readAddrArgs(args []string) ([]netip.Addr, error) {
+	ss, err := readArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]netip.Addr, len(ss))
+	for i, s := range ss {
+		ip, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, err
+		}
+		ips[i] = ip.Unmap()
+	}
+	if deterministic {
+		sortIPs(ips)
+	}
+	return ips, nil
+}
+
+// parsePrefixes parses networks in CIDR notation, IP addresses (as host prefixes) and aliases (e.g., linklocal).
+// The networks are masked i.e., host bits are cleared.
+func parsePrefixes(ss []string) ([]netip.Prefix, error) {
+	ps := make([]netip.Prefix, len(ss))
+	for i, s := range ss {
+		if v, ok, err := lookupAlias(s); err != nil {
+			return nil, err
+		} else if ok {
+			s = v
+		}
+		if a, err := netip.ParseAddr(s); err == nil {
+			ps[i] = netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen())
+		} else if p, err := netip.ParsePrefix(s); err == nil {
+			ps[i] = p.Masked()
+		} else {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+		}
+	}
+	return ps, nil
+}
+
+// addrToken matches the candidates for IP addresses (and networks in CIDR notation) in arbitrary text.
+var addrToken = regexp.MustCompile(`[0-9A-Fa-f:.]*[0-9A-Fa-f:](?:/[0-9]{1,3})?`)
+
+// addrMatch is an IP address or a network in CIDR notation, which is mentioned in a text.
+type addrMatch struct {
+	netip.Prefix
+	// cidr is true if the match is written in CIDR notation, otherwise, it is an IP address (as host prefix).
+	cidr bool
+}
+
+// findAddrs returns the IP addresses and networks in CIDR notation, which are mentioned in the text s.
+// IP addresses are returned as host prefixes and ports (e.g., 192.0.2.1:443) are ignored.
+func findAddrs(s string) []addrMatch {
+	var ms []addrMatch
+	for _, loc := range addrToken.FindAllStringIndex(s, -1) {
+		i, j := loc[0], loc[1]
+		if s[i] == ':' && !strings.HasPrefix(s[i:], "::") {
+			// separator like in "addr:192.0.2.1"
+			i++
+		}
+		if i > 0 && isWordChar(s[i-1]) || j < len(s) && isWordChar(s[j]) {
+			// part of a word (e.g., a hash or an identifier)
+			continue
+		}
+		if m, ok := parseAddrToken(s[i:j]); ok {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// parseAddrToken parses an IP address or a network in CIDR notation, which might be followed by a port.
+func parseAddrToken(t string) (addrMatch, bool) {
+	if p, err := netip.ParsePrefix(t); err == nil {
+		return addrMatch{p, true}, true
+	}
+	if a, err := netip.ParseAddr(t); err == nil {
+		return addrMatch{netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()), false}, true
+	}
+	if i := strings.LastIndexByte(t, ':'); i > 0 && strings.Count(t, ":") == 1 {
+		// IPv4:PORT
+		return parseAddrToken(t[:i])
+	}
+	return addrMatch{}, false
+}
+
+// String returns the network in CIDR notation or the IP address.
+func (m addrMatch) String() string {
+	if m.cidr {
+		return m.Prefix.String()
+	}
+	return m.Addr().String()
+}
+
+// openInput opens the file (or stdin if name is "-").
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// scanLines calls fn for every line of r.
+func scanLines(r io.Reader, fn func(line string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if err := fn(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// maxExpand is the maximum number of IP addresses an IP range argument may expand to.
+const maxExpand = 1 << 16
+
+// parseRangeArg parses an IP range (FROM-TO) or an nmap-style octet range pattern (e.g., 10.0.0-3.1-254).
+// If arg is neither of them, ok is false.
+func parseRangeArg(arg string) (rs []ipset.Range, ok bool, err error) {
+	if from, to, isRange := strings.Cut(arg, "-"); isRange {
+		if _, errFrom := netip.ParseAddr(from); errFrom == nil {
+			if _, errTo := netip.ParseAddr(to); errTo == nil {
+				r, err := ipset.ParseRange(arg)
+				if err != nil {
+					return nil, true, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+				}
+				return []ipset.Range{r}, true, nil
+			}
+		}
+	}
+
+	if strings.Count(arg, ".") != 3 || !strings.ContainsAny(arg, "-,*") || strings.Trim(arg, "0123456789.-,*") != "" {
+		return nil, false, nil
+	}
+	if rs, err = ipset.ParseOctetRanges(arg); err != nil {
+		return nil, true, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+	}
+	return rs, true, nil
+}
+
+// expandRanges replaces IP range arguments by the IP addresses they contain.
+func expandRanges(args []string) ([]string, error) {
+	var ss []string
+	for _, arg := range args {
+		rs, ok, err := parseRangeArg(arg)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			ss = append(ss, arg)
+			continue
+		}
+
+		if ipset.New(rs...).Size().Cmp(big.NewInt(maxExpand)) > 0 {
+			return nil, fmt.Errorf("IP range %s exceeds %d addresses", arg, maxExpand)
+		}
+		for _, r := range rs {
+			for a := r.From; ; a = a.Next() {
+				ss = append(ss, a.String())
+				if a == r.To {
+					break
+				}
+			}
+		}
+	}
+	return ss, nil
+}
+
+// inputTypes are the types of inputs, which can be forced by --input-type (in addition to "auto").
+var inputTypes = []string{"ip", "cidr", "range", "int", "host", "interface"}
+
+// lookupIP resolves host names. It can be replaced in tests.
+var lookupIP = net.LookupIP
+
+// detectInputType returns the type of the input: an IP address, a network (IP/PREFIX_LEN or IP/NETMASK),
+// an IP range, an integer, a host name (with at least one dot, unless it is an interface or alias) or an interface
+// (or alias). Integers are never detected as interface indexes, so that the result does not depend on the host.
+func detectInputType(arg string) string {
+	if _, ok, _ := parseRangeArg(arg); ok {
+		return "range"
+	} else if _, err := netip.ParseAddr(arg); err == nil {
+		return "ip"
+	} else if strings.Contains(arg, "/") {
+		return "cidr"
+	} else if arg != "" && strings.Trim(arg, "0123456789") == "" {
+		return "int"
+	}
+	if strings.Contains(arg, ".") && strings.ContainsAny(strings.ToLower(arg), "abcdefghijklmnopqrstuvwxyz") {
+		if _, ok, _ := lookupAlias(arg); !ok {
+			if _, err := iface.InterfaceByName(arg); err != nil {
+				return "host"
+			}
+		}
+	}
+	return "interface"
+}
+
+// convertInputs converts integers and host names into IP addresses, and interface indexes into interface names.
+// Unless typ is "auto", all inputs must be of the given type. Interface indexes are only accepted if typ is
+// "interface". Other inputs are returned as they are.
+func convertInputs(args []string, typ string) ([]string, error) {
+	if typ != "auto" && !contains(inputTypes, typ) {
+		return nil, usageError("unsupported input type: " + typ)
+	}
+
+	ss := make([]string, 0, len(args))
+	for _, arg := range args {
+		arg, port := splitPort(arg)
+		t := detectInputType(arg)
+		switch {
+		case (typ == "host" || typ == "interface") && (t == "host" || t == "interface"):
+			// any name can be a host name or an interface
+			t = typ
+		case typ == "interface" && t == "int":
+			// integers are interface indexes
+			t = typ
+		}
+		if typ != "auto" && t != typ {
+			return nil, fmt.Errorf("%w: %s is not of type %s", ErrInvalidCIDR, arg, typ)
+		}
+
+		add := func(s string) {
+			if port != "" {
+				s = net.JoinHostPort(s, port)
+			}
+			ss = append(ss, s)
+		}
+		switch t {
+		case "int":
+			a, err := addrFromInt(arg)
+			if err != nil {
+				return nil, err
+			}
+			add(a.String())
+		case "host":
+			ips, err := lookupIP(arg)
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range ips {
+				add(ip.String())
+			}
+		case "interface":
+			if i, err := iface.InterfaceByName(arg); err == nil {
+				// the index or an alternative name (e.g., a GUID on Windows) is replaced by the name
+				arg = i.Name
+			}
+			add(arg)
+		default:
+			add(arg)
+		}
+	}
+	return ss, nil
+}
+
+// splitPort strips the port from HOST:PORT and [IPv6]:PORT, or the scheme, port and path from URLs
+// (e.g., https://192.0.2.1:8443/index.html). If the URL does not contain a port, the default port of the scheme
+// is returned (if known). Interface names like eth0:1 and other inputs are returned as they are (without port).
+// Hosts without dots (e.g., lo:8080) must be aliases or interfaces.
+func splitPort(s string) (host, port string) {
+	if strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil && u.Hostname() != "" {
+			port = u.Port()
+			if port == "" {
+				if p, err := net.LookupPort("tcp", u.Scheme); err == nil {
+					port = strconv.Itoa(p)
+				}
+			}
+			return u.Hostname(), port
+		}
+	}
+
+	h, p, err := net.SplitHostPort(s)
+	if err != nil || h == "" {
+		return s, ""
+	} else if n, err := strconv.Atoi(p); err != nil || n < 0 || n > 65535 {
+		return s, ""
+	}
+	if strings.HasPrefix(s, "[") {
+		return h, p
+	} else if _, err := iface.InterfaceByName(s); err == nil {
+		return s, ""
+	}
+	if _, isAlias, _ := lookupAlias(h); !isAlias && !strings.Contains(h, ".") {
+		if _, err := iface.InterfaceByName(h); err != nil {
+			// neither a host name (with dots), an IPv4 address, an alias nor an interface
+			return s, ""
+		}
+	}
+	return h, p
+}
+
+// addrFromInt converts a decimal integer into an IPv4 address (up to 2^32-1) or an IPv6 address.
+func addrFromInt(s string) (netip.Addr, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok || i.Sign() < 0 || i.BitLen() > 128 {
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+	}
+	n := net.IPv6len
+	if i.BitLen() <= 32 {
+		n = net.IPv4len
+	}
+	a, _ := netip.AddrFromSlice(i.FillBytes(make([]byte, n)))
+	return a, nil
+}
+
+// cacheEntry holds the validators of a cached remote file.
+type cacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// fetch downloads a remote file.
+// Responses are cached and revalidated using conditional GET requests.
+func fetch(rawURL string) ([]byte, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	base := filepath.Join(dir, "terminus", "feeds", hex.EncodeToString(sum[:]))
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "terminus/"+version)
+
+	var ce cacheEntry
+	cached, err := os.ReadFile(base)
+	if meta, errMeta := os.ReadFile(base + ".json"); err == nil && errMeta == nil && json.Unmarshal(meta, &ce) == nil {
+		if ce.ETag != "" {
+			req.Header.Set("If-None-Match", ce.ETag)
+		}
+		if ce.LastModified != "" {
+			req.Header.Set("If-Modified-Since", ce.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return cached, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("cannot fetch %s: %s", rawURL, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// caching is best effort - errors are ignored
+	ce = cacheEntry{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	if ce.ETag != "" || ce.LastModified != "" {
+		meta, _ := json.Marshal(ce)
+		if os.MkdirAll(filepath.Dir(base), 0o700) == nil && os.WriteFile(base, b, 0o600) == nil {
+			_ = os.WriteFile(base+".json", meta, 0o600)
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseTargets(t *testing.T) {
+	b := []byte("; Spamhaus DROP List\n192.0.2.0/24 ; SBL1\n\n  198.51.100.0/24\t# comment\n203.0.113.1,foo\n#10.0.0.0/8\n")
+	Equal(t, []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.1"}, parseTargets(b))
+}
+
+func TestParseTargetsQuoted(t *testing.T) {
+	b := []byte("\"Ethernet 2\"\n  \"vEthernet (WSL)\" # Hyper-V\n\"unterminated\n")
+	Equal(t, []string{"Ethernet 2", "vEthernet (WSL)", "\"unterminated"}, parseTargets(b))
+}
+
+func TestReadInputFiles(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "blocklist.txt")
+	NoError(t, os.WriteFile(name, []byte("192.0.2.0/24\n198.51.100.0/24\n"), 0o600))
+
+	args, err := readInputFiles([]string{name, "file://" + filepath.ToSlash(name)}, verifier{})
+	NoError(t, err)
+	Equal(t, []string{"192.0.2.0/24", "198.51.100.0/24", "192.0.2.0/24", "198.51.100.0/24"}, args)
+
+	_, err = readInputFiles([]string{"s3://bucket/blocklist.txt"}, verifier{})
+	EqualError(t, err, "s3 input files are not supported by this build: s3://bucket/blocklist.txt")
+}
+
+func TestFetch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("192.0.2.0/24\n"))
+	}))
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		b, err := fetch(srv.URL + "/drop.txt")
+		NoError(t, err)
+		Equal(t, "192.0.2.0/24\n", string(b))
+	}
+	Equal(t, 2, requests)
+
+	_, err := fetch(srv.URL + "/%zz")
+	Error(t, err)
+}
+
+func TestFetchNotFound(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := fetch(srv.URL)
+	EqualError(t, err, "cannot fetch "+srv.URL+": 404 Not Found")
+}
+
+func TestReadAddrArgs(t *testing.T) {
+	ips, err := readAddrArgs([]string{"192.0.2.1", "::ffff:198.51.100.1"})
+	NoError(t, err)
+	Equal(t, []netip.Addr{netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("198.51.100.1")}, ips)
+
+	_, err = readAddrArgs([]string{"192.0.2.0/24"})
+	Error(t, err)
+}
+
+func TestParsePrefixes(t *testing.T) {
+	ps, err := parsePrefixes([]string{"10.0.1.7/24", "::ffff:192.0.2.1", "2001:db8::1"})
+	NoError(t, err)
+	Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.1.0/24"), netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("2001:db8::1/128"),
+	}, ps)
+
+	_, err = parsePrefixes([]string{"eth0"})
+	EqualError(t, err, "invalid IP address or CIDR: eth0")
+}
+
+func TestExpandRanges(t *testing.T) {
+	ss, err := expandRanges([]string{"10.0.0.1/24", "10.0.0-1.5,7", "192.0.2.254-192.0.3.0", "br-12ab", "eth0"})
+	NoError(t, err)
+	Equal(t, []string{"10.0.0.1/24", "10.0.0.5", "10.0.0.7", "10.0.1.5", "10.0.1.7",
+		"192.0.2.254", "192.0.2.255", "192.0.3.0", "br-12ab", "eth0"}, ss)
+
+	_, err = expandRanges([]string{"10.0.0.9-10.0.0.1"})
+	ErrorIs(t, err, ErrInvalidCIDR)
+	_, err = expandRanges([]string{"10.0.0.1-300"})
+	ErrorIs(t, err, ErrInvalidCIDR)
+	_, err = expandRanges([]string{"10.0-1.*.*"})
+	EqualError(t, err, "IP range 10.0-1.*.* exceeds 65536 addresses")
+}
+
+func TestDetectInputType(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"192.0.2.1", "ip"},
+		{"fe80::1%eth0", "ip"},
+		{"192.0.2.0/24", "cidr"},
+		{"192.0.2.1/255.255.255.0", "cidr"},
+		{"192.0.2.1-192.0.2.9", "range"},
+		{"10.0.0-3.1-254", "range"},
+		{"3221225985", "int"},
+		{"www.example.com", "host"},
+		{"eth0", "interface"},
+		{"localhost", "interface"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.arg, func(t *testing.T) {
+			Equal(t, tt.want, detectInputType(tt.arg))
+		})
+	}
+}
+
+func TestDetectInputTypeIndex(t *testing.T) {
+	defer func() { _ = iface.UseSnapshot(nil) }()
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{{Index: 7, Name: "Ethernet 2"}}}))
+
+	// integers are addresses, even if there is an interface with that index
+	Equal(t, "int", detectInputType("7"))
+	Equal(t, "int", detectInputType("8"))
+	ss, err := convertInputs([]string{"7", "8"}, "auto")
+	NoError(t, err)
+	Equal(t, []string{"0.0.0.7", "0.0.0.8"}, ss)
+	ss, err = convertInputs([]string{"7"}, "int")
+	NoError(t, err)
+	Equal(t, []string{"0.0.0.7"}, ss)
+
+	ss, err = convertInputs([]string{"7", "Ethernet 2"}, "interface")
+	NoError(t, err)
+	Equal(t, []string{"Ethernet 2", "Ethernet 2"}, ss)
+}
+
+func TestConvertInputs(t *testing.T) {
+	defer func() { lookupIP = net.LookupIP }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		Equal(t, "www.example.com", host)
+		return []net.IP{net.ParseIP("192.0.2.80"), net.ParseIP("2001:db8::80")}, nil
+	}
+
+	ss, err := convertInputs([]string{"10.0.0.1/8", "3221225985", "www.example.com", "0", "4294967296", "lo"}, "auto")
+	NoError(t, err)
+	Equal(t, []string{"10.0.0.1/8", "192.0.2.1", "192.0.2.80", "2001:db8::80", "0.0.0.0", "::1:0:0", "lo"}, ss)
+
+	ss, err = convertInputs([]string{"www.example.com"}, "host")
+	NoError(t, err)
+	Equal(t, []string{"192.0.2.80", "2001:db8::80"}, ss)
+
+	_, err = convertInputs([]string{"192.0.2.1", "192.0.2.0/24"}, "ip")
+	EqualError(t, err, "invalid IP address or CIDR: 192.0.2.0/24 is not of type ip")
+	ErrorIs(t, err, ErrInvalidCIDR)
+	_, err = convertInputs([]string{"1" + strings.Repeat("0", 40)}, "int")
+	ErrorIs(t, err, ErrInvalidCIDR)
+	_, err = convertInputs(nil, "mac")
+	EqualError(t, err, "unsupported input type: mac")
+}
+
+func TestSplitPort(t *testing.T) {
+	defer func() { _ = iface.UseSnapshot(nil) }()
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{{Name: "eth0"}, {Name: "eth0:1"}}}))
+
+	tests := []struct {
+		in   string
+		host string
+		port string
+	}{
+		{"203.0.113.10:8443", "203.0.113.10", "8443"},
+		{"[2001:db8::1]:443", "2001:db8::1", "443"},
+		{"www.example.com:80", "www.example.com", "80"},
+		{"https://192.0.2.1:8443/index.html?q=1", "192.0.2.1", "8443"},
+		{"https://[2001:db8::1]/", "2001:db8::1", "443"},
+		{"http://[fe80::1%25eth0]:8080", "fe80::1%eth0", "8080"},
+		{"localhost:8080", "localhost", "8080"},
+		{"eth0:8080", "eth0", "8080"},
+		{"eth0:1", "eth0:1", ""},
+		{"wlan0:1", "wlan0:1", ""},
+		{"192.0.2.1:65536", "192.0.2.1:65536", ""},
+		{"2001:db8::1", "2001:db8::1", ""},
+		{"192.0.2.0/24", "192.0.2.0/24", ""},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			host, port := splitPort(tt.in)
+			Equal(t, tt.host, host)
+			Equal(t, tt.port, port)
+		})
+	}
+}
+
+func TestFindAddrs(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Failed password for root from 192.0.2.1 port 22 ssh2", "[192.0.2.1]"},
+		{"inet addr:10.0.0.5  Bcast:10.0.0.255  Mask:255.255.255.0", "[10.0.0.5 10.0.0.255 255.255.255.0]"},
+		{"inet6 fe80::1/64 scope link, 2001:db8:: and ::1.", "[fe80::1/64 2001:db8:: ::1]"},
+		{"GET from [2001:db8::2]:443 and 198.51.100.7:8080", "[2001:db8::2 198.51.100.7]"},
+		{"route add 10.0.0.0/8, time 12:34:56, mac 00:11:22:33:44:55", "[10.0.0.0/8]"},
+		{"host 192.0.2.1/32 and 192.0.2.1", "[192.0.2.1/32 192.0.2.1]"},
+		{"sha 1a2b3c4d10.0.0.1 id_10.0.0.2 v1.2.3.4x", "[]"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.text, func(t *testing.T) {
+			Equal(t, tt.want, fmt.Sprint(findAddrs(tt.text)))
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use: `inventory [flags] IP/PREFIX_LEN
+  terminus inventory [flags] START-END`,
+	Short: "Generate an Ansible inventory from a subnet or an IP range",
+	Long: `Generate an Ansible inventory from a subnet or an IP range.
+Every usable IP address of the subnet (or every IP address of the range) becomes a host of the group.`,
+	Example: `  terminus inventory --group webservers 10.0.0.0/29
+  # [webservers]
+  # 10.0.0.1
+  # ...
+  # 10.0.0.6
+
+  terminus inventory --format yaml --resolve 10.0.0.10-10.0.0.20`,
+	Args:        cobra.ExactArgs(1),
+	Run:         runInventoryCmd,
+	Annotations: map[string]string{sandboxNetwork: "resolve"},
+}
+
+func init() {
+	inventoryCmd.Flags().SortFlags = false
+	inventoryCmd.Flags().String("group", "all", "Name of the inventory group")
+	inventoryCmd.Flags().String("format", "ini", "Inventory format (ini, yaml)")
+	inventoryCmd.Flags().Bool("resolve", false, "Use the PTR records as host aliases (if available)")
+	inventoryCmd.Flags().String("resolver", "", "DNS server (IP or IP:PORT) queried for --resolve")
+	inventoryCmd.Flags().String("doh", "",
+		"DNS over HTTPS endpoint queried for --resolve (e.g., https://dns.google/dns-query)")
+	inventoryCmd.Flags().Duration("timeout", 5*time.Second, "Timeout per PTR lookup")
+	inventoryCmd.Flags().Int("parallel", defaultParallel, "Number of concurrent PTR lookups")
+	rootCmd.AddCommand(inventoryCmd)
+}
+
+func runInventoryCmd(cmd *cobra.Command, args []string) {
+	start, end, err := hostRange(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	r, err := newResolver(cmd.Flag("resolver").Value.String(), cmd.Flag("doh").Value.String())
+	if err != nil {
+		fatal(err)
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	group, _ := cmd.Flags().GetString("group")
+	format, _ := cmd.Flags().GetString("format")
+	out := newOutput(cmd)
+	inv, err := newInventoryWriter(out, format, group)
+	if err != nil {
+		fatal(err)
+	}
+
+	// hosts are written while they are generated, so that large subnets are never held in memory as a whole
+	if resolve, _ := cmd.Flags().GetBool("resolve"); resolve {
+		from := iplib.IP4ToUint32(start)
+		forEachOrdered(par, int(countHosts(start, end, 0)), func(i int) interface{} {
+			return resolveHost(r, iplib.Uint32ToIP4(from+uint32(i)), timeout)
+		}, func(_ int, v interface{}) {
+			if err == nil {
+				err = inv.add(v.(*host))
+			}
+		})
+	} else {
+		err = visitHosts(start, end, 0, func(ip net.IP) error { return inv.add(&host{ip: ip}) })
+	}
+	_ = out.Flush()
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// host is an entry of an inventory.
+type host struct {
+	ip    net.IP
+	alias string
+}
+
+// hostRange returns the first and last host of a subnet, an interface or an explicit range START-END.
+func hostRange(arg string) (net.IP, net.IP, error) {
+	if from, to, ok := strings.Cut(arg, "-"); ok {
+		start, end := net.ParseIP(from).To4(), net.ParseIP(to).To4()
+		if start == nil || end == nil || iplib.CompareIPs(start, end) > 0 {
+			return nil, nil, errors.New("invalid IP range: " + arg)
+		}
+		return start, end, nil
+	}
+
+	_, n, err := determineIP(arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n.Version() != 4 {
+		return nil, nil, errors.New("not an IPv4 network: " + arg)
+	}
+	return n.FirstAddress(), n.LastAddress(), nil
+}
+
+// visitHosts calls fn for every IP address from start to end (inclusive) until fn returns an error.
+// If limit is greater than zero, fn is called at most limit times.
+func visitHosts(start, end net.IP, limit uint32, fn func(net.IP) error) error {
+	from, to := iplib.IP4ToUint32(start), iplib.IP4ToUint32(end)
+	if limit > 0 && to-from >= limit {
+		to = from + limit - 1
+	}
+
+	for i := from; ; i++ {
+		if err := fn(iplib.Uint32ToIP4(i)); err != nil {
+			return err
+		}
+		if i == to {
+			return nil
+		}
+	}
+}
+
+// countHosts returns the number of IP addresses from start to end (inclusive), but at most limit if it is greater
+// than zero.
+func countHosts(start, end net.IP, limit uint32) int64 {
+	n := int64(iplib.IP4ToUint32(end)) - int64(iplib.IP4ToUint32(start)) + 1
+	if limit > 0 && n > int64(limit) {
+		n = int64(limit)
+	}
+	return n
+}
+
+// resolveHost returns the host with the given IP address, which uses the PTR record as alias (if available).
+// Failed lookups (e.g., timeouts) are ignored, i.e., the host has no alias.
+func resolveHost(r dnsResolver, ip net.IP, timeout time.Duration) *host {
+	alias, _ := lookupPTR(r, ip, timeout)
+	return &host{ip: ip, alias: alias}
+}
+
+// inventoryWriter writes hosts as Ansible inventory group in INI or YAML format.
+type inventoryWriter struct {
+	w      io.Writer
+	format string
+	indent string
+}
+
+// newInventoryWriter writes the header of the inventory group and returns a writer for its hosts.
+func newInventoryWriter(w io.Writer, format, group string) (*inventoryWriter, error) {
+	inv := &inventoryWriter{w: w, format: format}
+	var err error
+	switch format {
+	case "ini":
+		_, err = fmt.Fprintf(w, "[%s]\n", group)
+	case "yaml":
+		inv.indent = "    "
+		if group == "all" {
+			_, err = fmt.Fprint(w, "all:\n  hosts:\n")
+		} else {
+			_, err = fmt.Fprintf(w, "all:\n  children:\n    %s:\n      hosts:\n", group)
+			inv.indent = "        "
+		}
+	default:
+		return nil, usageError("unsupported inventory format: " + format)
+	}
+	return inv, err
+}
+
+// add writes h as member of the inventory group.
+func (inv *inventoryWriter) add(h *host) (err error) {
+	switch {
+	case inv.format == "ini" && h.alias != "":
+		_, err = fmt.Fprintf(inv.w, "%s ansible_host=%v\n", h.alias, h.ip)
+	case inv.format == "ini":
+		_, err = fmt.Fprintln(inv.w, h.ip)
+	case h.alias != "":
+		_, err = fmt.Fprintf(inv.w, "%s%s:\n%s  ansible_host: %v\n", inv.indent, h.alias, inv.indent, h.ip)
+	default:
+		_, err = fmt.Fprintf(inv.w, "%s%v:\n", inv.indent, h.ip)
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestHostRange(t *testing.T) {
+	start, end, err := hostRange("10.0.0.0/29")
+	NoError(t, err)
+	Equal(t, "10.0.0.1", start.String())
+	Equal(t, "10.0.0.6", end.String())
+
+	start, end, err = hostRange("10.0.0.10-10.0.0.20")
+	NoError(t, err)
+	Equal(t, "10.0.0.10", start.String())
+	Equal(t, "10.0.0.20", end.String())
+
+	_, _, err = hostRange("10.0.0.20-10.0.0.10")
+	EqualError(t, err, "invalid IP range: 10.0.0.20-10.0.0.10")
+}
+
+func TestVisitHosts(t *testing.T) {
+	var ips []string
+	collect := func(ip net.IP) error {
+		ips = append(ips, ip.String())
+		return nil
+	}
+
+	start, end, _ := hostRange("255.255.255.254-255.255.255.255")
+	NoError(t, visitHosts(start, end, 0, collect))
+	Equal(t, []string{"255.255.255.254", "255.255.255.255"}, ips)
+
+	ips = nil
+	start, end, _ = hostRange("10.0.0.0/24")
+	NoError(t, visitHosts(start, end, 3, collect))
+	Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, ips)
+}
+
+func TestResolveHost(t *testing.T) {
+	addr := serveDNS(t, map[string]string{"1.2.0.192.in-addr.arpa.": "web1.example.com."})
+	r, err := newResolver(addr, "")
+	NoError(t, err)
+
+	Equal(t, &host{ip: net.IPv4(192, 0, 2, 1), alias: "web1.example.com"},
+		resolveHost(r, net.IPv4(192, 0, 2, 1), time.Second))
+	Equal(t, &host{ip: net.IPv4(192, 0, 2, 2)}, resolveHost(r, net.IPv4(192, 0, 2, 2), time.Second))
+}
+
+func TestInventoryWriter(t *testing.T) {
+	hosts := []*host{{ip: net.IPv4(10, 0, 0, 1)}, {ip: net.IPv4(10, 0, 0, 2), alias: "web2.example.com"}}
+	write := func(format, group string, hosts []*host) (string, error) {
+		s := &strings.Builder{}
+		inv, err := newInventoryWriter(s, format, group)
+		for i := 0; err == nil && i < len(hosts); i++ {
+			err = inv.add(hosts[i])
+		}
+		return s.String(), err
+	}
+
+	s, err := write("ini", "webservers", hosts)
+	NoError(t, err)
+	Equal(t, "[webservers]\n10.0.0.1\nweb2.example.com ansible_host=10.0.0.2\n", s)
+
+	s, err = write("yaml", "webservers", hosts)
+	NoError(t, err)
+	Equal(t, `all:
+  children:
+    webservers:
+      hosts:
+        10.0.0.1:
+        web2.example.com:
+          ansible_host: 10.0.0.2
+`, s)
+
+	s, err = write("yaml", "all", hosts[:1])
+	NoError(t, err)
+	Equal(t, "all:\n  hosts:\n    10.0.0.1:\n", s)
+
+	_, err = write("toml", "all", hosts)
+	EqualError(t, err, "unsupported inventory format: toml")
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/netip"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// printIPsec prints a conn section with the inputs as left and --ipsec-remote as right traffic selectors.
+// Overlapping selectors are reported as warnings.
+func printIPsec(cmd *cobra.Command, ins []*input) {
+	remote, _ := cmd.Flags().GetStringArray("ipsec-remote")
+	syntax, _ := cmd.Flags().GetString("ipsec-syntax")
+	conn, _ := cmd.Flags().GetString("ipsec-conn")
+
+	left := make([]netip.Prefix, len(ins))
+	for i, in := range ins {
+		left[i] = selector(in)
+	}
+	right, err := parsePrefixes(remote)
+	if err != nil {
+		fatal(err)
+	}
+
+	s, err := formatIPsec(conn, left, right, syntax)
+	if err != nil {
+		fatal(err)
+	}
+	for _, w := range overlapWarnings(left, right) {
+		log.Print("warning: ", w)
+	}
+	fmt.Print(s)
+}
+
+// selector returns the traffic selector of the input i.e., its network or the host if no prefix length is given.
+func selector(in *input) netip.Prefix {
+	if a, err := netip.ParseAddr(in.arg); err == nil {
+		return netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen())
+	}
+	return toPrefix(in.n)
+}
+
+// overlapWarnings reports overlapping selectors, which are subject to narrowing during IKE negotiation
+// (or, if a left selector overlaps a right one, capture traffic that should not be tunneled).
+func overlapWarnings(left, right []netip.Prefix) (ws []string) {
+	check := func(side string, ps []netip.Prefix) {
+		for i, p := range ps {
+			for _, q := range ps[i+1:] {
+				if p.Overlaps(q) {
+					ws = append(ws, fmt.Sprintf("%s selectors %s and %s overlap (traffic selectors might be narrowed)",
+						side, p, q))
+				}
+			}
+		}
+	}
+	check("left", left)
+	check("right", right)
+	for _, l := range left {
+		for _, r := range right {
+			if l.Overlaps(r) {
+				ws = append(ws, fmt.Sprintf("left selector %s and right selector %s overlap", l, r))
+			}
+		}
+	}
+	return ws
+}
+
+// formatIPsec renders a conn section with the left and right subnet selectors in ipsec.conf syntax of
+// strongSwan or libreswan. Libreswan expects multiple selectors as leftsubnets={...} and builds one
+// tunnel per combination, which requires all selectors of a conn to be of the same IP version.
+func formatIPsec(conn string, left, right []netip.Prefix, syntax string) (string, error) {
+	if len(left) == 0 || len(right) == 0 {
+		return "", usageError("left and right traffic selectors are required (e.g., --ipsec-remote CIDR)")
+	}
+
+	join := func(ps []netip.Prefix, sep string) string {
+		ss := make([]string, len(ps))
+		for i, p := range ps {
+			ss[i] = p.String()
+		}
+		return strings.Join(ss, sep)
+	}
+
+	s := &strings.Builder{}
+	_, _ = fmt.Fprintf(s, "conn %s\n", conn)
+	switch syntax {
+	case "strongswan":
+		_, _ = fmt.Fprintf(s, "\tleftsubnet=%s\n\trightsubnet=%s\n", join(left, ","), join(right, ","))
+	case "libreswan":
+		is4 := left[0].Addr().Is4()
+		for _, p := range append(left, right...) {
+			if p.Addr().Is4() != is4 {
+				return "", usageError("libreswan does not support mixing IPv4 and IPv6 traffic selectors in a conn")
+			}
+		}
+		for _, side := range []struct {
+			name string
+			ps   []netip.Prefix
+		}{{"left", left}, {"right", right}} {
+			if len(side.ps) == 1 {
+				_, _ = fmt.Fprintf(s, "\t%ssubnet=%s\n", side.name, side.ps[0])
+			} else {
+				_, _ = fmt.Fprintf(s, "\t%ssubnets={%s}\n", side.name, join(side.ps, " "))
+			}
+		}
+	default:
+		return "", usageError("unsupported IPsec syntax: " + syntax)
+	}
+	return s.String(), nil
+}
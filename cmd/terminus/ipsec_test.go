@@ -0,0 +1,64 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFormatIPsec(t *testing.T) {
+	left := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24"), netip.MustParsePrefix("10.0.2.0/24")}
+	right := []netip.Prefix{netip.MustParsePrefix("192.168.0.0/24")}
+
+	s, err := formatIPsec("tunnel", left, right, "strongswan")
+	NoError(t, err)
+	Equal(t, "conn tunnel\n\tleftsubnet=10.0.0.0/24,10.0.2.0/24\n\trightsubnet=192.168.0.0/24\n", s)
+
+	s, err = formatIPsec("site-b", left, right, "libreswan")
+	NoError(t, err)
+	Equal(t, "conn site-b\n\tleftsubnets={10.0.0.0/24 10.0.2.0/24}\n\trightsubnet=192.168.0.0/24\n", s)
+
+	_, err = formatIPsec("tunnel", left, []netip.Prefix{netip.MustParsePrefix("2001:db8::/32")}, "libreswan")
+	EqualError(t, err, "libreswan does not support mixing IPv4 and IPv6 traffic selectors in a conn")
+	_, err = formatIPsec("tunnel", left, nil, "strongswan")
+	EqualError(t, err, "left and right traffic selectors are required (e.g., --ipsec-remote CIDR)")
+	_, err = formatIPsec("tunnel", left, right, "racoon")
+	EqualError(t, err, "unsupported IPsec syntax: racoon")
+}
+
+func TestOverlapWarnings(t *testing.T) {
+	left, err := parsePrefixes([]string{"10.0.0.0/16", "10.0.2.1", "10.1.0.0/16"})
+	NoError(t, err)
+	right, err := parsePrefixes([]string{"192.168.0.0/24", "10.0.1.7/24"})
+	NoError(t, err)
+	Equal(t, "10.0.1.0/24", right[1].String())
+
+	Equal(t, []string{
+		"left selectors 10.0.0.0/16 and 10.0.2.1/32 overlap (traffic selectors might be narrowed)",
+		"left selector 10.0.0.0/16 and right selector 10.0.1.0/24 overlap",
+	}, overlapWarnings(left, right))
+	Empty(t, overlapWarnings(left[1:], right[:1]))
+}
+
+func TestSelector(t *testing.T) {
+	ins, err := parseInputs([]string{"10.0.2.1", "10.0.2.1/24", "2001:db8::1"}, "auto", false)
+	NoError(t, err)
+	Equal(t, "10.0.2.1/32", selector(ins[0]).String())
+	Equal(t, "10.0.2.0/24", selector(ins[1]).String())
+	Equal(t, "2001:db8::1/128", selector(ins[2]).String())
+}
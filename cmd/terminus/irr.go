@@ -0,0 +1,172 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/asn"
+	"github.com/abc-inc/terminus/whois"
+	"github.com/spf13/cobra"
+)
+
+var irrCmd = &cobra.Command{
+	Use:   "irr [flags] [PREFIX...]",
+	Short: "Look up the route objects of prefixes in the IRR",
+	Long: `Look up the route and route6 objects of prefixes in an Internet Routing Registry (IRR) via an IRRd
+or RIPE-style WHOIS server and report the registered origins.
+If no prefix is given (or "-"), prefixes are read from stdin (one per line).
+The exit status is 1 if any prefix has no route object (of the --origin AS, if given).`,
+	Example: `  terminus irr 203.0.113.0/24
+  # 203.0.113.0/24	203.0.113.0/24	AS64500	RADB	Example Network
+
+  terminus irr --origin AS64500 --sources RIPE,RADB --match less < announced.txt`,
+	Args:        cobra.ArbitraryArgs,
+	Run:         runIRRCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	irrCmd.Flags().String("server", "whois.radb.net:43", "IRR WHOIS server (HOST:PORT)")
+	irrCmd.Flags().StringSlice("sources", nil, "IRR databases to query (default: all sources of the server)")
+	irrCmd.Flags().String("match", "exact", "Route objects to match (exact, less, more)")
+	irrCmd.Flags().String("origin", "", "Expected origin AS of the route objects")
+	irrCmd.Flags().Duration("timeout", 10*time.Second, "Timeout per lookup")
+	// IRR servers tend to limit the number of concurrent connections per client
+	irrCmd.Flags().Int("parallel", 1, "Number of concurrent lookups")
+	irrCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(irrCmd)
+}
+
+func runIRRCmd(cmd *cobra.Command, args []string) {
+	server, _ := cmd.Flags().GetString("server")
+	sources, _ := cmd.Flags().GetStringSlice("sources")
+	match, _ := cmd.Flags().GetString("match")
+	origin, _ := cmd.Flags().GetString("origin")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	output, _ := cmd.Flags().GetString("output")
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+
+	var want uint32
+	if origin != "" {
+		n, err := asn.ParseASN(origin)
+		if err != nil {
+			fatal(err)
+		}
+		want = n
+	}
+
+	ps, err := readPrefixArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	rs := make([]irrResult, len(ps))
+	failed := false
+	forEachOrdered(par, len(ps), func(i int) interface{} {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		ros, err := whois.LookupRoutes(ctx, server, ps[i], whois.Match(match), sources...)
+		rs[i] = irrResult{ps[i], ros}
+		return err
+	}, func(i int, v interface{}) {
+		if v != nil {
+			fatal(v.(error))
+		}
+		if !rs[i].registered(want) {
+			log.Println(rs[i].missing(want))
+			failed = true
+		}
+	})
+
+	if err := writeIRR(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// readPrefixArgs parses the prefixes, reading them from stdin if there are no args or an arg is "-".
+func readPrefixArgs(args []string) ([]netip.Prefix, error) {
+	ss, err := readArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := parsePrefixes(ss)
+	if err == nil && deterministic {
+		sortPrefixes(ps)
+	}
+	return ps, err
+}
+
+// irrResult holds the route objects of a prefix.
+type irrResult struct {
+	Prefix netip.Prefix        `json:"prefix"`
+	Routes []whois.RouteObject `json:"routes"`
+}
+
+// registered reports whether there is a route object (of the origin AS, unless it is 0).
+func (r irrResult) registered(origin uint32) bool {
+	for _, ro := range r.Routes {
+		if origin == 0 || ro.Origin == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (r irrResult) missing(origin uint32) string {
+	if origin == 0 {
+		return "no route object found for " + r.Prefix.String()
+	}
+	return fmt.Sprintf("no route object of AS%d found for %s", origin, r.Prefix)
+}
+
+func writeIRR(w io.Writer, rs []irrResult, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			if len(r.Routes) == 0 {
+				_, _ = fmt.Fprintf(w, "%s\t-\n", r.Prefix)
+			}
+			for _, ro := range r.Routes {
+				l := fmt.Sprintf("%s\t%s\tAS%d\t%s\t%s", r.Prefix, ro.Route, ro.Origin, ro.Source, ro.Descr)
+				_, _ = fmt.Fprintln(w, strings.TrimRight(l, "\t"))
+			}
+		}
+		return nil
+	case "json":
+		for i := range rs {
+			if rs[i].Routes == nil {
+				rs[i].Routes = []whois.RouteObject{}
+			}
+		}
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
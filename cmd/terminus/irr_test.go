@@ -0,0 +1,60 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/whois"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestIRRResult(t *testing.T) {
+	p := netip.MustParsePrefix("203.0.113.0/24")
+	r := irrResult{p, []whois.RouteObject{{Route: p, Origin: 64500}, {Route: p, Origin: 64501}}}
+	True(t, r.registered(0))
+	True(t, r.registered(64501))
+	False(t, r.registered(64502))
+	Equal(t, "no route object of AS64502 found for 203.0.113.0/24", r.missing(64502))
+
+	r = irrResult{Prefix: p}
+	False(t, r.registered(0))
+	Equal(t, "no route object found for 203.0.113.0/24", r.missing(0))
+}
+
+func TestWriteIRR(t *testing.T) {
+	p := netip.MustParsePrefix("203.0.113.0/24")
+	rs := []irrResult{
+		{netip.MustParsePrefix("203.0.113.128/25"), []whois.RouteObject{
+			{Route: p, Origin: 64500, Descr: "Example Network", Source: "RADB"},
+			{Route: p, Origin: 64501, Source: "RIPE"},
+		}},
+		{Prefix: netip.MustParsePrefix("198.51.100.0/24")},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeIRR(s, rs, "text"))
+	Equal(t, "203.0.113.128/25\t203.0.113.0/24\tAS64500\tRADB\tExample Network\n"+
+		"203.0.113.128/25\t203.0.113.0/24\tAS64501\tRIPE\n"+
+		"198.51.100.0/24\t-\n", s.String())
+
+	s.Reset()
+	NoError(t, writeIRR(s, rs[1:], "json"))
+	Equal(t, `[{"prefix":"198.51.100.0/24","routes":[]}]`+"\n", s.String())
+
+	EqualError(t, writeIRR(s, rs, "xml"), "unsupported output format: xml")
+}
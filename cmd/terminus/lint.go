@@ -0,0 +1,248 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/spf13/cobra"
+)
+
+var lintTemplateCmd = &cobra.Command{
+	Use:   "lint-template [flags]",
+	Short: "Check a template for errors without processing any input",
+	Long: `Check a template for errors without processing any input.
+Besides syntax errors, it reports unknown properties (e.g., typos like .netmsk) and function calls with the wrong
+number of arguments. The exit status is 1 if any problem is found.`,
+	Example: `  terminus lint-template -T subnet.tmpl
+  terminus lint-template -t '{{.ip}}/{{.prefx}}'
+  # tmpl:1:10: unknown property .prefx (did you mean .prefix?)`,
+	Args: cobra.NoArgs,
+	Run:  runLintTemplateCmd,
+}
+
+func init() {
+	lintTemplateCmd.Flags().StringP("template", "t", "", "Template expression")
+	lintTemplateCmd.Flags().StringP("template-file", "T", "", "File containing the template")
+	rootCmd.AddCommand(lintTemplateCmd)
+}
+
+func runLintTemplateCmd(cmd *cobra.Command, _ []string) {
+	name, text := "tmpl", cmd.Flag("template").Value.String()
+	if file := cmd.Flag("template-file").Value.String(); file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			fatal(err)
+		}
+		name, text = file, string(b)
+	} else if text == "" {
+		fatal(usageError("either --template or --template-file is required"))
+	}
+
+	problems, err := lintTemplate(name, text)
+	if err != nil {
+		fatal(err)
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+// lintTemplate parses the template and returns the problems found in it.
+// Syntax errors (including undefined functions) are returned as error.
+func lintTemplate(name, text string) ([]string, error) {
+	t, err := template.New(name).Funcs(funcMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{tree: t.Tree, props: map[string]bool{}, funcs: map[string]reflect.Type{}}
+	for _, p := range templateProperties {
+		l.props[p.Name] = true
+	}
+	for n, f := range funcMap() {
+		l.funcs[n] = reflect.TypeOf(f)
+	}
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil && tt.Tree.Root != nil {
+			l.tree = tt.Tree
+			l.walk(tt.Tree.Root, true)
+		}
+	}
+	return l.problems, nil
+}
+
+// linter collects the problems of a template while walking its parse tree.
+type linter struct {
+	tree     *parse.Tree
+	props    map[string]bool
+	funcs    map[string]reflect.Type
+	problems []string
+	// visit is called for every property of the data passed to the template (default: checkField).
+	visit func(n parse.Node, ident []string)
+}
+
+// walk checks the node and its children. If root is true, the dot refers to the data passed to the template.
+func (l *linter) walk(n parse.Node, root bool) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, c := range n.Nodes {
+				l.walk(c, root)
+			}
+		}
+	case *parse.ActionNode:
+		l.walk(n.Pipe, root)
+	case *parse.IfNode:
+		l.walkBranch(&n.BranchNode, root, root)
+	case *parse.RangeNode:
+		l.walkBranch(&n.BranchNode, root, false)
+	case *parse.WithNode:
+		l.walkBranch(&n.BranchNode, root, false)
+	case *parse.TemplateNode:
+		l.walk(n.Pipe, root)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, d := range n.Decl {
+			l.walk(d, root)
+		}
+		for i, c := range n.Cmds {
+			l.checkCommand(c, i > 0, root)
+		}
+	case *parse.FieldNode:
+		if root {
+			l.visitField(n, n.Ident)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			l.visitField(n, n.Ident[1:])
+		}
+	case *parse.ChainNode:
+		l.walk(n.Node, root)
+	}
+}
+
+func (l *linter) walkBranch(b *parse.BranchNode, root, bodyRoot bool) {
+	l.walk(b.Pipe, root)
+	l.walk(b.List, bodyRoot)
+	l.walk(b.ElseList, root)
+}
+
+// checkCommand checks the arguments of a command and, if it calls a function, the number of arguments.
+// If piped is true, the result of the previous command is passed as last argument.
+func (l *linter) checkCommand(c *parse.CommandNode, piped, root bool) {
+	for _, a := range c.Args {
+		l.walk(a, root)
+	}
+
+	id, ok := c.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	}
+	ft, ok := l.funcs[id.Ident]
+	if !ok {
+		// built-in functions like printf are checked by the template engine
+		return
+	}
+
+	args := len(c.Args) - 1
+	if piped {
+		args++
+	}
+	if want := ft.NumIn(); args != want && !(ft.IsVariadic() && args >= want-1) {
+		l.report(c, "function %s expects %d argument(s), but got %d", id.Ident, want, args)
+	}
+}
+
+func (l *linter) visitField(n parse.Node, ident []string) {
+	if l.visit != nil {
+		l.visit(n, ident)
+		return
+	}
+	l.checkField(n, ident)
+}
+
+// checkField reports unknown properties of the data passed to the template.
+func (l *linter) checkField(n parse.Node, ident []string) {
+	name := ident[0]
+	if name == "interfaces" && len(ident) > 2 {
+		// .interfaces.NAME.PROPERTY
+		name = ident[2]
+	}
+	if l.props[name] && name != "interfaces" || name == "interfaces" && len(ident) <= 2 {
+		return
+	}
+
+	msg := "unknown property ." + name
+	if s := suggestProperty(name); s != "" {
+		msg += " (did you mean ." + s + "?)"
+	}
+	l.report(n, "%s", msg)
+}
+
+// suggestProperty returns the property, which is closest to name (or an empty string if none is similar).
+func suggestProperty(name string) string {
+	best, dist := "", 3
+	for _, p := range templateProperties {
+		if d := levenshtein(name, p.Name); d < dist && d <= len(name)/2 {
+			best, dist = p.Name, d
+		}
+	}
+	return best
+}
+
+func (l *linter) report(n parse.Node, format string, args ...interface{}) {
+	loc, _ := l.tree.ErrorContext(n)
+	l.problems = append(l.problems, loc+": "+fmt.Sprintf(format, args...))
+}
+
+// levenshtein returns the edit distance of a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(v int, vs ...int) int {
+	for _, x := range vs {
+		if x < v {
+			v = x
+		}
+	}
+	return v
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestLintTemplate(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{`{{.ip}}/{{.prefix}} {{.interfaces.eth0.ip}} {{. | toJson}}`, nil},
+		{`{{range $k, $v := .interfaces}}{{$k}} {{$v.ip}} {{.foo}}{{end}}`, nil},
+		{`{{with .gateway}}{{.}}{{else}}{{.netmsk}}{{end}}`, []string{"t:1:32: unknown property .netmsk (did you mean .netmask?)"}},
+		{"{{.ip}}\n{{.prefx}}", []string{"t:2:2: unknown property .prefx (did you mean .prefix?)"}},
+		{`{{$.x}}`, []string{"t:1:3: unknown property .x"}},
+		{`{{.interfaces.eth0.ipp}}`, []string{"t:1:13: unknown property .ipp (did you mean .ip?)"}},
+		{`{{.netmask | toHex .ip}}`, []string{"t:1:13: function toHex expects 1 argument(s), but got 2"}},
+		{`{{toBinary}}`, []string{"t:1:2: function toBinary expects 1 argument(s), but got 0"}},
+		{`{{toBinary .ip | printf "%s"}}`, nil},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.text, func(t *testing.T) {
+			problems, err := lintTemplate("t", tt.text)
+			NoError(t, err)
+			Equal(t, tt.want, problems)
+		})
+	}
+
+	_, err := lintTemplate("t", `{{.ip | toHx}}`)
+	EqualError(t, err, `template: t:1: function "toHx" not defined`)
+}
+
+func TestLevenshtein(t *testing.T) {
+	Equal(t, 0, levenshtein("netmask", "netmask"))
+	Equal(t, 1, levenshtein("netmsk", "netmask"))
+	Equal(t, 3, levenshtein("", "abc"))
+	Equal(t, 3, levenshtein("kitten", "sitting"))
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/mac"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [flags]",
+	Short: "List all network interfaces",
+	Long: `List the name, IP address, network address and prefix length of all network interfaces.
+With --verbose, the MAC addresses and vendors (according to the IEEE registry) are listed as well.`,
+	Example: `  terminus list
+  # eth0    172.16.57.200   172.16.56.0     23
+  # lo      127.0.0.1       127.0.0.0       8`,
+	Args:        cobra.NoArgs,
+	Run:         runListCmd,
+	Annotations: map[string]string{sandboxNetwork: "verbose"},
+}
+
+func init() {
+	listCmd.Flags().Bool("verbose", false, "List the MAC addresses and vendors of the network interfaces as well")
+	rootCmd.AddCommand(listCmd)
+}
+
+func runListCmd(cmd *cobra.Command, _ []string) {
+	var v mac.Vendors
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		var err error
+		if v, err = loadVendors(); err != nil {
+			log.Print("warning: cannot load vendors: ", err)
+			v = mac.Vendors{}
+		}
+	}
+	s, err := listInterfaces(v)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Print(s)
+}
+
+// listInterfaces lists the name, IP address and subnet of every network interface.
+// If vendors is not nil, the MAC address and the vendor are listed as well.
+func listInterfaces(vendors mac.Vendors) (string, error) {
+	is, err := iface.Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	s := &strings.Builder{}
+	for _, i := range is {
+		if ip, n, err := determineIP(i.Name); err == nil {
+			data := iface.GetParams(i.Name, ip, n.Mask)
+			_, _ = fmt.Fprintf(s, "%s\t%v\t%v\t%v", data[iface.Name], data[iface.IP], data[iface.Network], data[iface.Prefix])
+			if vendors != nil {
+				org, _ := vendors.Lookup(i.HardwareAddr)
+				_, _ = fmt.Fprintf(s, "\t%v\t%s", i.HardwareAddr, org)
+			}
+			s.WriteString("\n")
+		}
+	}
+	return s.String(), nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestListInterfaces(t *testing.T) {
+	is, err := net.Interfaces()
+	NoError(t, err)
+	NotEmpty(t, is)
+
+	s, err := listInterfaces(nil)
+	NoError(t, err)
+	Contains(t, s, "127.0.0.1")
+
+	for _, i := range is {
+		if ip, _, err := iface.GetAddr(i.Name); err == nil {
+			Contains(t, s, i.Name)
+			Contains(t, s, ip.String())
+		}
+	}
+
+	v := mac.Vendors{}
+	for _, i := range is {
+		if len(i.HardwareAddr) >= 3 {
+			v[fmt.Sprintf("%X", []byte(i.HardwareAddr[:3]))] = "Vendor of " + i.Name
+		}
+	}
+	s, err = listInterfaces(v)
+	NoError(t, err)
+	for _, i := range is {
+		if _, _, err := iface.GetAddr(i.Name); err == nil && len(i.HardwareAddr) >= 3 {
+			Contains(t, s, "\t"+i.HardwareAddr.String()+"\tVendor of "+i.Name+"\n")
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// translations contains the operator-facing messages per language.
+// Messages are translated segment by segment (separated by ": "), thus, dynamic parts are kept as they are.
+var translations = map[string]map[string]string{
+	"de": {
+		"warning":                        "Warnung",
+		"invalid IP address":             "ungültige IP-Adresse",
+		"invalid CIDR address":           "ungültige CIDR-Adresse",
+		"invalid IP address or CIDR":     "ungültige IP-Adresse oder CIDR",
+		"invalid IP range":               "ungültiger IP-Bereich",
+		"invalid network interface name": "ungültiger Name der Netzwerkschnittstelle",
+		"no such network interface":      "Netzwerkschnittstelle nicht gefunden",
+		"no IP address":                  "keine IP-Adresse",
+		"no default gateway":             "kein Standardgateway",
+		"no route to host":               "keine Route zum Host",
+		"not an IPv4 network":            "kein IPv4-Netzwerk",
+		"invalid template":               "ungültige Vorlage",
+		"cannot load vendors":            "Hersteller können nicht geladen werden",
+	},
+}
+
+// dateLayouts contains the date formats per language (or language and region).
+var dateLayouts = map[string]string{
+	"de":    "02.01.2006 15:04",
+	"en":    "02/01/2006 15:04",
+	"en-US": "01/02/2006 3:04 PM",
+	"es":    "02/01/2006 15:04",
+	"fr":    "02/01/2006 15:04",
+	"it":    "02/01/2006 15:04",
+	"ja":    "2006/01/02 15:04",
+	"zh":    "2006/01/02 15:04",
+}
+
+// localizer formats operator-facing output like counts, dates and messages.
+// Machine-readable output (e.g., JSON or templates) must not be localized.
+type localizer struct {
+	tag language.Tag
+	p   *message.Printer
+}
+
+// loc is the localizer of the process, which does not localize anything unless --locale is set.
+var loc = newLocalizer(language.Und)
+
+func newLocalizer(tag language.Tag) *localizer {
+	return &localizer{tag: tag, p: message.NewPrinter(tag)}
+}
+
+// applyLocale sets up the localizer and translates log messages if --locale is set.
+func applyLocale(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("locale")
+	if name == "" {
+		return nil
+	}
+
+	tag, err := language.Parse(name)
+	if err != nil {
+		return fmt.Errorf("invalid locale: %s", name)
+	}
+	loc = newLocalizer(tag)
+	log.SetOutput(localizedWriter{os.Stderr})
+	return nil
+}
+
+// format formats numbers with the digit grouping of the locale, and dates (e.g., the expiry of DHCP leases) in the
+// customary format of the locale. Other values (and numbers exceeding int64) are formatted as they are.
+func (l *localizer) format(v interface{}) string {
+	if l.tag == language.Und {
+		return fmt.Sprint(v)
+	}
+
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return l.p.Sprint(number.Decimal(n))
+	case *big.Int:
+		if n.IsInt64() {
+			return l.p.Sprint(number.Decimal(n.Int64()))
+		}
+	case time.Time:
+		return l.date(n)
+	case iface.DHCPLease:
+		if n.Address != nil && n.Expires != nil {
+			exp := *n.Expires
+			n.Expires = nil
+			return n.String() + " until " + l.date(exp)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// date formats t in the customary format of the locale (or in RFC 3339 format).
+func (l *localizer) date(t time.Time) string {
+	if l.tag == language.Und {
+		return t.Format(time.RFC3339)
+	}
+
+	base, _ := l.tag.Base()
+	region, _ := l.tag.Region()
+	if layout, ok := dateLayouts[base.String()+"-"+region.String()]; ok {
+		return t.Format(layout)
+	} else if layout, ok = dateLayouts[base.String()]; ok {
+		return t.Format(layout)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// message translates the known segments of msg.
+func (l *localizer) message(msg string) string {
+	if l.tag == language.Und {
+		return msg
+	}
+
+	base, _ := l.tag.Base()
+	tr, ok := translations[base.String()]
+	if !ok {
+		return msg
+	}
+
+	segs := strings.Split(msg, ": ")
+	for i, s := range segs {
+		if t, ok := tr[s]; ok {
+			segs[i] = t
+		}
+	}
+	return strings.Join(segs, ": ")
+}
+
+// localizedWriter translates log messages before writing them to w.
+type localizedWriter struct {
+	w io.Writer
+}
+
+func (lw localizedWriter) Write(b []byte) (int, error) {
+	msg := strings.TrimSuffix(strings.TrimPrefix(string(b), log.Prefix()), "\n")
+	if _, err := io.WriteString(lw.w, log.Prefix()+loc.message(msg)+"\n"); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizerFormat(t *testing.T) {
+	big128 := new(big.Int).Lsh(big.NewInt(1), 128)
+	exp := time.Date(2023, 11, 14, 22, 13, 0, 0, time.UTC)
+	lease := iface.DHCPLease{Address: net.ParseIP("10.0.0.42"), Server: net.ParseIP("10.0.0.1"), Expires: &exp}
+	tests := []struct {
+		tag  string
+		v    interface{}
+		want string
+	}{
+		{"und", 16777216, "16777216"},
+		{"en", 16777216, "16,777,216"},
+		{"de-DE", uint32(16777216), "16.777.216"},
+		{"de-CH", big.NewInt(1234567), "1’234’567"},
+		{"de", big128, big128.String()},
+		{"de", net.ParseIP("10.0.0.1"), "10.0.0.1"},
+		{"de", exp, "14.11.2023 22:13"},
+		{"und", lease, "10.0.0.42 from 10.0.0.1 until 2023-11-14T22:13:00Z"},
+		{"de", lease, "10.0.0.42 from 10.0.0.1 until 14.11.2023 22:13"},
+		{"de", iface.DHCPLease{}, ""},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.tag, func(t *testing.T) {
+			Equal(t, tt.want, newLocalizer(language.MustParse(tt.tag)).format(tt.v))
+		})
+	}
+}
+
+func TestLocalizerDate(t *testing.T) {
+	d := time.Date(2023, 11, 14, 22, 13, 0, 0, time.UTC)
+	Equal(t, "2023-11-14T22:13:00Z", newLocalizer(language.Und).date(d))
+	Equal(t, "14.11.2023 22:13", newLocalizer(language.German).date(d))
+	Equal(t, "11/14/2023 10:13 PM", newLocalizer(language.AmericanEnglish).date(d))
+	Equal(t, "14/11/2023 22:13", newLocalizer(language.BritishEnglish).date(d))
+	Equal(t, "2023-11-14T22:13:00Z", newLocalizer(language.Korean).date(d))
+}
+
+func TestLocalizerMessage(t *testing.T) {
+	msg := "warning: invalid IP address: 10.0.0.256"
+	Equal(t, msg, newLocalizer(language.Und).message(msg))
+	Equal(t, msg, newLocalizer(language.French).message(msg))
+	Equal(t, "Warnung: ungültige IP-Adresse: 10.0.0.256", newLocalizer(language.German).message(msg))
+}
+
+func TestLocalizedWriter(t *testing.T) {
+	defer func(l *localizer) { loc = l }(loc)
+	loc = newLocalizer(language.MustParse("de-AT"))
+
+	s := &strings.Builder{}
+	l := log.New(localizedWriter{s}, log.Prefix(), 0)
+	l.Print("no default gateway: eth0")
+	Equal(t, log.Prefix()+"kein Standardgateway: eth0\n", s.String())
+}
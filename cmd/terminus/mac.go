@@ -0,0 +1,238 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/abc-inc/terminus/mac"
+	"github.com/spf13/cobra"
+)
+
+// ouiURL is the location of the IEEE MA-L registry.
+const ouiURL = "https://standards-oui.ieee.org/oui/oui.csv"
+
+var macCmd = &cobra.Command{
+	Use:   "mac COMMAND",
+	Short: "Normalize, validate, inspect and generate MAC addresses",
+	Args:  cobra.NoArgs,
+}
+
+var macNormalizeCmd = &cobra.Command{
+	Use:   "normalize [flags] MAC...",
+	Short: "Print MAC addresses in a uniform notation",
+	Example: `  terminus mac normalize 0011.2233.4455 00-11-22-33-44-55
+  # 00:11:22:33:44:55
+  # 00:11:22:33:44:55
+
+  terminus mac normalize --notation dot 00:11:22:33:44:55
+  # 0011.2233.4455`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runMACNormalizeCmd,
+}
+
+var macValidateCmd = &cobra.Command{
+	Use:   "validate MAC...",
+	Short: "Validate MAC addresses",
+	Long:  "Validate MAC addresses. Invalid addresses are reported and the exit status is 1.",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runMACValidateCmd,
+}
+
+var macInfoCmd = &cobra.Command{
+	Use:   "info MAC",
+	Short: "Print the properties of a MAC address",
+	Example: `  terminus mac info 00:11:22:33:44:55
+  # address:      00:11:22:33:44:55
+  # oui:          00:11:22
+  # type:         unicast
+  # scope:        universal
+  # interface-id: 0211:22ff:fe33:4455`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMACInfoCmd,
+}
+
+var macRandomCmd = &cobra.Command{
+	Use:   "random [flags]",
+	Short: "Generate random locally administered unicast MAC addresses",
+	Example: `  terminus mac random --count 2
+  # 56:3e:c1:0b:7d:92
+  # 9a:10:4f:e2:63:08`,
+	Args: cobra.NoArgs,
+	Run:  runMACRandomCmd,
+}
+
+var macInterfaceIDCmd = &cobra.Command{
+	Use:   "interface-id MAC",
+	Short: "Calculate the modified EUI-64 IPv6 interface identifier of a MAC address",
+	Example: `  terminus mac interface-id 00:11:22:33:44:55
+  # 0211:22ff:fe33:4455`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMACInterfaceIDCmd,
+}
+
+var macVendorCmd = &cobra.Command{
+	Use:   "vendor MAC...",
+	Short: "Look up the vendors of MAC addresses",
+	Long: `Look up the organizations, to which the OUIs of MAC addresses are registered.
+The IEEE registry is downloaded on first use and cached.
+Set the environment variable TERMINUS_OUI_DB to use another file or URL.`,
+	Example: `  terminus mac vendor 00:11:22:33:44:55
+  # 00:11:22:33:44:55  CIMSYS Inc`,
+	Args:        cobra.MinimumNArgs(1),
+	Run:         runMACVendorCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	macNormalizeCmd.Flags().String("notation", "colon", "MAC address notation (colon, dash, dot, bare)")
+	macRandomCmd.Flags().Int("count", 1, "Number of MAC addresses")
+	macCmd.AddCommand(macNormalizeCmd, macValidateCmd, macInfoCmd, macRandomCmd, macInterfaceIDCmd, macVendorCmd)
+	rootCmd.AddCommand(macCmd)
+}
+
+func runMACNormalizeCmd(cmd *cobra.Command, args []string) {
+	notation, _ := cmd.Flags().GetString("notation")
+	for _, arg := range args {
+		hw, err := net.ParseMAC(arg)
+		if err != nil {
+			fatal(err)
+		}
+		s, err := mac.Format(hw, notation)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(s)
+	}
+}
+
+func runMACValidateCmd(_ *cobra.Command, args []string) {
+	if !validateMACs(os.Stderr, args) {
+		os.Exit(1)
+	}
+}
+
+func runMACInfoCmd(_ *cobra.Command, args []string) {
+	hw, err := net.ParseMAC(args[0])
+	if err != nil {
+		fatal(err)
+	}
+	printMACInfo(os.Stdout, hw)
+}
+
+func runMACRandomCmd(cmd *cobra.Command, _ []string) {
+	count, _ := cmd.Flags().GetInt("count")
+	for i := 0; i < count; i++ {
+		hw, err := mac.Random(randReader)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(hw)
+	}
+}
+
+func runMACInterfaceIDCmd(_ *cobra.Command, args []string) {
+	hw, err := net.ParseMAC(args[0])
+	if err != nil {
+		fatal(err)
+	}
+	id, err := mac.EUI64(hw)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Println(formatInterfaceID(id))
+}
+
+func runMACVendorCmd(_ *cobra.Command, args []string) {
+	v, err := loadVendors()
+	if err != nil {
+		fatal(err)
+	}
+	if err = printVendors(os.Stdout, v, args); err != nil {
+		fatal(err)
+	}
+}
+
+// loadVendors reads the IEEE registry from TERMINUS_OUI_DB (if set) or downloads the MA-L registry.
+func loadVendors() (mac.Vendors, error) {
+	name := os.Getenv("TERMINUS_OUI_DB")
+	if name == "" {
+		name = ouiURL
+	}
+
+	b, err := readInputFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return mac.ParseVendors(bytes.NewReader(b))
+}
+
+// printVendors prints every MAC address along with its vendor (or "unknown").
+func printVendors(w io.Writer, v mac.Vendors, args []string) error {
+	for _, arg := range args {
+		hw, err := net.ParseMAC(arg)
+		if err != nil {
+			return err
+		}
+		org, ok := v.Lookup(hw)
+		if !ok {
+			org = "unknown"
+		}
+		_, _ = fmt.Fprintf(w, "%v  %s\n", hw, org)
+	}
+	return nil
+}
+
+// validateMACs reports every invalid MAC address and returns whether all addresses are valid.
+func validateMACs(w io.Writer, args []string) bool {
+	ok := true
+	for _, arg := range args {
+		if _, err := net.ParseMAC(arg); err != nil {
+			_, _ = fmt.Fprintln(w, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// printMACInfo prints the OUI, the individual/group bit and the universal/local bit of hw.
+func printMACInfo(w io.Writer, hw net.HardwareAddr) {
+	typ, scope := "unicast", "universal"
+	if mac.IsBroadcast(hw) {
+		typ = "broadcast"
+	} else if mac.IsMulticast(hw) {
+		typ = "multicast"
+	}
+	if mac.IsLocal(hw) {
+		scope = "local"
+	}
+
+	_, _ = fmt.Fprintf(w, "address:      %v\n", hw)
+	_, _ = fmt.Fprintf(w, "oui:          %v\n", hw[:3])
+	_, _ = fmt.Fprintf(w, "type:         %s\n", typ)
+	_, _ = fmt.Fprintf(w, "scope:        %s\n", scope)
+	if id, err := mac.EUI64(hw); err == nil {
+		_, _ = fmt.Fprintf(w, "interface-id: %s\n", formatInterfaceID(id))
+	}
+}
+
+// formatInterfaceID formats a 64-bit IPv6 interface identifier as four groups of hexadecimal digits.
+func formatInterfaceID(id []byte) string {
+	return fmt.Sprintf("%x:%x:%x:%x", id[0:2], id[2:4], id[4:6], id[6:8])
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestValidateMACs(t *testing.T) {
+	s := &strings.Builder{}
+	True(t, validateMACs(s, []string{"00:11:22:33:44:55", "0011.2233.4455"}))
+	Empty(t, s.String())
+
+	False(t, validateMACs(s, []string{"00:11:22:33:44:55", "00:11:22", "xyz"}))
+	Equal(t, "address 00:11:22: invalid MAC address\naddress xyz: invalid MAC address\n", s.String())
+}
+
+func TestPrintMACInfo(t *testing.T) {
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	s := &strings.Builder{}
+	printMACInfo(s, hw)
+	Equal(t, `address:      00:11:22:33:44:55
+oui:          00:11:22
+type:         unicast
+scope:        universal
+interface-id: 0211:22ff:fe33:4455
+`, s.String())
+
+	hw, _ = net.ParseMAC("ff:ff:ff:ff:ff:ff")
+	s.Reset()
+	printMACInfo(s, hw)
+	Contains(t, s.String(), "type:         broadcast\nscope:        local\n")
+
+	hw, _ = net.ParseMAC("01:00:5e:00:00:fb:00:01")
+	s.Reset()
+	printMACInfo(s, hw)
+	Equal(t, `address:      01:00:5e:00:00:fb:00:01
+oui:          01:00:5e
+type:         multicast
+scope:        universal
+`, s.String())
+}
+
+func TestPrintVendors(t *testing.T) {
+	v := mac.Vendors{"001122": "CIMSYS Inc"}
+	s := &strings.Builder{}
+	NoError(t, printVendors(s, v, []string{"0011.2233.4455", "02-00-00-00-00-01"}))
+	Equal(t, "00:11:22:33:44:55  CIMSYS Inc\n02:00:00:00:00:01  unknown\n", s.String())
+
+	EqualError(t, printVendors(s, v, []string{"xyz"}), "address xyz: invalid MAC address")
+}
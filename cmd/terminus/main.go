@@ -15,21 +15,23 @@
 package main
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net"
+	"net/netip"
 	"os"
-	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/abc-inc/terminus/iface"
 	"github.com/c-robinson/iplib"
 	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 )
 
 var version = "0"
@@ -38,14 +40,20 @@ var rootCmd = &cobra.Command{
 	Use: `terminus [flags] IP
   terminus [flags] IP/PREFIX_LEN
   terminus [flags] INTERFACE
-  terminus [-L | --list-interfaces]`,
+  terminus [-L | --list-interfaces]
+  terminus COMMAND [flags]`,
 	Short: "terminus is an IP subnet address calculator.",
 	Long: `terminus is an IP subnet address calculator.
 For a given IPv4 address (and optional prefix length), ` +
-		`it calculates network address, broadcast address, maximum number of hosts, etc.`,
-	Run: runRootCmd,
+		`it calculates network address, broadcast address, maximum number of hosts, etc.
+Invoking terminus without a command is equivalent to "terminus info" (or "terminus list" with --list-interfaces).`,
+	Args:             cobra.ArbitraryArgs,
+	Run:              runRootCmd,
+	PersistentPreRun: preRun,
+	Annotations:      map[string]string{sandboxNetwork: "verbose,hosts,resolve"},
 	Example: `  terminus -i eth0                # 172.16.57.200
   terminus -p 10.0.0.138          # 8
+  terminus -g eth0                # 172.16.56.1
   terminus -b 192.168.100.1/24    # 192.168.100.255
 
   terminus -f -l lo
@@ -60,6 +68,17 @@ For a given IPv4 address (and optional prefix length), ` +
   # 10.197.63.254/11 (10.192.0.0 - 10.223.255.255)`,
 }
 
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information and exit",
+	Args:  cobra.NoArgs,
+	Run:   runVersionCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
+
 func main() {
 	log.SetPrefix("terminus: ")
 	log.SetFlags(0)
@@ -69,31 +88,31 @@ func main() {
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	rootCmd.Flags().SortFlags = false
-	rootCmd.Flags().BoolP(iface.Broadcast, "b", false, "Show the broadcast address of the subnet")
-	rootCmd.Flags().BoolP(iface.First, "f", false, "Show the first usable IP address of the subnet")
 	rootCmd.Flags().BoolP("help", "h", false, "Print this help information and exit")
-	rootCmd.Flags().BoolP(iface.IP, "i", false, "Show the IP address")
-	rootCmd.Flags().BoolP(iface.Last, "l", false, "Show the last usable IP address of the subnet")
 	rootCmd.Flags().BoolP("list-interfaces", "L", false, "List all network interfaces")
-	rootCmd.Flags().BoolP(iface.NetMask, "m", false, "Show the subnet mask in dot-decimal notation")
-	rootCmd.Flags().Bool(iface.Name, false, "Show the name of the network interface (if possible)")
-	rootCmd.Flags().BoolP(iface.Network, "n", false, "Show the network address")
-	rootCmd.Flags().BoolP(iface.Prefix, "p", false, "Show the prefix length")
-	rootCmd.Flags().BoolP("range", "r", false, "Show the IP range of the subnet")
-	rootCmd.Flags().BoolP(iface.Size, "s", false, "Count the total number of IPs of the subnet")
-	rootCmd.Flags().StringP("template", "t", "", "Format the output with the given template expression")
-	rootCmd.Flags().BoolP(iface.UsableSize, "u", false, "Count the number of hosts of the subnet")
+	rootCmd.Flags().Bool("verbose", false, "List the MAC addresses and vendors of the network interfaces as well")
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information and exit")
-	rootCmd.Flags().BoolP(iface.Wildcard, "w", false, "Show the wildcard mask of the subnet")
+	addInfoFlags(rootCmd.Flags())
+	rootCmd.PersistentFlags().String("locale", "", "Localize counts, dates and messages for operators (e.g., de-DE)")
+	rootCmd.PersistentFlags().Bool("deterministic", false, "Generate reproducible outputs (fixed seed and time)")
+	rootCmd.PersistentFlags().Int64("seed", 0, "Seed the random number generator (e.g., for mac random)")
+	rootCmd.PersistentFlags().String("default-prefix", "classful",
+		"Prefix length of bare IP addresses: classful, host (/32, /128) or an IPv4 prefix length")
+	rootCmd.PersistentFlags().String("fixture", "", "Use the network interfaces and routes of a snapshot (see snapshot)")
+	rootCmd.PersistentFlags().Bool("sandbox", false, "Deny file and network access, which is not needed (Linux only)")
+	rootCmd.PersistentFlags().Int("buffer-size", defaultBufferSize, "Size of the output buffer in bytes")
+	registerCompletions(rootCmd)
 
 	if args, err := readFromPipe(); err != nil {
-		log.Fatal(err)
+		fatal(err)
 	} else if args != nil {
 		rootCmd.SetArgs(append(os.Args[1:], args...))
 	}
 
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		// errors returned by cobra are caused by invalid arguments or flags
+		log.Print(err)
+		os.Exit(exitParse)
 	}
 }
 
@@ -112,68 +131,50 @@ func readFromPipe() ([]string, error) {
 	return shellquote.Split(strings.TrimRight(string(in), "\n"))
 }
 
+// preRun prepares the execution of every command.
+func preRun(cmd *cobra.Command, args []string) {
+	if err := applyLocale(cmd); err != nil {
+		fatal(err)
+	}
+	if err := applyFixture(cmd); err != nil {
+		fatal(err)
+	}
+	if err := applyDefaultPrefix(cmd); err != nil {
+		fatal(err)
+	}
+	if err := applyDeterministic(cmd); err != nil {
+		fatal(err)
+	}
+	applySandbox(cmd, args)
+}
+
+// runRootCmd handles the legacy flag-based invocation, which is equivalent to the info and list commands.
 func runRootCmd(cmd *cobra.Command, args []string) {
 	switch {
 	case cmd.Flag("version").Changed:
-		_, _ = fmt.Fprintln(os.Stderr, "terminus version", version)
-		return
+		runVersionCmd(cmd, args)
 	case cmd.Flag("list-interfaces").Changed:
-		fmt.Print(listInterfaces())
-		return
-	case strings.Contains(cmd.Flag("template").Value.String(), ".interfaces"):
-		// if the template refers to interfaces by name, the positional argument is optional
-	case len(args) == 0:
-		_ = cmd.Usage()
-		os.Exit(1)
-	}
-
-	data := map[string]interface{}{}
-	if len(args) > 0 {
-		arg := args[len(args)-1]
-		ip, n, err := determineIP(arg)
-		if err != nil {
-			log.Fatal(err)
-		}
-		data = iface.GetParams(arg, ip, n.Mask)
-	}
-
-	s := &strings.Builder{}
-	cmd.Flags().Visit(func(f *pflag.Flag) {
-		switch f.Name {
-		case "range":
-			_, _ = fmt.Fprintf(s, "%v - %v\n", data[iface.Network], data[iface.Broadcast])
-		case "template":
-			text, _ := cmd.Flags().GetString("template")
-			printTemplate(text, s, data)
-		default:
-			_, _ = fmt.Fprintln(s, data[f.Name])
-		}
-	})
-	fmt.Print(s)
-}
-
-func listInterfaces() string {
-	is, err := net.Interfaces()
-	if err != nil {
-		log.Fatal(err)
+		runListCmd(cmd, args)
+	default:
+		runInfoCmd(cmd, args)
 	}
+}
 
-	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
-	s := &strings.Builder{}
-	for _, i := range is {
-		if ip, n, err := determineIP(i.Name); err == nil {
-			data := iface.GetParams(i.Name, ip, n.Mask)
-			_, _ = fmt.Fprintf(s, "%s\t%v\t%v\t%v\n", data[iface.Name], data[iface.IP], data[iface.Network], data[iface.Prefix])
-		}
-	}
-	return s.String()
+// runVersionCmd prints the version information.
+func runVersionCmd(*cobra.Command, []string) {
+	_, _ = fmt.Fprintln(os.Stderr, "terminus version", version)
 }
 
+// determineIP returns the IP address and network of an IP address, a CIDR, an alias or a network interface.
+// Arguments, which consist of hex digits, dots, colons and slashes only, are not looked up as alias or interface.
 func determineIP(arg string) (net.IP, iplib.Net, error) {
+	if strings.Contains(arg, "%") {
+		return determineZonedIP(arg)
+	}
+
 	ip := net.ParseIP(arg)
 	if ip != nil {
-		size, _ := ip.DefaultMask().Size()
-		return ip, iplib.NewNet(ip, size), nil
+		return ip, iplib.NewNet(ip, bareIPPrefix(ip)), nil
 	}
 
 	ip, ipNet, err := net.ParseCIDR(arg)
@@ -182,46 +183,227 @@ func determineIP(arg string) (net.IP, iplib.Net, error) {
 		return ip, iplib.NewNet(ip, size), nil
 	}
 
-	ip, n, err := iface.GetAddr(arg)
+	if i := strings.LastIndexByte(arg, '/'); i > 0 && net.ParseIP(arg[:i]).To4() != nil {
+		// IP/NETMASK, where the subnet mask is given in dot-decimal or hexadecimal notation
+		if size, ok := parseMask(arg[i+1:]); ok {
+			ip = net.ParseIP(arg[:i]).To4()
+			return ip, iplib.NewNet(ip, size), nil
+		}
+		return nil, iplib.Net{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+	}
+
+	if strings.ContainsAny(arg, ".:/") && strings.Trim(arg, "0123456789abcdefABCDEF.:/") == "" {
+		return nil, iplib.Net{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+	}
+	if s, ok, err := lookupAlias(arg); err != nil {
+		return nil, iplib.Net{}, err
+	} else if ok {
+		return determineIP(s)
+	}
+	return interfaceAddr(arg)
+}
+
+const (
+	// classfulPrefix determines the prefix length of bare IPv4 addresses by their address class.
+	classfulPrefix = -1
+	// hostPrefix treats bare IPv4 and IPv6 addresses as host routes (/32 and /128).
+	hostPrefix = -2
+)
+
+// defaultPrefix is the prefix length of bare IPv4 addresses, classfulPrefix or hostPrefix (--default-prefix).
+var defaultPrefix = classfulPrefix
+
+// applyDefaultPrefix sets defaultPrefix according to --default-prefix or the environment variable
+// TERMINUS_DEFAULT_PREFIX (classful, host or the prefix length of IPv4 addresses).
+func applyDefaultPrefix(cmd *cobra.Command) error {
+	s, _ := cmd.Flags().GetString("default-prefix")
+	if !cmd.Flags().Changed("default-prefix") && os.Getenv("TERMINUS_DEFAULT_PREFIX") != "" {
+		s = os.Getenv("TERMINUS_DEFAULT_PREFIX")
+	}
+
+	switch s {
+	case "", "classful":
+		defaultPrefix = classfulPrefix
+	case "host":
+		defaultPrefix = hostPrefix
+	default:
+		size, err := strconv.Atoi(s)
+		if err != nil || size < 0 || size > 32 {
+			return fmt.Errorf("invalid default prefix (must be classful, host or 0-32): %s", s)
+		}
+		defaultPrefix = size
+	}
+	return nil
+}
+
+// bareIPPrefix returns the prefix length of an IP address without prefix length according to defaultPrefix.
+// IPv6 addresses are only affected by hostPrefix.
+func bareIPPrefix(ip net.IP) int {
+	switch {
+	case defaultPrefix == hostPrefix && ip.To4() != nil:
+		return 32
+	case defaultPrefix == hostPrefix:
+		return 128
+	case defaultPrefix >= 0 && ip.To4() != nil:
+		return defaultPrefix
+	}
+	size, _ := ip.DefaultMask().Size()
+	return size
+}
+
+var (
+	// addrIndex selects the n-th IP address of network interfaces (--index).
+	// If it is negative and addrFamily is 0, the first IPv4 address is selected.
+	addrIndex = -1
+	// addrFamily restricts the IP addresses of network interfaces to IPv4 (4) or IPv6 (6) if it is not 0 (--family).
+	addrFamily = 0
+)
+
+// interfaceAddr returns the IP address and network of the interface specified by name, which is selected by
+// addrIndex and addrFamily.
+func interfaceAddr(name string) (net.IP, iplib.Net, error) {
+	if addrIndex < 0 && addrFamily == 0 {
+		return iface.GetAddr(name)
+	}
+	ns, err := iface.GetAddrs(name)
 	if err != nil {
-		return nil, n, err
+		return nil, iplib.Net{}, err
+	}
+
+	var sel []*net.IPNet
+	for _, n := range ns {
+		if _, bits := n.Mask.Size(); addrFamily == 0 || (bits == 32) == (addrFamily == 4) {
+			sel = append(sel, n)
+		}
+	}
+	i := addrIndex
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sel) {
+		kind := "IP"
+		if addrFamily != 0 {
+			kind = "IPv" + strconv.Itoa(addrFamily)
+		}
+		return nil, iplib.Net{}, fmt.Errorf("%w: %s (index %d of %d %s addresses)",
+			iface.ErrNoAddress, name, i, len(sel), kind)
+	}
+	size, _ := sel[i].Mask.Size()
+	return sel[i].IP, iplib.NewNet(sel[i].IP, size), nil
+}
+
+// determineZonedIP returns the IP address and network of an IPv6 address with zone (e.g., fe80::1%eth0 or
+// fe80::1%eth0/64). Unless the prefix length is given, it is taken from the interface, which is identified by the zone
+// (name or index). If the interface does not have that address, the prefix length defaults to 64.
+func determineZonedIP(arg string) (net.IP, iplib.Net, error) {
+	addr, bits, hasBits := strings.Cut(arg, "/")
+	a, zone, _ := strings.Cut(addr, "%")
+	ip := net.ParseIP(a)
+	if ip == nil || ip.To4() != nil || zone == "" {
+		return nil, iplib.Net{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+	}
+
+	size := 64
+	if hasBits {
+		var err error
+		if size, err = strconv.Atoi(bits); err != nil || size < 0 || size > 128 {
+			return nil, iplib.Net{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+		}
+	} else if ns, err := iface.GetAddrs(zone); err == nil {
+		for _, n := range ns {
+			if n.IP.Equal(ip) {
+				size, _ = n.Mask.Size()
+			}
+		}
 	}
-	return ip, n, nil
+	return ip, iplib.NewNet(ip, size), nil
 }
 
-func printTemplate(text string, w io.Writer, data map[string]interface{}) {
+// parseMask returns the prefix length of an IPv4 subnet mask in dot-decimal (255.255.255.0) or hexadecimal notation
+// (0xffffff00 or ffffff00). Non-contiguous masks are rejected.
+func parseMask(s string) (int, bool) {
+	m := net.IPMask(net.ParseIP(s).To4())
+	if !strings.Contains(s, ".") {
+		m, _ = hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	}
+	if len(m) != net.IPv4len {
+		return 0, false
+	}
+	size, bits := m.Size()
+	return size, bits != 0
+}
+
+// toPrefix converts the network n to a netip.Prefix.
+func toPrefix(n iplib.Net) netip.Prefix {
+	a, _ := netip.AddrFromSlice(n.IP)
+	size, _ := n.Mask.Size()
+	return netip.PrefixFrom(a.Unmap(), size)
+}
+
+// printTemplate renders the template with the given data, handling missing properties according to missingKey.
+// If the template cannot be parsed, the error wraps ErrInvalidTemplate.
+func printTemplate(text string, w io.Writer, data map[string]interface{}) error {
 	if !strings.HasSuffix(text, "\n") {
 		text += "\n"
 	}
 
+	opt, def, fill := missingKeyOption()
 	t, err := template.New("tmpl").
-		Option("missingkey=zero").
-		Funcs(template.FuncMap{
-			"toBinary": toBinary,
-			"toHex":    toHex,
-			"toJson":   toJSON,
-		}).Parse(text)
+		Option(opt).
+		Funcs(funcMap()).Parse(text)
 
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
 	}
 
 	if strings.Contains(text, ".interfaces") {
 		ifByName := map[string]interface{}{}
 		data["interfaces"] = ifByName
 
-		is, _ := net.Interfaces()
+		is, _ := iface.Interfaces()
 		for _, i := range is {
 			ip, n, _ := iface.GetAddr(i.Name)
 			ifByName[i.Name] = iface.GetParams(i.Name, ip, n.Mask)
 		}
 	}
+	addContext(text, data)
+	if fill {
+		fillDefaults(t, data, def)
+	}
+
+	return t.Execute(w, data)
+}
 
-	if err := t.Execute(w, data); err != nil {
-		log.Fatal(err)
+// addContext adds the environment variables, the host name and the current time to data if text refers to them.
+// The host name of the machine is not added if data contains a host name already (e.g., the PTR name of --resolve).
+func addContext(text string, data map[string]interface{}) {
+	if strings.Contains(text, ".env") {
+		env := map[string]interface{}{}
+		for _, kv := range os.Environ() {
+			if k, v, ok := strings.Cut(kv, "="); ok && k != "" {
+				env[k] = v
+			}
+		}
+		data["env"] = env
+	}
+	if _, ok := data["hostname"]; !ok && strings.Contains(text, ".hostname") {
+		data["hostname"], _ = os.Hostname()
+	}
+	if strings.Contains(text, ".now") {
+		data["now"] = templateTime{now()}
 	}
 }
 
+// templateTime is a point in time, which is formatted according to RFC 3339 unless a layout is given
+// (e.g., {{.now.Format "2006-01-02"}}).
+type templateTime struct {
+	time.Time
+}
+
+func (t templateTime) String() string {
+	return t.Format(time.RFC3339)
+}
+
 func toBinary(ip net.IP) string {
 	ip = ip.To4()
 	return fmt.Sprintf("%08b.%08b.%08b.%08b", ip[0], ip[1], ip[2], ip[3])
@@ -231,10 +413,7 @@ func toHex(ip net.IP) string {
 	return "0x" + net.IPMask(ip.To4()).String()
 }
 
-func toJSON(i interface{}) string {
+func toJSON(i interface{}) (string, error) {
 	j, err := json.Marshal(i)
-	if err != nil {
-		log.Fatal(err)
-	}
-	return string(j)
+	return string(j), err
 }
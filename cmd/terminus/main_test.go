@@ -18,11 +18,14 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
 	. "github.com/stretchr/testify/require"
 )
 
@@ -52,7 +55,7 @@ func TestPrintTemplate(t *testing.T) {
 		tt := tests[i]
 		t.Run(tt.prop, func(t *testing.T) {
 			s := &strings.Builder{}
-			printTemplate("{{."+tt.prop+"}}", s, data)
+			NoError(t, printTemplate("{{."+tt.prop+"}}", s, data))
 			Equal(t, tt.want+"\n", s.String())
 		})
 	}
@@ -62,7 +65,7 @@ func TestPrintTemplateInterfaces(t *testing.T) {
 	ip, n, _ := net.ParseCIDR("127.0.0.1/8")
 	data := iface.GetParams(ip.String(), ip, n.Mask)
 	s := &strings.Builder{}
-	printTemplate(fmt.Sprintf("{{.interfaces.%s.ip}}", data[iface.Name]), s, data)
+	NoError(t, printTemplate(fmt.Sprintf("{{.interfaces.%s.ip}}", data[iface.Name]), s, data))
 	Equal(t, ip.String()+"\n", s.String())
 }
 
@@ -85,33 +88,45 @@ func TestPrintTemplateFunctions(t *testing.T) {
 		tt := tests[i]
 		t.Run(tt.tmpl, func(t *testing.T) {
 			s := &strings.Builder{}
-			printTemplate(tt.tmpl, s, data)
+			NoError(t, printTemplate(tt.tmpl, s, data))
 			Equal(t, tt.want+"\n", s.String())
 		})
 	}
 }
 
-func TestPrintTemplateNoData(t *testing.T) {
-	data := map[string]interface{}{}
+func TestPrintTemplateContext(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2024, 5, 4, 10, 20, 30, 0, time.UTC) }
+	t.Setenv("TERMINUS_TEST", "42")
+	host, _ := os.Hostname()
+
 	s := &strings.Builder{}
-	printTemplate("{{.name}}", s, data)
-	Equal(t, "<no value>\n", s.String())
-}
+	NoError(t, printTemplate(`{{.env.TERMINUS_TEST}} {{.hostname}} {{.now}} {{.now.Format "2006-01-02"}}`, s,
+		map[string]interface{}{}))
+	Equal(t, "42 "+host+" 2024-05-04T10:20:30Z 2024-05-04\n", s.String())
 
-func TestListInterfaces(t *testing.T) {
-	is, err := net.Interfaces()
-	NoError(t, err)
-	NotEmpty(t, is)
+	data := map[string]interface{}{}
+	NoError(t, printTemplate("{{.ip}}", &strings.Builder{}, data))
+	NotContains(t, data, "env")
+	NotContains(t, data, "now")
 
-	s := listInterfaces()
-	Contains(t, s, "127.0.0.1")
+	s.Reset()
+	data = map[string]interface{}{"hostname": "host1.example.com"}
+	NoError(t, printTemplate("{{.hostname}}", s, data))
+	Equal(t, "host1.example.com\n", s.String())
+}
 
-	for _, i := range is {
-		if ip, _, err := iface.GetAddr(i.Name); err == nil {
-			Contains(t, s, i.Name)
-			Contains(t, s, ip.String())
-		}
-	}
+func TestPrintTemplateInvalid(t *testing.T) {
+	err := printTemplate("{{.ip", &strings.Builder{}, map[string]interface{}{})
+	ErrorIs(t, err, ErrInvalidTemplate)
+	Equal(t, exitParse, exitCode(err))
+}
+
+func TestPrintTemplateNoData(t *testing.T) {
+	data := map[string]interface{}{}
+	s := &strings.Builder{}
+	NoError(t, printTemplate("{{.name}}", s, data))
+	Equal(t, "<no value>\n", s.String())
 }
 
 func TestDetermineIP(t *testing.T) {
@@ -122,6 +137,81 @@ func TestDetermineIP(t *testing.T) {
 	NoError(t, err)
 }
 
+func TestDetermineIPDefaultPrefix(t *testing.T) {
+	defer func() { defaultPrefix = classfulPrefix }()
+	tests := []struct {
+		prefix int
+		arg    string
+		want   string
+	}{
+		{classfulPrefix, "10.0.0.138", "10.0.0.0/8"},
+		{classfulPrefix, "192.168.1.1", "192.168.1.0/24"},
+		{hostPrefix, "10.0.0.138", "10.0.0.138/32"},
+		{hostPrefix, "2001:db8::1", "2001:db8::1/128"},
+		{24, "10.0.0.138", "10.0.0.0/24"},
+		{24, "10.0.0.138/16", "10.0.0.0/16"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want, func(t *testing.T) {
+			defaultPrefix = tt.prefix
+			_, n, err := determineIP(tt.arg)
+			NoError(t, err)
+			Equal(t, tt.want, n.String())
+		})
+	}
+}
+
+func TestApplyDefaultPrefix(t *testing.T) {
+	defer func() { defaultPrefix = classfulPrefix }()
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("default-prefix", "classful", "")
+		NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	NoError(t, applyDefaultPrefix(newCmd("--default-prefix", "host")))
+	Equal(t, hostPrefix, defaultPrefix)
+	NoError(t, applyDefaultPrefix(newCmd("--default-prefix", "32")))
+	Equal(t, 32, defaultPrefix)
+	NoError(t, applyDefaultPrefix(newCmd()))
+	Equal(t, classfulPrefix, defaultPrefix)
+
+	t.Setenv("TERMINUS_DEFAULT_PREFIX", "16")
+	NoError(t, applyDefaultPrefix(newCmd()))
+	Equal(t, 16, defaultPrefix)
+	NoError(t, applyDefaultPrefix(newCmd("--default-prefix", "classful")))
+	Equal(t, classfulPrefix, defaultPrefix)
+
+	EqualError(t, applyDefaultPrefix(newCmd("--default-prefix", "33")),
+		"invalid default prefix (must be classful, host or 0-32): 33")
+}
+
+func TestDetermineIPInvalid(t *testing.T) {
+	_, _, err := determineIP("10.0.0.1/33")
+	EqualError(t, err, "invalid IP address or CIDR: 10.0.0.1/33")
+	ErrorIs(t, err, ErrInvalidCIDR)
+
+	_, _, err = determineIP("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+	ErrorIs(t, err, iface.ErrNoSuchInterface)
+}
+
+func TestDetermineIPNetmask(t *testing.T) {
+	for _, arg := range []string{"192.168.1.10/255.255.255.0", "192.168.1.10/0xffffff00", "192.168.1.10/ffffff00"} {
+		ip, n, err := determineIP(arg)
+		NoError(t, err)
+		Equal(t, "192.168.1.10", ip.String())
+		Equal(t, "192.168.1.0/24", n.String())
+	}
+
+	_, _, err := determineIP("192.168.1.10/255.0.255.0")
+	ErrorIs(t, err, ErrInvalidCIDR)
+	_, _, err = determineIP("192.168.1.10/0xffff")
+	ErrorIs(t, err, ErrInvalidCIDR)
+}
+
 func TestDetermineIPCIDR(t *testing.T) {
 	ip, n, err := determineIP("127.0.100.1/24")
 	Equal(t, "127.0.100.1", ip.String())
@@ -129,3 +219,90 @@ func TestDetermineIPCIDR(t *testing.T) {
 	Equal(t, "ffffff00", n.Mask.String())
 	NoError(t, err)
 }
+
+func TestInterfaceAddr(t *testing.T) {
+	defer func() { addrIndex, addrFamily = -1, 0 }()
+	defer func() { _ = iface.UseSnapshot(nil) }()
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{
+		{Name: "eth0", Addrs: []string{"fe80::1/64", "192.0.2.2/24", "2001:db8::2/64", "198.51.100.2/25"}},
+	}}))
+
+	tests := []struct {
+		index, family int
+		want          string
+	}{
+		{-1, 0, "192.0.2.2/24"},
+		{0, 0, "fe80::1/64"},
+		{1, 4, "198.51.100.2/25"},
+		{-1, 6, "fe80::1/64"},
+		{1, 6, "2001:db8::2/64"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want, func(t *testing.T) {
+			addrIndex, addrFamily = tt.index, tt.family
+			ip, n, err := determineIP("eth0")
+			NoError(t, err)
+			size, _ := n.Mask.Size()
+			Equal(t, tt.want, ip.String()+"/"+strconv.Itoa(size))
+		})
+	}
+
+	addrIndex, addrFamily = 2, 6
+	_, _, err := determineIP("eth0")
+	EqualError(t, err, "no IP address: eth0 (index 2 of 2 IPv6 addresses)")
+	ErrorIs(t, err, iface.ErrNoAddress)
+}
+
+func TestApplyAddrSelection(t *testing.T) {
+	defer func() { addrIndex, addrFamily = -1, 0 }()
+	cmd := &cobra.Command{}
+	addInfoFlags(cmd.Flags())
+
+	NoError(t, cmd.ParseFlags([]string{"--index", "2", "--family", "6"}))
+	NoError(t, applyAddrSelection(cmd))
+	Equal(t, 2, addrIndex)
+	Equal(t, 6, addrFamily)
+
+	NoError(t, cmd.ParseFlags([]string{"--family", "ipx"}))
+	EqualError(t, applyAddrSelection(cmd), "invalid family (must be 4 or 6): ipx")
+	NoError(t, cmd.ParseFlags([]string{"--index", "-1"}))
+	EqualError(t, applyAddrSelection(cmd), "invalid index (must not be negative): -1")
+}
+
+func TestDetermineIPZone(t *testing.T) {
+	defer func() { _ = iface.UseSnapshot(nil) }()
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{
+		{Index: 2, Name: "eth0", Addrs: []string{"fe80::1/64"}},
+		{Index: 3, Name: "eth1", Addrs: []string{"fe80::1/80"}},
+	}}))
+
+	tests := []struct {
+		arg  string
+		want string
+		name string
+	}{
+		{"fe80::1%eth0", "fe80::/64", "eth0"},
+		{"fe80::1%eth1", "fe80::/80", "eth1"},
+		{"fe80::1%3", "fe80::/80", "eth1"},
+		{"fe80::1%eth1/10", "fe80::/10", "eth1"},
+		{"fe80::2%eth9", "fe80::/64", "eth9"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.arg, func(t *testing.T) {
+			ip, n, err := determineIP(tt.arg)
+			NoError(t, err)
+			Equal(t, tt.want, n.String())
+
+			data := iface.GetParams(tt.arg, ip, n.Mask)
+			Equal(t, tt.name, data[iface.Name])
+			Equal(t, strings.Split(strings.Split(tt.arg, "%")[1], "/")[0], data[iface.Zone])
+		})
+	}
+
+	for _, arg := range []string{"fe80::1%", "10.0.0.1%eth0", "fe80::1%eth0/129", "fe80::g%eth0"} {
+		_, _, err := determineIP(arg)
+		ErrorIs(t, err, ErrInvalidCIDR)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var maskCmd = &cobra.Command{
+	Use:   "mask [flags] VALUE",
+	Short: "Convert between prefix lengths, subnet masks, hexadecimal masks and wildcard masks",
+	Long: `Convert between prefix lengths, subnet masks, hexadecimal masks and wildcard masks.
+VALUE is a prefix length (24 or /24), a subnet mask (255.255.255.0 or ffff:ffff:ffff:ffff::), a hexadecimal mask
+(0xffffff00) or a wildcard mask (0.0.0.255) as used in ACLs.
+Prefix lengths up to 32 are IPv4 prefix lengths unless --family 6 is given.`,
+	Example: `  terminus mask 0.0.0.255
+  # prefix:   24
+  # netmask:  255.255.255.0
+  # hex:      0xffffff00
+  # wildcard: 0.0.0.255
+
+  terminus mask --family 6 /64`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMaskCmd,
+}
+
+func init() {
+	maskCmd.Flags().String("family", "", "Address family of prefix lengths (4, 6)")
+	maskCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(maskCmd)
+}
+
+func runMaskCmd(cmd *cobra.Command, args []string) {
+	family, _ := cmd.Flags().GetString("family")
+	m, err := parseMaskValue(args[0], family)
+	if err != nil {
+		fatal(err)
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if err := writeMask(os.Stdout, newMaskInfo(m), output); err != nil {
+		fatal(err)
+	}
+}
+
+// maskInfo holds the equivalent representations of a subnet mask.
+type maskInfo struct {
+	Prefix   int    `json:"prefix"`
+	Netmask  string `json:"netmask"`
+	Hex      string `json:"hex"`
+	Wildcard string `json:"wildcard"`
+}
+
+// newMaskInfo returns all representations of the subnet mask m.
+func newMaskInfo(m net.IPMask) maskInfo {
+	size, _ := m.Size()
+	return maskInfo{Prefix: size, Netmask: maskString(m), Hex: "0x" + m.String(), Wildcard: maskString(invertMask(m))}
+}
+
+// invertMask converts a subnet mask into a wildcard mask and vice versa.
+func invertMask(m net.IPMask) net.IPMask {
+	w := make(net.IPMask, len(m))
+	for i := range m {
+		w[i] = ^m[i]
+	}
+	return w
+}
+
+// maskString formats a subnet mask (or wildcard mask) in dot-decimal notation or as IPv6 address.
+// Unlike net.IP.String, IPv6 masks are never written as IPv4-mapped addresses (e.g., ::ffff:ffff:ffff).
+func maskString(m net.IPMask) string {
+	a, _ := netip.AddrFromSlice(m)
+	if a.Is4In6() {
+		return fmt.Sprintf("::ffff:%x:%x", int(m[12])<<8|int(m[13]), int(m[14])<<8|int(m[15]))
+	}
+	return a.String()
+}
+
+// parseMaskValue parses a prefix length, a subnet mask, a hexadecimal mask or a wildcard mask.
+// Prefix lengths are IPv4 prefix lengths if they do not exceed 32, unless the family is 6.
+func parseMaskValue(s, family string) (net.IPMask, error) {
+	invalid := usageError("invalid mask (must be a prefix length, subnet mask, hex or wildcard mask): " + s)
+	if family != "" && family != "4" && family != "6" {
+		return nil, usageError("invalid family (must be 4 or 6): " + family)
+	}
+
+	if v := strings.TrimPrefix(s, "/"); v != "" && strings.Trim(v, "0123456789") == "" && len(v) <= 3 {
+		size, _ := strconv.Atoi(v)
+		bits := 32
+		if family == "6" || size > 32 && family == "" {
+			bits = 128
+		}
+		if size > bits {
+			return nil, invalid
+		}
+		return net.CIDRMask(size, bits), nil
+	}
+
+	var m net.IPMask
+	if ip := net.ParseIP(s); ip != nil && strings.Contains(s, ".") {
+		m = net.IPMask(ip.To4())
+	} else if ip != nil {
+		m = net.IPMask(ip)
+	} else if b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")); err == nil {
+		m = b
+	}
+	if len(m) != net.IPv4len && len(m) != net.IPv6len {
+		return nil, invalid
+	}
+
+	if isMask(m) {
+		return m, nil
+	}
+	// wildcard masks are inverted subnet masks
+	if w := invertMask(m); isMask(w) {
+		return w, nil
+	}
+	return nil, invalid
+}
+
+// isMask reports whether m is a valid subnet mask, i.e., its ones are contiguous.
+func isMask(m net.IPMask) bool {
+	_, bits := m.Size()
+	return bits != 0
+}
+
+// writeMask writes all representations of a subnet mask.
+func writeMask(w io.Writer, m maskInfo, output string) error {
+	switch output {
+	case "text":
+		_, _ = fmt.Fprintf(w, "prefix:   %d\n", m.Prefix)
+		_, _ = fmt.Fprintf(w, "netmask:  %s\n", m.Netmask)
+		_, _ = fmt.Fprintf(w, "hex:      %s\n", m.Hex)
+		_, _ = fmt.Fprintf(w, "wildcard: %s\n", m.Wildcard)
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(m)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
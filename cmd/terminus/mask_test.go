@@ -0,0 +1,81 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseMaskValue(t *testing.T) {
+	v4 := maskInfo{24, "255.255.255.0", "0xffffff00", "0.0.0.255"}
+	v6 := maskInfo{64, "ffff:ffff:ffff:ffff::", "0xffffffffffffffff0000000000000000", "::ffff:ffff:ffff:ffff"}
+	tests := []struct {
+		in     string
+		family string
+		want   maskInfo
+	}{
+		{"24", "", v4},
+		{"/24", "4", v4},
+		{"255.255.255.0", "", v4},
+		{"0xffffff00", "", v4},
+		{"FFFFFF00", "", v4},
+		{"0.0.0.255", "", v4},
+		{"64", "", v6},
+		{"/64", "6", v6},
+		{"ffff:ffff:ffff:ffff::", "", v6},
+		{"::ffff:ffff:ffff:ffff", "", v6},
+		{"0xffffffffffffffff0000000000000000", "", v6},
+		{"80", "", maskInfo{80, "ffff:ffff:ffff:ffff:ffff::", "0xffffffffffffffffffff000000000000", "::ffff:ffff:ffff"}},
+		{"0.0.0.0", "", maskInfo{0, "0.0.0.0", "0x00000000", "255.255.255.255"}},
+		{"32", "6", maskInfo{32, "ffff:ffff::", "0xffffffff000000000000000000000000", "::ffff:ffff:ffff:ffff:ffff:ffff"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := parseMaskValue(tt.in, tt.family)
+			NoError(t, err)
+			Equal(t, tt.want, newMaskInfo(m))
+		})
+	}
+}
+
+func TestParseMaskValueInvalid(t *testing.T) {
+	for _, s := range []string{"33x", "129", "255.0.255.0", "0xffff", "ffff:0:ffff::", "x"} {
+		_, err := parseMaskValue(s, "")
+		EqualError(t, err, "invalid mask (must be a prefix length, subnet mask, hex or wildcard mask): "+s)
+		Equal(t, exitParse, exitCode(err))
+	}
+	_, err := parseMaskValue("64", "4")
+	Equal(t, exitParse, exitCode(err))
+	_, err = parseMaskValue("24", "ipx")
+	EqualError(t, err, "invalid family (must be 4 or 6): ipx")
+	Equal(t, exitParse, exitCode(err))
+}
+
+func TestWriteMask(t *testing.T) {
+	m, err := parseMaskValue("21", "")
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, writeMask(s, newMaskInfo(m), "text"))
+	Equal(t, "prefix:   21\nnetmask:  255.255.248.0\nhex:      0xfffff800\nwildcard: 0.0.7.255\n", s.String())
+
+	s.Reset()
+	NoError(t, writeMask(s, newMaskInfo(m), "json"))
+	Equal(t, `{"prefix":21,"netmask":"255.255.248.0","hex":"0xfffff800","wildcard":"0.0.7.255"}`+"\n", s.String())
+}
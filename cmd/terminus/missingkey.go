@@ -0,0 +1,78 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// missingKey controls how templates handle properties, which are missing in the data:
+// "error" aborts the execution, "zero" renders the zero value and "default=VALUE" renders VALUE.
+var missingKey = "zero"
+
+// parseMissingKey validates the value of --missingkey.
+func parseMissingKey(s string) (string, error) {
+	if s == "error" || s == "zero" || strings.HasPrefix(s, "default=") {
+		return s, nil
+	}
+	return "", errors.New("invalid missingkey option (must be error, zero or default=VALUE): " + s)
+}
+
+// missingKeyOption returns the template option corresponding to missingKey and the default value (if any).
+func missingKeyOption() (opt, def string, ok bool) {
+	if def, ok = cutPrefix(missingKey, "default="); ok {
+		return "missingkey=zero", def, true
+	}
+	return "missingkey=" + missingKey, "", false
+}
+
+// fillDefaults adds def to data for every property referenced by the template, which is missing.
+// Missing intermediate properties like .interfaces.NAME are created as nested maps.
+// Properties, which are relative to the dot inside range and with blocks, are not considered.
+func fillDefaults(t *template.Template, data map[string]interface{}, def string) {
+	l := &linter{visit: func(_ parse.Node, ident []string) {
+		m := data
+		for i, k := range ident {
+			v, ok := m[k]
+			switch {
+			case !ok && i == len(ident)-1:
+				m[k] = def
+			case !ok:
+				nm := map[string]interface{}{}
+				m[k], m = nm, nm
+			default:
+				if m, ok = v.(map[string]interface{}); !ok {
+					return
+				}
+			}
+		}
+	}}
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil && tt.Tree.Root != nil {
+			l.tree = tt.Tree
+			l.walk(tt.Tree.Root, true)
+		}
+	}
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseMissingKey(t *testing.T) {
+	for _, s := range []string{"error", "zero", "default=", "default=n/a"} {
+		v, err := parseMissingKey(s)
+		NoError(t, err)
+		Equal(t, s, v)
+	}
+	_, err := parseMissingKey("invalid")
+	EqualError(t, err, "invalid missingkey option (must be error, zero or default=VALUE): invalid")
+}
+
+func TestExecTemplateMissingKey(t *testing.T) {
+	defer func(old string) { missingKey = old }(missingKey)
+
+	tests := []struct {
+		missingKey string
+		tmpl       string
+		want       string
+		wantErr    string
+	}{
+		{"zero", "{{.ip}} {{.gw}}", "192.0.2.1 <no value>\n", ""},
+		{"default=n/a", "{{.ip}} {{.gw}} {{$.gw}}", "192.0.2.1 n/a n/a\n", ""},
+		{"default=-", "{{.interfaces.eth9.ip}} {{if .vlan}}{{.vlan}}{{end}}", "- -\n", ""},
+		{"default=", "{{with .ip}}{{.}}{{end}}/{{.prefix}}", "192.0.2.1/\n", ""},
+		{"error", "{{.ip}}", "192.0.2.1\n", ""},
+		{"error", "{{.ip}}\n{{.gw}}", "",
+			`template: tmpl:2:2: executing "tmpl" at <.gw>: map has no entry for key "gw"`},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.missingKey+" "+tt.tmpl, func(t *testing.T) {
+			missingKey = tt.missingKey
+			s := &strings.Builder{}
+			err := printTemplate(tt.tmpl, s, map[string]interface{}{"ip": "192.0.2.1"})
+			if tt.wantErr != "" {
+				EqualError(t, err, tt.wantErr)
+				return
+			}
+			NoError(t, err)
+			Equal(t, tt.want, s.String())
+		})
+	}
+}
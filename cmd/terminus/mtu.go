@@ -0,0 +1,188 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/mtu"
+	"github.com/spf13/cobra"
+)
+
+var mtuCmd = &cobra.Command{
+	Use: `mtu [flags] MTU
+  terminus mtu [flags] INTERFACE
+  terminus mtu [flags] --payload SIZE`,
+	Short: "Calculate the payload size of tunnels",
+	Long: `Calculate the payload size of tunnels i.e., the largest inner packet that fits into the given MTU.
+If an interface is given, its MTU is used. With --payload, the MTU required for inner packets of the given
+size is calculated instead. The outer header is IPv4 (or IPv6 with --ipv6) without options.
+
+Supported encapsulations:
+` + encapsulationList(),
+	Example: `  terminus mtu 1500
+  # vxlan	1450
+  # geneve	1450
+  # gre	1476
+  # ...
+  # wireguard	1440
+
+  terminus mtu --encap wireguard --ipv6 eth0
+  # wireguard	1420
+
+  terminus mtu --encap vxlan --payload 1500
+  # vxlan	1550`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runMtuCmd,
+}
+
+func init() {
+	mtuCmd.Flags().StringSlice("encap", nil, "Encapsulations to calculate (default: all)")
+	mtuCmd.Flags().Bool("ipv6", false, "Calculate with an outer IPv6 header")
+	mtuCmd.Flags().Int("payload", 0, "Calculate the MTU required for inner packets of the given size")
+	mtuCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(mtuCmd)
+}
+
+func runMtuCmd(cmd *cobra.Command, args []string) {
+	names, _ := cmd.Flags().GetStringSlice("encap")
+	ipv6, _ := cmd.Flags().GetBool("ipv6")
+	payload, _ := cmd.Flags().GetInt("payload")
+	output, _ := cmd.Flags().GetString("output")
+
+	es, err := lookupEncapsulations(names)
+	if err != nil {
+		fatal(err)
+	}
+
+	var rs []mtuResult
+	if cmd.Flag("payload").Changed {
+		if len(args) > 0 {
+			fatal(usageError("either MTU/INTERFACE or --payload can be given"))
+		}
+		rs, err = requiredMTUs(es, payload, ipv6)
+	} else {
+		if len(args) == 0 {
+			_ = cmd.Usage()
+			os.Exit(1)
+		}
+		var m int
+		if m, err = parseMTU(args[0]); err == nil {
+			rs, err = payloads(es, m, ipv6)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	if err := writeMTUs(os.Stdout, rs, cmd.Flag("payload").Changed, output); err != nil {
+		fatal(err)
+	}
+}
+
+// encapsulationList lists the names and descriptions of all encapsulations for the help text.
+func encapsulationList() string {
+	s := &strings.Builder{}
+	for _, e := range mtu.Encapsulations {
+		_, _ = fmt.Fprintf(s, "  %-12s %s\n", e.Name, e.Description)
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// lookupEncapsulations returns the encapsulations with the given names (or all if names is empty).
+func lookupEncapsulations(names []string) ([]mtu.Encapsulation, error) {
+	if len(names) == 0 {
+		return mtu.Encapsulations, nil
+	}
+	es := make([]mtu.Encapsulation, len(names))
+	for i, n := range names {
+		var err error
+		if es[i], err = mtu.Lookup(n); err != nil {
+			return nil, err
+		}
+	}
+	return es, nil
+}
+
+// parseMTU returns the given MTU or the MTU of the network interface with the given name.
+func parseMTU(arg string) (int, error) {
+	if m, err := strconv.Atoi(arg); err == nil {
+		return m, nil
+	}
+	i, err := iface.InterfaceByName(arg)
+	if err != nil {
+		return 0, err
+	}
+	return i.MTU, nil
+}
+
+// mtuResult is the MTU along with the payload size of an encapsulation.
+type mtuResult struct {
+	Encapsulation string `json:"encapsulation"`
+	MTU           int    `json:"mtu"`
+	Payload       int    `json:"payload"`
+	Overhead      int    `json:"overhead"`
+}
+
+// payloads calculates the payload size of every encapsulation.
+func payloads(es []mtu.Encapsulation, m int, ipv6 bool) ([]mtuResult, error) {
+	rs := make([]mtuResult, len(es))
+	for i, e := range es {
+		p, err := e.Payload(m, ipv6)
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = mtuResult{e.Name, m, p, m - p}
+	}
+	return rs, nil
+}
+
+// requiredMTUs calculates the MTU required by every encapsulation.
+func requiredMTUs(es []mtu.Encapsulation, p int, ipv6 bool) ([]mtuResult, error) {
+	rs := make([]mtuResult, len(es))
+	for i, e := range es {
+		m, err := e.MTU(p, ipv6)
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = mtuResult{e.Name, m, p, m - p}
+	}
+	return rs, nil
+}
+
+// writeMTUs prints the payload sizes (or the required MTUs if inverse is true).
+func writeMTUs(w io.Writer, rs []mtuResult, inverse bool, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			v := r.Payload
+			if inverse {
+				v = r.MTU
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%d\n", r.Encapsulation, v)
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPayloads(t *testing.T) {
+	es, err := lookupEncapsulations([]string{"vxlan", "wireguard"})
+	NoError(t, err)
+
+	rs, err := payloads(es, 1500, false)
+	NoError(t, err)
+	s := &strings.Builder{}
+	NoError(t, writeMTUs(s, rs, false, "text"))
+	Equal(t, "vxlan\t1450\nwireguard\t1440\n", s.String())
+
+	rs, err = requiredMTUs(es, 1500, true)
+	NoError(t, err)
+	s.Reset()
+	NoError(t, writeMTUs(s, rs, true, "json"))
+	Equal(t, `[{"encapsulation":"vxlan","mtu":1570,"payload":1500,"overhead":70},`+
+		`{"encapsulation":"wireguard","mtu":1580,"payload":1500,"overhead":80}]`+"\n", s.String())
+
+	EqualError(t, writeMTUs(s, rs, true, "xml"), "unsupported output format: xml")
+	_, err = lookupEncapsulations([]string{"pptp"})
+	EqualError(t, err, "unsupported encapsulation: pptp")
+}
+
+func TestParseMTU(t *testing.T) {
+	m, err := parseMTU("9000")
+	NoError(t, err)
+	Equal(t, 9000, m)
+
+	is, _ := net.Interfaces()
+	for _, i := range is {
+		m, err = parseMTU(i.Name)
+		NoError(t, err)
+		Equal(t, i.MTU, m)
+	}
+	_, err = parseMTU("xyz0")
+	Error(t, err)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/abc-inc/terminus/ipv6"
+	"github.com/abc-inc/terminus/mac"
+	"github.com/spf13/cobra"
+)
+
+var solicitedNodeCmd = &cobra.Command{
+	Use:   "solicited-node IPv6",
+	Short: "Calculate the solicited-node multicast address of an IPv6 address",
+	Example: `  terminus solicited-node 2001:db8::211:22ff:fe33:4455
+  # ff02::1:ff33:4455`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := printIPFunc(solicitedNode, args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var multicastMACCmd = &cobra.Command{
+	Use:   "multicast-mac IP",
+	Short: "Calculate the Ethernet multicast MAC address of an IPv4 or IPv6 multicast group",
+	Example: `  terminus multicast-mac 224.0.0.251
+  # 01:00:5e:00:00:fb
+
+  terminus multicast-mac ff02::1:ff33:4455
+  # 33:33:ff:33:44:55`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := printIPFunc(multicastMAC, args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(solicitedNodeCmd, multicastMACCmd)
+}
+
+// printIPFunc applies the function f to the IP address arg and prints the result.
+func printIPFunc[T fmt.Stringer](f func(net.IP) (T, error), arg string) error {
+	ip := net.ParseIP(arg)
+	if ip == nil {
+		return &net.ParseError{Type: "IP address", Text: arg}
+	}
+
+	v, err := f(ip)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(v)
+	return err
+}
+
+func solicitedNode(ip net.IP) (net.IP, error) {
+	return ipv6.SolicitedNode(ip)
+}
+
+func multicastMAC(ip net.IP) (net.HardwareAddr, error) {
+	return mac.Multicast(ip)
+}
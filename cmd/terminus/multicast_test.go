@@ -0,0 +1,34 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrintTemplateMulticast(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("224.0.0.251/32")
+	data := iface.GetParams(ip.String(), ip, n.Mask)
+	data["ip6"] = net.ParseIP("2001:db8::211:22ff:fe33:4455")
+
+	s := &strings.Builder{}
+	NoError(t, printTemplate("{{.ip | multicastMAC}} {{.ip6 | solicitedNode}} {{.ip6 | solicitedNode | multicastMAC}}", s, data))
+	Equal(t, "01:00:5e:00:00:fb ff02::1:ff33:4455 33:33:ff:33:44:55\n", s.String())
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var normalizeCmd = &cobra.Command{
+	Use:   "normalize [flags] [INPUT...]",
+	Short: "Rewrite IP addresses, networks and IP ranges in canonical form",
+	Long: `Rewrite IP addresses, networks and IP ranges in canonical form, such that equal inputs can be compared as text
+(e.g., by diff or sort -u):
+IPv6 addresses are lowercased and compressed (RFC 5952), host bits of networks are cleared, and subnet masks
+(IP/NETMASK) are replaced by prefix lengths. With --expand, IPv6 addresses are written in full, uncompressed form.
+If no argument is given (or "-"), the inputs are read from stdin (one per line).`,
+	Example: `  terminus normalize 2001:DB8:0:0::1 10.1.2.3/255.255.0.0 2001:db8:0:1:0:0:0:1/64
+  # 2001:db8::1
+  # 10.1.0.0/16
+  # 2001:db8:0:1::/64
+
+  terminus normalize --expand 2001:db8::1
+  # 2001:0db8:0000:0000:0000:0000:0000:0001`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runNormalizeCmd,
+}
+
+func init() {
+	normalizeCmd.Flags().Bool("expand", false, "Write IPv6 addresses in full, uncompressed form")
+	rootCmd.AddCommand(normalizeCmd)
+}
+
+func runNormalizeCmd(cmd *cobra.Command, args []string) {
+	expand, _ := cmd.Flags().GetBool("expand")
+	ss, err := readArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	out := newOutput(cmd)
+	for _, s := range ss {
+		n, err := normalize(s, expand)
+		if err != nil {
+			_ = out.Flush()
+			fatal(err)
+		}
+		_, _ = fmt.Fprintln(out, n)
+	}
+	_ = out.Flush()
+}
+
+// normalize returns the canonical form of an IP address, IP/PREFIX_LEN, IP/NETMASK or IP range (FROM-TO).
+// If expand is true, IPv6 addresses are not compressed.
+func normalize(s string, expand bool) (string, error) {
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		f, errFrom := normalizeAddr(from, expand)
+		t, errTo := normalizeAddr(to, expand)
+		if errFrom != nil || errTo != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+		}
+		return f + "-" + t, nil
+	}
+
+	addr, bits, ok := strings.Cut(s, "/")
+	if !ok {
+		return normalizeAddr(s, expand)
+	}
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+	}
+	p, err := netip.ParsePrefix(a.WithZone("").String() + "/" + bits)
+	if err != nil {
+		size, isMask := parseMask(bits)
+		if !a.Is4() || !isMask {
+			return "", fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+		}
+		p = netip.PrefixFrom(a, size)
+	}
+	n, _ := normalizeAddr(p.Masked().Addr().String(), expand)
+	return fmt.Sprintf("%s/%d", n, p.Bits()), nil
+}
+
+// normalizeAddr returns the canonical form of an IP address (including its zone, if any).
+func normalizeAddr(s string, expand bool) (string, error) {
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+	}
+	if expand && a.Is6() {
+		return a.StringExpanded(), nil
+	}
+	return a.String(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in     string
+		expand bool
+		want   string
+	}{
+		{"2001:DB8:0:0::1", false, "2001:db8::1"},
+		{"2001:db8:0:0:1:0:0:1", false, "2001:db8::1:0:0:1"},
+		{"2001:db8::1", true, "2001:0db8:0000:0000:0000:0000:0000:0001"},
+		{"FE80::1%eth0", false, "fe80::1%eth0"},
+		{"192.0.2.1", true, "192.0.2.1"},
+		{"10.1.2.3/8", false, "10.0.0.0/8"},
+		{"10.1.2.3/255.255.0.0", false, "10.1.0.0/16"},
+		{"10.1.2.3/0xffffff00", false, "10.1.2.0/24"},
+		{"2001:DB8:0:1::1/64", false, "2001:db8:0:1::/64"},
+		{"2001:db8::/32", true, "2001:0db8:0000:0000:0000:0000:0000:0000/32"},
+		{"10.0.0.1-10.0.0.5", false, "10.0.0.1-10.0.0.5"},
+		{"2001:DB8::1-2001:db8:0::ff", false, "2001:db8::1-2001:db8::ff"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			s, err := normalize(tt.in, tt.expand)
+			NoError(t, err)
+			Equal(t, tt.want, s)
+		})
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	for _, s := range []string{"10.0.0.256", "10.0.0.1/33", "10.0.0.1/255.0.255.0", "2001:db8::/ffff0000", "10.0.0.1-x"} {
+		_, err := normalize(s, false)
+		EqualError(t, err, "invalid IP address or CIDR: "+s)
+		ErrorIs(t, err, ErrInvalidCIDR)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"github.com/spf13/cobra"
+)
+
+var offsetCmd = &cobra.Command{
+	Use:   "offset [flags] IP +N|-N",
+	Short: "Print the IP address, which is N addresses away",
+	Long: `Print the IP address, which is N addresses after (+N) or before (-N) the given IP address.
+Flags must precede the IP address, because -N is not a flag.`,
+	Example: `  terminus offset 10.0.0.1 +255
+  # 10.0.1.0
+
+  terminus offset 2001:db8:1:: -1
+  # 2001:db8:0:ffff:ffff:ffff:ffff:ffff`,
+	Args: cobra.ExactArgs(2),
+	Run:  runOffsetCmd,
+}
+
+func init() {
+	// negative offsets must not be parsed as shorthand flags
+	offsetCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(offsetCmd)
+}
+
+func runOffsetCmd(_ *cobra.Command, args []string) {
+	a, err := offset(args[0], args[1])
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Println(a)
+}
+
+// offset returns the IP address, which is n addresses away from s.
+func offset(s, n string) (netip.Addr, error) {
+	a, err := parseAddr(s)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	i, ok := new(big.Int).SetString(n, 10)
+	if !ok {
+		return netip.Addr{}, usageError("invalid offset (must be +N or -N): " + n)
+	}
+
+	i.Add(i, addrInt(a))
+	if i.Sign() < 0 || i.BitLen() > a.BitLen() {
+		return netip.Addr{}, fmt.Errorf("offset out of range: %s %s", s, n)
+	}
+	a, _ = netip.AddrFromSlice(i.FillBytes(make([]byte, a.BitLen()/8)))
+	return a, nil
+}
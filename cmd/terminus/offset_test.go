@@ -0,0 +1,63 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		ip, n string
+		want  string
+	}{
+		{"10.0.0.1", "+255", "10.0.1.0"},
+		{"10.0.0.1", "255", "10.0.1.0"},
+		{"10.0.1.0", "-256", "10.0.0.0"},
+		{"10.0.0.1", "0", "10.0.0.1"},
+		{"0.0.0.0", "4294967295", "255.255.255.255"},
+		{"2001:db8:1::", "-1", "2001:db8:0:ffff:ffff:ffff:ffff:ffff"},
+		{"::", "+18446744073709551616", "0:0:0:1::"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip+" "+tt.n, func(t *testing.T) {
+			a, err := offset(tt.ip, tt.n)
+			NoError(t, err)
+			Equal(t, tt.want, a.String())
+		})
+	}
+}
+
+func TestOffsetInvalid(t *testing.T) {
+	_, err := offset("255.255.255.255", "+1")
+	EqualError(t, err, "offset out of range: 255.255.255.255 +1")
+	_, err = offset("::", "-1")
+	EqualError(t, err, "offset out of range: :: -1")
+	_, err = offset("10.0.0.1", "1k")
+	EqualError(t, err, "invalid offset (must be +N or -N): 1k")
+	Equal(t, exitParse, exitCode(err))
+	_, err = offset("10.0.0.1", "x")
+	Equal(t, exitParse, exitCode(err))
+	_, err = offset("10.0.0", "1")
+	ErrorIs(t, err, ErrInvalidCIDR)
+}
+
+func TestOffsetCmdNegative(t *testing.T) {
+	NoError(t, offsetCmd.ParseFlags([]string{"10.0.0.1", "-1"}))
+	Equal(t, []string{"10.0.0.1", "-1"}, offsetCmd.Flags().Args())
+}
@@ -0,0 +1,267 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// formatter renders the properties of one input after another in the output format selected by the flags of cmd.
+type formatter struct {
+	cmd    *cobra.Command
+	output string
+	// records is the number of records rendered so far.
+	records int
+}
+
+func newFormatter(cmd *cobra.Command) (*formatter, error) {
+	output, _ := cmd.Flags().GetString("output")
+	if cmd.Flag("export").Changed {
+		output = "shell"
+	}
+
+	switch output {
+	case "csv", "ipsec", "json", "shell", "text", "yaml":
+		return &formatter{cmd: cmd, output: output}, nil
+	default:
+		return nil, usageError("unsupported output format: " + output)
+	}
+}
+
+// format renders the properties of a single input.
+func (f *formatter) format(data map[string]interface{}) (string, error) {
+	defer func() { f.records++ }()
+
+	keys := selectedKeys(f.cmd, data)
+	switch f.output {
+	case "csv":
+		return formatCSV(data, keys, f.records == 0), nil
+	case "json":
+		return formatJSON(data, omitEmptyZone(f.cmd, data, keys))
+	case "shell":
+		return formatShell(data, keys), nil
+	case "yaml":
+		return formatYAML(data, omitEmptyZone(f.cmd, data, keys), f.records == 0)
+	default:
+		if all, _ := f.cmd.Flags().GetBool("all"); all {
+			// like other text output, the count is printed as prefix of every line
+			var ks []string
+			for _, k := range keys {
+				if k != "count" {
+					ks = append(ks, k)
+				}
+			}
+			return formatLabeled(data, ks), nil
+		}
+		return formatText(f.cmd, data)
+	}
+}
+
+// selectedKeys returns the properties selected by --fields followed by the properties selected by other flags
+// in the order of the command line. If no property is selected (or --all is set), all keys of data are returned in
+// alphabetical order.
+func selectedKeys(cmd *cobra.Command, data map[string]interface{}) []string {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return sortedKeys(data)
+	}
+
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	keys := append([]string{}, fields...)
+	visitFlags(cmd, func(f *pflag.Flag) {
+		if k := flagProperty(f.Name); k != "count" && !contains(keys, k) {
+			if _, ok := data[k]; ok {
+				keys = append(keys, k)
+			}
+		}
+	})
+	if len(keys) > 0 {
+		if _, ok := data["count"]; ok && !contains(keys, "count") {
+			keys = append([]string{"count"}, keys...)
+		}
+		return keys
+	}
+	return sortedKeys(data)
+}
+
+// omitEmptyZone removes the zone from the keys of JSON and YAML objects if it is empty (i.e., unless the input is an
+// IPv6 address with zone), but not if it is selected by --fields or --all.
+func omitEmptyZone(cmd *cobra.Command, data map[string]interface{}, keys []string) []string {
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	all, _ := cmd.Flags().GetBool("all")
+	if zone, _ := data[iface.Zone].(string); zone != "" || all || contains(fields, iface.Zone) {
+		return keys
+	}
+
+	ks := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k != iface.Zone {
+			ks = append(ks, k)
+		}
+	}
+	return ks
+}
+
+// sortedKeys returns the keys of data in alphabetical order.
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// visitFlags calls fn for every flag of cmd, which has been set, in the order of the command line
+// (regardless of the order, in which the flags are listed in the help).
+func visitFlags(cmd *cobra.Command, fn func(*pflag.Flag)) {
+	fs := cmd.Flags()
+	sorted := fs.SortFlags
+	fs.SortFlags = false
+	defer func() { fs.SortFlags = sorted }()
+	fs.Visit(fn)
+}
+
+// flagProperty returns the property shown by the flag, which is the name of the flag except for --resolve.
+func flagProperty(name string) string {
+	if name == "resolve" {
+		return "hostname"
+	}
+	return name
+}
+
+// checkFields verifies that --fields lists known properties only.
+// The host name is a property of the input if it is looked up by --resolve.
+func checkFields(cmd *cobra.Command) error {
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	resolve, _ := cmd.Flags().GetBool("resolve")
+	for _, f := range fields {
+		known := resolve && f == "hostname"
+		for _, p := range templateProperties {
+			known = known || p.Name == f && !isContextProperty(p.Name)
+		}
+		if known {
+			continue
+		}
+		if s := suggestProperty(f); s != "" {
+			return fmt.Errorf("%w: %s (did you mean %s?)", ErrUnknownField, f, s)
+		}
+		return fmt.Errorf("%w: %s", ErrUnknownField, f)
+	}
+	return nil
+}
+
+// formatLabeled renders the properties as "key: value" lines, whose values are aligned.
+func formatLabeled(data map[string]interface{}, keys []string) string {
+	width := 0
+	for _, k := range keys {
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+
+	s := &strings.Builder{}
+	for _, k := range keys {
+		l := fmt.Sprintf("%-*s %s", width+1, k+":", loc.format(data[k]))
+		_, _ = fmt.Fprintln(s, strings.TrimRight(l, " "))
+	}
+	return s.String()
+}
+
+// formatCSV renders the properties as CSV record, optionally preceded by a header row.
+func formatCSV(data map[string]interface{}, keys []string, header bool) string {
+	s := &strings.Builder{}
+	w := csv.NewWriter(s)
+	if header {
+		_ = w.Write(keys)
+	}
+
+	rec := make([]string, len(keys))
+	for i, k := range keys {
+		rec[i] = fmt.Sprint(data[k])
+	}
+	_ = w.Write(rec)
+	w.Flush()
+	return s.String()
+}
+
+// formatJSON renders the properties as JSON object on a single line, preserving the order of keys.
+func formatJSON(data map[string]interface{}, keys []string) (string, error) {
+	s := &strings.Builder{}
+	s.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		val, err := json.Marshal(data[k])
+		if err != nil {
+			return "", err
+		}
+		s.Write(name)
+		s.WriteByte(':')
+		s.Write(val)
+	}
+	return s.String() + "}\n", nil
+}
+
+// formatShell renders the properties as shell variable assignments, which can be evaluated by a POSIX shell.
+func formatShell(data map[string]interface{}, keys []string) string {
+	s := &strings.Builder{}
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(s, "%s=%s\n", shellVar(k), shellquote.Join(fmt.Sprint(data[k])))
+	}
+	return s.String()
+}
+
+// formatYAML renders the properties as YAML document.
+// Every document except the first one starts with a document separator.
+func formatYAML(data map[string]interface{}, keys []string, first bool) (string, error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, k := range keys {
+		v := &yaml.Node{}
+		if n, ok := data[k].(*big.Int); ok {
+			// big.Int would be encoded as string, because it implements encoding.TextMarshaler
+			v = &yaml.Node{Kind: yaml.ScalarNode, Value: n.String()}
+		} else if err := v.Encode(data[k]); err != nil {
+			return "", err
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, v)
+	}
+
+	y, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	if first {
+		return string(y), nil
+	}
+	return "---\n" + string(y), nil
+}
+
+// shellVar returns the name of the shell variable for the given property e.g., TERMINUS_NETWORK.
+func shellVar(key string) string {
+	return "TERMINUS_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFormatShell(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("Local Area Connection", ip, n.Mask)
+	s := formatShell(data, []string{iface.Network, iface.Prefix, iface.Name})
+	Equal(t, "TERMINUS_NETWORK=10.0.0.0\nTERMINUS_PREFIX=24\nTERMINUS_NAME='Local Area Connection'\n", s)
+}
+
+func TestShellVar(t *testing.T) {
+	Equal(t, "TERMINUS_BROADCAST", shellVar(iface.Broadcast))
+	Equal(t, "TERMINUS_USABLE", shellVar(iface.UsableSize))
+	Equal(t, "TERMINUS_CLASSFUL_NETWORK", shellVar("classful-network"))
+}
+
+func TestFormatCSV(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("Local Area Connection", ip, n.Mask)
+	keys := []string{iface.Name, iface.Network, iface.Prefix}
+	Equal(t, "name,network,prefix\nLocal Area Connection,10.0.0.0,24\n", formatCSV(data, keys, true))
+	Equal(t, "Local Area Connection,10.0.0.0,24\n", formatCSV(data, keys, false))
+}
+
+func TestFormatJSON(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("eth0", ip, n.Mask)
+	s, err := formatJSON(data, []string{iface.Prefix, iface.Network, iface.Name})
+	NoError(t, err)
+	Equal(t, `{"prefix":24,"network":"10.0.0.0","name":"eth0"}`+"\n", s)
+
+	_, err = formatJSON(map[string]interface{}{"x": make(chan int)}, []string{"x"})
+	EqualError(t, err, "json: unsupported type: chan int")
+}
+
+func TestFormatLabeled(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("", ip, n.Mask)
+	keys := []string{iface.Name, iface.Network, iface.Broadcast}
+	Equal(t, "name:\nnetwork:   10.0.0.0\nbroadcast: 10.0.0.255\n", formatLabeled(data, keys))
+}
+
+func TestFormatYAML(t *testing.T) {
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("eth0", ip, n.Mask)
+	keys := []string{iface.Prefix, iface.Network, iface.Name}
+	s, err := formatYAML(data, keys, true)
+	NoError(t, err)
+	Equal(t, "prefix: 24\nnetwork: 10.0.0.0\nname: eth0\n", s)
+	s, err = formatYAML(data, keys, false)
+	NoError(t, err)
+	Equal(t, "---\nprefix: 24\nnetwork: 10.0.0.0\nname: eth0\n", s)
+
+	ip, n, _ = net.ParseCIDR("2001:db8::/64")
+	data = iface.GetParams("", ip, n.Mask)
+	keys = []string{iface.Size, iface.UsableSize}
+	s, err = formatYAML(data, keys, true)
+	NoError(t, err)
+	Equal(t, "size: 18446744073709551616\nusable: 18446744073709551616\n", s)
+	s, err = formatJSON(data, keys)
+	NoError(t, err)
+	Equal(t, `{"size":18446744073709551616,"usable":18446744073709551616}`+"\n", s)
+}
+
+func TestSelectedKeys(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-p", "-n", "-i"}, []string{iface.Prefix, iface.Network, iface.IP}},
+		{[]string{"-i", "-p", "-n"}, []string{iface.IP, iface.Prefix, iface.Network}},
+		{[]string{"-n", "--fields", "prefix,ip"}, []string{iface.Prefix, iface.IP, iface.Network}},
+		{[]string{"-p", "--fields", "ip,prefix"}, []string{iface.IP, iface.Prefix}},
+		{[]string{"-p", "-a"}, []string{iface.Broadcast, iface.Class, iface.ClassfulNetwork, iface.DHCP, iface.DNS,
+			iface.First, iface.Flags, iface.Gateway, iface.HardwareAddr, iface.IP, iface.ClassfulBoundary, iface.Last,
+			iface.LocalInterface, iface.MTU, iface.Name, iface.NetMask, iface.Network, iface.OnLink, iface.Prefix,
+			iface.Search, iface.Size, iface.Speed, iface.UsableSize, iface.Version, iface.Wildcard, iface.Zone}},
+	}
+
+	ip, n, _ := net.ParseCIDR("10.0.0.1/24")
+	data := iface.GetParams("eth0", ip, n.Mask)
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want[0], func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addInfoFlags(cmd.Flags())
+			NoError(t, cmd.ParseFlags(tt.args))
+			Equal(t, tt.want, selectedKeys(cmd, data))
+		})
+	}
+}
+
+func TestSelectedKeysResolve(t *testing.T) {
+	data := map[string]interface{}{iface.IP: "192.0.2.1", "hostname": "host1.example.com"}
+	cmd := &cobra.Command{}
+	addInfoFlags(cmd.Flags())
+	NoError(t, cmd.ParseFlags([]string{"-R", "-i"}))
+	Equal(t, []string{"hostname", iface.IP}, selectedKeys(cmd, data))
+	Equal(t, []string{"hostname", iface.IP}, requestedKeys(cmd))
+
+	s, err := formatText(cmd, data)
+	NoError(t, err)
+	Equal(t, "host1.example.com\n192.0.2.1\n", s)
+}
+
+func TestOmitEmptyZone(t *testing.T) {
+	keys := []string{iface.IP, iface.Zone}
+	omit := func(zone string, args ...string) []string {
+		cmd := &cobra.Command{}
+		addInfoFlags(cmd.Flags())
+		NoError(t, cmd.ParseFlags(args))
+		return omitEmptyZone(cmd, map[string]interface{}{iface.IP: "fe80::1", iface.Zone: zone}, keys)
+	}
+
+	Equal(t, []string{iface.IP}, omit(""))
+	Equal(t, keys, omit("eth0"))
+	Equal(t, keys, omit("", "--fields", "ip,zone"))
+	Equal(t, keys, omit("", "-a"))
+}
+
+func TestCheckFields(t *testing.T) {
+	check := func(fields string, args ...string) error {
+		cmd := &cobra.Command{}
+		addInfoFlags(cmd.Flags())
+		NoError(t, cmd.ParseFlags(append([]string{"--fields", fields}, args...)))
+		return checkFields(cmd)
+	}
+
+	NoError(t, check("ip,network,count"))
+	err := check("ip,prefx")
+	EqualError(t, err, "unknown field: prefx (did you mean prefix?)")
+	Equal(t, exitParse, exitCode(err))
+	ErrorIs(t, check("interfaces"), ErrUnknownField)
+	ErrorIs(t, check("ip,hostname"), ErrUnknownField)
+	NoError(t, check("ip,hostname", "--resolve"))
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultParallel is the default number of concurrent lookups of commands, which query remote services.
+const defaultParallel = 16
+
+// parallelism returns the number of workers given by --parallel.
+func parallelism(cmd *cobra.Command) (int, error) {
+	n, _ := cmd.Flags().GetInt("parallel")
+	if n < 1 {
+		return 0, fmt.Errorf("invalid parallelism (must be positive): %d", n)
+	}
+	return n, nil
+}
+
+// forEachOrdered calls fn for every index from 0 to count-1 using n workers and passes the results to emit (if not nil)
+// in the order of the indexes. Results are emitted as soon as all previous ones are available, and at most n results
+// are pending at any time. Hence, large batches are processed with constant memory.
+func forEachOrdered(n, count int, fn func(i int) interface{}, emit func(i int, v interface{})) {
+	type job struct {
+		i   int
+		res chan interface{}
+	}
+	jobs := make(chan job)
+	pending := make(chan chan interface{}, n)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.res <- fn(j.i)
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < count; i++ {
+			res := make(chan interface{}, 1)
+			pending <- res
+			jobs <- job{i, res}
+		}
+		close(pending)
+		close(jobs)
+	}()
+
+	i := 0
+	for res := range pending {
+		if v := <-res; emit != nil {
+			emit(i, v)
+		}
+		i++
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestForEachOrdered(t *testing.T) {
+	for _, n := range []int{1, 4, 100} {
+		var running, maxRunning int32
+		var got []int
+		forEachOrdered(n, 50, func(i int) interface{} {
+			r := atomic.AddInt32(&running, 1)
+			for m := atomic.LoadInt32(&maxRunning); r > m && !atomic.CompareAndSwapInt32(&maxRunning, m, r); {
+				m = atomic.LoadInt32(&maxRunning)
+			}
+			// later inputs finish first
+			time.Sleep(time.Duration(50-i) * 10 * time.Microsecond)
+			atomic.AddInt32(&running, -1)
+			return i * i
+		}, func(i int, v interface{}) {
+			Equal(t, i*i, v)
+			got = append(got, i)
+		})
+
+		Len(t, got, 50)
+		for i := range got {
+			Equal(t, i, got[i])
+		}
+		LessOrEqual(t, int(maxRunning), n)
+	}
+
+	forEachOrdered(4, 0, func(int) interface{} { panic("unexpected call") }, nil)
+}
+
+func TestParallelism(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("parallel", defaultParallel, "")
+	n, err := parallelism(cmd)
+	NoError(t, err)
+	Equal(t, defaultParallel, n)
+
+	NoError(t, cmd.ParseFlags([]string{"--parallel", "0"}))
+	_, err = parallelism(cmd)
+	EqualError(t, err, "invalid parallelism (must be positive): 0")
+}
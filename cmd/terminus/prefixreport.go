@@ -0,0 +1,173 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var prefixReportCmd = &cobra.Command{
+	Use:   "prefix-report [flags] [FILE|URL...]",
+	Short: "Report the number of prefixes per family and prefix length",
+	Long: `Report the number of prefixes per family and prefix length of a prefix list (files, stdin or URLs).
+This helps comparing the prefixes to be announced against the max-prefix settings of BGP peers.
+Prefixes longer than /24 (IPv4) or /48 (IPv6) are counted separately, since they are filtered by most networks.
+With --aggregate, the prefixes are aggregated before counting.
+If a max-prefix limit is given and exceeded, the exit status is 1.`,
+	Example: `  terminus prefix-report --max-prefix-v4 100 announce.txt
+  # ipv4	87 prefixes (max-prefix 100, 87%)
+  # 	/22	5
+  # 	/23	2
+  # 	/24	80
+  # ipv6	2 prefixes
+  # 	/32	1
+  # 	/48	1`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runPrefixReportCmd,
+}
+
+func init() {
+	prefixReportCmd.Flags().BoolP("aggregate", "A", false, "Aggregate the prefixes before counting")
+	prefixReportCmd.Flags().Int("max-prefix-v4", 0, "Max-prefix limit for IPv4 prefixes")
+	prefixReportCmd.Flags().Int("max-prefix-v6", 0, "Max-prefix limit for IPv6 prefixes")
+	prefixReportCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(prefixReportCmd)
+}
+
+func runPrefixReportCmd(cmd *cobra.Command, args []string) {
+	aggregate, _ := cmd.Flags().GetBool("aggregate")
+	max4, _ := cmd.Flags().GetInt("max-prefix-v4")
+	max6, _ := cmd.Flags().GetInt("max-prefix-v6")
+	output, _ := cmd.Flags().GetString("output")
+
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	var ss []string
+	for _, name := range args {
+		b, err := readInputFile(name)
+		if err != nil {
+			fatal(err)
+		}
+		ss = append(ss, parseTargets(b)...)
+	}
+
+	ps, err := parsePrefixes(ss)
+	if err != nil {
+		fatal(err)
+	}
+	if aggregate {
+		ps = setOf(ps).Prefixes()
+	}
+
+	r := newPrefixReport(ps, max4, max6)
+	if err := writePrefixReport(os.Stdout, r, output); err != nil {
+		fatal(err)
+	}
+	if r.IPv4.exceeded() || r.IPv6.exceeded() {
+		os.Exit(1)
+	}
+}
+
+// setOf returns the set of addresses covered by the prefixes.
+func setOf(ps []netip.Prefix) ipset.Set {
+	rs := make([]ipset.Range, len(ps))
+	for i, p := range ps {
+		rs[i] = ipset.PrefixRange(p)
+	}
+	return ipset.New(rs...)
+}
+
+// familyReport holds the prefix statistics of an address family.
+type familyReport struct {
+	Prefixes int `json:"prefixes"`
+	// MaxPrefix is the max-prefix limit (0 if unknown).
+	MaxPrefix int `json:"maxPrefix,omitempty"`
+	// TooSpecific is the number of prefixes longer than /24 (IPv4) or /48 (IPv6).
+	TooSpecific int `json:"tooSpecific"`
+	// Lengths maps prefix lengths to the number of prefixes.
+	Lengths map[int]int `json:"lengths"`
+}
+
+func (f familyReport) exceeded() bool {
+	return f.MaxPrefix > 0 && f.Prefixes > f.MaxPrefix
+}
+
+// prefixReport holds the prefix statistics per address family.
+type prefixReport struct {
+	IPv4 familyReport `json:"ipv4"`
+	IPv6 familyReport `json:"ipv6"`
+}
+
+// newPrefixReport counts the prefixes per family and prefix length.
+func newPrefixReport(ps []netip.Prefix, max4, max6 int) prefixReport {
+	r := prefixReport{
+		IPv4: familyReport{MaxPrefix: max4, Lengths: map[int]int{}},
+		IPv6: familyReport{MaxPrefix: max6, Lengths: map[int]int{}},
+	}
+	for _, p := range ps {
+		f, limit := &r.IPv6, 48
+		if p.Addr().Is4() {
+			f, limit = &r.IPv4, 24
+		}
+		f.Prefixes++
+		f.Lengths[p.Bits()]++
+		if p.Bits() > limit {
+			f.TooSpecific++
+		}
+	}
+	return r
+}
+
+func writePrefixReport(w io.Writer, r prefixReport, output string) error {
+	switch output {
+	case "text":
+		for _, f := range []struct {
+			name string
+			familyReport
+		}{{"ipv4", r.IPv4}, {"ipv6", r.IPv6}} {
+			_, _ = fmt.Fprintf(w, "%s\t%s prefixes", f.name, loc.format(f.Prefixes))
+			if f.MaxPrefix > 0 {
+				_, _ = fmt.Fprintf(w, " (max-prefix %s, %d%%)", loc.format(f.MaxPrefix), f.Prefixes*100/f.MaxPrefix)
+			}
+			if f.TooSpecific > 0 {
+				_, _ = fmt.Fprintf(w, ", %s too specific", loc.format(f.TooSpecific))
+			}
+			_, _ = fmt.Fprintln(w)
+
+			lengths := make([]int, 0, len(f.Lengths))
+			for l := range f.Lengths {
+				lengths = append(lengths, l)
+			}
+			sort.Ints(lengths)
+			for _, l := range lengths {
+				_, _ = fmt.Fprintf(w, "\t/%d\t%s\n", l, loc.format(f.Lengths[l]))
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(r)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrefixReport(t *testing.T) {
+	ps, err := parsePrefixes([]string{"10.0.0.0/22", "10.0.4.0/24", "10.0.5.0/24", "10.0.6.0/25", "2001:db8::/32"})
+	NoError(t, err)
+
+	r := newPrefixReport(ps, 3, 0)
+	True(t, r.IPv4.exceeded())
+	False(t, r.IPv6.exceeded())
+
+	s := &strings.Builder{}
+	NoError(t, writePrefixReport(s, r, "text"))
+	Equal(t, "ipv4\t4 prefixes (max-prefix 3, 133%), 1 too specific\n\t/22\t1\n\t/24\t2\n\t/25\t1\n"+
+		"ipv6\t1 prefixes\n\t/32\t1\n", s.String())
+
+	s.Reset()
+	NoError(t, writePrefixReport(s, newPrefixReport(setOf(ps).Prefixes(), 0, 0), "json"))
+	Equal(t, `{"ipv4":{"prefixes":3,"tooSpecific":1,"lengths":{"22":1,"23":1,"25":1}},`+
+		`"ipv6":{"prefixes":1,"tooSpecific":0,"lengths":{"32":1}}}`+"\n", s.String())
+
+	EqualError(t, writePrefixReport(s, r, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/abc-inc/terminus/probe"
+	"github.com/spf13/cobra"
+)
+
+var probeCmd = &cobra.Command{
+	Use:   "probe [flags] [IP:PORT...]",
+	Short: "Check whether TCP or UDP ports are reachable",
+	Long: `Check whether TCP (or UDP) ports are reachable, e.g., in health-check scripts instead of "nc -z".
+Targets are IP:PORT, [IPv6]:PORT or HOST:PORT (using the first IP address of the host).
+If no target is given (or "-"), targets are read from stdin (one per line).
+A port, which refuses the connection (or responds with ICMP port unreachable), is closed. A port without response is
+filtered, or open|filtered in case of UDP, because many services ignore unexpected datagrams.
+Ports without response are probed again up to --retries times.
+The exit status is 0 if all ports are open (or open|filtered with --udp), 1 otherwise, and 2 if a target is invalid.`,
+	Example: `  terminus probe 192.0.2.1:443 db.example.com:5432
+  # 192.0.2.1:443	tcp	open	1.37ms
+  # db.example.com:5432	tcp	closed	0.42ms
+
+  terminus probe --udp --timeout 1s --retries 2 -o json 192.0.2.53:53`,
+	Args:        cobra.ArbitraryArgs,
+	Run:         runProbeCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	probeCmd.Flags().BoolP("udp", "u", false, "Probe UDP instead of TCP ports")
+	probeCmd.Flags().Duration("timeout", 3*time.Second, "Timeout per attempt")
+	probeCmd.Flags().Int("retries", 0, "Number of additional attempts if a port does not respond")
+	probeCmd.Flags().Int("parallel", defaultParallel, "Number of targets probed concurrently")
+	probeCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(probeCmd)
+}
+
+func runProbeCmd(cmd *cobra.Command, args []string) {
+	udp, _ := cmd.Flags().GetBool("udp")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	retries, _ := cmd.Flags().GetInt("retries")
+	output, _ := cmd.Flags().GetString("output")
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if output != "text" && output != "json" {
+		fatal(usageError("unsupported output format: " + output))
+	} else if retries < 0 {
+		fatal(fmt.Errorf("invalid retries (must not be negative): %d", retries))
+	}
+
+	targets, err := readArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+	rs := make([]probeResult, len(targets))
+	for i, t := range targets {
+		if rs[i], err = newProbeResult(t, udp); err != nil {
+			fatal(err)
+		}
+	}
+
+	out := newOutput(cmd)
+	a := &jsonArray{w: out}
+	failed := false
+	forEachOrdered(par, len(rs), func(i int) interface{} {
+		rs[i].probe(timeout, retries)
+		return nil
+	}, func(i int, _ interface{}) {
+		r := rs[i]
+		if r.Error != "" {
+			_ = out.Flush()
+			log.Print("warning: ", r.Target, ": ", r.Error)
+		}
+		failed = failed || !r.reachable()
+
+		if output == "json" {
+			err = a.add(r)
+		} else {
+			err = writeProbeResult(out, r)
+		}
+		if err != nil {
+			fatal(err)
+		}
+	})
+
+	if output == "json" {
+		err = a.close()
+	}
+	_ = out.Flush()
+	if err != nil {
+		fatal(err)
+	}
+	if failed {
+		os.Exit(exitFailure)
+	}
+}
+
+// probeUDP sends the UDP probes. It can be replaced in tests (like probeTCP).
+var probeUDP = probe.UDP
+
+// probeResult is the reachability of a port.
+type probeResult struct {
+	Target   string         `json:"target"`
+	Addr     netip.AddrPort `json:"addr"`
+	Protocol string         `json:"protocol"`
+	State    probe.State    `json:"state"`
+	// RTT is the round-trip time in milliseconds.
+	RTT      float64 `json:"rtt,omitempty"`
+	Attempts int     `json:"attempts"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// newProbeResult parses the target (IP:PORT, [IPv6]:PORT or HOST:PORT) and resolves the host name (if any).
+func newProbeResult(target string, udp bool) (probeResult, error) {
+	r := probeResult{Target: target, Protocol: "tcp"}
+	if udp {
+		r.Protocol = "udp"
+	}
+
+	host, p, err := net.SplitHostPort(target)
+	port, perr := strconv.ParseUint(p, 10, 16)
+	if err != nil || perr != nil || port == 0 || host == "" {
+		return r, &net.ParseError{Type: "target (IP:PORT or HOST:PORT)", Text: target}
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		ips, err := lookupIP(host)
+		if err != nil {
+			return r, err
+		} else if len(ips) == 0 {
+			return r, errors.New("no IP address: " + host)
+		}
+		ip, _ = netip.AddrFromSlice(ips[0])
+	}
+	r.Addr = netip.AddrPortFrom(ip.Unmap(), uint16(port))
+	return r, nil
+}
+
+// probe checks the port until it responds, but at most 1+retries times.
+func (r *probeResult) probe(timeout time.Duration, retries int) {
+	for r.Attempts = 1; ; r.Attempts++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		var rtt time.Duration
+		var err error
+		if r.Protocol == "udp" {
+			r.State, rtt, err = probeUDP(ctx, r.Addr, nil)
+		} else {
+			r.State, rtt, err = probeTCP(ctx, r.Addr)
+		}
+		cancel()
+
+		r.RTT, r.Error = float64(rtt.Microseconds())/1000, ""
+		if err != nil {
+			r.Error = err.Error()
+		}
+		if r.State == probe.Open || r.State == probe.Closed || r.Attempts > retries {
+			return
+		}
+	}
+}
+
+// reachable reports whether the port is open (or might be open in case of UDP).
+func (r probeResult) reachable() bool {
+	return r.State == probe.Open || r.State == probe.OpenFiltered
+}
+
+func writeProbeResult(w io.Writer, r probeResult) error {
+	if r.RTT == 0 {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\n", r.Target, r.Protocol, r.State)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%.2fms\n", r.Target, r.Protocol, r.State, r.RTT)
+	return err
+}
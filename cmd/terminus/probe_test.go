@@ -0,0 +1,114 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/probe"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNewProbeResult(t *testing.T) {
+	defer func() { lookupIP = net.LookupIP }()
+	lookupIP = func(host string) ([]net.IP, error) {
+		if host == "db.example.com" {
+			return []net.IP{net.ParseIP("192.0.2.5"), net.ParseIP("2001:db8::5")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"192.0.2.1:443", "192.0.2.1:443"},
+		{"[2001:db8::1]:53", "[2001:db8::1]:53"},
+		{"[::ffff:192.0.2.1]:22", "192.0.2.1:22"},
+		{"db.example.com:5432", "192.0.2.5:5432"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.target, func(t *testing.T) {
+			r, err := newProbeResult(tt.target, false)
+			NoError(t, err)
+			Equal(t, tt.want, r.Addr.String())
+			Equal(t, "tcp", r.Protocol)
+		})
+	}
+
+	for _, target := range []string{"192.0.2.1", "192.0.2.1:0", "192.0.2.1:65536", ":443", "2001:db8::1:443"} {
+		_, err := newProbeResult(target, true)
+		EqualError(t, err, "invalid target (IP:PORT or HOST:PORT): "+target)
+		Equal(t, exitParse, exitCode(err))
+	}
+	_, err := newProbeResult("unknown.example.com:80", false)
+	EqualError(t, err, "no such host")
+}
+
+func TestProbeResultRetries(t *testing.T) {
+	defer func() { probeTCP, probeUDP = probe.TCP, probe.UDP }()
+	attempts := 0
+	probeTCP = func(context.Context, netip.AddrPort) (probe.State, time.Duration, error) {
+		if attempts++; attempts < 3 {
+			return probe.Filtered, 0, nil
+		}
+		return probe.Open, 1500 * time.Microsecond, nil
+	}
+	probeUDP = func(context.Context, netip.AddrPort, []byte) (probe.State, time.Duration, error) {
+		return probe.Filtered, 0, errors.New("no route to host")
+	}
+
+	r := probeResult{Target: "192.0.2.1:443", Protocol: "tcp"}
+	r.probe(time.Second, 5)
+	Equal(t, probeResult{Target: "192.0.2.1:443", Protocol: "tcp", State: probe.Open, RTT: 1.5, Attempts: 3}, r)
+	True(t, r.reachable())
+
+	attempts = 0
+	r = probeResult{Target: "192.0.2.1:443", Protocol: "tcp"}
+	r.probe(time.Second, 1)
+	Equal(t, probe.Filtered, r.State)
+	Equal(t, 2, r.Attempts)
+	False(t, r.reachable())
+
+	r = probeResult{Target: "192.0.2.1:53", Protocol: "udp"}
+	r.probe(time.Second, 0)
+	Equal(t, probeResult{Target: "192.0.2.1:53", Protocol: "udp", State: probe.Filtered, Attempts: 1,
+		Error: "no route to host"}, r)
+}
+
+func TestProbeResultOpen(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	r, err := newProbeResult(l.Addr().String(), false)
+	NoError(t, err)
+	r.probe(time.Second, 0)
+	Equal(t, probe.Open, r.State)
+}
+
+func TestWriteProbeResult(t *testing.T) {
+	s := &strings.Builder{}
+	NoError(t, writeProbeResult(s, probeResult{Target: "192.0.2.1:443", Protocol: "tcp", State: probe.Open, RTT: 1.372}))
+	NoError(t, writeProbeResult(s, probeResult{Target: "192.0.2.1:53", Protocol: "udp", State: probe.OpenFiltered}))
+	Equal(t, "192.0.2.1:443\ttcp\topen\t1.37ms\n192.0.2.1:53\tudp\topen|filtered\n", s.String())
+}
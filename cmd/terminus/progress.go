@@ -0,0 +1,130 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// progressInterval is the interval, in which the progress is redrawn on a terminal.
+	progressInterval = 200 * time.Millisecond
+	// progressLogInterval is the interval, in which a progress line is written if stderr is not a terminal.
+	progressLogInterval = 5 * time.Second
+	progressBarWidth    = 20
+)
+
+// progress reports the number of processed items and the rate on stderr, so that stdout remains clean for piping.
+type progress struct {
+	w     io.Writer
+	tty   bool
+	total int64
+	done  atomic.Int64
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newProgress starts reporting the progress on stderr if --progress is set (nil otherwise).
+// total is the number of items to process (0 if unknown).
+func newProgress(cmd *cobra.Command, total int64) *progress {
+	if enabled, _ := cmd.Flags().GetBool("progress"); !enabled {
+		return nil
+	}
+	fi, err := os.Stderr.Stat()
+	tty := err == nil && fi.Mode()&os.ModeCharDevice != 0
+	interval := progressLogInterval
+	if tty {
+		interval = progressInterval
+	}
+	return startProgress(os.Stderr, tty, total, interval)
+}
+
+// startProgress renders the progress to w every interval. On a terminal, the line is redrawn in place.
+func startProgress(w io.Writer, tty bool, total int64, interval time.Duration) *progress {
+	p := &progress{w: w, tty: tty, total: total, start: time.Now(), stop: make(chan struct{})}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				p.print()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// add increments the number of processed items by n.
+func (p *progress) add(n int64) {
+	if p != nil {
+		p.done.Add(n)
+	}
+}
+
+// finish stops the reporting and renders the final state.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+	p.print()
+	if p.tty {
+		_, _ = fmt.Fprintln(p.w)
+	}
+}
+
+func (p *progress) print() {
+	if p.tty {
+		// clear the rest of the line in case the previous one was longer
+		_, _ = fmt.Fprint(p.w, "\r", p.render(time.Now()), "\x1b[K")
+		return
+	}
+	_, _ = fmt.Fprintln(p.w, p.render(time.Now()))
+}
+
+// render returns a progress bar and the number of processed items (of the total, if known) along with the rate.
+func (p *progress) render(t time.Time) string {
+	done := p.done.Load()
+	rate := int64(0)
+	if d := t.Sub(p.start); d > 0 {
+		rate = int64(float64(done) / d.Seconds())
+	}
+	if p.total <= 0 {
+		return fmt.Sprintf("%s processed (%s/s)", loc.format(done), loc.format(rate))
+	}
+
+	n := done
+	if n > p.total {
+		n = p.total
+	}
+	bar := strings.Repeat("=", int(n*progressBarWidth/p.total))
+	return fmt.Sprintf("[%-*s] %3d%% %s/%s (%s/s)", progressBarWidth, bar, n*100/p.total,
+		loc.format(done), loc.format(p.total), loc.format(rate))
+}
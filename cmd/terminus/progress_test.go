@@ -0,0 +1,64 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestProgressRender(t *testing.T) {
+	start := time.Date(2024, 5, 4, 10, 20, 30, 0, time.UTC)
+	p := &progress{total: 200, start: start}
+	Equal(t, "[                    ]   0% 0/200 (0/s)", p.render(start))
+
+	p.add(50)
+	Equal(t, "[=====               ]  25% 50/200 (25/s)", p.render(start.Add(2*time.Second)))
+	p.add(150)
+	Equal(t, "[====================] 100% 200/200 (50/s)", p.render(start.Add(4*time.Second)))
+
+	p = &progress{start: start}
+	p.add(1234)
+	Equal(t, "1234 processed (617/s)", p.render(start.Add(2*time.Second)))
+}
+
+func TestProgressFinish(t *testing.T) {
+	s := &strings.Builder{}
+	p := startProgress(s, false, 2, time.Hour)
+	p.add(2)
+	p.finish()
+	Regexp(t, `^\[=+\] 100% 2/2 \(\d+/s\)\n$`, s.String())
+
+	s.Reset()
+	p = startProgress(s, true, 0, time.Hour)
+	p.add(1)
+	p.finish()
+	Regexp(t, "^\r1 processed \\(\\d+/s\\)\x1b\\[K\n$", s.String())
+}
+
+func TestNewProgressDisabled(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("progress", false, "")
+	p := newProgress(cmd, 10)
+	Nil(t, p)
+
+	// a disabled progress can be used like an enabled one
+	p.add(1)
+	p.finish()
+}
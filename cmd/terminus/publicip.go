@@ -0,0 +1,184 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/stun"
+	"github.com/spf13/cobra"
+)
+
+var publicIPCmd = &cobra.Command{
+	Use:   "public-ip [flags]",
+	Short: "Discover the public IPv4 and IPv6 address",
+	Long: `Discover the public IPv4 and IPv6 address of the host (as seen by other hosts on the Internet).
+The addresses are queried from HTTPS endpoints, which respond with the IP address of the client, or a STUN server.`,
+	Example: `  terminus public-ip
+  # ipv4: 203.0.113.7
+  # ipv6: 2001:db8::7
+
+  terminus public-ip -4 --method stun --stun-server stun.example.com:3478
+  terminus public-ip --endpoint https://ip.example.com -o json`,
+	Args:        cobra.NoArgs,
+	Run:         runPublicIPCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	publicIPCmd.Flags().SortFlags = false
+	publicIPCmd.Flags().BoolP("ipv4", "4", false, "Discover the public IPv4 address only")
+	publicIPCmd.Flags().BoolP("ipv6", "6", false, "Discover the public IPv6 address only")
+	publicIPCmd.Flags().String("method", "https", "Discovery method (https, stun)")
+	publicIPCmd.Flags().StringArray("endpoint", []string{"https://api64.ipify.org", "https://icanhazip.com"},
+		"HTTPS endpoint, which responds with the IP address of the client (tried in order)")
+	publicIPCmd.Flags().String("stun-server", "stun.l.google.com:19302", "STUN server (HOST:PORT)")
+	publicIPCmd.Flags().Duration("timeout", 5*time.Second, "Timeout per address family")
+	publicIPCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(publicIPCmd)
+}
+
+func runPublicIPCmd(cmd *cobra.Command, _ []string) {
+	v4, _ := cmd.Flags().GetBool("ipv4")
+	v6, _ := cmd.Flags().GetBool("ipv6")
+	method, _ := cmd.Flags().GetString("method")
+	endpoints, _ := cmd.Flags().GetStringArray("endpoint")
+	server, _ := cmd.Flags().GetString("stun-server")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	output, _ := cmd.Flags().GetString("output")
+
+	var discover func(ctx context.Context, family string) (netip.Addr, error)
+	switch method {
+	case "https":
+		discover = func(ctx context.Context, family string) (netip.Addr, error) {
+			return discoverHTTPS(ctx, "tcp"+family, endpoints)
+		}
+	case "stun":
+		discover = func(ctx context.Context, family string) (netip.Addr, error) {
+			ap, err := stun.Query(ctx, "udp"+family, server)
+			return ap.Addr(), err
+		}
+	default:
+		fatal(usageError("unsupported discovery method: " + method))
+	}
+
+	ips := publicIPs{}
+	var errs []string
+	for _, family := range []string{"4", "6"} {
+		if v4 != v6 && (family == "4") != v4 {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ip, err := discover(ctx, family)
+		cancel()
+		if err != nil {
+			errs = append(errs, "IPv"+family+": "+err.Error())
+		} else if family == "4" {
+			ips.IPv4 = ip.String()
+		} else {
+			ips.IPv6 = ip.String()
+		}
+	}
+
+	if ips.IPv4 == "" && ips.IPv6 == "" {
+		fatal(errors.New("cannot discover public IP address: " + strings.Join(errs, ", ")))
+	}
+	if err := ips.write(os.Stdout, output); err != nil {
+		fatal(err)
+	}
+}
+
+// publicIPs holds the public IPv4 and IPv6 address (empty if unknown).
+type publicIPs struct {
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+func (ips publicIPs) write(w io.Writer, output string) error {
+	switch output {
+	case "text":
+		if ips.IPv4 != "" {
+			_, _ = fmt.Fprintf(w, "ipv4: %s\n", ips.IPv4)
+		}
+		if ips.IPv6 != "" {
+			_, _ = fmt.Fprintf(w, "ipv6: %s\n", ips.IPv6)
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(ips)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
+
+// discoverHTTPS queries the endpoints in order via the given network ("tcp4" or "tcp6") and returns the first
+// IP address of the respective family.
+func discoverHTTPS(ctx context.Context, network string, endpoints []string) (netip.Addr, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+	client := &http.Client{Transport: tr}
+	defer tr.CloseIdleConnections()
+
+	err := errors.New("no endpoints")
+	for _, ep := range endpoints {
+		var ip netip.Addr
+		if ip, err = queryEndpoint(ctx, client, ep); err == nil {
+			if ip.Is4() == (network == "tcp4") {
+				return ip, nil
+			}
+			err = errors.New("unexpected address family: " + ip.String())
+		}
+	}
+	return netip.Addr{}, err
+}
+
+func queryEndpoint(ctx context.Context, client *http.Client, endpoint string) (netip.Addr, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	req.Header.Set("User-Agent", "terminus/"+version)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("cannot query %s: %s", endpoint, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	ip, err := netip.ParseAddr(strings.TrimSpace(string(b)))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+	return ip.Unmap(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDiscoverHTTPS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, _ = fmt.Fprintln(w, host)
+	}))
+	defer srv.Close()
+
+	ip, err := discoverHTTPS(context.Background(), "tcp4", []string{srv.URL + "/fail", srv.URL})
+	NoError(t, err)
+	Equal(t, "127.0.0.1", ip.String())
+
+	_, err = discoverHTTPS(context.Background(), "tcp4", []string{srv.URL + "/fail"})
+	EqualError(t, err, "cannot query "+srv.URL+"/fail: 503 Service Unavailable")
+}
+
+func TestPublicIPsWrite(t *testing.T) {
+	ips := publicIPs{IPv4: "203.0.113.7", IPv6: "2001:db8::7"}
+	s := &strings.Builder{}
+	NoError(t, ips.write(s, "text"))
+	Equal(t, "ipv4: 203.0.113.7\nipv6: 2001:db8::7\n", s.String())
+
+	s.Reset()
+	NoError(t, publicIPs{IPv4: "203.0.113.7"}.write(s, "json"))
+	Equal(t, `{"ipv4":"203.0.113.7"}`+"\n", s.String())
+
+	EqualError(t, ips.write(s, "xml"), "unsupported output format: xml")
+}
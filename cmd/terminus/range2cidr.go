@@ -0,0 +1,98 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var range2cidrCmd = &cobra.Command{
+	Use: `range2cidr [flags] START END
+  terminus range2cidr [flags] RANGE...`,
+	Short: "Convert IP ranges into the minimal list of prefixes",
+	Long: `Convert IP ranges into the minimal list of prefixes, which covers exactly the same addresses.
+A range is given by its first and last IP address, in FROM-TO notation or as nmap-style octet range
+(e.g., 10.0.0-3.1-254). Multiple ranges are aggregated.
+The conversion into the other direction is done by "terminus torange CIDR".`,
+	Example: `  terminus range2cidr 192.168.1.10 192.168.1.50
+  # 192.168.1.10/31
+  # 192.168.1.12/30
+  # 192.168.1.16/28
+  # 192.168.1.32/28
+  # 192.168.1.48/31
+  # 192.168.1.50/32
+
+  terminus range2cidr 10.0.0-3.*
+  # 10.0.0.0/22`,
+	Aliases: []string{"tocidr"},
+	Args:    cobra.MinimumNArgs(1),
+	Run:     runRange2CIDRCmd,
+}
+
+func init() {
+	range2cidrCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(range2cidrCmd)
+}
+
+func runRange2CIDRCmd(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+
+	rs, err := parseRanges(args)
+	if err != nil {
+		fatal(err)
+	}
+	out := newOutput(cmd)
+	if err := writeAggregate(out, ipset.New(rs...).Prefixes(), output); err != nil {
+		fatal(err)
+	}
+	_ = out.Flush()
+}
+
+// parseRanges parses the first and last IP address of a range (START END) or a list of ranges.
+// IP addresses and networks in CIDR notation are accepted as well.
+func parseRanges(args []string) ([]ipset.Range, error) {
+	if len(args) == 2 {
+		from, errFrom := netip.ParseAddr(args[0])
+		to, errTo := netip.ParseAddr(args[1])
+		if errFrom == nil && errTo == nil {
+			r, err := ipset.ParseRange(from.String() + "-" + to.String())
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %s", ErrInvalidCIDR, args[0], args[1])
+			}
+			return []ipset.Range{r}, nil
+		}
+	}
+
+	var rs []ipset.Range
+	for _, arg := range args {
+		if r, ok, err := parseRangeArg(arg); err != nil {
+			return nil, err
+		} else if ok {
+			rs = append(rs, r...)
+			continue
+		}
+
+		r, err := ipset.ParseRange(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseRanges(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"192.168.1.10", "192.168.1.50"},
+			"[192.168.1.10/31 192.168.1.12/30 192.168.1.16/28 192.168.1.32/28 192.168.1.48/31 192.168.1.50/32]"},
+		{[]string{"10.0.0.0-10.0.1.255"}, "[10.0.0.0/23]"},
+		{[]string{"10.0.0-3.*", "10.0.4.0/24"}, "[10.0.0.0/22 10.0.4.0/24]"},
+		{[]string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, "[10.0.0.1/32 10.0.0.2/31]"},
+		{[]string{"2001:db8::", "2001:db8::ff"}, "[2001:db8::/120]"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want, func(t *testing.T) {
+			rs, err := parseRanges(tt.args)
+			NoError(t, err)
+			Equal(t, tt.want, fmt.Sprint(ipset.New(rs...).Prefixes()))
+		})
+	}
+
+	_, err := parseRanges([]string{"10.0.0.9", "10.0.0.1"})
+	EqualError(t, err, "invalid IP address or CIDR: 10.0.0.9 10.0.0.1")
+	_, err = parseRanges([]string{"10.0.0.1", "foo"})
+	ErrorIs(t, err, ErrInvalidCIDR)
+}
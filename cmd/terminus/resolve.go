@@ -0,0 +1,86 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/doh"
+)
+
+// dnsResolver looks up host names and IP addresses (implemented by net.Resolver and doh.Resolver).
+type dnsResolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+// newResolver returns the resolver, which sends DNS queries to the given server (IP or IP:PORT) or DoH endpoint,
+// or the default resolver of the system if both are empty.
+func newResolver(addr, dohURL string) (dnsResolver, error) {
+	switch {
+	case addr != "" && dohURL != "":
+		return nil, errors.New("cannot use --resolver and --doh together")
+	case dohURL != "":
+		if u, err := url.Parse(dohURL); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			return nil, errors.New("invalid DoH URL: " + dohURL)
+		}
+		return &doh.Resolver{URL: dohURL, Client: httpClient}, nil
+	case addr == "":
+		return net.DefaultResolver, nil
+	}
+
+	hostPort := addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		hostPort = net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+	}
+	if host, _, _ := net.SplitHostPort(hostPort); net.ParseIP(host) == nil {
+		return nil, errors.New("invalid resolver (must be IP or IP:PORT): " + addr)
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, network, hostPort)
+		},
+	}, nil
+}
+
+// lookupPTR returns the first PTR record of ip (without trailing dot) or an empty string if there is none.
+func lookupPTR(r dnsResolver, ip net.IP, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	names, err := r.LookupAddr(ctx, ip.String())
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return "", nil
+	} else if err != nil || len(names) == 0 {
+		return "", err
+	}
+	return strings.TrimSuffix(names[0], "."), nil
+}
+
+// lookupHost returns the IP addresses of the host name.
+func lookupHost(r dnsResolver, host string, timeout time.Duration) ([]net.IP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.LookupIP(ctx, "ip", host)
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/doh"
+	. "github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// serveDNS starts a DNS server, which answers PTR queries with the given names (NXDOMAIN otherwise),
+// and returns its address.
+func serveDNS(t *testing.T, ptrs map[string]string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := answerPTR(buf[:n], ptrs); resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func answerPTR(req []byte, ptrs map[string]string) []byte {
+	var m dnsmessage.Message
+	if m.Unpack(req) != nil || len(m.Questions) == 0 {
+		return nil
+	}
+	q := m.Questions[0]
+	m.Header.Response, m.Header.RecursionAvailable = true, true
+	m.Header.RCode = dnsmessage.RCodeNameError
+	if name, ok := ptrs[q.Name.String()]; ok && q.Type == dnsmessage.TypePTR {
+		m.Header.RCode = dnsmessage.RCodeSuccess
+		m.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+			Body:   &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(name)},
+		}}
+	}
+	b, _ := m.Pack()
+	return b
+}
+
+func TestLookupPTR(t *testing.T) {
+	addr := serveDNS(t, map[string]string{"1.2.0.192.in-addr.arpa.": "host1.example.com."})
+	r, err := newResolver(addr, "")
+	NoError(t, err)
+
+	name, err := lookupPTR(r, net.ParseIP("192.0.2.1"), time.Second)
+	NoError(t, err)
+	Equal(t, "host1.example.com", name)
+
+	name, err = lookupPTR(r, net.ParseIP("192.0.2.2"), time.Second)
+	NoError(t, err)
+	Empty(t, name)
+}
+
+func TestNewResolver(t *testing.T) {
+	r, err := newResolver("", "")
+	NoError(t, err)
+	Same(t, net.DefaultResolver, r)
+
+	for _, addr := range []string{"192.0.2.53", "192.0.2.53:5353", "2001:db8::53", "[2001:db8::53]:53"} {
+		_, err := newResolver(addr, "")
+		NoError(t, err, addr)
+	}
+	_, err = newResolver("dns.example.com", "")
+	EqualError(t, err, "invalid resolver (must be IP or IP:PORT): dns.example.com")
+
+	r, err = newResolver("", "https://dns.example.com/dns-query")
+	NoError(t, err)
+	Equal(t, &doh.Resolver{URL: "https://dns.example.com/dns-query", Client: httpClient}, r)
+	for _, u := range []string{"dns.example.com", "ftp://dns.example.com/", "https:///dns-query"} {
+		_, err = newResolver("", u)
+		EqualError(t, err, "invalid DoH URL: "+u)
+	}
+	_, err = newResolver("192.0.2.53", "https://dns.example.com/dns-query")
+	EqualError(t, err, "cannot use --resolver and --doh together")
+}
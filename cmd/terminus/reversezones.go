@@ -0,0 +1,127 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/abc-inc/terminus/rdns"
+	"github.com/spf13/cobra"
+)
+
+var reverseZonesCmd = &cobra.Command{
+	Use:   "reverse-zones [flags] [CIDR...]",
+	Short: "Plan the reverse DNS zones, which cover the given subnets",
+	Long: `Derive the reverse DNS zones (in-addr.arpa and ip6.arpa), which are needed to cover the given subnets.
+The subnets are aggregated first. IPv4 subnets are split at octet boundaries and subnets longer than /24 become
+classless delegations according to RFC 2317 (e.g., 64/26.2.0.192.in-addr.arpa delegated from 2.0.192.in-addr.arpa).
+IPv6 subnets are split at nibble boundaries.
+If no CIDR is given (or "-"), CIDRs are read from stdin (one per line).
+
+With --zones, the zones are compared against the zone statements of a named.conf file (or a list of zone names).
+Then, only missing zones and obsolete reverse zones are printed and the exit status is 1 if there is any.
+A zone is not missing if a parent zone exists.`,
+	Example: `  terminus reverse-zones 10.1.0.0/23 192.0.2.64/26
+  # 0.1.10.in-addr.arpa	10.1.0.0/24
+  # 1.1.10.in-addr.arpa	10.1.1.0/24
+  # 64/26.2.0.192.in-addr.arpa	192.0.2.64/26	2.0.192.in-addr.arpa
+
+  terminus reverse-zones --zones /etc/bind/named.conf < allocated.txt
+  # 64/26.2.0.192.in-addr.arpa	192.0.2.64/26	2.0.192.in-addr.arpa	missing
+  # 113.0.203.in-addr.arpa			obsolete`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runReverseZonesCmd,
+}
+
+func init() {
+	reverseZonesCmd.Flags().String("zones", "", "Compare against the zones of a named.conf file or a list of zone names")
+	reverseZonesCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(reverseZonesCmd)
+}
+
+func runReverseZonesCmd(cmd *cobra.Command, args []string) {
+	zonesFile, _ := cmd.Flags().GetString("zones")
+	output, _ := cmd.Flags().GetString("output")
+
+	ps, err := readPrefixArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+	zs := rdns.Zones(setOf(ps).Prefixes())
+	rs := make([]reverseZone, len(zs))
+	for i, z := range zs {
+		rs[i] = reverseZone{Zone: z}
+	}
+
+	if zonesFile != "" {
+		b, err := readInputFile(zonesFile)
+		if err != nil {
+			fatal(err)
+		}
+		rs = diffZones(zs, rdns.ParseInventory(b))
+	}
+
+	if err := writeReverseZones(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+	if zonesFile != "" && len(rs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// reverseZone is a reverse DNS zone along with its status compared to the inventory (missing or obsolete).
+type reverseZone struct {
+	rdns.Zone
+	Status string `json:"status,omitempty"`
+}
+
+// diffZones returns the missing zones followed by the obsolete zones of the inventory.
+func diffZones(zs []rdns.Zone, inventory []string) []reverseZone {
+	missing, obsolete := rdns.Diff(zs, inventory)
+	rs := make([]reverseZone, 0, len(missing)+len(obsolete))
+	for _, z := range missing {
+		rs = append(rs, reverseZone{Zone: z, Status: "missing"})
+	}
+	for _, n := range obsolete {
+		rs = append(rs, reverseZone{Zone: rdns.Zone{Name: n}, Status: "obsolete"})
+	}
+	return rs
+}
+
+func writeReverseZones(w io.Writer, rs []reverseZone, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			p := ""
+			if r.Prefix.IsValid() {
+				p = r.Prefix.String()
+			}
+			l := fmt.Sprintf("%s\t%s\t%s\t%s", r.Name, p, r.Parent, r.Status)
+			_, _ = fmt.Fprintln(w, strings.TrimRight(l, "\t"))
+		}
+		return nil
+	case "json":
+		if rs == nil {
+			rs = []reverseZone{}
+		}
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/rdns"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDiffZones(t *testing.T) {
+	zs := rdns.Zones([]netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("10.1.0.0/16")})
+	rs := diffZones(zs, []string{"10.in-addr.arpa", "113.0.203.in-addr.arpa"})
+	Equal(t, []reverseZone{
+		{Zone: zs[0], Status: "missing"},
+		{Zone: rdns.Zone{Name: "113.0.203.in-addr.arpa"}, Status: "obsolete"},
+	}, rs)
+}
+
+func TestWriteReverseZones(t *testing.T) {
+	rs := []reverseZone{
+		{Zone: rdns.Zones([]netip.Prefix{netip.MustParsePrefix("192.0.2.64/26")})[0], Status: "missing"},
+		{Zone: rdns.Zone{Name: "113.0.203.in-addr.arpa"}, Status: "obsolete"},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeReverseZones(s, rs, "text"))
+	Equal(t, "64/26.2.0.192.in-addr.arpa\t192.0.2.64/26\t2.0.192.in-addr.arpa\tmissing\n"+
+		"113.0.203.in-addr.arpa\t\t\tobsolete\n", s.String())
+
+	s.Reset()
+	NoError(t, writeReverseZones(s, rs[:1], "json"))
+	Equal(t, `[{"name":"64/26.2.0.192.in-addr.arpa","prefix":"192.0.2.64/26",`+
+		`"parent":"2.0.192.in-addr.arpa","status":"missing"}]`+"\n", s.String())
+
+	s.Reset()
+	NoError(t, writeReverseZones(s, nil, "json"))
+	Equal(t, "[]\n", s.String())
+
+	EqualError(t, writeReverseZones(s, rs, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+var routeCmd = &cobra.Command{
+	Use:   "route IP",
+	Short: "Look up the route, which covers an IP address",
+	Long: `Look up the route, which covers an IP address, in the local routing table.
+It prints the destination network, the gateway (or "on-link"), the outgoing interface and the metric.`,
+	Example: `  terminus route 1.1.1.1
+  # destination: 0.0.0.0/0
+  # gateway:     192.168.1.1
+  # interface:   eth0
+  # metric:      100`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRouteCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+}
+
+func runRouteCmd(_ *cobra.Command, args []string) {
+	ip := net.ParseIP(args[0])
+	if ip == nil {
+		fatal(&net.ParseError{Type: "IP address", Text: args[0]})
+	}
+
+	if ip.To4() == nil {
+		if err := supported(iface.FeatureIPv6Routes); err != nil {
+			fatal(err)
+		}
+	}
+	rs, err := iface.Routes()
+	if err != nil {
+		fatal(err)
+	}
+	r, err := iface.LookupRoute(rs, ip)
+	if err != nil {
+		fatal(err)
+	}
+	printRoute(os.Stdout, r)
+}
+
+func printRoute(w io.Writer, r iface.Route) {
+	gw := "on-link"
+	if r.Gateway != nil {
+		gw = r.Gateway.String()
+	}
+
+	_, _ = fmt.Fprintf(w, "destination: %v\n", r.Dst)
+	_, _ = fmt.Fprintf(w, "gateway:     %s\n", gw)
+	_, _ = fmt.Fprintf(w, "interface:   %s\n", r.Interface)
+	_, _ = fmt.Fprintf(w, "metric:      %d\n", r.Metric)
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrintRoute(t *testing.T) {
+	_, dst, _ := net.ParseCIDR("0.0.0.0/0")
+	s := &strings.Builder{}
+	printRoute(s, iface.Route{Dst: dst, Gateway: net.ParseIP("192.168.1.1"), Interface: "eth0", Metric: 100})
+	Equal(t, "destination: 0.0.0.0/0\ngateway:     192.168.1.1\ninterface:   eth0\nmetric:      100\n", s.String())
+
+	_, dst, _ = net.ParseCIDR("fe80::/64")
+	s.Reset()
+	printRoute(s, iface.Route{Dst: dst, Interface: "eth0", Metric: 256})
+	Equal(t, "destination: fe80::/64\ngateway:     on-link\ninterface:   eth0\nmetric:      256\n", s.String())
+}
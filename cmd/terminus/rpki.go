@@ -0,0 +1,206 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/asn"
+	"github.com/abc-inc/terminus/rpki"
+	"github.com/spf13/cobra"
+)
+
+var rpkiCmd = &cobra.Command{
+	Use:   "rpki [flags] [PREFIX ASN]...",
+	Short: "Validate the origin AS of routes against the RPKI",
+	Long: `Validate the origin AS of routes (RFC 6811) against the validated ROA payloads (VRPs) of the RPKI.
+The VRPs are fetched from an RPKI cache via the RPKI-to-Router protocol (--rtr) or read from a JSON export (--vrps)
+like the jsonext output of Routinator or the JSON output of rpki-client. Alternatively, the validity of each route is
+queried from the HTTP API of Routinator (--routinator).
+If no route is given, routes are read from stdin (one "PREFIX ASN" per line).
+The exit status is 1 if any route is invalid.`,
+	Example: `  terminus rpki --rtr localhost:3323 203.0.113.0/24 AS64500
+  # 203.0.113.0/24	AS64500	valid	203.0.113.0/24-24 AS64500
+
+  terminus rpki --routinator http://localhost:8323 -o json < announced.txt`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args)%2 != 0 {
+			return errors.New("routes must be given as PREFIX ASN pairs")
+		}
+		return nil
+	},
+	Run:         runRPKICmd,
+	Annotations: map[string]string{sandboxNetwork: "rtr,routinator"},
+}
+
+func init() {
+	rpkiCmd.Flags().String("rtr", "", "RPKI cache (HOST:PORT) to fetch the VRPs from via RTR")
+	rpkiCmd.Flags().String("routinator", "", "Base URL of the Routinator HTTP API")
+	rpkiCmd.Flags().String("vrps", "", "JSON export of the VRPs (file or URL)")
+	rpkiCmd.MarkFlagsMutuallyExclusive("rtr", "routinator", "vrps")
+	rpkiCmd.Flags().Duration("timeout", 30*time.Second, "Timeout for fetching the VRPs or querying a route")
+	rpkiCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(rpkiCmd)
+}
+
+func runRPKICmd(cmd *cobra.Command, args []string) {
+	rtrAddr, _ := cmd.Flags().GetString("rtr")
+	routinator, _ := cmd.Flags().GetString("routinator")
+	vrpsName, _ := cmd.Flags().GetString("vrps")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	output, _ := cmd.Flags().GetString("output")
+
+	if rtrAddr == "" && routinator == "" && vrpsName == "" {
+		fatal(usageError("one of --rtr, --routinator or --vrps is required"))
+	}
+
+	if len(args) == 0 {
+		b, err := readInputFile("-")
+		if err != nil {
+			fatal(err)
+		}
+		args = parseRouteLines(b)
+	}
+	routes, err := parseRoutes(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	validate := func(r route) (rpki.Result, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return rpki.LookupRoutinator(ctx, http.DefaultClient, routinator, r.Prefix, r.ASN)
+	}
+	if routinator == "" {
+		vrps, err := loadVRPs(rtrAddr, vrpsName, timeout)
+		if err != nil {
+			fatal(err)
+		}
+		validate = func(r route) (rpki.Result, error) {
+			return rpki.Validate(vrps, r.Prefix, r.ASN), nil
+		}
+	}
+
+	rs := make([]rpki.Result, len(routes))
+	invalid := false
+	for i, r := range routes {
+		if rs[i], err = validate(r); err != nil {
+			fatal(err)
+		}
+		invalid = invalid || rs[i].State == rpki.Invalid
+	}
+
+	if err := writeRPKI(os.Stdout, rs, output); err != nil {
+		fatal(err)
+	}
+	if invalid {
+		os.Exit(1)
+	}
+}
+
+// route is a prefix originated by an AS.
+type route struct {
+	Prefix netip.Prefix
+	ASN    uint32
+}
+
+// parseRoutes parses PREFIX ASN pairs.
+func parseRoutes(args []string) ([]route, error) {
+	if len(args)%2 != 0 {
+		return nil, errors.New("missing AS number for " + args[len(args)-1])
+	}
+	rs := make([]route, len(args)/2)
+	for i := range rs {
+		ps, err := parsePrefixes(args[2*i : 2*i+1])
+		if err != nil {
+			return nil, err
+		}
+		n, err := asn.ParseASN(args[2*i+1])
+		if err != nil {
+			return nil, err
+		}
+		rs[i] = route{ps[0], n}
+	}
+	return rs, nil
+}
+
+// parseRouteLines returns the first two fields of every line, skipping empty lines and comments (# or ;).
+func parseRouteLines(b []byte) (args []string) {
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		l := sc.Text()
+		if i := strings.IndexAny(l, "#;"); i >= 0 {
+			l = l[:i]
+		}
+		if fs := strings.Fields(l); len(fs) >= 2 {
+			args = append(args, fs[0], fs[1])
+		} else if len(fs) == 1 {
+			args = append(args, fs[0])
+		}
+	}
+	return args
+}
+
+// loadVRPs fetches the VRPs from an RPKI cache or reads them from a JSON export.
+func loadVRPs(rtrAddr, name string, timeout time.Duration) ([]rpki.VRP, error) {
+	if rtrAddr != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return rpki.FetchRTR(ctx, rtrAddr)
+	}
+	b, err := readInputFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return rpki.ReadJSON(bytes.NewReader(b))
+}
+
+func writeRPKI(w io.Writer, rs []rpki.Result, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			state := string(r.State)
+			if r.Reason != "" {
+				state += " (" + r.Reason + ")"
+			}
+			vrps := r.Matched
+			if len(vrps) == 0 {
+				vrps = r.Unmatched
+			}
+			ss := make([]string, len(vrps))
+			for i, v := range vrps {
+				ss[i] = v.String()
+			}
+			_, _ = fmt.Fprintln(w, strings.TrimRight(
+				fmt.Sprintf("%s\tAS%d\t%s\t%s", r.Prefix, r.ASN, state, strings.Join(ss, ", ")), "\t"))
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(rs)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
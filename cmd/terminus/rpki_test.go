@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/rpki"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseRoutes(t *testing.T) {
+	rs, err := parseRoutes(parseRouteLines([]byte("# announced\n203.0.113.1/24 AS64500\n2001:db8::/32\t64501 ; x\n")))
+	NoError(t, err)
+	Equal(t, []route{
+		{netip.MustParsePrefix("203.0.113.0/24"), 64500},
+		{netip.MustParsePrefix("2001:db8::/32"), 64501},
+	}, rs)
+
+	_, err = parseRoutes([]string{"203.0.113.0/24"})
+	EqualError(t, err, "missing AS number for 203.0.113.0/24")
+	_, err = parseRoutes([]string{"203.0.113.0/24", "ASX"})
+	Error(t, err)
+}
+
+func TestWriteRPKI(t *testing.T) {
+	vrp := rpki.VRP{ASN: 64500, Prefix: netip.MustParsePrefix("203.0.113.0/24"), MaxLength: 24}
+	rs := []rpki.Result{
+		{Prefix: vrp.Prefix, ASN: 64500, State: rpki.Valid, Matched: []rpki.VRP{vrp}},
+		{Prefix: vrp.Prefix, ASN: 64501, State: rpki.Invalid, Reason: "as", Unmatched: []rpki.VRP{vrp}},
+		{Prefix: netip.MustParsePrefix("198.51.100.0/24"), ASN: 64500, State: rpki.NotFound},
+	}
+
+	s := &strings.Builder{}
+	NoError(t, writeRPKI(s, rs, "text"))
+	Equal(t, "203.0.113.0/24\tAS64500\tvalid\t203.0.113.0/24-24 AS64500\n"+
+		"203.0.113.0/24\tAS64501\tinvalid (as)\t203.0.113.0/24-24 AS64500\n"+
+		"198.51.100.0/24\tAS64500\tnot-found\n", s.String())
+
+	s.Reset()
+	NoError(t, writeRPKI(s, rs[2:], "json"))
+	Equal(t, `[{"prefix":"198.51.100.0/24","asn":64500,"state":"not-found"}]`+"\n", s.String())
+
+	EqualError(t, writeRPKI(s, rs, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// sandboxNetwork is the annotation listing the flags, which require network access if set
+	// (or "always" if the command requires network access anyway). The pseudo flag "hosts" requires network access
+	// if an argument is a host name, which has to be resolved.
+	sandboxNetwork = "terminus/sandbox-network"
+	// sandboxOffline is the annotation listing the flags, which make a command work offline, although it requires
+	// network access "always" otherwise (e.g., a local database instead of an online service).
+	sandboxOffline = "terminus/sandbox-offline"
+	// sandboxWrite is the annotation listing the flags, whose values are files to be written.
+	sandboxWrite = "terminus/sandbox-write"
+)
+
+// sandboxPolicy describes the resources, which remain accessible in sandbox mode.
+// Everything else (apart from reading files) is denied.
+type sandboxPolicy struct {
+	network  bool
+	writable []string
+}
+
+// applySandbox restricts the process according to the sandbox policy of cmd if --sandbox is set.
+func applySandbox(cmd *cobra.Command, args []string) {
+	if enabled, _ := cmd.Flags().GetBool("sandbox"); enabled {
+		if err := sandbox(newSandboxPolicy(cmd, args)); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// newSandboxPolicy derives the sandbox policy from the annotations, flags and arguments of cmd.
+// Network access is granted if the annotations require it or an argument or flag value is a URL.
+// In the latter case, the cache directory remains writable as well.
+// Host names in input files are not taken into account, i.e., untrusted files cannot cause network access.
+func newSandboxPolicy(cmd *cobra.Command, args []string) (p sandboxPolicy) {
+	p.network = cmd.Annotations[sandboxNetwork] == "always"
+	for _, name := range strings.Split(cmd.Annotations[sandboxOffline], ",") {
+		p.network = p.network && (name == "" || !cmd.Flags().Changed(name))
+	}
+	netFlags := strings.Split(cmd.Annotations[sandboxNetwork], ",")
+	for _, v := range args {
+		p.network = p.network || isURL(v) || contains(netFlags, "hosts") && isHostInput(cmd, v)
+	}
+
+	writeFlags := strings.Split(cmd.Annotations[sandboxWrite], ",")
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		vals := []string{f.Value.String()}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			vals = sv.GetSlice()
+		}
+		for _, v := range vals {
+			p.network = p.network || contains(netFlags, f.Name) || isURL(v)
+			if contains(writeFlags, f.Name) && v != "" && v != "-" {
+				if abs, err := filepath.Abs(v); err == nil {
+					p.writable = append(p.writable, filepath.Dir(abs))
+				}
+			}
+		}
+	})
+
+	if dir, err := os.UserCacheDir(); err == nil && p.network {
+		if dir = filepath.Join(dir, "terminus"); os.MkdirAll(dir, 0o700) == nil {
+			p.writable = append(p.writable, dir)
+		}
+	}
+	return p
+}
+
+// isHostInput reports whether the argument is a host name (detected or forced by --input-type).
+func isHostInput(cmd *cobra.Command, arg string) bool {
+	if f := cmd.Flags().Lookup("input-type"); f != nil && f.Value.String() == "host" {
+		return true
+	}
+	host, _ := splitPort(arg)
+	return detectInputType(host) == "host"
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
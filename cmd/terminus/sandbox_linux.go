@@ -0,0 +1,150 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && (amd64 || arm64)
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/abc-inc/terminus/iface"
+	"golang.org/x/sys/unix"
+)
+
+var sandboxCapability = iface.Capability{
+	Feature: featureSandbox, Description: "sandbox mode", Supported: true, Detail: "landlock (Linux 5.13+) and seccomp",
+}
+
+const (
+	seccompSetModeFilter   = 1
+	seccompFilterFlagTSync = 1
+	seccompRetKillProcess  = 0x80000000
+	seccompRetErrno        = 0x00050000
+	seccompRetAllow        = 0x7fff0000
+	x32SyscallBit          = 0x40000000
+)
+
+// sandbox applies a landlock ruleset, which makes the file system read-only (except for the writable paths),
+// and a seccomp filter, which denies IPv4 and IPv6 sockets unless network access is granted.
+// Both apply to all threads, which requires a build without cgo (see syscall.AllThreadsSyscall).
+func sandbox(p sandboxPolicy) error {
+	if _, _, errno := syscall.AllThreadsSyscall(syscall.SYS_PRCTL, unix.PR_SET_NO_NEW_PRIVS, 1, 0); errno != 0 {
+		return fmt.Errorf("cannot enter sandbox mode: %w", errno)
+	}
+	if err := restrictFS(p.writable); err != nil {
+		return fmt.Errorf("cannot enter sandbox mode: %w", err)
+	}
+	if !p.network {
+		if err := denyIPSockets(); err != nil {
+			return fmt.Errorf("cannot enter sandbox mode: %w", err)
+		}
+	}
+	return nil
+}
+
+// restrictFS allows reading the whole file system, but writing only beneath the given directories.
+func restrictFS(writable []string) error {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return fmt.Errorf("landlock is not available: %w", errno)
+	}
+
+	read := uint64(unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR)
+	all := uint64(unix.LANDLOCK_ACCESS_FS_MAKE_SYM<<1 - 1)
+	if abi >= 2 {
+		all |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		all |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: all}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("cannot create landlock ruleset: %w", errno)
+	}
+	defer func() { _ = unix.Close(int(fd)) }()
+
+	if err := addLandlockRule(fd, "/", read); err != nil {
+		return err
+	}
+	for _, dir := range writable {
+		if err := addLandlockRule(fd, dir, all); err != nil {
+			return err
+		}
+	}
+
+	if _, _, errno = syscall.AllThreadsSyscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("cannot apply landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// addLandlockRule grants the access rights beneath the directory to the landlock ruleset.
+func addLandlockRule(ruleset uintptr, dir string, access uint64) error {
+	fd, err := unix.Open(dir, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", dir, err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, ruleset,
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("cannot add landlock rule for %s: %w", dir, errno)
+	}
+	return nil
+}
+
+// denyIPSockets installs a seccomp filter, which lets socket(2) fail for AF_INET and AF_INET6.
+// Other address families (e.g., netlink for querying network interfaces) remain available.
+func denyIPSockets() error {
+	arch := map[string]uint32{"amd64": unix.AUDIT_ARCH_X86_64, "arm64": unix.AUDIT_ARCH_AARCH64}[runtime.GOARCH]
+	filter := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 4), // seccomp_data.arch
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetKillProcess),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 0), // seccomp_data.nr
+		bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 5, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.SYS_SOCKET, 0, 3),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, 16), // lower half of seccomp_data.args[0]
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.AF_INET, 2, 0),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.AF_INET6, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetAllow),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetErrno|uint32(unix.EACCES)),
+	}
+
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	r, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, seccompFilterFlagTSync,
+		uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("cannot install seccomp filter: %w", errno)
+	} else if r != 0 {
+		return fmt.Errorf("cannot install seccomp filter on thread %d", r)
+	}
+	return nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && (amd64 || arm64)
+
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+// TestSandbox enters sandbox mode in a child process, because it cannot be left again.
+func TestSandbox(t *testing.T) {
+	if dir := os.Getenv("TERMINUS_TEST_SANDBOX"); dir != "" {
+		err := sandbox(sandboxPolicy{writable: []string{dir}})
+		if errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.ENOSYS) {
+			t.Skip(err)
+		}
+		NoError(t, err)
+
+		_, err = net.Dial("tcp", "127.0.0.1:1")
+		ErrorIs(t, err, syscall.EACCES)
+		_, err = net.Interfaces()
+		NoError(t, err)
+		ErrorIs(t, os.WriteFile(filepath.Join(filepath.Dir(dir), "denied.txt"), nil, 0o600), syscall.EACCES)
+		NoError(t, os.WriteFile(filepath.Join(dir, "allowed.txt"), nil, 0o600))
+		_, err = os.ReadFile("/etc/hosts")
+		NoError(t, err)
+		return
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	NoError(t, os.Mkdir(dir, 0o700))
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSandbox$", "-test.v")
+	cmd.Env = append(os.Environ(), "TERMINUS_TEST_SANDBOX="+dir)
+	out, err := cmd.CombinedOutput()
+	NoError(t, err, string(out))
+	if _, err = os.Stat(filepath.Join(dir, "allowed.txt")); err != nil {
+		t.Skip("sandbox mode is not available:\n" + string(out))
+	}
+	NoFileExists(t, filepath.Join(filepath.Dir(dir), "denied.txt"))
+}
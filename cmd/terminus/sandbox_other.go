@@ -0,0 +1,29 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux || !(amd64 || arm64)
+
+package main
+
+import (
+	"github.com/abc-inc/terminus/iface"
+)
+
+var sandboxCapability = iface.Capability{
+	Feature: featureSandbox, Description: "sandbox mode", Detail: "requires Linux on amd64 or arm64",
+}
+
+func sandbox(sandboxPolicy) error {
+	return supported(featureSandbox)
+}
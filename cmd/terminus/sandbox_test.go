@@ -0,0 +1,79 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestNewSandboxPolicy(t *testing.T) {
+	newCmd := func(annotations map[string]string, args ...string) *cobra.Command {
+		cmd := &cobra.Command{Annotations: annotations}
+		cmd.Flags().Bool("resolve", false, "")
+		cmd.Flags().String("out", "-", "")
+		cmd.Flags().StringArray("input-file", nil, "")
+		cmd.Flags().String("input-type", "auto", "")
+		cmd.Flags().String("db", "", "")
+		NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	p := newSandboxPolicy(newCmd(nil), []string{"10.0.0.0/8"})
+	False(t, p.network)
+	Empty(t, p.writable)
+
+	p = newSandboxPolicy(newCmd(map[string]string{sandboxNetwork: "always"}), nil)
+	True(t, p.network)
+
+	offline := map[string]string{sandboxNetwork: "always", sandboxOffline: "db"}
+	True(t, newSandboxPolicy(newCmd(offline), nil).network)
+	False(t, newSandboxPolicy(newCmd(offline, "--db", "rib.mrt"), nil).network)
+	True(t, newSandboxPolicy(newCmd(offline, "--db", "https://example.com/rib.mrt"), nil).network)
+
+	cmd := newCmd(map[string]string{sandboxNetwork: "resolve"})
+	False(t, newSandboxPolicy(cmd, nil).network)
+	cmd = newCmd(map[string]string{sandboxNetwork: "resolve"}, "--resolve")
+	True(t, newSandboxPolicy(cmd, nil).network)
+
+	hosts := map[string]string{sandboxNetwork: "hosts,resolve"}
+	False(t, newSandboxPolicy(newCmd(hosts), []string{"10.0.0.1", "eth0", "3221225985"}).network)
+	False(t, newSandboxPolicy(newCmd(hosts, "--input-type", "ip"), []string{"10.0.0.1"}).network)
+	False(t, newSandboxPolicy(newCmd(nil), []string{"www.example.com"}).network)
+	True(t, newSandboxPolicy(newCmd(hosts), []string{"10.0.0.1", "www.example.com"}).network)
+	True(t, newSandboxPolicy(newCmd(hosts), []string{"www.example.com:443"}).network)
+	True(t, newSandboxPolicy(newCmd(hosts, "--input-type", "host"), []string{"db"}).network)
+	False(t, newSandboxPolicy(newCmd(hosts, "--input-file", "hosts.txt"), nil).network)
+
+	cmd = newCmd(nil, "--input-file", "feed.txt", "--input-file", "https://example.com/feed.txt")
+	True(t, newSandboxPolicy(cmd, nil).network)
+	True(t, newSandboxPolicy(newCmd(nil), []string{"http://example.com/feed.txt"}).network)
+
+	dir := t.TempDir()
+	cmd = newCmd(map[string]string{sandboxWrite: "out"}, "--out", filepath.Join(dir, "map.png"))
+	Equal(t, []string{dir}, newSandboxPolicy(cmd, nil).writable)
+	cmd = newCmd(map[string]string{sandboxWrite: "out"}, "--out", "-")
+	Empty(t, newSandboxPolicy(cmd, nil).writable)
+}
+
+func TestIsURL(t *testing.T) {
+	True(t, isURL("https://example.com"))
+	False(t, isURL("file:///etc/hosts"))
+	False(t, isURL(os.DevNull))
+}
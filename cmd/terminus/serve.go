@@ -0,0 +1,258 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Serve subnet calculations via an HTTP REST API (and gRPC)",
+	Long: `Serve subnet calculations via an HTTP REST API (and gRPC).
+All endpoints respond with JSON, which equals the output of the corresponding command:
+
+  GET  /v1/subnet/IP/PREFIX_LEN                  properties of the subnet (like terminus -o json)
+  GET  /v1/split?cidr=IP/PREFIX_LEN&new-prefix=N  subnets of the subnet (like terminus split)
+  GET  /v1/aggregate?cidr=CIDR&cidr=...           minimal list of prefixes covering all CIDRs
+  POST /v1/aggregate                              same as above, but reads one CIDR per line from the body
+  GET  /v1/contains?cidr=CIDR&ip=IP&ip=...        whether the CIDRs contain the IP addresses
+
+Errors are reported as {"error":"..."} along with status 400.
+Splits are limited to 65536 subnets per request (use terminus split for more).
+Network interfaces are not resolved, hence the host configuration is not exposed.
+
+With --grpc-listen, the gRPC service Terminus (see api/terminus.proto) is provided as well.
+It offers GetSubnet, Split, Aggregate and ListInterfaces. Set --listen "" to disable the REST API.`,
+	Example: `  terminus serve --listen :8080
+  curl -s localhost:8080/v1/subnet/192.168.100.1/24
+  # {"broadcast":"192.168.100.255",...,"wildcard":"0.0.0.255"}
+
+  curl -s 'localhost:8080/v1/split?cidr=192.168.100.0/24&new-prefix=25'
+  # ["192.168.100.0/25","192.168.100.128/25"]
+
+  curl -s 'localhost:8080/v1/contains?cidr=10.0.0.0/8&ip=10.1.2.3&ip=192.0.2.1'
+  # [{"ip":"10.1.2.3","contains":true},{"ip":"192.0.2.1","contains":false}]
+
+  terminus serve --listen "" --grpc-listen :9090
+  grpcurl -plaintext -d '{"cidr":"192.168.100.1/24"}' localhost:9090 terminus.v1.Terminus/GetSubnet`,
+	Args:        cobra.NoArgs,
+	Run:         runServeCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	serveCmd.Flags().String("listen", ":8080", "Address (HOST:PORT) of the REST API (empty to disable)")
+	serveCmd.Flags().String("grpc-listen", "", "Address (HOST:PORT) of the gRPC service (empty to disable)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServeCmd(cmd *cobra.Command, _ []string) {
+	addr, _ := cmd.Flags().GetString("listen")
+	grpcAddr, _ := cmd.Flags().GetString("grpc-listen")
+	if addr == "" && grpcAddr == "" {
+		fatal(usageError("either --listen or --grpc-listen is required"))
+	}
+
+	errs := make(chan error, 2)
+	if addr != "" {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			fatal(err)
+		}
+		log.Print("REST API listening on ", l.Addr())
+		srv := &http.Server{Handler: newServeMux(), ReadHeaderTimeout: 10 * time.Second}
+		go func() { errs <- srv.Serve(l) }()
+	}
+	if grpcAddr != "" {
+		l, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal(err)
+		}
+		log.Print("gRPC service listening on ", l.Addr())
+		go func() { errs <- newGRPCServer().Serve(l) }()
+	}
+	fatal(<-errs)
+}
+
+// newServeMux returns the handler for all endpoints of the REST API.
+func newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/subnet/", get(serveSubnet))
+	mux.HandleFunc("/v1/split", get(serveSplit))
+	mux.HandleFunc("/v1/aggregate", serveAggregate)
+	mux.HandleFunc("/v1/contains", get(serveContains))
+	return mux
+}
+
+// get restricts the handler h to GET (and HEAD) requests.
+func get(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed: "+r.Method))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// subnetKeys are the properties of a subnet, which do not depend on the network interfaces of the host.
+var subnetKeys = []string{iface.Broadcast, iface.Class, iface.ClassfulNetwork, iface.ClassfulBoundary, iface.First,
+	iface.IP, iface.Last, iface.NetMask, iface.Network, iface.Prefix, iface.Size, iface.UsableSize, iface.Version,
+	iface.Wildcard}
+
+// serveSubnet responds with the properties of the subnet in the path.
+// Only subnetKeys are determined, i.e., the interface, gateway, DNS and DHCP configuration is not exposed.
+func serveSubnet(w http.ResponseWriter, r *http.Request) {
+	arg := strings.TrimPrefix(r.URL.Path, "/v1/subnet/")
+	ip, n, err := parseSubnet(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, iface.GetParamsOf(arg, ip, n.Mask, subnetKeys...))
+}
+
+// serveSplit responds with the subnets of the given CIDR.
+func serveSplit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := 0
+	if p := q.Get("new-prefix"); p != "" {
+		var err error
+		if prefix, err = strconv.Atoi(p); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid prefix length: "+p))
+			return
+		}
+	}
+
+	_, n, err := parseSubnet(q.Get("cidr"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sns, err := subnets(n, prefix, maxSubnets)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ns := make([]string, len(sns))
+	for i, sn := range sns {
+		ns[i] = sn.String()
+	}
+	writeJSON(w, ns)
+}
+
+// serveAggregate responds with the minimal list of prefixes covering all CIDRs
+// given as query parameters or, in case of POST requests, in the body.
+func serveAggregate(w http.ResponseWriter, r *http.Request) {
+	args := r.URL.Query()["cidr"]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 16<<20))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		args = append(args, parseTargets(b)...)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed: "+r.Method))
+		return
+	}
+
+	s, err := ipset.Parse(args)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ps := []string{}
+	for _, p := range s.Prefixes() {
+		ps = append(ps, p.String())
+	}
+	writeJSON(w, ps)
+}
+
+// containsResult reports whether the set of CIDRs contains an IP address.
+type containsResult struct {
+	IP       netip.Addr `json:"ip"`
+	Contains bool       `json:"contains"`
+}
+
+// serveContains responds with a containsResult for every IP address.
+func serveContains(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s, err := ipset.Parse(q["cidr"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := []containsResult{}
+	for _, a := range q["ip"] {
+		ip, err := netip.ParseAddr(a)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		ip = ip.Unmap()
+		res = append(res, containsResult{ip, s.Contains(ip)})
+	}
+	writeJSON(w, res)
+}
+
+// parseSubnet is like determineIP, but it accepts IP addresses and CIDRs only i.e., no network interfaces.
+func parseSubnet(arg string) (net.IP, iplib.Net, error) {
+	if _, err := netip.ParsePrefix(arg); err != nil && net.ParseIP(arg) == nil {
+		return nil, iplib.Net{}, fmt.Errorf("%w: %s", ErrInvalidCIDR, arg)
+	}
+	return determineIP(arg)
+}
+
+// writeJSON responds with v encoded as single line of JSON.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(b, '\n'))
+}
+
+// writeError responds with the error message as JSON object and the given status code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	b, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(append(b, '\n'))
+}
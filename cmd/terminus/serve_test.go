@@ -0,0 +1,89 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		method string
+		target string
+		body   string
+		code   int
+		want   string
+	}{
+		{"GET", "/v1/split?cidr=192.168.100.0/24&new-prefix=25", "", 200,
+			`["192.168.100.0/25","192.168.100.128/25"]`},
+		{"GET", "/v1/split?cidr=192.168.100.0/24&new-prefix=24", "", 400,
+			`{"error":"invalid prefix length for splitting 192.168.100.0/24: 24"}`},
+		{"GET", "/v1/split?cidr=lo", "", 400, `{"error":"invalid IP address or CIDR: lo"}`},
+		{"GET", "/v1/split?cidr=::/0&new-prefix=128", "", 400,
+			`{"error":"too many subnets (limit 65536): ::/0 into /128"}`},
+		{"GET", "/v1/split?cidr=0.0.0.0/0&new-prefix=32", "", 400,
+			`{"error":"too many subnets (limit 65536): 0.0.0.0/0 into /32"}`},
+		{"GET", "/v1/aggregate?cidr=10.0.0.0/25&cidr=10.0.0.128/25", "", 200, `["10.0.0.0/24"]`},
+		{"POST", "/v1/aggregate", "10.0.0.0/25\n10.0.0.128/25 ; comment\n10.0.2.0/24\n", 200,
+			`["10.0.0.0/24","10.0.2.0/24"]`},
+		{"GET", "/v1/aggregate", "", 200, `[]`},
+		{"GET", "/v1/contains?cidr=10.0.0.0/8&ip=10.1.2.3&ip=192.0.2.1", "", 200,
+			`[{"ip":"10.1.2.3","contains":true},{"ip":"192.0.2.1","contains":false}]`},
+		{"GET", "/v1/contains?cidr=10.0.0.0/8&ip=x", "", 400, `{"error":"ParseAddr(\"x\"): unable to parse IP"}`},
+		{"DELETE", "/v1/contains", "", 405, `{"error":"method not allowed: DELETE"}`},
+	}
+
+	mux := newServeMux()
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			Equal(t, tt.code, w.Code)
+			Equal(t, "application/json", w.Header().Get("Content-Type"))
+			Equal(t, tt.want+"\n", w.Body.String())
+		})
+	}
+}
+
+func TestServeSubnet(t *testing.T) {
+	w := httptest.NewRecorder()
+	newServeMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/subnet/192.168.100.1/24", http.NoBody))
+	Equal(t, http.StatusOK, w.Code)
+	Contains(t, w.Body.String(), `"broadcast":"192.168.100.255"`)
+	Contains(t, w.Body.String(), `"prefix":24`)
+	Contains(t, w.Body.String(), `"usable":254`)
+}
+
+func TestServeSubnetHostConfig(t *testing.T) {
+	w := httptest.NewRecorder()
+	newServeMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/subnet/127.0.0.1/8", http.NoBody))
+	Equal(t, http.StatusOK, w.Code)
+
+	m := map[string]interface{}{}
+	NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	Equal(t, "127.0.0.1", m[iface.IP])
+	Len(t, m, len(subnetKeys))
+	for _, k := range []string{iface.Name, iface.Gateway, iface.DNS, iface.Search, iface.DHCP, iface.HardwareAddr} {
+		NotContains(t, m, k)
+	}
+}
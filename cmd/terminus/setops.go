@@ -0,0 +1,104 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set COMMAND",
+	Short: "Combine CIDR lists using set operations",
+	Long: `Combine CIDR lists (files, stdin "-" or URLs) using set operations.
+IP addresses, networks in CIDR notation and IP ranges (FROM-TO) are accepted, one per line.
+The result is printed as the minimal list of prefixes, IPv4 prefixes before IPv6 prefixes.`,
+	Args: cobra.NoArgs,
+}
+
+var setUnionCmd = &cobra.Command{
+	Use:   "union [flags] FILE...",
+	Short: "Print the addresses, which are part of any list",
+	Example: `  terminus set union allow-a.txt allow-b.txt
+  # 10.0.0.0/15`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSetCmd(ipset.Set.Union),
+}
+
+var setIntersectCmd = &cobra.Command{
+	Use:   "intersect [flags] FILE FILE...",
+	Short: "Print the addresses, which are part of all lists",
+	Example: `  terminus set intersect allow-a.txt allow-b.txt
+  # 10.1.0.0/16`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  runSetCmd(ipset.Set.Intersect),
+}
+
+var setDiffCmd = &cobra.Command{
+	Use:   "diff [flags] FILE FILE...",
+	Short: "Print the addresses of the first list, which are not part of any other list",
+	Example: `  terminus set diff allow.txt deny.txt
+  # 10.0.0.0/16`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  runSetCmd(ipset.Set.Difference),
+}
+
+func init() {
+	setCmd.PersistentFlags().StringP("output", "o", "text", "Output format (text, json)")
+	setCmd.AddCommand(setUnionCmd, setIntersectCmd, setDiffCmd)
+	rootCmd.AddCommand(setCmd)
+}
+
+// runSetCmd returns a command, which combines the lists from left to right using op.
+func runSetCmd(op func(s, o ipset.Set) ipset.Set) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		sets, err := readSets(args)
+		if err != nil {
+			fatal(err)
+		}
+		out := newOutput(cmd)
+		if err := writeAggregate(out, combine(sets, op).Prefixes(), output); err != nil {
+			fatal(err)
+		}
+		_ = out.Flush()
+	}
+}
+
+// readSets parses the CIDR lists.
+func readSets(names []string) ([]ipset.Set, error) {
+	sets := make([]ipset.Set, len(names))
+	for i, name := range names {
+		b, err := readInputFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if sets[i], err = ipset.Parse(parseTargets(b)); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return sets, nil
+}
+
+// combine applies op to the sets from left to right.
+func combine(sets []ipset.Set, op func(s, o ipset.Set) ipset.Set) ipset.Set {
+	s := sets[0]
+	for _, o := range sets[1:] {
+		s = op(s, o)
+	}
+	return s
+}
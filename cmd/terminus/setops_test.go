@@ -0,0 +1,53 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCombine(t *testing.T) {
+	a, _ := ipset.Parse([]string{"10.0.0.0/16", "10.1.0.0/16", "2001:db8::/32"})
+	b, _ := ipset.Parse([]string{"10.1.0.0/16", "10.2.0.0-10.2.0.255"})
+	c, _ := ipset.Parse([]string{"10.1.128.0/17"})
+
+	Equal(t, "[10.0.0.0/15 10.2.0.0/24 2001:db8::/32]", fmt.Sprint(combine([]ipset.Set{a, b}, ipset.Set.Union).Prefixes()))
+	Equal(t, "[10.1.0.0/16]", fmt.Sprint(combine([]ipset.Set{a, b}, ipset.Set.Intersect).Prefixes()))
+	Equal(t, "[10.1.128.0/17]", fmt.Sprint(combine([]ipset.Set{a, b, c}, ipset.Set.Intersect).Prefixes()))
+	Equal(t, "[10.0.0.0/16 2001:db8::/32]", fmt.Sprint(combine([]ipset.Set{a, b}, ipset.Set.Difference).Prefixes()))
+	Equal(t, "[10.0.0.0/16 10.1.0.0/17 2001:db8::/32]",
+		fmt.Sprint(combine([]ipset.Set{a, c}, ipset.Set.Difference).Prefixes()))
+	Equal(t, a.Prefixes(), combine([]ipset.Set{a}, ipset.Set.Union).Prefixes())
+}
+
+func TestReadSets(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	NoError(t, os.WriteFile(a, []byte("# allow-list\n10.0.0.0/24 ; office\n10.0.1.0/24\n"), 0o600))
+	NoError(t, os.WriteFile(b, []byte("10.0.0.300\n"), 0o600))
+
+	sets, err := readSets([]string{a})
+	NoError(t, err)
+	Equal(t, "[10.0.0.0/23]", fmt.Sprint(sets[0].Prefixes()))
+
+	_, err = readSets([]string{a, b})
+	ErrorContains(t, err, b+": ")
+}
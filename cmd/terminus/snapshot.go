@@ -0,0 +1,80 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture the network interfaces and routes as a fixture for --fixture",
+	Long: `Capture the network interfaces (including their addresses, DNS configuration, DHCP lease and traffic counters)
+and the routes of this host as JSON. The snapshot can be passed to --fixture, such that terminus reports the captured
+state instead of the live state of the operating system, e.g., to reproduce the output on another host.
+Note that the snapshot contains host names, MAC addresses and DHCP options, which might be confidential.`,
+	Example: `  terminus snapshot > host.json
+  terminus --fixture host.json -L`,
+	Args: cobra.NoArgs,
+	Run:  runSnapshotCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotCmd(*cobra.Command, []string) {
+	s, err := iface.Capture()
+	if err != nil {
+		fatal(err)
+	}
+	s.Captured = now().UTC()
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		fatal(err)
+	}
+}
+
+// applyFixture replaces the live state of the network interfaces and routes by the snapshot given by --fixture.
+// The current time is set to the time of the snapshot.
+func applyFixture(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("fixture")
+	if name == "" {
+		return nil
+	}
+	b, err := readInputFile(name)
+	if err != nil {
+		return err
+	}
+
+	s := &iface.Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return fmt.Errorf("invalid fixture %s: %w", name, err)
+	} else if err := iface.UseSnapshot(s); err != nil {
+		return fmt.Errorf("invalid fixture %s: %w", name, err)
+	}
+	if !s.Captured.IsZero() {
+		now = func() time.Time { return s.Captured }
+	}
+	return nil
+}
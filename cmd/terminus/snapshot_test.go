@@ -0,0 +1,49 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestApplyFixture(t *testing.T) {
+	defer func() { now = time.Now }()
+	defer func() { _ = iface.UseSnapshot(nil) }()
+
+	name := filepath.Join(t.TempDir(), "host.json")
+	NoError(t, os.WriteFile(name, []byte(`{"captured": "2024-05-04T10:20:30Z", "interfaces": [
+		{"index": 7, "name": "eth0", "flags": ["up"], "addrs": ["192.0.2.2/24"]}]}`), 0o600))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("fixture", "", "")
+	NoError(t, applyFixture(cmd))
+	NoError(t, cmd.ParseFlags([]string{"--fixture", name}))
+	NoError(t, applyFixture(cmd))
+
+	Equal(t, time.Date(2024, 5, 4, 10, 20, 30, 0, time.UTC), now())
+	s, err := listInterfaces(nil)
+	NoError(t, err)
+	Equal(t, "eth0\t192.0.2.2\t192.0.2.0\t24\n", s)
+
+	NoError(t, os.WriteFile(name, []byte(`{"interfaces": [{"name": "eth0", "addrs": ["192.0.2.2"]}]}`), 0o600))
+	EqualError(t, applyFixture(cmd), "invalid fixture "+name+": invalid CIDR address: 192.0.2.2")
+}
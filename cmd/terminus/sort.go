@@ -0,0 +1,113 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var sortCmd = &cobra.Command{
+	Use:   "sort [flags] [IP|CIDR...]",
+	Short: "Sort IP addresses and subnets numerically",
+	Long: `Sort IP addresses and subnets numerically, which "sort -n" cannot do for dot-decimal and IPv6 notation.
+Subnets are ordered by their address and then by their prefix length. IPv4 precedes IPv6 unless --ipv6-first is set
+(regardless of --reverse).
+The inputs are printed as they are given. With --unique, inputs, which denote the same address or subnet
+(e.g., 10.0.0.1 and 10.0.0.1/32), are printed only once.
+If no argument is given (or "-"), the inputs are read from stdin (one per line).`,
+	Example: `  printf '10.0.0.10\n10.0.0.9\n2001:db8::1\n10.0.0.0/8\n' | terminus sort
+  # 10.0.0.0/8
+  # 10.0.0.9
+  # 10.0.0.10
+  # 2001:db8::1`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runSortCmd,
+}
+
+func init() {
+	sortCmd.Flags().BoolP("unique", "u", false, "Print equal addresses and subnets only once")
+	sortCmd.Flags().BoolP("reverse", "r", false, "Sort in descending order")
+	sortCmd.Flags().Bool("ipv6-first", false, "Sort IPv6 before IPv4")
+	rootCmd.AddCommand(sortCmd)
+}
+
+func runSortCmd(cmd *cobra.Command, args []string) {
+	unique, _ := cmd.Flags().GetBool("unique")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	ipv6First, _ := cmd.Flags().GetBool("ipv6-first")
+
+	ss, err := readArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+	es, err := sortAddrs(ss, unique, reverse, ipv6First)
+	if err != nil {
+		fatal(err)
+	}
+
+	out := newOutput(cmd)
+	for _, e := range es {
+		_, _ = fmt.Fprintln(out, e.s)
+	}
+	_ = out.Flush()
+}
+
+// sortEntry is an input along with the subnet it denotes (a host prefix for IP addresses).
+type sortEntry struct {
+	s string
+	p netip.Prefix
+}
+
+// sortAddrs parses and sorts IP addresses and subnets (without masking the host bits).
+func sortAddrs(ss []string, unique, reverse, ipv6First bool) ([]sortEntry, error) {
+	es := make([]sortEntry, 0, len(ss))
+	seen := map[netip.Prefix]bool{}
+	for _, s := range ss {
+		var p netip.Prefix
+		if a, err := netip.ParseAddr(s); err == nil {
+			// zones (e.g., fe80::1%eth0) are ignored for sorting
+			a = a.Unmap().WithZone("")
+			p = netip.PrefixFrom(a, a.BitLen())
+		} else if p, err = netip.ParsePrefix(s); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, s)
+		}
+
+		if unique && seen[p] {
+			continue
+		}
+		seen[p] = true
+		es = append(es, sortEntry{s, p})
+	}
+
+	sort.SliceStable(es, func(i, j int) bool {
+		a, b := es[i].p, es[j].p
+		if a.Addr().Is4() != b.Addr().Is4() {
+			// the order of the address families is not reversed
+			return a.Addr().Is4() != ipv6First
+		}
+		if reverse {
+			a, b = b, a
+		}
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+	return es, nil
+}
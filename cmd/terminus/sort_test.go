@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSortAddrs(t *testing.T) {
+	in := []string{"10.0.0.10", "2001:db8::1", "10.0.0.9", "::ffff:10.0.0.8", "10.0.0.0/8", "10.0.0.9/32", "10.0.0.0/24"}
+	tests := []struct {
+		name                       string
+		unique, reverse, ipv6First bool
+		want                       []string
+	}{
+		{"default", false, false, false,
+			[]string{"10.0.0.0/8", "10.0.0.0/24", "::ffff:10.0.0.8", "10.0.0.9", "10.0.0.9/32", "10.0.0.10", "2001:db8::1"}},
+		{"unique", true, false, false,
+			[]string{"10.0.0.0/8", "10.0.0.0/24", "::ffff:10.0.0.8", "10.0.0.9", "10.0.0.10", "2001:db8::1"}},
+		{"reverse", true, true, false,
+			[]string{"10.0.0.10", "10.0.0.9", "::ffff:10.0.0.8", "10.0.0.0/24", "10.0.0.0/8", "2001:db8::1"}},
+		{"ipv6-first", true, false, true,
+			[]string{"2001:db8::1", "10.0.0.0/8", "10.0.0.0/24", "::ffff:10.0.0.8", "10.0.0.9", "10.0.0.10"}},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			es, err := sortAddrs(in, tt.unique, tt.reverse, tt.ipv6First)
+			NoError(t, err)
+			var ss []string
+			for _, e := range es {
+				ss = append(ss, e.s)
+			}
+			Equal(t, tt.want, ss)
+		})
+	}
+
+	_, err := sortAddrs([]string{"10.0.0.1", "10.0.0.256"}, false, false, false)
+	EqualError(t, err, "invalid IP address or CIDR: 10.0.0.256")
+
+	es, err := sortAddrs([]string{"fe80::2", "fe80::1%eth0"}, false, false, false)
+	NoError(t, err)
+	Equal(t, "fe80::1%eth0", es[0].s)
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use: `split [flags] IP/PREFIX_LEN
+  terminus split [flags] INTERFACE`,
+	Short: "Split a subnet into smaller subnets",
+	Long: `Split a subnet into smaller subnets.
+If an interface is given, the subnet of its IP address is split.`,
+	Example: `  terminus split --new-prefix 26 192.168.100.0/24
+  # 192.168.100.0/26
+  # 192.168.100.64/26
+  # 192.168.100.128/26
+  # 192.168.100.192/26
+
+  terminus split --new-prefix 26 eth0`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSplitCmd,
+}
+
+func init() {
+	splitCmd.Flags().Int("new-prefix", 0, "Prefix length of the subnets (defaults to halving the subnet)")
+	rootCmd.AddCommand(splitCmd)
+}
+
+func runSplitCmd(cmd *cobra.Command, args []string) {
+	_, n, err := determineIP(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	prefix, _ := cmd.Flags().GetInt("new-prefix")
+	out := newOutput(cmd)
+	err = split(out, n, prefix)
+	_ = out.Flush()
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// split divides the network n into subnets with the given prefix length and writes them one after another.
+func split(w io.Writer, n iplib.Net, prefix int) error {
+	prefix, err := newPrefix(n, prefix)
+	if err != nil {
+		return err
+	}
+
+	p := toPrefix(n)
+	last := ipset.LastAddr(p)
+	for sn := netip.PrefixFrom(p.Addr(), prefix); ; {
+		if _, err := fmt.Fprintln(w, sn); err != nil {
+			return err
+		}
+		if end := ipset.LastAddr(sn); end != last {
+			sn = netip.PrefixFrom(end.Next(), prefix)
+			continue
+		}
+		return nil
+	}
+}
+
+// maxSubnets limits the number of subnets, which are returned by the REST API and the gRPC service at once.
+const maxSubnets = 1 << 16
+
+// errTooManySubnets is returned by subnets if the number of subnets exceeds the limit.
+var errTooManySubnets = errors.New("too many subnets")
+
+// subnets returns the subnets of n with the given prefix length (or half the size of n if prefix is 0).
+// Unlike split, it allocates all subnets, hence it fails if there would be more than limit subnets.
+func subnets(n iplib.Net, prefix, limit int) ([]iplib.Net, error) {
+	prefix, err := newPrefix(n, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if size, _ := n.Mask.Size(); prefix-size >= 63 || 1<<(prefix-size) > limit {
+		return nil, fmt.Errorf("%w (limit %d): %s into /%d", errTooManySubnets, limit, n.String(), prefix)
+	}
+	return n.Subnet(prefix)
+}
+
+// newPrefix validates the prefix length of the subnets of n (or halves the size of n if prefix is 0).
+func newPrefix(n iplib.Net, prefix int) (int, error) {
+	size, bits := n.Mask.Size()
+	if prefix == 0 {
+		prefix = size + 1
+	}
+	if prefix <= size || prefix > bits {
+		return 0, fmt.Errorf("invalid prefix length for splitting %s: %d", n.String(), prefix)
+	}
+	return prefix, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	_, n, _ := determineIP("192.168.100.1/24")
+	s := &strings.Builder{}
+	NoError(t, split(s, n, 26))
+	Equal(t, "192.168.100.0/26\n192.168.100.64/26\n192.168.100.128/26\n192.168.100.192/26\n", s.String())
+
+	s.Reset()
+	NoError(t, split(s, n, 0))
+	Equal(t, "192.168.100.0/25\n192.168.100.128/25\n", s.String())
+
+	_, n, _ = determineIP("2001:db8::/126")
+	s.Reset()
+	NoError(t, split(s, n, 127))
+	Equal(t, "2001:db8::/127\n2001:db8::2/127\n", s.String())
+}
+
+func TestSplitInterface(t *testing.T) {
+	_, n, err := determineIP("lo")
+	if err != nil {
+		_, n, err = determineIP("lo0")
+	}
+	NoError(t, err)
+
+	s := &strings.Builder{}
+	NoError(t, split(s, n, 9))
+	Equal(t, "127.0.0.0/9\n127.128.0.0/9\n", s.String())
+}
+
+func TestSubnets(t *testing.T) {
+	_, n, _ := determineIP("10.0.0.0/8")
+	sns, err := subnets(n, 24, maxSubnets)
+	NoError(t, err)
+	Len(t, sns, 1<<16)
+	Equal(t, "10.255.255.0/24", sns[len(sns)-1].String())
+
+	_, err = subnets(n, 25, maxSubnets)
+	EqualError(t, err, "too many subnets (limit 65536): 10.0.0.0/8 into /25")
+	_, err = subnets(n, 8, maxSubnets)
+	EqualError(t, err, "invalid prefix length for splitting 10.0.0.0/8: 8")
+}
+
+func TestSplitInvalidPrefix(t *testing.T) {
+	_, n, _ := determineIP("192.168.100.1/24")
+	err := split(io.Discard, n, 24)
+	EqualError(t, err, "invalid prefix length for splitting 192.168.100.0/24: 24")
+	err = split(io.Discard, n, 33)
+	EqualError(t, err, "invalid prefix length for splitting 192.168.100.0/24: 33")
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultBufferSize is the default size of the output buffer in bytes.
+const defaultBufferSize = 64 * 1024
+
+// newOutput returns a buffered writer for stdout, whose size is set by --buffer-size.
+// Thus, large outputs are written in chunks instead of being held in memory as a whole.
+// The caller must flush the writer before terminating.
+func newOutput(cmd *cobra.Command) *bufio.Writer {
+	size, _ := cmd.Flags().GetInt("buffer-size")
+	return bufio.NewWriterSize(os.Stdout, size)
+}
+
+// jsonArray writes a JSON array element by element, so that the array is never held in memory as a whole.
+// The output is the same as encoding the whole array with a json.Encoder.
+type jsonArray struct {
+	w io.Writer
+	n int
+}
+
+// add appends v to the array.
+func (a *jsonArray) add(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	sep := ","
+	if a.n == 0 {
+		sep = "["
+	}
+	a.n++
+	if _, err = io.WriteString(a.w, sep); err == nil {
+		_, err = a.w.Write(b)
+	}
+	return err
+}
+
+// close terminates the array.
+func (a *jsonArray) close() error {
+	end := "]\n"
+	if a.n == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
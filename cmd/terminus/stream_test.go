@@ -0,0 +1,55 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestJSONArray(t *testing.T) {
+	for _, vs := range [][]interface{}{nil, {1}, {"<a>", netip.MustParsePrefix("10.0.0.0/8"), map[string]int{"b": 2}}} {
+		want := &strings.Builder{}
+		if vs == nil {
+			NoError(t, json.NewEncoder(want).Encode([]interface{}{}))
+		} else {
+			NoError(t, json.NewEncoder(want).Encode(vs))
+		}
+
+		s := &strings.Builder{}
+		a := &jsonArray{w: s}
+		for _, v := range vs {
+			NoError(t, a.add(v))
+		}
+		NoError(t, a.close())
+		Equal(t, want.String(), s.String())
+	}
+
+	Error(t, (&jsonArray{w: &strings.Builder{}}).add(func() {}))
+}
+
+func TestNewOutput(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("buffer-size", defaultBufferSize, "")
+	Equal(t, defaultBufferSize, newOutput(cmd).Size())
+
+	NoError(t, cmd.Flags().Set("buffer-size", "1048576"))
+	Equal(t, 1048576, newOutput(cmd).Size())
+}
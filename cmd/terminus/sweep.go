@@ -0,0 +1,254 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/netip"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/abc-inc/terminus/probe"
+	"github.com/c-robinson/iplib"
+	"github.com/spf13/cobra"
+)
+
+var sweepCmd = &cobra.Command{
+	Use: `sweep [flags] IP/PREFIX_LEN
+  terminus sweep [flags] INTERFACE
+  terminus sweep [flags] START-END`,
+	Short: "Discover the hosts of a subnet, which are alive",
+	Long: `Discover the hosts of an IPv4 subnet, which are alive.
+With --method auto, every host is sent an ICMP echo request if the process is privileged (or unprivileged ICMP
+sockets are permitted). Hosts without reply are probed via TCP: a host is alive if it accepts or refuses a connection
+to any of --ports. --method icmp and --method tcp use one of the methods only.
+The exit status is 1 if no host is alive.`,
+	Example: `  terminus sweep 192.168.100.0/24
+  # 192.168.100.1	icmp	0.42ms
+  # 192.168.100.20	tcp/22	1.37ms
+
+  terminus sweep --method tcp --ports 22,443 --timeout 500ms --rate 100 -o json eth0`,
+	Args:        cobra.ExactArgs(1),
+	Run:         runSweepCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	sweepCmd.Flags().String("method", "auto", "Probe method (auto, icmp, tcp)")
+	sweepCmd.Flags().IntSlice("ports", []int{22, 80, 443}, "TCP ports to probe")
+	sweepCmd.Flags().Duration("timeout", time.Second, "Timeout per probe")
+	sweepCmd.Flags().Int("rate", 100, "Maximum number of probes per second (0 means unlimited)")
+	sweepCmd.Flags().Int("parallel", 64, "Number of hosts probed concurrently")
+	sweepCmd.Flags().Bool("all", false, "Report the hosts, which are not alive, as well")
+	sweepCmd.Flags().Bool("progress", false, "Report the number of probed hosts and the rate on stderr")
+	sweepCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(sweepCmd)
+}
+
+func runSweepCmd(cmd *cobra.Command, args []string) {
+	method, _ := cmd.Flags().GetString("method")
+	ports, _ := cmd.Flags().GetIntSlice("ports")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	rate, _ := cmd.Flags().GetInt("rate")
+	all, _ := cmd.Flags().GetBool("all")
+	output, _ := cmd.Flags().GetString("output")
+	par, err := parallelism(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if output != "text" && output != "json" {
+		fatal(usageError("unsupported output format: " + output))
+	}
+
+	s, err := newSweeper(method, ports, timeout)
+	if err != nil {
+		fatal(err)
+	}
+	start, end, err := hostRange(args[0])
+	if err != nil {
+		fatal(err)
+	}
+
+	if rate < 0 {
+		fatal(fmt.Errorf("invalid rate (must not be negative): %d", rate))
+	} else if rate > 0 {
+		t := time.NewTicker(time.Second / time.Duration(rate))
+		defer t.Stop()
+		s.wait = func() { <-t.C }
+	}
+
+	out := newOutput(cmd)
+	w := newSweepWriter(out, output)
+	total := countHosts(start, end, 0)
+	p := newProgress(cmd, total)
+	from := iplib.IP4ToUint32(start)
+	alive := false
+	forEachOrdered(par, int(total), func(i int) interface{} {
+		ip, _ := netip.AddrFromSlice(iplib.Uint32ToIP4(from + uint32(i)).To4())
+		return s.sweep(ip)
+	}, func(_ int, v interface{}) {
+		r := v.(sweepResult)
+		alive = alive || r.Alive
+		if r.Alive || all {
+			// sweeps take a while, hence every host is reported as soon as it is known
+			if err := w.write(r); err != nil {
+				fatal(err)
+			}
+			if err := out.Flush(); err != nil {
+				fatal(err)
+			}
+		}
+		p.add(1)
+	})
+	p.finish()
+
+	err = w.close()
+	_ = out.Flush()
+	if err != nil {
+		fatal(err)
+	}
+	if !alive {
+		os.Exit(1)
+	}
+}
+
+// pingHost and probeTCP send the probes. They can be replaced in tests.
+var (
+	pingHost = probe.Ping
+	probeTCP = probe.TCP
+)
+
+// sweeper probes hosts via ICMP and/or TCP.
+type sweeper struct {
+	icmp    bool
+	ports   []int
+	timeout time.Duration
+	// wait blocks until the next probe may be sent (nil if the rate is unlimited).
+	wait func()
+}
+
+// newSweeper validates the method and the ports. If the method is "auto" and ICMP is not permitted, only TCP is used.
+func newSweeper(method string, ports []int, timeout time.Duration) (*sweeper, error) {
+	for _, p := range ports {
+		if p < 1 || p > 65535 {
+			return nil, fmt.Errorf("invalid port: %d", p)
+		}
+	}
+
+	s := &sweeper{ports: ports, timeout: timeout}
+	switch method {
+	case "auto":
+		if s.icmp = probe.CanPing(false); !s.icmp {
+			log.Println("ICMP not permitted, falling back to TCP")
+		}
+	case "icmp":
+		if !probe.CanPing(false) {
+			return nil, fmt.Errorf("%w (run as root or use --method tcp)", probe.ErrNotPermitted)
+		}
+		s.icmp, s.ports = true, nil
+	case "tcp":
+	default:
+		return nil, usageError("unsupported probe method: " + method)
+	}
+	if !s.icmp && len(s.ports) == 0 {
+		return nil, errors.New("no TCP ports to probe")
+	}
+	return s, nil
+}
+
+// sweepResult is the reachability of a host.
+type sweepResult struct {
+	IP    netip.Addr `json:"ip"`
+	Alive bool       `json:"alive"`
+	// Method is the probe, which the host answered (icmp or tcp/PORT).
+	Method string `json:"method,omitempty"`
+	// RTT is the round-trip time in milliseconds.
+	RTT float64 `json:"rtt,omitempty"`
+}
+
+// sweep probes the host via ICMP first (if enabled) and the TCP ports one after another until the host answers.
+func (s *sweeper) sweep(ip netip.Addr) sweepResult {
+	r := sweepResult{IP: ip}
+	alive := func(method string, rtt time.Duration) sweepResult {
+		r.Alive, r.Method, r.RTT = true, method, float64(rtt.Microseconds())/1000
+		return r
+	}
+
+	if s.icmp {
+		s.throttle()
+		if rtt, err := s.ping(ip); err == nil {
+			return alive("icmp", rtt)
+		}
+	}
+	for _, port := range s.ports {
+		s.throttle()
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		state, rtt, _ := probeTCP(ctx, netip.AddrPortFrom(ip, uint16(port)))
+		cancel()
+		if state == probe.Open || state == probe.Closed {
+			return alive("tcp/"+strconv.Itoa(port), rtt)
+		}
+	}
+	return r
+}
+
+func (s *sweeper) ping(ip netip.Addr) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return pingHost(ctx, ip)
+}
+
+func (s *sweeper) throttle() {
+	if s.wait != nil {
+		s.wait()
+	}
+}
+
+// sweepWriter writes the results one after another as text lines or as JSON array.
+type sweepWriter struct {
+	w     io.Writer
+	array *jsonArray
+}
+
+func newSweepWriter(w io.Writer, output string) *sweepWriter {
+	if output == "json" {
+		return &sweepWriter{w: w, array: &jsonArray{w: w}}
+	}
+	return &sweepWriter{w: w}
+}
+
+func (sw *sweepWriter) write(r sweepResult) error {
+	if sw.array != nil {
+		return sw.array.add(r)
+	}
+	if !r.Alive {
+		_, err := fmt.Fprintf(sw.w, "%s\t-\n", r.IP)
+		return err
+	}
+	_, err := fmt.Fprintf(sw.w, "%s\t%s\t%.2fms\n", r.IP, r.Method, r.RTT)
+	return err
+}
+
+func (sw *sweepWriter) close() error {
+	if sw.array != nil {
+		return sw.array.close()
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/probe"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSweep(t *testing.T) {
+	defer func() { pingHost, probeTCP = probe.Ping, probe.TCP }()
+	pingHost = func(_ context.Context, ip netip.Addr) (time.Duration, error) {
+		if ip == netip.MustParseAddr("192.0.2.1") {
+			return 1500 * time.Microsecond, nil
+		}
+		return 0, errors.New("i/o timeout")
+	}
+	probeTCP = func(_ context.Context, addr netip.AddrPort) (probe.State, time.Duration, error) {
+		switch addr.String() {
+		case "192.0.2.2:443":
+			return probe.Open, 2 * time.Millisecond, nil
+		case "192.0.2.3:22":
+			return probe.Closed, 3 * time.Millisecond, nil
+		}
+		return probe.Filtered, 0, nil
+	}
+
+	tests := []struct {
+		icmp bool
+		ip   string
+		want sweepResult
+	}{
+		{true, "192.0.2.1", sweepResult{Alive: true, Method: "icmp", RTT: 1.5}},
+		{true, "192.0.2.2", sweepResult{Alive: true, Method: "tcp/443", RTT: 2}},
+		{false, "192.0.2.1", sweepResult{}},
+		{false, "192.0.2.3", sweepResult{Alive: true, Method: "tcp/22", RTT: 3}},
+		{true, "192.0.2.4", sweepResult{}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			waits := 0
+			s := &sweeper{icmp: tt.icmp, ports: []int{22, 443}, timeout: time.Second, wait: func() { waits++ }}
+			tt.want.IP = netip.MustParseAddr(tt.ip)
+			Equal(t, tt.want, s.sweep(tt.want.IP))
+			Positive(t, waits)
+		})
+	}
+}
+
+func TestNewSweeper(t *testing.T) {
+	s, err := newSweeper("tcp", []int{22}, time.Second)
+	NoError(t, err)
+	Equal(t, &sweeper{ports: []int{22}, timeout: time.Second}, s)
+
+	_, err = newSweeper("tcp", []int{0}, time.Second)
+	EqualError(t, err, "invalid port: 0")
+	_, err = newSweeper("tcp", nil, time.Second)
+	EqualError(t, err, "no TCP ports to probe")
+	_, err = newSweeper("arp", []int{22}, time.Second)
+	EqualError(t, err, "unsupported probe method: arp")
+}
+
+func TestSweepWriter(t *testing.T) {
+	rs := []sweepResult{
+		{IP: netip.MustParseAddr("192.0.2.1"), Alive: true, Method: "icmp", RTT: 0.423},
+		{IP: netip.MustParseAddr("192.0.2.2")},
+	}
+
+	s := &strings.Builder{}
+	w := newSweepWriter(s, "text")
+	for _, r := range rs {
+		NoError(t, w.write(r))
+	}
+	NoError(t, w.close())
+	Equal(t, "192.0.2.1\ticmp\t0.42ms\n192.0.2.2\t-\n", s.String())
+
+	s.Reset()
+	w = newSweepWriter(s, "json")
+	for _, r := range rs {
+		NoError(t, w.write(r))
+	}
+	NoError(t, w.close())
+	Equal(t, `[{"ip":"192.0.2.1","alive":true,"method":"icmp","rtt":0.423},`+
+		`{"ip":"192.0.2.2","alive":false}]`+"\n", s.String())
+}
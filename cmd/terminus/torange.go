@@ -0,0 +1,96 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/spf13/cobra"
+)
+
+var torangeCmd = &cobra.Command{
+	Use:   "torange [flags] [CIDR...]",
+	Short: "Convert subnets into IP ranges",
+	Long: `Convert subnets into IP ranges (FROM-TO), as used by many firewalls.
+With --merge, overlapping and adjacent subnets are merged into a single range.
+If no CIDR is given (or "-"), CIDRs are read from stdin (one per line).
+The conversion into the other direction is done by "terminus tocidr START END".`,
+	Example: `  terminus torange 192.168.1.0/26 192.168.1.64/26
+  # 192.168.1.0-192.168.1.63
+  # 192.168.1.64-192.168.1.127
+
+  terminus torange --merge 192.168.1.0/26 192.168.1.64/26
+  # 192.168.1.0-192.168.1.127`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runToRangeCmd,
+}
+
+func init() {
+	torangeCmd.Flags().Bool("merge", false, "Merge overlapping and adjacent subnets")
+	torangeCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(torangeCmd)
+}
+
+func runToRangeCmd(cmd *cobra.Command, args []string) {
+	merge, _ := cmd.Flags().GetBool("merge")
+	output, _ := cmd.Flags().GetString("output")
+
+	ps, err := readPrefixArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+
+	rs := make([]ipset.Range, len(ps))
+	for i, p := range ps {
+		rs[i] = ipset.PrefixRange(p)
+	}
+	if merge {
+		rs = ipset.New(rs...).Ranges()
+	}
+	out := newOutput(cmd)
+	if err := writeRanges(out, rs, output); err != nil {
+		fatal(err)
+	}
+	_ = out.Flush()
+}
+
+// addrRange is the JSON representation of an IP range.
+type addrRange struct {
+	From netip.Addr `json:"from"`
+	To   netip.Addr `json:"to"`
+}
+
+func writeRanges(w io.Writer, rs []ipset.Range, output string) error {
+	switch output {
+	case "text":
+		for _, r := range rs {
+			_, _ = fmt.Fprintln(w, r)
+		}
+		return nil
+	case "json":
+		a := &jsonArray{w: w}
+		for _, r := range rs {
+			if err := a.add(addrRange{r.From, r.To}); err != nil {
+				return err
+			}
+		}
+		return a.close()
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
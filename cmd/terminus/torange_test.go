@@ -0,0 +1,42 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteRanges(t *testing.T) {
+	r1, _ := ipset.ParseRange("192.168.1.0/26")
+	r2, _ := ipset.ParseRange("2001:db8::/127")
+
+	s := &strings.Builder{}
+	NoError(t, writeRanges(s, []ipset.Range{r1, r2}, "text"))
+	Equal(t, "192.168.1.0-192.168.1.63\n2001:db8::-2001:db8::1\n", s.String())
+
+	s.Reset()
+	NoError(t, writeRanges(s, []ipset.Range{r1}, "json"))
+	Equal(t, `[{"from":"192.168.1.0","to":"192.168.1.63"}]`+"\n", s.String())
+
+	s.Reset()
+	NoError(t, writeRanges(s, nil, "json"))
+	Equal(t, "[]\n", s.String())
+
+	EqualError(t, writeRanges(s, nil, "xml"), "unsupported output format: xml")
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/abc-inc/terminus/plan"
+	"github.com/spf13/cobra"
+)
+
+// maxTreeDepth is the maximum depth of a subnet tree i.e., it has at most 2^17-1 nodes.
+const maxTreeDepth = 16
+
+var treeCmd = &cobra.Command{
+	Use:   "tree [flags] IP/PREFIX_LEN",
+	Short: "Render the binary split tree of a network",
+	Long: `Render the binary split tree of a network like visual subnet calculators i.e., every network is split into
+two halves down to the given depth.
+With --used (or --plan), every network is marked as free, partial or used, depending on how much of it is covered
+by the used addresses (or by the networks without children and the reservations of the address plan).
+With --plan, networks of the address plan are annotated with their names.
+With --watch-file, the tree is re-rendered whenever the file (e.g., the plan) is modified.`,
+	Example: `  terminus tree --depth 2 --used allocated.txt 10.0.0.0/22
+  # 10.0.0.0/22          partial
+  # ├── 10.0.0.0/23      used
+  # │   ├── 10.0.0.0/24  used
+  # │   └── 10.0.1.0/24  used
+  # └── 10.0.2.0/23      partial
+  #     ├── 10.0.2.0/24  free
+  #     └── 10.0.3.0/24  partial`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTreeCmd,
+}
+
+func init() {
+	treeCmd.Flags().Int("depth", 3, "Number of levels below the network")
+	treeCmd.Flags().String("used", "", "File containing the used IP addresses, networks or ranges")
+	treeCmd.Flags().String("plan", "", "Address plan (YAML file)")
+	treeCmd.Flags().Bool("strict", false, "Reject address plans with unknown keys (e.g., typos)")
+	treeCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	treeCmd.Flags().StringArray("watch-file", nil, "Re-render the tree whenever the file is modified (e.g., the plan)")
+	rootCmd.AddCommand(treeCmd)
+}
+
+func runTreeCmd(cmd *cobra.Command, args []string) {
+	_, n, err := determineIP(args[0])
+	if err != nil {
+		fatal(err)
+	}
+	depth, _ := cmd.Flags().GetInt("depth")
+	if depth < 0 || depth > maxTreeDepth {
+		fatal(fmt.Errorf("depth must be between 0 and %d", maxTreeDepth))
+	}
+
+	render := func() error {
+		out := newOutput(cmd)
+		err := runTree(out, cmd, toPrefix(n), depth)
+		_ = out.Flush()
+		return err
+	}
+	if watched, _ := cmd.Flags().GetStringArray("watch-file"); len(watched) > 0 {
+		watchOutput(cmd.Context(), watched, render)
+	} else if err := render(); err != nil {
+		fatal(err)
+	}
+}
+
+// runTree reads the used addresses and the plan (as given by the flags of cmd) and writes the tree of p.
+func runTree(w io.Writer, cmd *cobra.Command, p netip.Prefix, depth int) error {
+	var used *ipset.Set
+	if name, _ := cmd.Flags().GetString("used"); name != "" {
+		b, err := readInputFile(name)
+		if err != nil {
+			return err
+		}
+		s, err := ipset.Parse(parseTargets(b))
+		if err != nil {
+			return err
+		}
+		used = &s
+	}
+
+	names := map[netip.Prefix]string{}
+	if name, _ := cmd.Flags().GetString("plan"); name != "" {
+		strict, _ := cmd.Flags().GetBool("strict")
+		pl, err := readPlan(name, strict)
+		if err != nil {
+			return err
+		}
+		names = planNames(pl)
+		if used == nil {
+			s := planUsed(pl)
+			used = &s
+		}
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	return writeTree(w, buildTree(p, depth, used, names), output)
+}
+
+// treeNode is a network in the binary split tree.
+type treeNode struct {
+	Prefix netip.Prefix `json:"cidr"`
+	// Status is free, partial or used (empty if the used addresses are unknown).
+	Status   string      `json:"status,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+// buildTree splits the network p into halves recursively down to the given depth (or host prefixes).
+// If used is not nil, the nodes are marked by their share of used addresses.
+func buildTree(p netip.Prefix, depth int, used *ipset.Set, names map[netip.Prefix]string) *treeNode {
+	n := &treeNode{Prefix: p, Name: names[p]}
+	if used != nil {
+		r := ipset.PrefixRange(p)
+		switch c := used.CountIn(r); {
+		case c.Sign() == 0:
+			n.Status = "free"
+		case c.Cmp(r.Size()) == 0:
+			n.Status = "used"
+		default:
+			n.Status = "partial"
+		}
+	}
+
+	if depth > 0 && p.Bits() < p.Addr().BitLen() {
+		lo := netip.PrefixFrom(p.Addr(), p.Bits()+1)
+		hi := netip.PrefixFrom(ipset.LastAddr(lo).Next(), p.Bits()+1)
+		n.Children = []*treeNode{buildTree(lo, depth-1, used, names), buildTree(hi, depth-1, used, names)}
+	}
+	return n
+}
+
+// planNames returns the names of the networks and the reservations (if they are networks) of the address plan.
+func planNames(p *plan.Plan) map[netip.Prefix]string {
+	names := map[netip.Prefix]string{}
+	p.Walk(func(n *plan.Network, _ int) bool {
+		names[n.Prefix] = n.Name
+		for _, r := range n.Reservations {
+			if ps := r.Range.Prefixes(); len(ps) == 1 {
+				names[ps[0]] = r.Name
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// planUsed returns the addresses, which are allocated by the address plan i.e., the networks without children
+// and the reservations.
+func planUsed(p *plan.Plan) ipset.Set {
+	var rs []ipset.Range
+	p.Walk(func(n *plan.Network, _ int) bool {
+		if len(n.Children) == 0 {
+			rs = append(rs, ipset.PrefixRange(n.Prefix))
+		}
+		for _, r := range n.Reservations {
+			rs = append(rs, r.Range)
+		}
+		return true
+	})
+	return ipset.New(rs...)
+}
+
+func writeTree(w io.Writer, root *treeNode, output string) error {
+	switch output {
+	case "text":
+		b := &strings.Builder{}
+		tw := tabwriter.NewWriter(b, 0, 8, 2, ' ', 0)
+		writeTreeNode(tw, root, "", "")
+		_ = tw.Flush()
+		for _, l := range strings.SplitAfter(b.String(), "\n") {
+			if l != "" {
+				if _, err := io.WriteString(w, strings.TrimRight(l, " \n")+"\n"); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(root)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
+
+// writeTreeNode writes the node and its children with box-drawing characters like the tree command.
+// The prefix is prepended to the line of the node, and indent to the lines of its children.
+func writeTreeNode(w io.Writer, n *treeNode, prefix, indent string) {
+	_, _ = fmt.Fprintf(w, "%s%s\t%s\t%s\n", prefix, n.Prefix, n.Status, n.Name)
+	for i, c := range n.Children {
+		if i < len(n.Children)-1 {
+			writeTreeNode(w, c, indent+"├── ", indent+"│   ")
+		} else {
+			writeTreeNode(w, c, indent+"└── ", indent+"    ")
+		}
+	}
+}
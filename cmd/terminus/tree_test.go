@@ -0,0 +1,77 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	"github.com/abc-inc/terminus/plan"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteTree(t *testing.T) {
+	used, err := ipset.Parse([]string{"10.0.0.0/23", "10.0.3.5"})
+	NoError(t, err)
+	names := map[netip.Prefix]string{netip.MustParsePrefix("10.0.0.0/23"): "servers"}
+	root := buildTree(netip.MustParsePrefix("10.0.0.0/22"), 2, &used, names)
+
+	s := &strings.Builder{}
+	NoError(t, writeTree(s, root, "text"))
+	Equal(t, `10.0.0.0/22          partial
+├── 10.0.0.0/23      used     servers
+│   ├── 10.0.0.0/24  used
+│   └── 10.0.1.0/24  used
+└── 10.0.2.0/23      partial
+    ├── 10.0.2.0/24  free
+    └── 10.0.3.0/24  partial
+`, s.String())
+
+	s.Reset()
+	NoError(t, writeTree(s, buildTree(netip.MustParsePrefix("192.0.2.0/31"), 3, nil, nil), "json"))
+	Equal(t, `{"cidr":"192.0.2.0/31","children":[{"cidr":"192.0.2.0/32"},{"cidr":"192.0.2.1/32"}]}`+"\n", s.String())
+
+	EqualError(t, writeTree(s, root, "yaml"), "unsupported output format: yaml")
+}
+
+func TestBuildTreeIPv6(t *testing.T) {
+	root := buildTree(netip.MustParsePrefix("2001:db8::/32"), 1, nil, nil)
+	Len(t, root.Children, 2)
+	Equal(t, "2001:db8::/33", root.Children[0].Prefix.String())
+	Equal(t, "2001:db8:8000::/33", root.Children[1].Prefix.String())
+}
+
+func TestPlanTree(t *testing.T) {
+	p, err := plan.Parse([]byte(`supernets:
+  - cidr: 10.0.0.0/22
+    name: campus
+    reservations:
+      - cidr: 10.0.0.0/25
+        name: infra
+    children:
+      - cidr: 10.0.2.0/24
+        name: servers
+`))
+	NoError(t, err)
+
+	names := planNames(p)
+	Equal(t, "campus", names[netip.MustParsePrefix("10.0.0.0/22")])
+	Equal(t, "infra", names[netip.MustParsePrefix("10.0.0.0/25")])
+	Equal(t, "servers", names[netip.MustParsePrefix("10.0.2.0/24")])
+	Equal(t, "[10.0.0.0/25 10.0.2.0/24]", fmt.Sprint(planUsed(p).Prefixes()))
+}
@@ -0,0 +1,272 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/spf13/cobra"
+)
+
+// featureTUI is the interactive terminal UI, which requires a terminal in raw mode.
+const featureTUI iface.Feature = "tui"
+
+// maxTUICache is the maximum number of inputs, whose properties are cached by the terminal UI.
+const maxTUICache = 256
+
+// errNoTerminal is returned if the terminal UI is started without a terminal.
+var errNoTerminal = errors.New("stdin is not a terminal")
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui [IP | IP/PREFIX_LEN | INTERFACE]",
+	Short: "Explore IP addresses and subnets in an interactive terminal UI",
+	Long: `Explore IP addresses and subnets in an interactive terminal UI.
+All properties are updated live while typing an IP address, a CIDR or the name of a network interface.
+The arrow keys up and down increase or decrease the prefix length, Tab browses the local network interfaces,
+Ctrl-U clears the input, and Esc (or Ctrl-C) quits.`,
+	Example: `  terminus tui 192.168.1.10/24`,
+	Args:    cobra.MaximumNArgs(1),
+	Run:     runTUICmd,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUICmd(_ *cobra.Command, args []string) {
+	st := &tuiState{ifaces: tuiInterfaces()}
+	if len(args) > 0 {
+		st.input = args[0]
+	}
+
+	restore, err := makeRaw(os.Stdin, os.Stdout)
+	if err != nil {
+		fatal(err)
+	}
+	err = runTUI(os.Stdin, os.Stdout, st)
+	restore()
+	_, _ = fmt.Fprint(os.Stdout, clearScreen)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// tuiInterfaces returns the names of the network interfaces, which have an IP address.
+func tuiInterfaces() (names []string) {
+	is, _ := iface.Interfaces()
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	for _, i := range is {
+		if _, _, err := iface.GetAddr(i.Name); err == nil {
+			names = append(names, i.Name)
+		}
+	}
+	return names
+}
+
+// runTUI renders the state and processes the keys read from r until the user quits or r is exhausted.
+func runTUI(r io.Reader, w io.Writer, st *tuiState) error {
+	br := bufio.NewReader(r)
+	for {
+		if _, err := io.WriteString(w, st.render()); err != nil {
+			return err
+		}
+		k, err := readKey(br)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !st.handle(k) {
+			return nil
+		}
+	}
+}
+
+// tuiKey is a key (or a combination of keys), which is recognized by the terminal UI.
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyRune
+	keyUp
+	keyDown
+	keyTab
+	keyBackspace
+	keyClear
+	keyQuit
+)
+
+// keyEvent is a key press.
+type keyEvent struct {
+	key tuiKey
+	// r is the character of a keyRune.
+	r rune
+}
+
+// readKey reads a key press from the terminal in raw mode, decoding the escape sequences of the arrow keys.
+// Unknown escape sequences and control characters are returned as keyNone.
+func readKey(r *bufio.Reader) (keyEvent, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return keyEvent{}, err
+	}
+
+	switch c {
+	case 0x03, 0x04:
+		// Ctrl-C, Ctrl-D
+		return keyEvent{key: keyQuit}, nil
+	case '\t':
+		return keyEvent{key: keyTab}, nil
+	case 0x7f, '\b':
+		return keyEvent{key: keyBackspace}, nil
+	case 0x15:
+		// Ctrl-U
+		return keyEvent{key: keyClear}, nil
+	case 0x1b:
+		if r.Buffered() == 0 {
+			// Esc without an escape sequence
+			return keyEvent{key: keyQuit}, nil
+		}
+		return readEscape(r)
+	}
+
+	if unicode.IsPrint(c) {
+		return keyEvent{key: keyRune, r: c}, nil
+	}
+	return keyEvent{}, nil
+}
+
+// readEscape reads the remainder of an escape sequence (CSI or SS3) after Esc.
+func readEscape(r *bufio.Reader) (keyEvent, error) {
+	if b, err := r.ReadByte(); err != nil {
+		return keyEvent{}, err
+	} else if b != '[' && b != 'O' {
+		return keyEvent{}, nil
+	}
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return keyEvent{}, err
+		}
+		if b < 0x40 || b > 0x7e {
+			// parameter or intermediate byte
+			continue
+		}
+		switch b {
+		case 'A':
+			return keyEvent{key: keyUp}, nil
+		case 'B':
+			return keyEvent{key: keyDown}, nil
+		default:
+			return keyEvent{}, nil
+		}
+	}
+}
+
+// tuiState is the state of the terminal UI.
+type tuiState struct {
+	// input is the IP address, CIDR or network interface typed by the user.
+	input string
+	// ifaces are the network interfaces, which can be browsed with Tab.
+	ifaces []string
+	// next is the index of the network interface, which is selected by the next Tab.
+	next int
+	// params caches the properties per input, so that the network interfaces and routes are not read again on every
+	// keystroke (e.g., when browsing the prefix lengths back and forth).
+	params map[string]map[string]interface{}
+}
+
+// handle updates the state according to the key and reports whether the terminal UI is still running.
+func (st *tuiState) handle(k keyEvent) bool {
+	switch k.key {
+	case keyQuit:
+		return false
+	case keyRune:
+		st.input += string(k.r)
+	case keyBackspace:
+		if rs := []rune(st.input); len(rs) > 0 {
+			st.input = string(rs[:len(rs)-1])
+		}
+	case keyClear:
+		st.input = ""
+	case keyUp:
+		st.stepPrefix(1)
+	case keyDown:
+		st.stepPrefix(-1)
+	case keyTab:
+		if len(st.ifaces) > 0 {
+			st.input = st.ifaces[st.next%len(st.ifaces)]
+			st.next++
+		}
+	}
+	return true
+}
+
+// stepPrefix changes the prefix length of the input by delta (within the bounds of the address family).
+// Network interfaces are replaced by their IP address and prefix length.
+func (st *tuiState) stepPrefix(delta int) {
+	ip, n, err := determineIP(st.input)
+	if err != nil {
+		return
+	}
+	size, bits := n.Mask.Size()
+	if size += delta; size < 0 || size > bits {
+		return
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	st.input = ip.String() + "/" + strconv.Itoa(size)
+}
+
+// render returns the screen, which shows the input and the properties of the IP address (or an error message).
+// Since the terminal is in raw mode, lines are terminated by CR LF.
+func (st *tuiState) render() string {
+	s := &strings.Builder{}
+	s.WriteString(clearScreen)
+	s.WriteString("terminus tui (Up/Down: prefix length, Tab: next interface, Ctrl-U: clear, Esc: quit)\n\n")
+	s.WriteString("> " + st.input + "\n\n")
+
+	switch ip, n, err := determineIP(st.input); {
+	case st.input == "":
+		s.WriteString("Type an IP address, a CIDR or the name of a network interface.\n")
+	case err != nil:
+		s.WriteString(err.Error() + "\n")
+	default:
+		data, ok := st.params[st.input]
+		if !ok {
+			if st.params == nil || len(st.params) >= maxTUICache {
+				st.params = map[string]map[string]interface{}{}
+			}
+			data = iface.GetParams(st.input, ip, n.Mask)
+			st.params[st.input] = data
+		}
+		s.WriteString(formatLabeled(data, sortedKeys(data)))
+	}
+
+	// move the cursor to the end of the input
+	s.WriteString("\033[3;" + strconv.Itoa(3+len([]rune(st.input))) + "H")
+	return strings.ReplaceAll(s.String(), "\n", "\r\n")
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("1\x1b[A\x1bOB\x1b[1;5C\t\x7f\x15\x01ä\x03"))
+	var ks []keyEvent
+	for {
+		k, err := readKey(r)
+		if err != nil {
+			break
+		}
+		ks = append(ks, k)
+	}
+	Equal(t, []keyEvent{
+		{keyRune, '1'}, {key: keyUp}, {key: keyDown}, {}, {key: keyTab}, {key: keyBackspace}, {key: keyClear}, {},
+		{keyRune, 'ä'}, {key: keyQuit},
+	}, ks)
+
+	k, err := readKey(bufio.NewReader(strings.NewReader("\x1b")))
+	NoError(t, err)
+	Equal(t, keyQuit, k.key)
+}
+
+func TestTUIStateHandle(t *testing.T) {
+	st := &tuiState{ifaces: []string{"lo", "eth0"}}
+	for _, c := range "10.0.0.1/244" {
+		True(t, st.handle(keyEvent{keyRune, c}))
+	}
+	st.handle(keyEvent{key: keyBackspace})
+	Equal(t, "10.0.0.1/24", st.input)
+
+	st.handle(keyEvent{key: keyUp})
+	Equal(t, "10.0.0.1/25", st.input)
+	st.handle(keyEvent{key: keyDown})
+	st.handle(keyEvent{key: keyDown})
+	Equal(t, "10.0.0.1/23", st.input)
+
+	st.input = "10.0.0.1/32"
+	st.handle(keyEvent{key: keyUp})
+	Equal(t, "10.0.0.1/32", st.input)
+	st.input = "2001:db8::1/64"
+	st.handle(keyEvent{key: keyUp})
+	Equal(t, "2001:db8::1/65", st.input)
+
+	st.handle(keyEvent{key: keyTab})
+	Equal(t, "lo", st.input)
+	st.handle(keyEvent{key: keyTab})
+	st.handle(keyEvent{key: keyTab})
+	Equal(t, "lo", st.input)
+
+	st.handle(keyEvent{key: keyClear})
+	Equal(t, "", st.input)
+	False(t, st.handle(keyEvent{key: keyQuit}))
+}
+
+func TestRunTUI(t *testing.T) {
+	st := &tuiState{input: "192.168.1.10/24"}
+	s := &strings.Builder{}
+	NoError(t, runTUI(strings.NewReader("\x1b[B\x03"), s, st))
+	Equal(t, "192.168.1.10/23", st.input)
+
+	screens := strings.Split(s.String(), clearScreen)
+	Len(t, screens, 3)
+	Contains(t, screens[1], "> 192.168.1.10/24\r\n")
+	Contains(t, screens[1], "broadcast:            192.168.1.255\r\n")
+	Contains(t, screens[2], "netmask:              255.255.254.0\r\n")
+	True(t, strings.HasSuffix(screens[2], "\033[3;18H"))
+	Len(t, st.params, 2)
+	Contains(t, st.params, "192.168.1.10/24")
+
+	st = &tuiState{input: "10.0.0.1/33"}
+	Contains(t, st.render(), "invalid IP address or CIDR: 10.0.0.1/33\r\n")
+}
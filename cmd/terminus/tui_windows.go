@@ -0,0 +1,53 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/abc-inc/terminus/iface"
+	"golang.org/x/sys/windows"
+)
+
+var tuiCapability = iface.Capability{
+	Feature: featureTUI, Description: "interactive terminal UI", Supported: true, Detail: "virtual terminal sequences",
+}
+
+// makeRaw disables line input and echo of the console, and enables virtual terminal sequences.
+// The returned function restores the previous modes.
+func makeRaw(in, out *os.File) (func(), error) {
+	hin, hout := windows.Handle(in.Fd()), windows.Handle(out.Fd())
+	var inMode, outMode uint32
+	if err := windows.GetConsoleMode(hin, &inMode); err != nil {
+		return nil, errNoTerminal
+	}
+	if err := windows.GetConsoleMode(hout, &outMode); err != nil {
+		return nil, errNoTerminal
+	}
+
+	raw := inMode&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_PROCESSED_INPUT|windows.ENABLE_LINE_INPUT) |
+		windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(hin, raw); err != nil {
+		return nil, err
+	}
+	if err := windows.SetConsoleMode(hout, outMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		_ = windows.SetConsoleMode(hin, inMode)
+		return nil, err
+	}
+	return func() {
+		_ = windows.SetConsoleMode(hin, inMode)
+		_ = windows.SetConsoleMode(hout, outMode)
+	}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+
+	"github.com/abc-inc/terminus/iface"
+	"github.com/abc-inc/terminus/ipv6"
+	"github.com/abc-inc/terminus/mac"
+	"github.com/spf13/cobra"
+)
+
+var ulaCmd = &cobra.Command{
+	Use:   "ula [flags]",
+	Short: "Generate a Unique Local IPv6 Unicast Address prefix",
+	Long: `Generate a Unique Local IPv6 Unicast Address (ULA) prefix according to RFC 4193.
+The global ID is derived from the current time and the EUI-64 identifier of a MAC address.
+Unless a MAC address is given, the first network interface with a MAC address is used.`,
+	Example: `  terminus ula --mac 00:11:22:33:44:55 --subnets 2
+  # prefix: fd3f:9a5c:1b2e::/48
+  # subnet: fd3f:9a5c:1b2e::/64
+  # subnet: fd3f:9a5c:1b2e:1::/64`,
+	Args: cobra.NoArgs,
+	Run:  runULACmd,
+}
+
+func init() {
+	ulaCmd.Flags().String("mac", "", "MAC address used for generating the global ID")
+	ulaCmd.Flags().Int("subnets", 4, "Number of suggested /64 subnets")
+	rootCmd.AddCommand(ulaCmd)
+}
+
+func runULACmd(cmd *cobra.Command, _ []string) {
+	id, err := ulaEUI64(cmd.Flag("mac").Value.String())
+	if err != nil {
+		fatal(err)
+	}
+
+	n, err := ipv6.ULA(now(), id)
+	if err != nil {
+		fatal(err)
+	}
+
+	count, _ := cmd.Flags().GetInt("subnets")
+	if err := printULA(os.Stdout, n, count); err != nil {
+		fatal(err)
+	}
+}
+
+// ulaEUI64 returns the EUI-64 identifier of the given MAC address.
+// If hwAddr is empty, the MAC address of the first network interface is used or, if none exists, a random identifier.
+func ulaEUI64(hwAddr string) ([]byte, error) {
+	if hwAddr != "" {
+		hw, err := net.ParseMAC(hwAddr)
+		if err != nil {
+			return nil, err
+		}
+		return mac.EUI64(hw)
+	}
+
+	is, _ := iface.Interfaces()
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	for _, i := range is {
+		if id, err := mac.EUI64(i.HardwareAddr); err == nil {
+			return id, nil
+		}
+	}
+
+	id := make([]byte, 8)
+	_, err := io.ReadFull(randReader, id)
+	return id, err
+}
+
+func printULA(w io.Writer, n *net.IPNet, count int) error {
+	ns, err := ipv6.Subnets(n, 64, count)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "prefix: %v\n", n)
+	for _, sn := range ns {
+		_, _ = fmt.Fprintf(w, "subnet: %v\n", sn)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestULAEUI64(t *testing.T) {
+	id, err := ulaEUI64("00:11:22:33:44:55")
+	NoError(t, err)
+	Equal(t, []byte{0x02, 0x11, 0x22, 0xff, 0xfe, 0x33, 0x44, 0x55}, id)
+
+	id, err = ulaEUI64("")
+	NoError(t, err)
+	Len(t, id, 8)
+
+	_, err = ulaEUI64("00:11")
+	EqualError(t, err, "address 00:11: invalid MAC address")
+}
+
+func TestPrintULA(t *testing.T) {
+	_, n, _ := net.ParseCIDR("fd12:3456:789a::/48")
+	s := &strings.Builder{}
+	NoError(t, printULA(s, n, 2))
+	Equal(t, "prefix: fd12:3456:789a::/48\nsubnet: fd12:3456:789a::/64\nsubnet: fd12:3456:789a:1::/64\n", s.String())
+}
@@ -0,0 +1,230 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [flags] [INPUT...]",
+	Short: "Check IP addresses and networks, reporting why an input is invalid",
+	Long: `Check IP addresses and networks (IP/PREFIX_LEN and IP/NETMASK) and report why an input is invalid
+(e.g., an octet out of range, a bad prefix length, a non-contiguous subnet mask or host bits set with --strict).
+With --strict, the address of a network must be its network address (i.e., all host bits are cleared).
+If no argument is given (or "-"), the inputs are read from stdin (one per line).
+The exit status is 0 if all inputs are valid, 1 otherwise.`,
+	Example: `  terminus validate 10.0.0.0/8 192.168.1.300 10.1.2.3/255.0.255.0
+  # 10.0.0.0/8: valid IPv4 network
+  # 192.168.1.300: octet 4 (300) is out of range (0-255)
+  # 10.1.2.3/255.0.255.0: subnet mask 255.0.255.0 is not contiguous
+
+  terminus validate --strict 10.1.2.3/8
+  # 10.1.2.3/8: host bits are set (network address: 10.0.0.0/8)`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runValidateCmd,
+}
+
+func init() {
+	validateCmd.Flags().Bool("strict", false, "Require networks to be given by their network address")
+	validateCmd.Flags().BoolP("quiet", "q", false, "Print nothing, only set the exit status")
+	validateCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidateCmd(cmd *cobra.Command, args []string) {
+	strict, _ := cmd.Flags().GetBool("strict")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	output, _ := cmd.Flags().GetString("output")
+
+	ss, err := readArgs(args)
+	if err != nil {
+		fatal(err)
+	}
+	ds := make([]diagnostic, len(ss))
+	valid := true
+	for i, s := range ss {
+		ds[i] = diagnose(s, strict)
+		valid = valid && ds[i].Valid
+	}
+
+	if !quiet {
+		out := newOutput(cmd)
+		if err := writeDiagnostics(out, ds, output); err != nil {
+			_ = out.Flush()
+			fatal(err)
+		}
+		_ = out.Flush()
+	}
+	if !valid {
+		os.Exit(exitFailure)
+	}
+}
+
+// diagnostic is the result of validating an input.
+type diagnostic struct {
+	Input string `json:"input"`
+	Valid bool   `json:"valid"`
+	// Kind describes a valid input (e.g., "IPv4 network").
+	Kind string `json:"kind,omitempty"`
+	// Network is the network, which the input denotes (networks only).
+	Network string `json:"network,omitempty"`
+	// Problem is the reason why the input is invalid.
+	Problem string `json:"problem,omitempty"`
+}
+
+// diagnose validates an IP address, IP/PREFIX_LEN or IP/NETMASK.
+// If strict is true, networks must be given by their network address.
+func diagnose(s string, strict bool) diagnostic {
+	d := diagnostic{Input: s}
+	addr, bits, hasBits := strings.Cut(s, "/")
+	a, err := diagnoseAddr(addr)
+	if err != nil {
+		d.Problem = err.Error()
+		return d
+	}
+
+	fam := "IPv4"
+	if a.Is6() {
+		fam = "IPv6"
+	}
+	if !hasBits {
+		d.Valid, d.Kind = true, fam+" address"
+		return d
+	}
+
+	size, err := diagnoseBits(bits, a.BitLen())
+	if err != nil {
+		d.Problem = err.Error()
+		return d
+	}
+	p := netip.PrefixFrom(a.WithZone(""), size)
+	d.Network = p.Masked().String()
+	if p.Masked().Addr() == p.Addr() {
+		d.Valid, d.Kind = true, fam+" network"
+	} else if strict {
+		d.Problem = "host bits are set (network address: " + d.Network + ")"
+	} else {
+		d.Valid, d.Kind = true, fam+" address in network "+d.Network
+	}
+	return d
+}
+
+// diagnoseAddr parses an IPv4 or IPv6 address, reporting the malformed octet of IPv4 addresses.
+func diagnoseAddr(s string) (netip.Addr, error) {
+	if s == "" {
+		return netip.Addr{}, errors.New("IP address is missing")
+	}
+	if strings.Contains(s, ":") {
+		a, err := netip.ParseAddr(s)
+		if err != nil {
+			// strip the `ParseAddr("...")` prefix
+			msg := err.Error()
+			if _, m, ok := strings.Cut(msg, "): "); ok {
+				msg = m
+			}
+			return netip.Addr{}, errors.New("invalid IPv6 address: " + msg)
+		}
+		return a, nil
+	}
+
+	octets := strings.Split(s, ".")
+	if len(octets) != 4 {
+		if strings.Trim(s, "0123456789.") != "" {
+			return netip.Addr{}, errors.New("not an IP address")
+		}
+		return netip.Addr{}, fmt.Errorf("expected 4 octets, got %d", len(octets))
+	}
+	for i, o := range octets {
+		switch n, err := strconv.Atoi(o); {
+		case o == "":
+			return netip.Addr{}, fmt.Errorf("octet %d is empty", i+1)
+		case err != nil || o[0] == '+' || o[0] == '-':
+			return netip.Addr{}, fmt.Errorf("octet %d (%s) is not a decimal number", i+1, o)
+		case n > 255:
+			return netip.Addr{}, fmt.Errorf("octet %d (%s) is out of range (0-255)", i+1, o)
+		case len(o) > 1 && o[0] == '0':
+			return netip.Addr{}, fmt.Errorf("octet %d (%s) has a leading zero (ambiguous octal notation)", i+1, o)
+		}
+	}
+	return netip.ParseAddr(s)
+}
+
+// diagnoseBits parses a prefix length or an IPv4 subnet mask (dot-decimal or hexadecimal notation).
+func diagnoseBits(s string, bitLen int) (int, error) {
+	if s == "" {
+		return 0, errors.New("prefix length is missing")
+	}
+	if n, err := strconv.Atoi(s); err == nil && s[0] != '+' && s[0] != '-' {
+		if n > bitLen {
+			return 0, fmt.Errorf("prefix length %s is out of range (0-%d)", s, bitLen)
+		} else if len(s) > 1 && s[0] == '0' {
+			return 0, fmt.Errorf("prefix length %s has a leading zero", s)
+		}
+		return n, nil
+	}
+	if bitLen != 32 {
+		return 0, fmt.Errorf("prefix length %s is not a number", s)
+	}
+
+	var m net.IPMask
+	if strings.Contains(s, ".") {
+		if _, err := diagnoseAddr(s); err != nil {
+			return 0, fmt.Errorf("invalid subnet mask %s: %v", s, err)
+		}
+		m = net.IPMask(net.ParseIP(s).To4())
+	} else if b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")); err == nil {
+		m = b
+	}
+	if len(m) != net.IPv4len {
+		return 0, fmt.Errorf("prefix length %s is neither a number nor a subnet mask", s)
+	}
+	size, bits := m.Size()
+	if bits == 0 {
+		return 0, fmt.Errorf("subnet mask %s is not contiguous", s)
+	}
+	return size, nil
+}
+
+func writeDiagnostics(w io.Writer, ds []diagnostic, output string) error {
+	switch output {
+	case "text":
+		for _, d := range ds {
+			msg := d.Problem
+			if d.Valid {
+				msg = "valid " + d.Kind
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s\n", d.Input, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(ds)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		in     string
+		strict bool
+		want   string
+	}{
+		{"192.0.2.1", false, "valid IPv4 address"},
+		{"192.0.2.0/24", true, "valid IPv4 network"},
+		{"192.0.2.1/24", false, "valid IPv4 address in network 192.0.2.0/24"},
+		{"192.0.2.1/24", true, "host bits are set (network address: 192.0.2.0/24)"},
+		{"192.0.2.1/255.255.255.0", true, "host bits are set (network address: 192.0.2.0/24)"},
+		{"2001:db8::/32", true, "valid IPv6 network"},
+		{"192.0.2", false, "expected 4 octets, got 3"},
+		{"192.0..1", false, "octet 3 is empty"},
+		{"192.0.2.x1", false, "octet 4 (x1) is not a decimal number"},
+		{"192.0.2.256", false, "octet 4 (256) is out of range (0-255)"},
+		{"192.0.02.1", false, "octet 3 (02) has a leading zero (ambiguous octal notation)"},
+		{"example.com", false, "not an IP address"},
+		{"192.0.2.0/", false, "prefix length is missing"},
+		{"192.0.2.0/33", false, "prefix length 33 is out of range (0-32)"},
+		{"192.0.2.0/024", false, "prefix length 024 has a leading zero"},
+		{"2001:db8::/ffff0000", false, "prefix length ffff0000 is not a number"},
+		{"192.0.2.0/255.0.255.0", false, "subnet mask 255.0.255.0 is not contiguous"},
+		{"192.0.2.0/0xff00ff00", false, "subnet mask 0xff00ff00 is not contiguous"},
+		{"192.0.2.0/255.255.255.300", false,
+			"invalid subnet mask 255.255.255.300: octet 4 (300) is out of range (0-255)"},
+		{"192.0.2.0/ffff", false, "prefix length ffff is neither a number nor a subnet mask"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			d := diagnose(tt.in, tt.strict)
+			if d.Valid {
+				Equal(t, tt.want, "valid "+d.Kind)
+			} else {
+				Equal(t, tt.want, d.Problem)
+			}
+		})
+	}
+}
+
+func TestDiagnoseIPv6(t *testing.T) {
+	// the details are reported by net/netip
+	d := diagnose("2001:db8:::1/64", false)
+	False(t, d.Valid)
+	True(t, strings.HasPrefix(d.Problem, "invalid IPv6 address: "), d.Problem)
+	NotContains(t, d.Problem, "ParseAddr")
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	ds := []diagnostic{diagnose("10.0.0.0/8", false), diagnose("10.0.0.300", false)}
+	s := &strings.Builder{}
+	NoError(t, writeDiagnostics(s, ds, "text"))
+	Equal(t, "10.0.0.0/8: valid IPv4 network\n10.0.0.300: octet 4 (300) is out of range (0-255)\n", s.String())
+
+	s.Reset()
+	NoError(t, writeDiagnostics(s, ds, "json"))
+	Equal(t, `[{"input":"10.0.0.0/8","valid":true,"kind":"IPv4 network","network":"10.0.0.0/8"},`+
+		`{"input":"10.0.0.300","valid":false,"problem":"octet 4 (300) is out of range (0-255)"}]`+"\n", s.String())
+
+	EqualError(t, writeDiagnostics(s, ds, "yaml"), "unsupported output format: yaml")
+}
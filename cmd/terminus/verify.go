@@ -0,0 +1,158 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/blake2b"
+)
+
+var errInvalidSignature = errors.New("invalid minisign signature")
+
+// verifier checks the integrity of input files before they are used.
+type verifier struct {
+	// checksums are the expected checksums (ALGORITHM:HEX) of the input files in the same order.
+	checksums []string
+	// key is the minisign public key, which signed the input files (optional).
+	key *minisignKey
+}
+
+// newVerifier creates a verifier from the flags of cmd.
+func newVerifier(cmd *cobra.Command) (v verifier, err error) {
+	v.checksums, _ = cmd.Flags().GetStringArray("input-checksum")
+	if key, _ := cmd.Flags().GetString("input-minisign-key"); key != "" {
+		v.key, err = parseMinisignKey(key)
+	}
+	return v, err
+}
+
+// verify checks the content b of the i-th input file name.
+// If a public key is configured, the detached signature is read from name + ".minisig".
+func (v verifier) verify(i int, name string, b []byte) error {
+	if i < len(v.checksums) {
+		if err := verifyChecksum(v.checksums[i], b); err != nil {
+			return errors.New(err.Error() + ": " + name)
+		}
+	}
+	if v.key == nil {
+		return nil
+	}
+
+	sig, err := readInputFile(name + ".minisig")
+	if err != nil {
+		return err
+	}
+	if err := v.key.verify(b, sig); err != nil {
+		return errors.New(err.Error() + ": " + name)
+	}
+	return nil
+}
+
+// verifyChecksum compares the checksum of b with the expected one (sha256:HEX or sha512:HEX).
+func verifyChecksum(checksum string, b []byte) error {
+	alg, want, _ := strings.Cut(checksum, ":")
+	var h hash.Hash
+	switch strings.ToLower(alg) {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return usageError("unsupported checksum algorithm: " + alg)
+	}
+
+	_, _ = h.Write(b)
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+		return errors.New("checksum mismatch")
+	}
+	return nil
+}
+
+// minisignKey is a minisign Ed25519 public key.
+type minisignKey struct {
+	id [8]byte
+	pk ed25519.PublicKey
+}
+
+// parseMinisignKey parses a base64 encoded minisign public key or reads it from a file.
+func parseMinisignKey(s string) (*minisignKey, error) {
+	if b, err := os.ReadFile(s); err == nil {
+		s = lastLine(string(b))
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(b) != 42 || string(b[:2]) != "Ed" {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	k := &minisignKey{pk: b[10:]}
+	copy(k.id[:], b[2:10])
+	return k, nil
+}
+
+// verify checks the detached minisign signature sig of the message msg.
+// Both legacy (Ed) and pre-hashed (ED) signatures are supported.
+func (k minisignKey) verify(msg, sig []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(sig)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errInvalidSignature
+	}
+
+	s, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(s) != 74 {
+		return errInvalidSignature
+	}
+	if subtle.ConstantTimeCompare(s[2:10], k.id[:]) != 1 {
+		return errors.New("minisign signature was created by a different key")
+	}
+
+	switch string(s[:2]) {
+	case "Ed":
+	case "ED":
+		sum := blake2b.Sum512(msg)
+		msg = sum[:]
+	default:
+		return errInvalidSignature
+	}
+	if !ed25519.Verify(k.pk, msg, s[10:]) {
+		return errInvalidSignature
+	}
+
+	// the global signature covers the signature and the trusted comment
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	comment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	if err != nil || !ed25519.Verify(k.pk, bytes.Join([][]byte{s[10:], []byte(comment)}, nil), global) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
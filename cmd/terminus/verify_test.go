@@ -0,0 +1,103 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisign creates a minisign public key and a detached signature of msg.
+func minisign(t *testing.T, alg string, msg []byte) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	NoError(t, err)
+	id := []byte("01234567")
+
+	sigMsg := msg
+	if alg == "ED" {
+		sum := blake2b.Sum512(msg)
+		sigMsg = sum[:]
+	}
+	sig := ed25519.Sign(priv, sigMsg)
+	comment := "timestamp:1600000000"
+	global := ed25519.Sign(priv, append(append([]byte{}, sig...), comment...))
+
+	key := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), pub...))
+	return "untrusted comment: minisign public key\n" + key + "\n",
+		"untrusted comment: signature from minisign secret key\n" +
+			base64.StdEncoding.EncodeToString(append(append([]byte(alg), id...), sig...)) + "\n" +
+			"trusted comment: " + comment + "\n" +
+			base64.StdEncoding.EncodeToString(global) + "\n"
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	b := []byte("192.0.2.0/24\n")
+	NoError(t, verifyChecksum("sha256:7c05f476485b46ce90b613f8e594ed25245cacfc0337ffb25c47b4771807f6a4", b))
+	EqualError(t, verifyChecksum("sha256:00", b), "checksum mismatch")
+	EqualError(t, verifyChecksum("md5:00", b), "unsupported checksum algorithm: md5")
+}
+
+func TestMinisign(t *testing.T) {
+	msg := []byte("192.0.2.0/24\n")
+	for _, alg := range []string{"Ed", "ED"} {
+		t.Run(alg, func(t *testing.T) {
+			pub, sig := minisign(t, alg, msg)
+			k, err := parseMinisignKey(lastLine(pub))
+			NoError(t, err)
+			NoError(t, k.verify(msg, []byte(sig)))
+			EqualError(t, k.verify([]byte("198.51.100.0/24\n"), []byte(sig)), "invalid minisign signature")
+
+			other, _ := minisign(t, alg, msg)
+			k, err = parseMinisignKey(lastLine(other))
+			NoError(t, err)
+			EqualError(t, k.verify(msg, []byte(sig)), "invalid minisign signature")
+		})
+	}
+
+	_, err := parseMinisignKey("RWQ")
+	EqualError(t, err, "invalid minisign public key")
+}
+
+func TestReadInputFilesVerified(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "drop.txt")
+	msg := []byte("192.0.2.0/24\n")
+	pub, sig := minisign(t, "ED", msg)
+	NoError(t, os.WriteFile(name, msg, 0o600))
+	NoError(t, os.WriteFile(name+".minisig", []byte(sig), 0o600))
+	NoError(t, os.WriteFile(filepath.Join(dir, "minisign.pub"), []byte(pub), 0o600))
+
+	k, err := parseMinisignKey(filepath.Join(dir, "minisign.pub"))
+	NoError(t, err)
+	args, err := readInputFiles([]string{name}, verifier{key: k})
+	NoError(t, err)
+	Equal(t, []string{"192.0.2.0/24"}, args)
+
+	NoError(t, os.WriteFile(name, []byte("0.0.0.0/0\n"), 0o600))
+	_, err = readInputFiles([]string{name}, verifier{key: k})
+	EqualError(t, err, "invalid minisign signature: "+name)
+
+	_, err = readInputFiles([]string{name}, verifier{checksums: []string{"sha256:00"}})
+	EqualError(t, err, "checksum mismatch: "+name)
+
+	_, err = readInputFiles([]string{name}, verifier{checksums: []string{"sha256:00", "sha256:00"}})
+	EqualError(t, err, "2 --input-checksum values given for 1 input files")
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// watchInterval is the interval, in which watched files are checked for modifications.
+var watchInterval = 500 * time.Millisecond
+
+// clearScreen is the ANSI escape sequence, which clears the terminal and moves the cursor to the top left corner.
+const clearScreen = "\033[H\033[2J"
+
+// watchFiles calls render initially and whenever one of the files is modified until ctx is done.
+// Modifications are detected by polling the modification time and size, which works on every platform and
+// file system, and also detects editors replacing the file on save.
+func watchFiles(ctx context.Context, names []string, render func()) {
+	prev := fileStates(names)
+	render()
+
+	t := time.NewTicker(watchInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if cur := fileStates(names); cur != prev {
+				prev = cur
+				render()
+			}
+		}
+	}
+}
+
+// watchOutput calls run initially and whenever one of the files is modified until ctx is done (see watchFiles).
+// If stdout is a terminal, the screen is cleared before every run. Errors are logged instead of terminating,
+// because they might be fixed by the next modification.
+func watchOutput(ctx context.Context, names []string, run func() error) {
+	fi, err := os.Stdout.Stat()
+	terminal := err == nil && fi.Mode()&os.ModeCharDevice != 0
+	watchFiles(ctx, names, func() {
+		if terminal {
+			fmt.Print(clearScreen)
+		}
+		if err := run(); err != nil {
+			log.Print(err)
+		}
+	})
+}
+
+// fileStates returns a fingerprint of the modification times and sizes of the files.
+func fileStates(names []string) string {
+	s := &strings.Builder{}
+	for _, name := range names {
+		if fi, err := os.Stat(name); err == nil {
+			_, _ = fmt.Fprintf(s, "%s\t%d\t%d\n", name, fi.ModTime().UnixNano(), fi.Size())
+		} else {
+			_, _ = fmt.Fprintf(s, "%s\t%v\n", name, err)
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWatchFiles(t *testing.T) {
+	defer func(d time.Duration) { watchInterval = d }(watchInterval)
+	watchInterval = 5 * time.Millisecond
+
+	name := filepath.Join(t.TempDir(), "plan.yaml")
+	NoError(t, os.WriteFile(name, []byte("devices: {}\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	renders := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		watchFiles(ctx, []string{name}, func() { renders <- struct{}{} })
+		close(done)
+	}()
+
+	<-renders
+	NoError(t, os.WriteFile(name, []byte("devices:\n  rtr1: {}\n"), 0o600))
+	select {
+	case <-renders:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no render after modification")
+	}
+
+	cancel()
+	<-done
+	Empty(t, renders)
+}
+
+func TestFileStates(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "plan.yaml")
+	missing := fileStates([]string{name})
+	NoError(t, os.WriteFile(name, []byte("devices: {}\n"), 0o600))
+	NotEqual(t, missing, fileStates([]string{name}))
+	Equal(t, fileStates([]string{name}), fileStates([]string{name}))
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/abc-inc/terminus/whois"
+	"github.com/spf13/cobra"
+)
+
+var whoisCmd = &cobra.Command{
+	Use:   "whois [flags] IP",
+	Short: "Look up the registration data of an IP address",
+	Long: `Look up the registration data of an IP address via RDAP.
+It prints the allocation, the name of the network, the org, the country and the abuse contact.
+The RDAP service is determined by the IANA bootstrap files and redirects to other registries are followed.
+If RDAP fails, the plain-text WHOIS service (port 43) is queried, following referrals starting at IANA.`,
+	Example: `  terminus whois 192.0.2.1
+  # allocation: 192.0.0.0 - 192.0.2.255
+  # name:       EXAMPLE-NET
+  # org:        Example Org
+  # country:    US
+  # abuse:      abuse@example.com
+  # source:     https://rdap.arin.net/registry/ip/192.0.2.1
+
+  terminus whois --method whois 2001:db8::1 -o json`,
+	Args:        cobra.ExactArgs(1),
+	Run:         runWhoisCmd,
+	Annotations: map[string]string{sandboxNetwork: "always"},
+}
+
+func init() {
+	whoisCmd.Flags().SortFlags = false
+	whoisCmd.Flags().String("method", "auto", "Lookup method (auto, rdap, whois)")
+	whoisCmd.Flags().String("rdap-server", "", "RDAP base URL (default: determined by the IANA bootstrap files)")
+	whoisCmd.Flags().String("whois-server", "whois.iana.org:43", "WHOIS server (HOST:PORT) to start at")
+	whoisCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of the lookup")
+	whoisCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	rootCmd.AddCommand(whoisCmd)
+}
+
+func runWhoisCmd(cmd *cobra.Command, args []string) {
+	method, _ := cmd.Flags().GetString("method")
+	rdapServer, _ := cmd.Flags().GetString("rdap-server")
+	whoisServer, _ := cmd.Flags().GetString("whois-server")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	output, _ := cmd.Flags().GetString("output")
+
+	ip, err := netip.ParseAddr(args[0])
+	if err != nil {
+		fatal(&net.ParseError{Type: "IP address", Text: args[0]})
+	}
+	ip = ip.Unmap()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var r whois.Record
+	switch method {
+	case "auto":
+		if r, err = lookupRDAP(ctx, rdapServer, ip); err != nil {
+			log.Println("RDAP lookup failed, falling back to WHOIS:", err)
+			r, err = whois.LookupWHOIS(ctx, whoisServer, ip)
+		}
+	case "rdap":
+		r, err = lookupRDAP(ctx, rdapServer, ip)
+	case "whois":
+		r, err = whois.LookupWHOIS(ctx, whoisServer, ip)
+	default:
+		err = usageError("unsupported lookup method: " + method)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	if err := writeWhois(os.Stdout, r, output); err != nil {
+		fatal(err)
+	}
+}
+
+// lookupRDAP queries the RDAP service at base, or the one responsible for ip according to the IANA bootstrap files.
+func lookupRDAP(ctx context.Context, base string, ip netip.Addr) (whois.Record, error) {
+	bases := []string{base}
+	if base == "" {
+		file := "https://data.iana.org/rdap/ipv4.json"
+		if ip.Is6() {
+			file = "https://data.iana.org/rdap/ipv6.json"
+		}
+		b, err := fetch(file)
+		if err != nil {
+			return whois.Record{}, err
+		}
+		bs, err := whois.ParseBootstrap(b)
+		if err != nil {
+			return whois.Record{}, err
+		}
+		if bases = bs.Lookup(ip); len(bases) == 0 {
+			return whois.Record{}, errors.New("no RDAP service for " + ip.String())
+		}
+	}
+
+	var r whois.Record
+	var err error
+	for _, b := range bases {
+		if r, err = whois.LookupRDAP(ctx, httpClient, b, ip); err == nil {
+			break
+		}
+	}
+	return r, err
+}
+
+func writeWhois(w io.Writer, r whois.Record, output string) error {
+	switch output {
+	case "text":
+		for _, kv := range [][2]string{
+			{"allocation", r.Allocation}, {"name", r.Name}, {"org", r.Org},
+			{"country", r.Country}, {"abuse", r.Abuse}, {"source", r.Source},
+		} {
+			if kv[1] != "" {
+				_, _ = fmt.Fprintf(w, "%-11s %s\n", kv[0]+":", kv[1])
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(r)
+	default:
+		return usageError("unsupported output format: " + output)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/whois"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestWriteWhois(t *testing.T) {
+	r := whois.Record{Allocation: "192.0.2.0 - 192.0.2.255", Org: "Example Org", Country: "US"}
+	s := &strings.Builder{}
+	NoError(t, writeWhois(s, r, "text"))
+	Equal(t, "allocation: 192.0.2.0 - 192.0.2.255\norg:        Example Org\ncountry:    US\n", s.String())
+
+	s.Reset()
+	NoError(t, writeWhois(s, whois.Record{Country: "US"}, "json"))
+	Equal(t, `{"country":"US"}`+"\n", s.String())
+
+	EqualError(t, writeWhois(s, r, "xml"), "unsupported output format: xml")
+}
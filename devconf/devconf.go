@@ -0,0 +1,229 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devconf extracts the interface addresses from saved device configurations.
+// Supported are Cisco IOS (and IOS-like) configs, Junos configs (hierarchical or set commands)
+// and the output of "ip address show" on Linux.
+package devconf
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// Format is the syntax of a device configuration.
+type Format string
+
+// Supported configuration formats.
+const (
+	IOS   Format = "ios"
+	Junos Format = "junos"
+	Linux Format = "linux"
+)
+
+// Address is an IP address assigned to an interface along with the prefix length of its subnet.
+type Address struct {
+	Interface string       `json:"interface"`
+	Prefix    netip.Prefix `json:"address"`
+}
+
+// Detect determines the format of the configuration (an empty string if it is unknown).
+func Detect(text string) Format {
+	switch {
+	case junosSet.MatchString(text) || junosBlock.MatchString(text):
+		return Junos
+	case linuxLink.MatchString(text):
+		return Linux
+	case iosInterface.MatchString(text):
+		return IOS
+	}
+	return ""
+}
+
+// Extract returns the interface addresses in the configuration.
+// If format is empty, it is detected automatically.
+func Extract(format Format, text string) ([]Address, error) {
+	if format == "" {
+		if format = Detect(text); format == "" {
+			return nil, errors.New("unknown configuration format")
+		}
+	}
+
+	switch format {
+	case IOS:
+		return extractIOS(text)
+	case Junos:
+		return extractJunos(text)
+	case Linux:
+		return extractLinux(text)
+	default:
+		return nil, errors.New("unsupported configuration format: " + string(format))
+	}
+}
+
+var (
+	iosInterface = regexp.MustCompile(`(?m)^interface \S+`)
+	junosSet     = regexp.MustCompile(`(?m)^set interfaces \S+`)
+	junosBlock   = regexp.MustCompile(`(?m)^interfaces \{`)
+	linuxLink    = regexp.MustCompile(`(?m)^\d+: \S+:? .*(<|link/|inet6? )`)
+)
+
+// extractIOS parses "ip address IP MASK [secondary]" and "ipv6 address IP/PREFIX_LEN" in interface sections.
+func extractIOS(text string) ([]Address, error) {
+	var as []Address
+	name := ""
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		l := sc.Text()
+		fs := strings.Fields(l)
+		switch {
+		case len(fs) == 0:
+		case l[0] != ' ' && l[0] != '\t':
+			// a top-level command ends the interface section
+			name = ""
+			if fs[0] == "interface" && len(fs) == 2 {
+				name = fs[1]
+			}
+		case name == "" || len(fs) < 3 || fs[1] != "address":
+		case fs[0] == "ip" && len(fs) >= 4:
+			a, err := netip.ParseAddr(fs[2])
+			if err != nil {
+				return nil, errors.New("invalid IP address in " + strings.TrimSpace(l))
+			}
+			mask, err := netip.ParseAddr(fs[3])
+			bits := maskBits(mask)
+			if err != nil || !a.Is4() || bits < 0 {
+				return nil, errors.New("invalid netmask in " + strings.TrimSpace(l))
+			}
+			as = append(as, Address{name, netip.PrefixFrom(a, bits)})
+		case fs[0] == "ipv6" && strings.Contains(fs[2], "/"):
+			p, err := netip.ParsePrefix(fs[2])
+			if err != nil {
+				return nil, errors.New("invalid IPv6 address in " + strings.TrimSpace(l))
+			}
+			as = append(as, Address{name, p})
+		}
+	}
+	return as, sc.Err()
+}
+
+// maskBits returns the prefix length of a contiguous netmask (or -1).
+func maskBits(mask netip.Addr) int {
+	ones, bits := net.IPMask(mask.AsSlice()).Size()
+	if bits == 0 {
+		return -1
+	}
+	return ones
+}
+
+// extractJunos parses "family inet|inet6 address" statements of logical interfaces (unit 0 of ge-0/0/0 is
+// reported as ge-0/0/0.0), either in hierarchical or in set format.
+func extractJunos(text string) ([]Address, error) {
+	var as []Address
+	var stack []string
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		l := strings.TrimSpace(sc.Text())
+		if i := strings.Index(l, "#"); i >= 0 && !strings.HasPrefix(l, "set ") {
+			l = strings.TrimSpace(l[:i])
+		}
+
+		var path []string
+		switch {
+		case strings.HasPrefix(l, "set "):
+			path = strings.Fields(l)[1:]
+		case strings.HasSuffix(l, "{"):
+			stack = append(stack, strings.TrimSpace(strings.TrimSuffix(l, "{")))
+			if !strings.HasPrefix(stack[len(stack)-1], "address ") {
+				continue
+			}
+			// "address 192.0.2.1/24 { primary; }"
+			path = strings.Fields(strings.Join(stack, " "))
+		case l == "}":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case strings.HasPrefix(l, "address "):
+			path = strings.Fields(strings.Join(append(stack, strings.TrimSuffix(l, ";")), " "))
+		default:
+			continue
+		}
+
+		if a, ok, err := junosAddress(path); err != nil {
+			return nil, err
+		} else if ok {
+			as = append(as, a)
+		}
+	}
+	return as, sc.Err()
+}
+
+// junosAddress extracts the address from a statement path like
+// "interfaces ge-0/0/0 unit 0 family inet address 192.0.2.1/24".
+func junosAddress(path []string) (Address, bool, error) {
+	i := len(path) - 1
+	for i >= 0 && path[i] != "interfaces" {
+		i--
+	}
+	path = path[i+1:]
+	if i < 0 || len(path) < 7 || path[1] != "unit" || path[3] != "family" || path[5] != "address" {
+		return Address{}, false, nil
+	}
+	if path[4] != "inet" && path[4] != "inet6" {
+		return Address{}, false, nil
+	}
+
+	p, err := netip.ParsePrefix(path[6])
+	if err != nil {
+		return Address{}, false, errors.New("invalid address in interfaces " + strings.Join(path, " "))
+	}
+	return Address{path[0] + "." + path[2], p}, true, nil
+}
+
+// extractLinux parses the "inet" and "inet6" lines of "ip address show" (or "ip -o address show").
+// Addresses are assigned to the label (e.g., eth0:1) if there is one.
+func extractLinux(text string) ([]Address, error) {
+	var as []Address
+	name := ""
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		fs := strings.Fields(sc.Text())
+		if len(fs) >= 2 && strings.HasSuffix(fs[0], ":") {
+			// "2: eth0: <BROADCAST,...>" or "2: eth0    inet 192.0.2.1/24 ..." (one line per address)
+			name = strings.TrimSuffix(strings.SplitN(fs[1], "@", 2)[0], ":")
+			fs = fs[2:]
+		}
+
+		if len(fs) < 2 || fs[0] != "inet" && fs[0] != "inet6" {
+			continue
+		}
+		p, err := netip.ParsePrefix(fs[1])
+		if err != nil {
+			return nil, errors.New("invalid address in " + strings.Join(fs, " "))
+		}
+		label := name
+		for _, f := range fs[2:] {
+			if strings.HasPrefix(f, name+":") {
+				label = f
+			}
+		}
+		as = append(as, Address{label, p})
+	}
+	return as, sc.Err()
+}
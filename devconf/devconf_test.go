@@ -0,0 +1,152 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devconf_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/devconf"
+	. "github.com/stretchr/testify/require"
+)
+
+const iosConfig = `!
+hostname rtr1
+!
+interface Loopback0
+ ip address 198.51.100.1 255.255.255.255
+ ipv6 address 2001:DB8::1/128
+!
+interface GigabitEthernet0/0
+ description uplink
+ ip address 192.0.2.1 255.255.255.0
+ ip address 192.0.2.129 255.255.255.128 secondary
+ ipv6 address FE80::1 link-local
+!
+interface GigabitEthernet0/1
+ no ip address
+ shutdown
+!
+router ospf 1
+ network 192.0.2.0 0.0.0.255 area 0
+`
+
+const junosConfig = `interfaces {
+    ge-0/0/0 {
+        description uplink;
+        unit 0 {
+            family inet {
+                address 192.0.2.1/24 {
+                    primary;
+                }
+                address 192.0.2.129/25;
+            }
+            family inet6 {
+                address 2001:db8::1/64;
+            }
+        }
+    }
+    lo0 {
+        unit 0 {
+            family inet {
+                address 198.51.100.1/32;
+            }
+        }
+    }
+}
+protocols {
+    ospf {
+        area 0.0.0.0 {
+            interface ge-0/0/0.0;
+        }
+    }
+}
+`
+
+const junosSetConfig = `set system host-name rtr1
+set interfaces ge-0/0/0 unit 0 family inet address 192.0.2.1/24
+set interfaces ge-0/0/0 unit 0 family inet6 address 2001:db8::1/64
+set interfaces lo0 unit 0 family inet address 198.51.100.1/32 primary
+set interfaces ge-0/0/0 unit 0 family inet mtu 1500
+`
+
+const linuxConfig = `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
+    inet 127.0.0.1/8 scope host lo
+       valid_lft forever preferred_lft forever
+2: eth0@if5: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc noqueue state UP group default qlen 1000
+    link/ether 02:42:ac:11:00:02 brd ff:ff:ff:ff:ff:ff link-netnsid 0
+    inet 192.0.2.1/24 brd 192.0.2.255 scope global eth0
+       valid_lft forever preferred_lft forever
+    inet 192.0.2.129/25 brd 192.0.2.255 scope global secondary eth0:1
+       valid_lft forever preferred_lft forever
+    inet6 2001:db8::1/64 scope global
+       valid_lft forever preferred_lft forever
+`
+
+func addrs(kvs ...string) (as []devconf.Address) {
+	for i := 0; i < len(kvs); i += 2 {
+		as = append(as, devconf.Address{Interface: kvs[i], Prefix: netip.MustParsePrefix(kvs[i+1])})
+	}
+	return as
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format devconf.Format
+		want   []devconf.Address
+	}{
+		{"ios", iosConfig, devconf.IOS, addrs(
+			"Loopback0", "198.51.100.1/32", "Loopback0", "2001:db8::1/128",
+			"GigabitEthernet0/0", "192.0.2.1/24", "GigabitEthernet0/0", "192.0.2.129/25")},
+		{"junos", junosConfig, devconf.Junos, addrs(
+			"ge-0/0/0.0", "192.0.2.1/24", "ge-0/0/0.0", "192.0.2.129/25", "ge-0/0/0.0", "2001:db8::1/64",
+			"lo0.0", "198.51.100.1/32")},
+		{"junos-set", junosSetConfig, devconf.Junos, addrs(
+			"ge-0/0/0.0", "192.0.2.1/24", "ge-0/0/0.0", "2001:db8::1/64", "lo0.0", "198.51.100.1/32")},
+		{"linux", linuxConfig, devconf.Linux, addrs(
+			"lo", "127.0.0.1/8", "eth0", "192.0.2.1/24", "eth0:1", "192.0.2.129/25", "eth0", "2001:db8::1/64")},
+		{"linux-oneline", "2: eth0    inet 192.0.2.1/24 brd 192.0.2.255 scope global eth0\\" +
+			"       valid_lft forever preferred_lft forever\n" +
+			"2: eth0    inet6 2001:db8::1/64 scope global \\       valid_lft forever preferred_lft forever\n",
+			devconf.Linux, addrs("eth0", "192.0.2.1/24", "eth0", "2001:db8::1/64")},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			Equal(t, tt.format, devconf.Detect(tt.text))
+			as, err := devconf.Extract("", tt.text)
+			NoError(t, err)
+			Equal(t, tt.want, as)
+		})
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	_, err := devconf.Extract("", "hostname rtr1\n")
+	EqualError(t, err, "unknown configuration format")
+
+	_, err = devconf.Extract(devconf.IOS, "interface Gi0/0\n ip address 192.0.2.1 255.0.255.0\n")
+	EqualError(t, err, "invalid netmask in ip address 192.0.2.1 255.0.255.0")
+
+	_, err = devconf.Extract(devconf.Junos, "set interfaces ge-0/0/0 unit 0 family inet address 192.0.2.1\n")
+	EqualError(t, err, "invalid address in interfaces ge-0/0/0 unit 0 family inet address 192.0.2.1")
+
+	_, err = devconf.Extract("eos", "")
+	EqualError(t, err, "unsupported configuration format: eos")
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doh resolves host names and IP addresses via DNS over HTTPS (RFC 8484).
+package doh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/abc-inc/terminus/rdns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mediaType is the media type of DNS messages in wire format.
+const mediaType = "application/dns-message"
+
+// Resolver sends DNS queries to a DoH server. Its methods behave like those of net.Resolver.
+type Resolver struct {
+	// URL is the URL of the DoH endpoint e.g., https://cloudflare-dns.com/dns-query.
+	URL string
+	// Client is the HTTP client sending the queries (http.DefaultClient if nil).
+	Client *http.Client
+}
+
+// LookupAddr returns the names (PTR records) of the IP address.
+func (r *Resolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return nil, &net.DNSError{Err: "unrecognized address", Name: addr}
+	}
+	ip = ip.Unmap().WithZone("")
+
+	rs, err := r.query(ctx, rdns.Name(netip.PrefixFrom(ip, ip.BitLen()))+".", dnsmessage.TypePTR)
+	var names []string
+	for _, res := range rs {
+		if ptr, ok := res.Body.(*dnsmessage.PTRResource); ok {
+			names = append(names, ptr.PTR.String())
+		}
+	}
+	return names, err
+}
+
+// LookupIP returns the IP addresses (A and AAAA records) of the host.
+// network must be "ip" (IPv4 and IPv6), "ip4" or "ip6".
+func (r *Resolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	var types []dnsmessage.Type
+	switch network {
+	case "ip":
+		types = []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	case "ip4":
+		types = []dnsmessage.Type{dnsmessage.TypeA}
+	case "ip6":
+		types = []dnsmessage.Type{dnsmessage.TypeAAAA}
+	default:
+		return nil, net.UnknownNetworkError(network)
+	}
+
+	var ips []net.IP
+	var lastErr error
+	for _, typ := range types {
+		rs, err := r.query(ctx, host, typ)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, res := range rs {
+			switch b := res.Body.(type) {
+			case *dnsmessage.AResource:
+				ips = append(ips, net.IP(b.A[:]))
+			case *dnsmessage.AAAAResource:
+				ips = append(ips, net.IP(b.AAAA[:]))
+			}
+		}
+	}
+	if len(ips) == 0 && lastErr != nil {
+		return nil, lastErr
+	} else if len(ips) == 0 {
+		return nil, r.notFound(host)
+	}
+	return ips, nil
+}
+
+// query sends a recursive query for the name and returns the resources of the answer section.
+func (r *Resolver) query(ctx context.Context, name string, typ dnsmessage.Type) ([]dnsmessage.Resource, error) {
+	if name == "" || name[len(name)-1] != '.' {
+		name += "."
+	}
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, &net.DNSError{Err: "invalid name", Name: name}
+	}
+
+	// the ID is 0 to make the response cacheable (see RFC 8484, section 4.1)
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: n, Type: typ, Class: dnsmessage.ClassINET}},
+	}
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("Accept", mediaType)
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &net.DNSError{Err: err.Error(), Name: name, Server: r.URL, IsTimeout: ctx.Err() != nil}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &net.DNSError{Err: "DoH server returned " + resp.Status, Name: name, Server: r.URL}
+	}
+	if b, err = io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err != nil {
+		return nil, err
+	}
+
+	if err := msg.Unpack(b); err != nil {
+		return nil, &net.DNSError{Err: fmt.Sprintf("cannot unmarshal DNS message: %v", err), Name: name, Server: r.URL}
+	}
+	switch msg.RCode {
+	case dnsmessage.RCodeSuccess:
+		return msg.Answers, nil
+	case dnsmessage.RCodeNameError:
+		return nil, r.notFound(name)
+	default:
+		return nil, &net.DNSError{Err: "server misbehaving: " + msg.RCode.String(), Name: name, Server: r.URL}
+	}
+}
+
+func (r *Resolver) notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, Server: r.URL, IsNotFound: true}
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doh_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abc-inc/terminus/doh"
+	. "github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var records = map[string][]dnsmessage.ResourceBody{
+	"1.2.0.192.in-addr.arpa.": {&dnsmessage.PTRResource{PTR: dnsmessage.MustNewName("host1.example.com.")}},
+	"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.": {
+		&dnsmessage.PTRResource{PTR: dnsmessage.MustNewName("host6.example.com.")},
+	},
+	"host1.example.com.": {
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		&dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}},
+	},
+	"servfail.example.com.": nil,
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "unsupported request", http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(req.Body)
+		var m dnsmessage.Message
+		if m.Unpack(b) != nil {
+			http.Error(w, "malformed message", http.StatusBadRequest)
+			return
+		}
+
+		q := m.Questions[0]
+		m.Response = true
+		rs, ok := records[q.Name.String()]
+		switch {
+		case q.Name.String() == "servfail.example.com.":
+			m.RCode = dnsmessage.RCodeServerFailure
+		case !ok:
+			m.RCode = dnsmessage.RCodeNameError
+		}
+		for _, r := range rs {
+			if typeOf(r) == q.Type {
+				h := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60}
+				m.Answers = append(m.Answers, dnsmessage.Resource{Header: h, Body: r})
+			}
+		}
+		b, _ = m.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func typeOf(r dnsmessage.ResourceBody) dnsmessage.Type {
+	switch r.(type) {
+	case *dnsmessage.AResource:
+		return dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		return dnsmessage.TypeAAAA
+	default:
+		return dnsmessage.TypePTR
+	}
+}
+
+func TestLookupAddr(t *testing.T) {
+	r := &doh.Resolver{URL: newServer(t).URL}
+	names, err := r.LookupAddr(context.Background(), "192.0.2.1")
+	NoError(t, err)
+	Equal(t, []string{"host1.example.com."}, names)
+
+	names, err = r.LookupAddr(context.Background(), "2001:db8::1")
+	NoError(t, err)
+	Equal(t, []string{"host6.example.com."}, names)
+
+	_, err = r.LookupAddr(context.Background(), "192.0.2.2")
+	var dnsErr *net.DNSError
+	True(t, errors.As(err, &dnsErr))
+	True(t, dnsErr.IsNotFound)
+
+	_, err = r.LookupAddr(context.Background(), "192.0.2")
+	EqualError(t, err, "lookup 192.0.2: unrecognized address")
+}
+
+func TestLookupIP(t *testing.T) {
+	r := &doh.Resolver{URL: newServer(t).URL}
+	ips, err := r.LookupIP(context.Background(), "ip", "host1.example.com")
+	NoError(t, err)
+	Equal(t, []net.IP{net.ParseIP("192.0.2.1").To4(), net.ParseIP("2001:db8::1")}, ips)
+
+	ips, err = r.LookupIP(context.Background(), "ip6", "host1.example.com.")
+	NoError(t, err)
+	Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, ips)
+
+	ips, err = r.LookupIP(context.Background(), "ip", "192.0.2.10")
+	NoError(t, err)
+	Equal(t, []net.IP{net.ParseIP("192.0.2.10")}, ips)
+
+	_, err = r.LookupIP(context.Background(), "ip", "unknown.example.com")
+	var dnsErr *net.DNSError
+	True(t, errors.As(err, &dnsErr))
+	True(t, dnsErr.IsNotFound)
+
+	_, err = r.LookupIP(context.Background(), "ip4", "servfail.example.com")
+	ErrorContains(t, err, "server misbehaving: RCodeServerFailure")
+	_, err = r.LookupIP(context.Background(), "tcp", "host1.example.com")
+	EqualError(t, err, "unknown network tcp")
+}
+
+func TestLookupIPHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	r := &doh.Resolver{URL: srv.URL, Client: srv.Client()}
+	_, err := r.LookupIP(context.Background(), "ip4", "host1.example.com")
+	EqualError(t, err, "lookup host1.example.com. on "+srv.URL+": DoH server returned 404 Not Found")
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geo looks up the geolocation of IP addresses in MaxMind DB (MMDB) files like GeoLite2-City.
+package geo
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is the geolocation of an IP address.
+// Fields, which are not contained in the database (e.g., the city in GeoLite2-Country), are empty.
+type Location struct {
+	// Network is the network, which the location applies to.
+	Network netip.Prefix `json:"network"`
+	// Country is the ISO 3166 code of the country.
+	Country string `json:"country,omitempty"`
+	// CountryName is the localized name of the country.
+	CountryName string `json:"countryName,omitempty"`
+	// City is the localized name of the city.
+	City string `json:"city,omitempty"`
+	// Latitude and Longitude are the approximate coordinates (nil if unknown).
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// String returns the country code and the city (if known) e.g., "US, Mountain View".
+func (l Location) String() string {
+	return strings.TrimPrefix(strings.TrimSuffix(l.Country+", "+l.City, ", "), ", ")
+}
+
+// record is the subset of the GeoIP2/GeoLite2 City and Country schema, which is decoded.
+type record struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// DB is an open MMDB file.
+type DB struct {
+	r *maxminddb.Reader
+}
+
+// Open opens the MMDB file.
+func Open(name string) (*DB, error) {
+	r, err := maxminddb.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %w", name, err)
+	}
+	return &DB{r}, nil
+}
+
+// FromBytes reads the MMDB from memory.
+func FromBytes(b []byte) (*DB, error) {
+	r, err := maxminddb.FromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{r}, nil
+}
+
+// Close releases the resources of the database.
+func (db *DB) Close() error {
+	return db.r.Close()
+}
+
+// Lookup returns the location of ip with names in the given language (falling back to English).
+// If the database does not contain ip, ok is false.
+func (db *DB) Lookup(ip netip.Addr, lang string) (l Location, ok bool, err error) {
+	var rec record
+	n, ok, err := db.r.LookupNetwork(net.IP(ip.Unmap().AsSlice()), &rec)
+	if err != nil || !ok {
+		return Location{}, ok, err
+	}
+
+	ones, _ := n.Mask.Size()
+	a, _ := netip.AddrFromSlice(n.IP)
+	l = Location{
+		Network:     netip.PrefixFrom(a.Unmap(), ones),
+		Country:     rec.Country.ISOCode,
+		CountryName: name(rec.Country.Names, lang),
+		City:        name(rec.City.Names, lang),
+		Latitude:    rec.Location.Latitude,
+		Longitude:   rec.Location.Longitude,
+	}
+	return l, true, nil
+}
+
+func name(names map[string]string, lang string) string {
+	if n, ok := names[lang]; ok {
+		return n
+	}
+	return names["en"]
+}
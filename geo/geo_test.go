@@ -0,0 +1,153 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo_test
+
+import (
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/geo"
+	. "github.com/stretchr/testify/require"
+)
+
+// The following functions encode values in the MaxMind DB data section format (sizes < 29 only).
+
+func ctrl(typ, size int) []byte {
+	if typ > 7 {
+		return []byte{byte(size), byte(typ - 7)}
+	}
+	return []byte{byte(typ<<5 | size)}
+}
+
+func str(s string) []byte {
+	return append(ctrl(2, len(s)), s...)
+}
+
+func double(f float64) []byte {
+	return binary.BigEndian.AppendUint64(ctrl(3, 8), math.Float64bits(f))
+}
+
+func uint16v(v uint16) []byte {
+	return binary.BigEndian.AppendUint16(ctrl(5, 2), v)
+}
+
+func uint32v(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(ctrl(6, 4), v)
+}
+
+// kv encodes a map with alternating keys and (encoded) values.
+func kv(kvs ...interface{}) []byte {
+	b := ctrl(7, len(kvs)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		b = append(b, str(kvs[i].(string))...)
+		b = append(b, kvs[i+1].([]byte)...)
+	}
+	return b
+}
+
+// mmdb builds an IPv4 database with 24-bit records, which maps the networks to the (encoded) records.
+func mmdb(nets map[string][]byte) []byte {
+	const empty, leaf = -1, -2
+	nodes := [][2]int{{empty, empty}}
+	var data []byte
+	offsets := map[int]int{} // leaf marker -> data offset
+
+	for s, rec := range nets {
+		p := netip.MustParsePrefix(s)
+		a, n := p.Addr().As4(), 0
+		for i := 0; i < p.Bits(); i++ {
+			bit := int(a[i/8]>>(7-i%8)) & 1
+			if i == p.Bits()-1 {
+				nodes[n][bit] = leaf - len(offsets)
+				offsets[leaf-len(offsets)] = len(data)
+				data = append(data, rec...)
+				break
+			}
+			if nodes[n][bit] == empty {
+				nodes = append(nodes, [2]int{empty, empty})
+				nodes[n][bit] = len(nodes) - 1
+			}
+			n = nodes[n][bit]
+		}
+	}
+
+	var b []byte
+	for _, n := range nodes {
+		for _, r := range n {
+			v := r
+			switch {
+			case r == empty:
+				v = len(nodes)
+			case r <= leaf:
+				v = len(nodes) + 16 + offsets[r]
+			}
+			b = append(b, byte(v>>16), byte(v>>8), byte(v))
+		}
+	}
+	b = append(b, make([]byte, 16)...)
+	b = append(b, data...)
+	b = append(b, "\xab\xcd\xefMaxMind.com"...)
+	return append(b, kv(
+		"binary_format_major_version", uint16v(2),
+		"binary_format_minor_version", uint16v(0),
+		"build_epoch", uint32v(1700000000),
+		"database_type", str("Test-City"),
+		"description", kv("en", str("Test database")),
+		"ip_version", uint16v(4),
+		"languages", append(ctrl(11, 1), str("en")...),
+		"node_count", uint32v(uint32(len(nodes))),
+		"record_size", uint16v(24),
+	)...)
+}
+
+func TestLookup(t *testing.T) {
+	db, err := geo.FromBytes(mmdb(map[string][]byte{
+		"192.0.2.0/24": kv(
+			"city", kv("names", kv("en", str("Vienna"), "de", str("Wien"))),
+			"country", kv("iso_code", str("AT"), "names", kv("en", str("Austria"), "de", str("Österreich"))),
+			"location", kv("latitude", double(48.2), "longitude", double(16.3667)),
+		),
+		"198.51.100.128/25": kv("country", kv("iso_code", str("US"), "names", kv("en", str("United States")))),
+	}))
+	NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	l, ok, err := db.Lookup(netip.MustParseAddr("192.0.2.1"), "de")
+	NoError(t, err)
+	True(t, ok)
+	lat, lon := 48.2, 16.3667
+	Equal(t, geo.Location{
+		Network: netip.MustParsePrefix("192.0.2.0/24"), Country: "AT", CountryName: "Österreich", City: "Wien",
+		Latitude: &lat, Longitude: &lon,
+	}, l)
+	Equal(t, "AT, Wien", l.String())
+
+	l, ok, err = db.Lookup(netip.MustParseAddr("::ffff:198.51.100.200"), "de")
+	NoError(t, err)
+	True(t, ok)
+	Equal(t, geo.Location{
+		Network: netip.MustParsePrefix("198.51.100.128/25"), Country: "US", CountryName: "United States",
+	}, l)
+	Equal(t, "US", l.String())
+
+	_, ok, err = db.Lookup(netip.MustParseAddr("198.51.100.1"), "en")
+	NoError(t, err)
+	False(t, ok)
+
+	_, err = geo.FromBytes([]byte("invalid"))
+	Error(t, err)
+}
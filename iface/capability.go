@@ -0,0 +1,91 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Feature identifies an optional feature, which is not available on every platform.
+type Feature string
+
+// Optional features of the iface package.
+const (
+	// FeatureRoutes is the lookup of the (IPv4) routing table.
+	FeatureRoutes Feature = "routes"
+	// FeatureIPv6Routes is the lookup of IPv6 routes.
+	FeatureIPv6Routes Feature = "ipv6-routes"
+	// FeatureStats is the reading of interface statistics.
+	FeatureStats Feature = "stats"
+	// FeatureDHCPLease is the reading of DHCP leases.
+	FeatureDHCPLease Feature = "dhcp-lease"
+	// FeatureDNS is the reading of the DNS configuration of network interfaces.
+	FeatureDNS Feature = "dns"
+	// FeatureSpeed is the reading of the link speed of network interfaces.
+	FeatureSpeed Feature = "speed"
+)
+
+// ErrNotSupported is returned if a feature is not supported on the current platform.
+var ErrNotSupported = errors.New("not supported on this platform")
+
+// Capability describes whether a feature is available on the current platform.
+type Capability struct {
+	Feature Feature `json:"feature"`
+	// Description is a human-readable name of the feature, which is used in error messages.
+	Description string `json:"description"`
+	Supported   bool   `json:"supported"`
+	// Detail describes how the feature is implemented or why it is not supported.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Err returns nil if the feature is supported or an error wrapping ErrNotSupported otherwise.
+func (c Capability) Err() error {
+	if c.Supported {
+		return nil
+	}
+	return &notSupportedError{c}
+}
+
+type notSupportedError struct {
+	c Capability
+}
+
+func (e *notSupportedError) Error() string {
+	s := e.c.Description + " is " + ErrNotSupported.Error() + " (" + runtime.GOOS + "/" + runtime.GOARCH
+	if e.c.Detail != "" {
+		s += ": " + e.c.Detail
+	}
+	return s + ")"
+}
+
+func (e *notSupportedError) Unwrap() error {
+	return ErrNotSupported
+}
+
+// Capabilities returns the optional features of the iface package and whether they are available.
+func Capabilities() []Capability {
+	return append([]Capability(nil), capabilities...)
+}
+
+// Supported returns nil if the feature is available or an error wrapping ErrNotSupported otherwise.
+func Supported(f Feature) error {
+	for _, c := range capabilities {
+		if c.Feature == f {
+			return c.Err()
+		}
+	}
+	return &notSupportedError{Capability{Feature: f, Description: string(f)}}
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+var capabilities = []Capability{
+	{FeatureRoutes, "routing table lookup", true, "/proc/net/route"},
+	{FeatureIPv6Routes, "IPv6 routing table lookup", true, "/proc/net/ipv6_route"},
+	{FeatureStats, "interface statistics", true, "/sys/class/net"},
+	{FeatureDHCPLease, "DHCP lease reading", true, "systemd-networkd, NetworkManager and dhclient lease files"},
+	{FeatureDNS, "DNS configuration", true, "systemd-resolved, systemd-networkd and resolv.conf"},
+	{FeatureSpeed, "link speed", true, "/sys/class/net"},
+}
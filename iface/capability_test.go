@@ -0,0 +1,48 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface_test
+
+import (
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	cs := iface.Capabilities()
+	NotEmpty(t, cs)
+	for _, c := range cs {
+		NotEmpty(t, c.Description, c.Feature)
+		if c.Supported {
+			NoError(t, c.Err())
+			NoError(t, iface.Supported(c.Feature))
+		} else {
+			ErrorIs(t, iface.Supported(c.Feature), iface.ErrNotSupported)
+		}
+	}
+
+	cs[0].Supported = false
+	Equal(t, iface.Supported(cs[0].Feature) == nil, iface.Capabilities()[0].Supported)
+}
+
+func TestCapabilityErr(t *testing.T) {
+	c := iface.Capability{Feature: iface.FeatureRoutes, Description: "routing table lookup", Detail: "no API"}
+	err := c.Err()
+	ErrorIs(t, err, iface.ErrNotSupported)
+	Regexp(t, `^routing table lookup is not supported on this platform \(\w+/\w+: no API\)$`, err.Error())
+
+	ErrorIs(t, iface.Supported("teleport"), iface.ErrNotSupported)
+}
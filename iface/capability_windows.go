@@ -0,0 +1,24 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+var capabilities = []Capability{
+	{FeatureRoutes, "routing table lookup", true, "GetIpForwardTable"},
+	{FeatureIPv6Routes, "IPv6 routing table lookup", false, "GetIpForwardTable is limited to IPv4"},
+	{FeatureStats, "interface statistics", true, "GetIfEntry (32-bit counters)"},
+	{FeatureDHCPLease, "DHCP lease reading", true, "GetAdaptersInfo"},
+	{FeatureDNS, "DNS configuration", true, "GetAdaptersAddresses"},
+	{FeatureSpeed, "link speed", false, ""},
+}
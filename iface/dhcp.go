@@ -0,0 +1,185 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DHCPLease is the current DHCPv4 lease of a network interface.
+// Fields, which the operating system does not expose, are empty.
+type DHCPLease struct {
+	// Address is the leased IP address.
+	Address net.IP `json:"address,omitempty" yaml:"address,omitempty"`
+	// Server is the DHCP server, which granted the lease.
+	Server net.IP `json:"server,omitempty" yaml:"server,omitempty"`
+	// LeaseTime is the duration of the lease in seconds.
+	LeaseTime int `json:"leaseTime,omitempty" yaml:"leaseTime,omitempty"`
+	// Obtained is the time, when the lease was obtained (or renewed).
+	Obtained *time.Time `json:"obtained,omitempty" yaml:"obtained,omitempty"`
+	// Renew is the time, when the client starts renewing the lease (T1).
+	Renew *time.Time `json:"renew,omitempty" yaml:"renew,omitempty"`
+	// Rebind is the time, when the client starts rebinding the lease with any server (T2).
+	Rebind *time.Time `json:"rebind,omitempty" yaml:"rebind,omitempty"`
+	// Expires is the time, when the lease expires.
+	Expires *time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+	// Options are the obtained options by name (e.g., "routers" or "domain-name-servers").
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// String returns a summary of the lease e.g., "192.168.1.10 from 192.168.1.1 until 2024-01-03T03:04:05Z".
+func (l DHCPLease) String() string {
+	if l.Address == nil {
+		return ""
+	}
+	s := l.Address.String()
+	if l.Server != nil {
+		s += " from " + l.Server.String()
+	}
+	if l.Expires != nil {
+		s += " until " + l.Expires.Format(time.RFC3339)
+	}
+	return s
+}
+
+// GetDHCPLease returns the current DHCP lease of the interface specified by name.
+func GetDHCPLease(name string) (DHCPLease, error) {
+	i, err := InterfaceByName(name)
+	if err != nil {
+		return DHCPLease{}, err
+	}
+	var l DHCPLease
+	if si := snapshotOf(name); si != nil {
+		if si.DHCP != nil {
+			l = *si.DHCP
+		}
+	} else {
+		l, err = dhcpLease(i)
+	}
+	if err == nil && l.Address == nil {
+		err = errors.New("no DHCP lease: " + name)
+	}
+	return l, err
+}
+
+// optionNames maps the option names used by various DHCP clients to the names used by ISC dhclient (RFC 2132).
+var optionNames = map[string]string{
+	"dns":                "domain-name-servers",
+	"domain-name-server": "domain-name-servers",
+	"domainname":         "domain-name",
+	"hostname":           "host-name",
+	"mtu":                "interface-mtu",
+	"netmask":            "subnet-mask",
+	"ntp":                "ntp-servers",
+	"router":             "routers",
+}
+
+func optionName(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(s), "_", "-")
+	if n, ok := optionNames[s]; ok {
+		return n
+	}
+	return s
+}
+
+// setTimes sets the renewal, rebinding and expiry time relative to the time the lease was obtained.
+func (l *DHCPLease) setTimes(obtained time.Time, t1, t2 int) {
+	at := func(secs int) *time.Time {
+		t := obtained.Add(time.Duration(secs) * time.Second).UTC()
+		return &t
+	}
+	l.Obtained = at(0)
+	if t1 > 0 {
+		l.Renew = at(t1)
+	}
+	if t2 > 0 {
+		l.Rebind = at(t2)
+	}
+	if l.LeaseTime > 0 {
+		l.Expires = at(l.LeaseTime)
+	}
+}
+
+// parseDhclientLeases returns the last lease of the interface in an ISC dhclient lease file.
+// Times are in UTC ("renew 2 2024/01/02 15:04:05;") or seconds since the epoch ("renew epoch 1704207845;").
+func parseDhclientLeases(r io.Reader, name string) (last DHCPLease, err error) {
+	var cur DHCPLease
+	ifName := ""
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		l := strings.TrimSuffix(strings.TrimSpace(sc.Text()), ";")
+		fs := strings.Fields(l)
+		switch {
+		case len(fs) == 0:
+			continue
+		case fs[0] == "lease":
+			cur, ifName = DHCPLease{Options: map[string]string{}}, ""
+		case fs[0] == "}":
+			// lease files of a single interface may omit the interface
+			if ifName == name || ifName == "" {
+				last = cur
+				if last.Obtained == nil && last.Expires != nil && last.LeaseTime > 0 {
+					t := last.Expires.Add(-time.Duration(last.LeaseTime) * time.Second)
+					last.Obtained = &t
+				}
+			}
+		case fs[0] == "interface" && len(fs) == 2:
+			ifName = strings.Trim(fs[1], `"`)
+		case fs[0] == "fixed-address" && len(fs) == 2:
+			cur.Address = net.ParseIP(fs[1])
+		case fs[0] == "option" && len(fs) >= 3:
+			v := strings.Trim(strings.Join(fs[2:], " "), `"`)
+			switch fs[1] {
+			case "dhcp-server-identifier":
+				cur.Server = net.ParseIP(v)
+			case "dhcp-lease-time":
+				cur.LeaseTime, _ = strconv.Atoi(v)
+			default:
+				cur.Options[fs[1]] = v
+			}
+		case fs[0] == "renew" || fs[0] == "rebind" || fs[0] == "expire":
+			t, ok := parseDhclientTime(fs[1:])
+			if !ok {
+				continue
+			}
+			switch fs[0] {
+			case "renew":
+				cur.Renew = &t
+			case "rebind":
+				cur.Rebind = &t
+			default:
+				cur.Expires = &t
+			}
+		}
+	}
+	return last, sc.Err()
+}
+
+func parseDhclientTime(fs []string) (time.Time, bool) {
+	if len(fs) == 2 && fs[0] == "epoch" {
+		secs, err := strconv.ParseInt(fs[1], 10, 64)
+		return time.Unix(secs, 0).UTC(), err == nil
+	} else if len(fs) == 3 {
+		t, err := time.Parse("2006/01/02 15:04:05", fs[1]+" "+fs[2])
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
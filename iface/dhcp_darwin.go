@@ -0,0 +1,72 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// dhcpLease returns the lease of the interface as reported by "ipconfig getpacket".
+// The DHCP packet does not contain the time, when the lease was obtained, thus, only the lease time is known.
+func dhcpLease(i *net.Interface) (DHCPLease, error) {
+	out, err := exec.Command("/usr/sbin/ipconfig", "getpacket", i.Name).Output()
+	if err != nil {
+		// no DHCP packet for the interface
+		return DHCPLease{}, nil
+	}
+	return parseIPConfigPacket(bytes.NewReader(out))
+}
+
+// parseIPConfigPacket parses the output of "ipconfig getpacket" e.g.,
+//
+//	yiaddr = 192.168.1.10
+//	server_identifier (ip): 192.168.1.1
+//	lease_time (uint32): 0x15180
+//	router (ip_mult): {192.168.1.1}
+func parseIPConfigPacket(r io.Reader) (DHCPLease, error) {
+	l := DHCPLease{Options: map[string]string{}}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if k, v, ok := strings.Cut(line, " = "); ok && k == "yiaddr" {
+			l.Address = net.ParseIP(strings.TrimSpace(v))
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, "): ")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(k, " (")
+		v = strings.TrimSpace(v)
+		switch name {
+		case "server_identifier":
+			l.Server = net.ParseIP(v)
+		case "lease_time":
+			n, _ := strconv.ParseInt(v, 0, 64)
+			l.LeaseTime = int(n)
+		case "dhcp_message_type", "end":
+		default:
+			l.Options[optionName(name)] = strings.ReplaceAll(strings.Trim(v, "{}"), ", ", ",")
+		}
+	}
+	return l, sc.Err()
+}
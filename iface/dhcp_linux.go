@@ -0,0 +1,119 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dhcpLease returns the lease of systemd-networkd, the internal DHCP client of NetworkManager or ISC dhclient.
+func dhcpLease(i *net.Interface) (DHCPLease, error) {
+	networkd := []string{"/run/systemd/netif/leases/" + strconv.Itoa(i.Index)}
+	nm, _ := filepath.Glob("/var/lib/NetworkManager/internal-*-" + i.Name + ".lease")
+	for _, name := range append(networkd, nm...) {
+		if l, err := readNetworkdLease(name); err == nil && l.Address != nil {
+			return l, nil
+		}
+	}
+
+	var dhclient []string
+	for _, p := range []string{
+		"/var/lib/dhcp/dhclient*.leases",
+		"/var/lib/dhclient/dhclient*.leases",
+		"/var/lib/NetworkManager/dhclient-*-" + i.Name + ".lease",
+	} {
+		ms, _ := filepath.Glob(p)
+		dhclient = append(dhclient, ms...)
+	}
+
+	// the lease, which expires last, is the current one
+	var best DHCPLease
+	for _, name := range dhclient {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		l, err := parseDhclientLeases(f, i.Name)
+		_ = f.Close()
+		if err == nil && l.Address != nil && (best.Address == nil || newer(l, best)) {
+			best = l
+		}
+	}
+	return best, nil
+}
+
+func newer(l, o DHCPLease) bool {
+	return l.Expires != nil && (o.Expires == nil || l.Expires.After(*o.Expires))
+}
+
+// readNetworkdLease reads a lease file of systemd-networkd.
+// Since the file is (re)written whenever the lease is obtained or renewed, its modification time is used as
+// reference for the relative renewal, rebinding and expiry times.
+func readNetworkdLease(name string) (DHCPLease, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return DHCPLease{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return DHCPLease{}, err
+	}
+	return parseNetworkdLease(f, fi.ModTime())
+}
+
+// networkdOptions are the keys of a systemd-networkd lease file, which are options obtained from the server.
+var networkdOptions = map[string]bool{
+	"DNS": true, "DOMAINNAME": true, "DOMAIN_SEARCH_LIST": true, "HOSTNAME": true, "MTU": true,
+	"NETMASK": true, "NTP": true, "ROUTER": true, "SIP": true, "TIMEZONE": true,
+}
+
+func parseNetworkdLease(r io.Reader, obtained time.Time) (DHCPLease, error) {
+	l := DHCPLease{Options: map[string]string{}}
+	t1, t2 := 0, 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if !ok || strings.HasPrefix(k, "#") {
+			continue
+		}
+		switch k {
+		case "ADDRESS":
+			l.Address = net.ParseIP(v)
+		case "SERVER_ADDRESS":
+			l.Server = net.ParseIP(v)
+		case "LIFETIME":
+			l.LeaseTime, _ = strconv.Atoi(v)
+		case "T1":
+			t1, _ = strconv.Atoi(v)
+		case "T2":
+			t2, _ = strconv.Atoi(v)
+		default:
+			if networkdOptions[k] {
+				l.Options[optionName(k)] = strings.Join(strings.Fields(v), ",")
+			}
+		}
+	}
+	l.setTimes(obtained, t1, t2)
+	return l, sc.Err()
+}
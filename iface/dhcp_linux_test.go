@@ -0,0 +1,47 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkdLease(t *testing.T) {
+	l, err := parseNetworkdLease(strings.NewReader(`# This is private data. Do not parse.
+ADDRESS=192.168.1.10
+NETMASK=255.255.255.0
+ROUTER=192.168.1.1
+SERVER_ADDRESS=192.168.1.1
+NEXT_SERVER=0.0.0.0
+T1=43200
+T2=75600
+LIFETIME=86400
+DNS=192.168.1.1 8.8.8.8
+DOMAINNAME=lan
+CLIENTID=ffb6220feb00020000ab11
+`), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	NoError(t, err)
+	Equal(t, "192.168.1.10 from 192.168.1.1 until 2024-01-03T03:04:05Z", l.String())
+	Equal(t, "2024-01-02T15:04:05Z", l.Renew.Format(time.RFC3339))
+	Equal(t, "2024-01-02T00:04:05Z", l.Rebind.Add(-24*time.Hour).Format(time.RFC3339))
+	Equal(t, map[string]string{
+		"subnet-mask": "255.255.255.0", "routers": "192.168.1.1", "domain-name-servers": "192.168.1.1,8.8.8.8",
+		"domain-name": "lan",
+	}, l.Options)
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin && !windows
+
+package iface
+
+import (
+	"net"
+	"os"
+)
+
+// dhcpLease returns the lease of the interface in the lease file of dhclient (as used by the BSDs).
+func dhcpLease(i *net.Interface) (DHCPLease, error) {
+	f, err := os.Open("/var/db/dhclient.leases." + i.Name)
+	if err != nil {
+		return DHCPLease{}, nil
+	}
+	defer func() { _ = f.Close() }()
+	return parseDhclientLeases(f, i.Name)
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/stretchr/testify/require"
+)
+
+const dhclientLeases = `lease {
+  interface "eth0";
+  fixed-address 192.168.1.9;
+  option dhcp-lease-time 3600;
+  expire 2 2024/01/02 00:00:00;
+}
+lease {
+  interface "eth1";
+  fixed-address 10.0.0.9;
+}
+lease {
+  interface "eth0";
+  fixed-address 192.168.1.10;
+  option subnet-mask 255.255.255.0;
+  option routers 192.168.1.1;
+  option dhcp-lease-time 86400;
+  option dhcp-message-type 5;
+  option domain-name-servers 192.168.1.1,8.8.8.8;
+  option dhcp-server-identifier 192.168.1.1;
+  option domain-name "lan";
+  renew 2 2024/01/02 15:04:05;
+  rebind epoch 1704243845;
+  expire 3 2024/01/03 03:04:05;
+}
+`
+
+func TestParseDhclientLeases(t *testing.T) {
+	l, err := parseDhclientLeases(strings.NewReader(dhclientLeases), "eth0")
+	NoError(t, err)
+	Equal(t, "192.168.1.10", l.Address.String())
+	Equal(t, "192.168.1.1", l.Server.String())
+	Equal(t, 86400, l.LeaseTime)
+	Equal(t, "2024-01-02T03:04:05Z", l.Obtained.Format(time.RFC3339))
+	Equal(t, "2024-01-02T15:04:05Z", l.Renew.Format(time.RFC3339))
+	Equal(t, "2024-01-03T01:04:05Z", l.Rebind.Format(time.RFC3339))
+	Equal(t, "2024-01-03T03:04:05Z", l.Expires.Format(time.RFC3339))
+	Equal(t, map[string]string{
+		"subnet-mask": "255.255.255.0", "routers": "192.168.1.1", "dhcp-message-type": "5",
+		"domain-name-servers": "192.168.1.1,8.8.8.8", "domain-name": "lan",
+	}, l.Options)
+	Equal(t, "192.168.1.10 from 192.168.1.1 until 2024-01-03T03:04:05Z", l.String())
+
+	l, err = parseDhclientLeases(strings.NewReader(dhclientLeases), "eth2")
+	NoError(t, err)
+	Nil(t, l.Address)
+	Equal(t, "", l.String())
+}
+
+func TestDHCPLeaseSetTimes(t *testing.T) {
+	l := DHCPLease{Address: net.ParseIP("10.0.0.9"), LeaseTime: 600}
+	l.setTimes(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), 300, 0)
+	Equal(t, "2024-01-02T03:09:05Z", l.Renew.Format(time.RFC3339))
+	Nil(t, l.Rebind)
+	Equal(t, "10.0.0.9 until 2024-01-02T03:14:05Z", l.String())
+}
+
+func TestOptionName(t *testing.T) {
+	Equal(t, "routers", optionName("ROUTER"))
+	Equal(t, "domain-name-servers", optionName("domain_name_server"))
+	Equal(t, "domain-search-list", optionName("DOMAIN_SEARCH_LIST"))
+}
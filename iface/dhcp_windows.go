@@ -0,0 +1,74 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// dhcpLease returns the lease of the adapter as reported by GetAdaptersInfo.
+func dhcpLease(i *net.Interface) (l DHCPLease, err error) {
+	size := uint32(15000)
+	var b []byte
+	for {
+		b = make([]byte, size)
+		err = windows.GetAdaptersInfo((*windows.IpAdapterInfo)(unsafe.Pointer(&b[0])), &size)
+		if err == nil {
+			break
+		} else if !errors.Is(err, windows.ERROR_BUFFER_OVERFLOW) {
+			return l, errors.New("cannot read network adapters: " + err.Error())
+		}
+	}
+
+	for a := (*windows.IpAdapterInfo)(unsafe.Pointer(&b[0])); a != nil; a = a.Next {
+		if int(a.Index) != i.Index || a.DhcpEnabled == 0 {
+			continue
+		}
+
+		l.Address = addrString(a.IpAddressList)
+		l.Server = addrString(a.DhcpServer)
+		l.Options = map[string]string{}
+		if gw := addrString(a.GatewayList); gw != nil {
+			l.Options["routers"] = gw.String()
+		}
+		if c, err := dnsConfig(i); err == nil && len(c.Servers) > 0 {
+			l.Options["domain-name-servers"] = strings.ReplaceAll(c.Servers.String(), " ", ",")
+		}
+		if a.LeaseObtained > 0 && a.LeaseExpires > a.LeaseObtained {
+			l.LeaseTime = int(a.LeaseExpires - a.LeaseObtained)
+			l.setTimes(time.Unix(a.LeaseObtained, 0), 0, 0)
+		}
+		return l, nil
+	}
+	return l, nil
+}
+
+// addrString returns the IP address of the IP_ADDR_STRING (or nil if it is empty).
+func addrString(s windows.IpAddrString) net.IP {
+	b := s.IpAddress.String[:]
+	if n := strings.IndexByte(string(b), 0); n >= 0 {
+		b = b[:n]
+	}
+	if ip := net.ParseIP(string(b)); ip != nil && !ip.IsUnspecified() {
+		return ip
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// IPList is a list of IP addresses, which is formatted as space-separated list.
+type IPList []net.IP
+
+func (l IPList) String() string {
+	ss := make([]string, len(l))
+	for i, ip := range l {
+		ss[i] = ip.String()
+	}
+	return strings.Join(ss, " ")
+}
+
+// StringList is a list of strings, which is formatted as space-separated list.
+type StringList []string
+
+func (l StringList) String() string {
+	return strings.Join(l, " ")
+}
+
+// DNSConfig is the DNS configuration of a network interface.
+type DNSConfig struct {
+	// Servers are the DNS servers (name servers).
+	Servers IPList `json:"servers"`
+	// Search are the search domains, which are appended to unqualified names.
+	Search StringList `json:"search"`
+}
+
+// GetDNS returns the DNS servers and search domains of the interface specified by name.
+// If the operating system does not associate them with interfaces (e.g., /etc/resolv.conf),
+// the system-wide configuration is returned for all interfaces except loopback interfaces.
+func GetDNS(name string) (DNSConfig, error) {
+	i, err := InterfaceByName(name)
+	if err != nil {
+		return DNSConfig{}, err
+	}
+	var c DNSConfig
+	if si := snapshotOf(name); si != nil {
+		if si.DNS != nil {
+			c = *si.DNS
+		}
+	} else {
+		c, err = dnsConfig(i)
+	}
+	if c.Servers == nil {
+		c.Servers = IPList{}
+	}
+	if c.Search == nil {
+		c.Search = StringList{}
+	}
+	return c, err
+}
+
+// globalDNS returns the system-wide DNS configuration from the resolv.conf file (unless i is a loopback interface).
+func globalDNS(i *net.Interface, name string) (DNSConfig, error) {
+	if i.Flags&net.FlagLoopback != 0 {
+		return DNSConfig{}, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return DNSConfig{}, err
+	}
+	defer func() { _ = f.Close() }()
+	return parseResolvConf(f)
+}
+
+// parseResolvConf parses the name servers and search domains of a resolv.conf file.
+// As of glibc 2.26, the last search or domain directive wins.
+func parseResolvConf(r io.Reader) (c DNSConfig, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fs := strings.Fields(sc.Text())
+		if len(fs) < 2 || strings.HasPrefix(fs[0], "#") || strings.HasPrefix(fs[0], ";") {
+			continue
+		}
+		switch fs[0] {
+		case "nameserver":
+			if ip := parseServer(fs[1]); ip != nil {
+				c.Servers = append(c.Servers, ip)
+			}
+		case "domain", "search":
+			c.Search = StringList(fs[1:])
+		}
+	}
+	return c, sc.Err()
+}
+
+// parseServer parses a DNS server address, which may contain a port, a zone (interface)
+// and a server name e.g., "[2001:db8::53]:853%eth0#dns.example.com" (or returns nil if it is invalid).
+func parseServer(s string) net.IP {
+	s, _, _ = strings.Cut(s, "#")
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return net.IP(ap.Addr().Unmap().AsSlice())
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return net.IP(a.Unmap().AsSlice())
+	}
+	// IPv4 addresses with zone and systemd-style [IPv6]:PORT%ZONE
+	s, _, _ = strings.Cut(s, "%")
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return net.IP(ap.Addr().Unmap().AsSlice())
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return net.IP(a.Unmap().AsSlice())
+	}
+	return nil
+}
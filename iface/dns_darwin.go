@@ -0,0 +1,79 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// dnsConfig returns the scoped resolver of the interface as reported by scutil.
+// Otherwise, the system-wide configuration (/etc/resolv.conf) is returned.
+func dnsConfig(i *net.Interface) (DNSConfig, error) {
+	if out, err := exec.Command("/usr/sbin/scutil", "--dns").Output(); err == nil {
+		if c := parseScutilDNS(bytes.NewReader(out), i.Index); len(c.Servers) > 0 {
+			return c, nil
+		}
+	}
+	return globalDNS(i, "/etc/resolv.conf")
+}
+
+// parseScutilDNS parses the resolver of the interface in the "scoped queries" section of "scutil --dns".
+func parseScutilDNS(r io.Reader, index int) (c DNSConfig) {
+	var cur DNSConfig
+	scoped, match := false, false
+	flush := func() {
+		if scoped && match && len(c.Servers) == 0 {
+			c = cur
+		}
+		cur, match = DNSConfig{}, false
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		l := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(l, "DNS configuration"):
+			flush()
+			scoped = strings.Contains(l, "scoped")
+		case strings.HasPrefix(l, "resolver #"):
+			flush()
+		default:
+			k, v, ok := strings.Cut(l, ":")
+			if !ok {
+				continue
+			}
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			switch {
+			case strings.HasPrefix(k, "nameserver["):
+				if ip := parseServer(v); ip != nil {
+					cur.Servers = append(cur.Servers, ip)
+				}
+			case strings.HasPrefix(k, "search domain["):
+				cur.Search = append(cur.Search, v)
+			case k == "if_index":
+				idx, _ := strconv.Atoi(strings.Fields(v + " ")[0])
+				match = idx == index
+			}
+		}
+	}
+	flush()
+	return c
+}
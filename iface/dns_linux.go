@@ -0,0 +1,77 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// linkStateFiles are the per-interface state files of systemd-resolved and systemd-networkd (by interface index).
+var linkStateFiles = []string{"/run/systemd/resolve/netif/", "/run/systemd/netif/links/"}
+
+// dnsConfig returns the DNS configuration of systemd-resolved or systemd-networkd for the interface.
+// Otherwise, the system-wide configuration (resolv.conf as written by NetworkManager, resolvconf, etc.) is returned.
+func dnsConfig(i *net.Interface) (DNSConfig, error) {
+	for _, dir := range linkStateFiles {
+		if f, err := os.Open(dir + strconv.Itoa(i.Index)); err == nil {
+			c, err := parseLinkState(f)
+			_ = f.Close()
+			if err == nil && len(c.Servers) > 0 {
+				return c, nil
+			}
+		}
+	}
+
+	c, err := globalDNS(i, "/etc/resolv.conf")
+	if err == nil && len(c.Servers) == 1 && c.Servers[0].Equal(net.IPv4(127, 0, 0, 53)) {
+		// systemd-resolved stub resolver - report the upstream servers instead
+		if up, err := globalDNS(i, "/run/systemd/resolve/resolv.conf"); err == nil {
+			return up, nil
+		}
+	}
+	return c, err
+}
+
+// parseLinkState parses the DNS servers and search domains of a link state file of systemd.
+// Route-only domains (prefixed with "~") are skipped.
+func parseLinkState(r io.Reader) (c DNSConfig, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "DNS":
+			for _, s := range strings.Fields(v) {
+				if ip := parseServer(s); ip != nil {
+					c.Servers = append(c.Servers, ip)
+				}
+			}
+		case "DOMAINS":
+			for _, d := range strings.Fields(v) {
+				if !strings.HasPrefix(d, "~") {
+					c.Search = append(c.Search, d)
+				}
+			}
+		}
+	}
+	return c, sc.Err()
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseLinkState(t *testing.T) {
+	c, err := parseLinkState(strings.NewReader(`# This is private data. Do not parse.
+ADMIN_STATE=configured
+OPER_STATE=routable
+DNS=192.168.1.1 [2001:db8::53]:53%2#dns.example.com
+NTP=
+DOMAINS=corp.example.com ~.
+ROUTE_DOMAINS=
+`))
+	NoError(t, err)
+	Equal(t, "192.168.1.1 2001:db8::53", c.Servers.String())
+	Equal(t, StringList{"corp.example.com"}, c.Search)
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	c, err := parseResolvConf(strings.NewReader(`# Generated by NetworkManager
+domain example.org
+search corp.example.com example.com
+nameserver 10.0.0.53
+nameserver fe80::1%eth0
+; nameserver 10.0.0.54
+nameserver invalid
+options edns0
+`))
+	NoError(t, err)
+	Equal(t, "10.0.0.53 fe80::1", c.Servers.String())
+	Equal(t, StringList{"corp.example.com", "example.com"}, c.Search)
+}
+
+func TestParseServer(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"10.0.0.53", "10.0.0.53"},
+		{"10.0.0.53:53", "10.0.0.53"},
+		{"10.0.0.53%3#dns.example.com", "10.0.0.53"},
+		{"2001:db8::53", "2001:db8::53"},
+		{"[2001:db8::53]:853#dns.example.com", "2001:db8::53"},
+		{"[fe80::1]:53%eth0", "fe80::1"},
+		{"::ffff:10.0.0.53", "10.0.0.53"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.s, func(t *testing.T) {
+			Equal(t, tt.want, parseServer(tt.s).String())
+		})
+	}
+	Nil(t, parseServer("dns.example.com"))
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// adapterAddresses returns the addresses of all network adapters.
+func adapterAddresses() ([]byte, error) {
+	size := uint32(15000)
+	for {
+		b := make([]byte, size)
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, windows.GAA_FLAG_INCLUDE_PREFIX, 0,
+			(*windows.IpAdapterAddresses)(unsafe.Pointer(&b[0])), &size)
+		if err == nil {
+			return b, nil
+		} else if !errors.Is(err, windows.ERROR_BUFFER_OVERFLOW) {
+			return nil, errors.New("cannot read network adapters: " + err.Error())
+		}
+	}
+}
+
+// adapter returns the adapter of the interface, which is identified by its friendly name on Windows.
+func adapter(b []byte, i *net.Interface) *windows.IpAdapterAddresses {
+	for a := (*windows.IpAdapterAddresses)(unsafe.Pointer(&b[0])); a != nil; a = a.Next {
+		if windows.UTF16PtrToString(a.FriendlyName) == i.Name {
+			return a
+		}
+	}
+	return nil
+}
+
+// dnsConfig returns the DNS servers and the DNS suffixes of the adapter.
+func dnsConfig(i *net.Interface) (c DNSConfig, err error) {
+	b, err := adapterAddresses()
+	if err != nil {
+		return c, err
+	}
+	a := adapter(b, i)
+	if a == nil {
+		return c, nil
+	}
+
+	for s := a.FirstDnsServerAddress; s != nil; s = s.Next {
+		if ip := s.Address.IP(); ip != nil {
+			c.Servers = append(c.Servers, ip)
+		}
+	}
+	if s := windows.UTF16PtrToString(a.DnsSuffix); s != "" {
+		c.Search = append(c.Search, s)
+	}
+	for s := a.FirstDnsSuffix; s != nil; s = s.Next {
+		if d := windows.UTF16ToString(s.String[:]); d != "" && !contains(c.Search, d) {
+			c.Search = append(c.Search, d)
+		}
+	}
+	return c, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
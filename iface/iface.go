@@ -16,7 +16,10 @@ package iface
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 	"net"
+	"strconv"
 	"strings"
 
 	"github.com/c-robinson/iplib"
@@ -25,101 +28,450 @@ import (
 const (
 	// Broadcast address
 	Broadcast = "broadcast"
+	// Class of the IPv4 address (A, B, C, D or E)
+	Class = "class"
+	// ClassfulNetwork is the network of the IPv4 address according to its class
+	ClassfulNetwork = "classful-network"
+	// ClassfulBoundary reports whether the prefix length is the one of the address class
+	ClassfulBoundary = "is-classful-boundary"
+	// DHCP lease of the interface
+	DHCP = "dhcp"
+	// DNS servers of the interface
+	DNS = "dns"
 	// First usable IP address of the subnet
 	First = "first"
+	// Flags of the interface (e.g., up, broadcast, multicast)
+	Flags = "flags"
+	// Gateway of the default route via the interface
+	Gateway = "gateway"
+	// HardwareAddr is the MAC address of the interface
+	HardwareAddr = "hwaddr"
 	// IP address
 	IP = "ip"
 	// Last usable IP address of the subnet
 	Last = "last"
+	// LocalInterface is the interface, whose subnet contains the IP address
+	LocalInterface = "local-interface"
+	// MTU of the interface
+	MTU = "mtu"
 	// Name of the interface
 	Name = "name"
 	// NetMask of the subnet
 	NetMask = "netmask"
 	// Network address
 	Network = "network"
+	// OnLink reports whether the IP address is within a subnet of a local interface
+	OnLink = "on-link"
 	// Prefix in bits
 	Prefix = "prefix"
+	// Search domains of the interface
+	Search = "search"
 	// Size of the subnet
 	Size = "size"
+	// Speed of the interface in Mbit/s
+	Speed = "speed"
 	// UsableSize of the subnet
 	UsableSize = "usable"
 	// Version of the IP address
 	Version = "version"
 	// Wildcard mask
 	Wildcard = "wildcard"
+	// Zone of the IPv6 address
+	Zone = "zone"
 )
 
-var errNoIP = errors.New("no IP address")
+// Property describes a parameter returned by GetParams.
+type Property struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+}
+
+// Properties lists the parameters returned by GetParams.
+var Properties = []Property{
+	{Broadcast, "net.IP", "broadcast address", "10.0.3.255"},
+	{Class, "string", "address class (A, B, C, D, E) of the IPv4 address", "A"},
+	{ClassfulNetwork, "string", "network of the address class (A, B, C)", "10.0.0.0/8"},
+	{DHCP, "DHCPLease", "DHCP lease of the network interface", "10.0.0.42 from 10.0.0.1"},
+	{DNS, "[]net.IP", "DNS servers of the network interface", "10.0.0.53 10.0.1.53"},
+	{First, "net.IP", "first usable IP address of the subnet", "10.0.0.1"},
+	{Flags, "StringList", "flags of the network interface", "up broadcast multicast"},
+	{Gateway, "net.IP", "default gateway of the network interface", "10.0.0.1"},
+	{HardwareAddr, "string", "hardware (MAC) address of the network interface", "00:00:5e:00:53:01"},
+	{IP, "net.IP", "IP address", "10.0.0.42"},
+	{ClassfulBoundary, "bool", "whether the prefix length is the one of the address class", "false"},
+	{Last, "net.IP", "last usable IP address of the subnet", "10.0.3.254"},
+	{LocalInterface, "string", "network interface, whose subnet contains the IP address", "eth0"},
+	{MTU, "int", "MTU of the network interface", "1500"},
+	{Name, "string", "name of the network interface", "eth0"},
+	{NetMask, "net.IP", "subnet mask", "255.255.252.0"},
+	{Network, "net.IP", "network address", "10.0.0.0"},
+	{OnLink, "bool", "whether the IP address is within a subnet of a local network interface", "true"},
+	{Prefix, "int", "prefix length", "22"},
+	{Search, "[]string", "DNS search domains of the network interface", "corp.example.com"},
+	{Size, "*big.Int", "size of the subnet", "1024"},
+	{Speed, "int", "link speed of the network interface in Mbit/s (0 if unknown)", "1000"},
+	{UsableSize, "*big.Int", "usable size of the subnet (host count)", "1022"},
+	{Version, "int", "IP version", "4"},
+	{Wildcard, "net.IP", "wildcard mask", "0.0.3.255"},
+	{Zone, "string", "zone (scope) of the link-local IPv6 address", "eth0"},
+}
+
+var (
+	// ErrNoSuchInterface is returned if there is no network interface with the given name.
+	ErrNoSuchInterface = errors.New("no such network interface")
+	// ErrNoAddress is returned if the network interface has no (IPv4) address.
+	ErrNoAddress = errors.New("no IP address")
+)
+
+// InterfaceByName returns the network interface specified by name or index (of the snapshot, if any).
+// On Windows, the adapter name (GUID, e.g., {4d36e972-e325-11ce-bfc1-08002be10318}) and the friendly name
+// (e.g., "Ethernet 2", ignoring case) are accepted as well.
+// The error contains the name and wraps ErrNoSuchInterface if there is no such interface.
+func InterfaceByName(name string) (*net.Interface, error) {
+	if snapshot != nil {
+		if si := snapshot.lookup(name); si != nil {
+			i, err := si.iface()
+			return &i, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNoSuchInterface, name)
+	}
+
+	i, err := net.InterfaceByName(name)
+	if err == nil {
+		return i, nil
+	} else if i, errAlt := interfaceByAltName(name); errAlt == nil {
+		return i, nil
+	} else if idx, errIdx := strconv.Atoi(name); errIdx == nil && idx > 0 {
+		if i, errIdx := net.InterfaceByIndex(idx); errIdx == nil {
+			return i, nil
+		}
+	}
+	if u := errors.Unwrap(err); u != nil {
+		err = u
+	}
+	if err.Error() == ErrNoSuchInterface.Error() {
+		// the net package does not export its error
+		err = ErrNoSuchInterface
+	}
+	return nil, fmt.Errorf("%w: %s", err, name)
+}
 
 // GetAddr returns the first IPv4 unicast address for the interface specified by name.
 func GetAddr(name string) (ip net.IP, n iplib.Net, err error) {
-	i, err := net.InterfaceByName(name)
+	ns, err := GetAddrs(name)
 	if err != nil {
-		return ip, n, errors.New(errors.Unwrap(err).Error() + ": " + name)
+		return ip, n, err
 	}
-	addrs, err := i.Addrs()
+	for _, ipNet := range ns {
+		if size, bits := ipNet.Mask.Size(); bits == 32 {
+			return ipNet.IP, iplib.NewNet(ipNet.IP, size), nil
+		}
+	}
+	return ip, n, fmt.Errorf("%w: %s", ErrNoAddress, name)
+}
+
+// GetAddrs returns all IPv4 and IPv6 addresses of the interface specified by name (in the order reported by the
+// operating system). The IP of every network is the address of the interface, not the network address.
+func GetAddrs(name string) ([]*net.IPNet, error) {
+	i, err := InterfaceByName(name)
 	if err != nil {
-		return ip, n, errors.Unwrap(err)
+		return nil, err
 	}
+	addrs, err := Addrs(i)
+	if err != nil {
+		return nil, errors.Unwrap(err)
+	}
+	var ns []*net.IPNet
 	for _, a := range addrs {
 		if n, ok := a.(*net.IPNet); ok {
-			if size, bits := n.Mask.Size(); bits == 32 {
-				return n.IP, iplib.NewNet(n.IP, size), nil
-			}
+			ns = append(ns, n)
 		}
 	}
-	return ip, n, errNoIP
+	if len(ns) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoAddress, name)
+	}
+	return ns, nil
 }
 
 // GetParams returns the parameters for the specified IP.
+// If name is an IPv6 address with zone (e.g., fe80::1%eth0), the zone is the name of the interface.
 func GetParams(name string, ip net.IP, mask net.IPMask) (m map[string]interface{}) {
+	return GetParamsOf(name, ip, mask)
+}
+
+// GetParamsOf returns the given parameters for the specified IP (all parameters if no key is given).
+// Expensive parameters are only determined on demand, e.g., the network interfaces are only scanned if the name of
+// the interface, its configuration (gateway, DNS, DHCP) or whether the IP address is on-link is requested.
+// Unknown keys are ignored.
+func GetParamsOf(name string, ip net.IP, mask net.IPMask, keys ...string) (m map[string]interface{}) {
+	want := func(ks ...string) bool {
+		if len(keys) == 0 {
+			return true
+		}
+		for _, k := range ks {
+			for _, key := range keys {
+				if k == key {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
 	size, _ := mask.Size()
 	n := iplib.NewNet(ip, size)
 
 	m = make(map[string]interface{})
-	m[Broadcast] = n.BroadcastAddress()
-	m[First] = n.FirstAddress()
-	m[Name] = name
-	if ip.String() == strings.SplitN(name, "/", 2)[0] {
-		m[Name] = findInterface(ip)
-	}
-	m[Network] = n.NetworkAddress()
-	m[IP] = ip
-	m[Last] = n.LastAddress()
-	m[NetMask] = net.IP(mask)
-	m[Prefix] = size
-	m[Size] = int(n.Count4() + 2)
-	m[UsableSize] = int(n.Count())
-	m[Version] = n.Version()
-	m[Wildcard] = net.IP(n.Wildcard())
-
-	// special handling for /32 and /31
-	if size == 32 {
-		m[Size] = 1
-	} else if size == 31 {
-		m[Size] = 2
+	if want(Broadcast) {
+		m[Broadcast] = n.BroadcastAddress()
+	}
+	if want(First) {
+		m[First] = n.FirstAddress()
+	}
+	if want(Class, ClassfulNetwork, ClassfulBoundary) {
+		class, classful := classOf(ip)
+		if want(Class) {
+			m[Class] = class
+		}
+		if want(ClassfulNetwork) {
+			m[ClassfulNetwork] = ""
+			if classful != nil {
+				m[ClassfulNetwork] = classful.String()
+			}
+		}
+		if want(ClassfulBoundary) {
+			m[ClassfulBoundary] = false
+			if classful != nil {
+				ones, _ := classful.Mask.Size()
+				m[ClassfulBoundary] = ones == size
+			}
+		}
+	}
+	if want(Name, Zone, Gateway, DHCP, DNS, Search, MTU, Flags, Speed, HardwareAddr) {
+		ifName, zone := interfaceOf(name, ip)
+		if want(Name) {
+			m[Name] = ifName
+		}
+		if want(Zone) {
+			m[Zone] = zone
+		}
+		if want(Gateway, DHCP, DNS, Search) {
+			addConfig(m, ifName, ip, want)
+		}
+		if want(MTU, Flags, Speed, HardwareAddr) {
+			addLink(m, ifName, want)
+		}
+	}
+	if want(OnLink, LocalInterface) {
+		local := localInterfaceOf(name, ip)
+		if want(OnLink) {
+			m[OnLink] = local != ""
+		}
+		if want(LocalInterface) {
+			m[LocalInterface] = local
+		}
+	}
+	if want(Network) {
+		m[Network] = n.NetworkAddress()
+	}
+	if want(IP) {
+		m[IP] = ip
+	}
+	if want(Last) {
+		m[Last] = n.LastAddress()
+	}
+	if want(NetMask) {
+		m[NetMask] = net.IP(mask)
+	}
+	if want(Prefix) {
+		m[Prefix] = size
+	}
+	if want(Size) {
+		m[Size] = addrCount(n)
+	}
+	if want(UsableSize) {
+		m[UsableSize] = usableCount(n)
+	}
+	if want(Version) {
+		m[Version] = n.Version()
+	}
+	if want(Wildcard) {
+		m[Wildcard] = net.IP(n.Wildcard())
 	}
-
 	return m
 }
 
-func findInterface(ip net.IP) string {
-	is, err := net.Interfaces()
-	if err != nil {
+// addrCount returns the total number of IP addresses of the network.
+// Unlike iplib.Net.Count4, it does not overflow for /0 and works for IPv6 networks.
+func addrCount(n iplib.Net) *big.Int {
+	ones, bits := n.Mask.Size()
+	return new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+}
+
+// usableCount returns the number of usable IP addresses of the network.
+// Point-to-point links (/31 and /127) have two usable IP addresses (RFC 3021, RFC 6164).
+func usableCount(n iplib.Net) *big.Int {
+	if ones, bits := n.Mask.Size(); ones == bits-1 {
+		return big.NewInt(2)
+	}
+	return n.Count6()
+}
+
+// classOf returns the address class of an IPv4 address and its classful network (RFC 791).
+// Class D (multicast) and E (reserved) addresses have no classful network. IPv6 addresses do not have a class.
+func classOf(ip net.IP) (string, *net.IPNet) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return "", nil
+	}
+
+	var class string
+	switch {
+	case ip4[0] < 128:
+		class = "A"
+	case ip4[0] < 192:
+		class = "B"
+	case ip4[0] < 224:
+		class = "C"
+	case ip4[0] < 240:
+		return "D", nil
+	default:
+		return "E", nil
+	}
+	mask := ip4.DefaultMask()
+	return class, &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+}
+
+// interfaceOf returns the name of the interface and the zone of the input.
+// If the input is an IP address without zone, the interfaces are scanned for the IP address.
+func interfaceOf(name string, ip net.IP) (ifName, zone string) {
+	addr := strings.SplitN(name, "/", 2)[0]
+	if a, zone, ok := strings.Cut(addr, "%"); ok && ip.Equal(net.ParseIP(a)) {
+		// the zone identifies the interface, even if the address is assigned to multiple interfaces
+		if i, err := InterfaceByName(zone); err == nil {
+			return i.Name, zone
+		}
+		return zone, zone
+	} else if ip.String() == addr {
+		name, _ := findInterface(ip)
+		return name, ""
+	}
+	return name, ""
+}
+
+// localInterfaceOf returns the name of the interface, whose subnet contains the IP address of the input.
+// The zone of a link-local IPv6 address identifies the interface.
+func localInterfaceOf(name string, ip net.IP) string {
+	addr := strings.SplitN(name, "/", 2)[0]
+	if a, zone, ok := strings.Cut(addr, "%"); ok && ip.Equal(net.ParseIP(a)) {
+		if i, err := InterfaceByName(zone); err == nil {
+			return i.Name
+		}
 		return ""
 	}
+	_, local := findInterface(ip)
+	return local
+}
+
+// addLink adds the requested link properties (MTU, flags, speed, hardware address) of the interface to m.
+// If there is no such interface, the properties are zero values.
+func addLink(m map[string]interface{}, name string, want func(...string) bool) {
+	var i *net.Interface
+	if name != "" {
+		i, _ = InterfaceByName(name)
+	}
+	if i == nil {
+		i = &net.Interface{}
+	}
+
+	if want(MTU) {
+		m[MTU] = i.MTU
+	}
+	if want(Flags) {
+		m[Flags] = StringList{}
+		if i.Flags != 0 {
+			m[Flags] = StringList(strings.Split(i.Flags.String(), "|"))
+		}
+	}
+	if want(Speed) {
+		m[Speed] = 0
+		if i.Name != "" {
+			m[Speed], _ = GetSpeed(i.Name)
+		}
+	}
+	if want(HardwareAddr) {
+		m[HardwareAddr] = i.HardwareAddr.String()
+	}
+}
+
+// addConfig adds the requested configuration (gateway, DNS, DHCP) of the interface to m.
+func addConfig(m map[string]interface{}, name string, ip net.IP, want func(...string) bool) {
+	if want(Gateway) {
+		m[Gateway] = ""
+	}
+	if want(DHCP) {
+		m[DHCP] = DHCPLease{}
+	}
+	if want(DNS) {
+		m[DNS] = IPList{}
+	}
+	if want(Search) {
+		m[Search] = StringList{}
+	}
+	if name == "" {
+		return
+	}
+
+	if want(Gateway) {
+		if gw, err := GetGateway(name, ip); err == nil {
+			m[Gateway] = gw
+		}
+	}
+	if want(DNS, Search) {
+		if c, err := GetDNS(name); err == nil {
+			if want(DNS) {
+				m[DNS] = c.Servers
+			}
+			if want(Search) {
+				m[Search] = c.Search
+			}
+		}
+	}
+	if want(DHCP) {
+		if l, err := GetDHCPLease(name); err == nil {
+			m[DHCP] = l
+		}
+	}
+}
+
+// findInterface returns the name of the interface, to which the IP address is assigned, and the name of the
+// interface, whose subnet contains the IP address. All (including secondary) addresses are taken into account and
+// the longest prefix wins if the subnets of several interfaces contain the IP address.
+func findInterface(ip net.IP) (assigned, local string) {
+	is, err := Interfaces()
+	if err != nil {
+		return "", ""
+	}
 
+	best := -1
 	for _, i := range is {
-		addrs, err := i.Addrs()
+		addrs, err := Addrs(&i)
 		if err != nil {
 			continue
 		}
 
 		for _, a := range addrs {
-			if ia, ok := a.(*net.IPNet); ok && ip.Equal(ia.IP) {
-				return i.Name
+			ia, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			} else if ip.Equal(ia.IP) {
+				return i.Name, i.Name
+			} else if ones, _ := ia.Mask.Size(); ones > best && ia.Contains(ip) {
+				best, local = ones, i.Name
 			}
 		}
 	}
-	return ""
+	return "", local
 }
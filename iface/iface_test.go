@@ -15,8 +15,12 @@
 package iface_test
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"net"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/abc-inc/terminus/iface"
@@ -36,6 +40,26 @@ func TestGetAddr(t *testing.T) {
 func TestGetAddrInvalidName(t *testing.T) {
 	_, _, err := iface.GetAddr("")
 	EqualError(t, err, "invalid network interface name: ")
+	False(t, errors.Is(err, iface.ErrNoSuchInterface))
+}
+
+func TestGetAddrNoSuchInterface(t *testing.T) {
+	_, _, err := iface.GetAddr("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+	ErrorIs(t, err, iface.ErrNoSuchInterface)
+}
+
+func TestInterfaceByNameIndex(t *testing.T) {
+	is, err := net.Interfaces()
+	NoError(t, err)
+	for _, want := range is {
+		i, err := iface.InterfaceByName(fmt.Sprint(want.Index))
+		NoError(t, err)
+		Equal(t, want.Name, i.Name)
+	}
+
+	_, err = iface.InterfaceByName("0")
+	ErrorIs(t, err, iface.ErrNoSuchInterface)
 }
 
 func TestGetParams(t *testing.T) {
@@ -47,12 +71,181 @@ func TestGetParams(t *testing.T) {
 	EqualValues(t, 24, m[iface.Prefix])
 	EqualValues(t, "0.0.0.255", fmt.Sprint(m[iface.Wildcard]))
 	EqualValues(t, "192.168.0.255", fmt.Sprint(m[iface.Broadcast]))
-	EqualValues(t, 256, m[iface.Size])
-	EqualValues(t, 254, m[iface.UsableSize])
+	Equal(t, "256", fmt.Sprint(m[iface.Size]))
+	Equal(t, "254", fmt.Sprint(m[iface.UsableSize]))
 	EqualValues(t, "192.168.0.1", fmt.Sprint(m[iface.First]))
 	EqualValues(t, "192.168.0.254", fmt.Sprint(m[iface.Last]))
 	EqualValues(t, "192.168.0.0", fmt.Sprint(m[iface.Network]))
 	EqualValues(t, "4", fmt.Sprint(m[iface.Version]))
+	Contains(t, m, iface.Gateway)
+	Contains(t, m, iface.DNS)
+	Contains(t, m, iface.Search)
+}
+
+func TestGetParamsZone(t *testing.T) {
+	m := iface.GetParams("fe80::1%tap9/64", net.ParseIP("fe80::1"), net.CIDRMask(64, 128))
+	Equal(t, "tap9", m[iface.Name])
+	Equal(t, "tap9", m[iface.Zone])
+
+	m = iface.GetParams("eth0", net.ParseIP("192.168.0.1"), net.CIDRMask(24, 32))
+	Equal(t, "", m[iface.Zone])
+}
+
+func TestGetParamsOf(t *testing.T) {
+	ip, mask := net.ParseIP("10.1.2.3"), net.CIDRMask(8, 32)
+	all := iface.GetParams("10.1.2.3/8", ip, mask)
+	Equal(t, all, iface.GetParamsOf("10.1.2.3/8", ip, mask))
+
+	m := iface.GetParamsOf("10.1.2.3/8", ip, mask, iface.IP, iface.Size, "count")
+	Equal(t, map[string]interface{}{iface.IP: ip, iface.Size: big.NewInt(1 << 24)}, m)
+
+	for _, p := range iface.Properties {
+		m := iface.GetParamsOf("10.1.2.3/8", ip, mask, p.Name)
+		Equal(t, map[string]interface{}{p.Name: all[p.Name]}, m, p.Name)
+	}
+}
+
+func TestGetParamsSize(t *testing.T) {
+	tests := []struct {
+		cidr   string
+		size   string
+		usable string
+	}{
+		{"0.0.0.0/0", "4294967296", "4294967294"},
+		{"10.0.0.0/8", "16777216", "16777214"},
+		{"10.0.0.0/31", "2", "2"},
+		{"10.0.0.1/32", "1", "1"},
+		{"::/0", "340282366920938463463374607431768211456", "340282366920938463463374607431768211456"},
+		{"2001:db8::/64", "18446744073709551616", "18446744073709551616"},
+		{"2001:db8::/127", "2", "2"},
+		{"2001:db8::1/128", "1", "1"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.cidr, func(t *testing.T) {
+			ip, n, _ := net.ParseCIDR(tt.cidr)
+			m := iface.GetParamsOf(tt.cidr, ip, n.Mask, iface.Size, iface.UsableSize)
+			Equal(t, tt.size, fmt.Sprint(m[iface.Size]))
+			Equal(t, tt.usable, fmt.Sprint(m[iface.UsableSize]))
+		})
+	}
+}
+
+func TestGetParamsPointToPoint(t *testing.T) {
+	tests := []struct {
+		cidr      string
+		broadcast string
+		first     string
+		last      string
+	}{
+		{"192.0.2.0/31", "192.0.2.1", "192.0.2.0", "192.0.2.1"},
+		{"192.0.2.1/31", "192.0.2.1", "192.0.2.0", "192.0.2.1"},
+		{"192.0.2.0/30", "192.0.2.3", "192.0.2.1", "192.0.2.2"},
+		{"2001:db8::/127", "2001:db8::1", "2001:db8::", "2001:db8::1"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.cidr, func(t *testing.T) {
+			ip, n, _ := net.ParseCIDR(tt.cidr)
+			m := iface.GetParams(tt.cidr, ip, n.Mask)
+			Equal(t, tt.broadcast, fmt.Sprint(m[iface.Broadcast]))
+			Equal(t, tt.first, fmt.Sprint(m[iface.First]))
+			Equal(t, tt.last, fmt.Sprint(m[iface.Last]))
+		})
+	}
+}
+
+func TestGetParamsClass(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		class    string
+		network  string
+		boundary bool
+	}{
+		{"10.1.2.3/8", "A", "10.0.0.0/8", true},
+		{"10.1.2.3/24", "A", "10.0.0.0/8", false},
+		{"172.16.5.4/16", "B", "172.16.0.0/16", true},
+		{"192.168.1.1/24", "C", "192.168.1.0/24", true},
+		{"192.168.1.1/23", "C", "192.168.1.0/24", false},
+		{"224.0.0.1/4", "D", "", false},
+		{"240.0.0.1/4", "E", "", false},
+		{"2001:db8::1/64", "", "", false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.cidr, func(t *testing.T) {
+			ip, n, _ := net.ParseCIDR(tt.cidr)
+			m := iface.GetParamsOf(tt.cidr, ip, n.Mask, iface.Class, iface.ClassfulNetwork, iface.ClassfulBoundary)
+			Equal(t, tt.class, m[iface.Class])
+			Equal(t, tt.network, m[iface.ClassfulNetwork])
+			Equal(t, tt.boundary, m[iface.ClassfulBoundary])
+		})
+	}
+}
+
+func TestGetDHCPLease(t *testing.T) {
+	_, err := iface.GetDHCPLease("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+}
+
+func TestGetStats(t *testing.T) {
+	_, err := iface.GetStats("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+
+	if runtime.GOOS == "linux" {
+		is, _ := net.Interfaces()
+		for _, i := range is {
+			_, err := iface.GetStats(i.Name)
+			NoError(t, err, i.Name)
+		}
+	}
+}
+
+func TestGetParamsLink(t *testing.T) {
+	m := iface.GetParamsOf("xyz0", net.ParseIP("10.0.0.1"), net.CIDRMask(8, 32),
+		iface.MTU, iface.Flags, iface.Speed, iface.HardwareAddr)
+	Equal(t, map[string]interface{}{iface.MTU: 0, iface.Flags: iface.StringList{}, iface.Speed: 0,
+		iface.HardwareAddr: ""}, m)
+
+	is, _ := net.Interfaces()
+	for _, i := range is {
+		m := iface.GetParamsOf(i.Name, net.ParseIP("10.0.0.1"), net.CIDRMask(8, 32), iface.MTU, iface.Flags)
+		Equal(t, i.MTU, m[iface.MTU], i.Name)
+		Equal(t, iface.StringList(strings.Split(i.Flags.String(), "|")), m[iface.Flags], i.Name)
+	}
+}
+
+func TestGetSpeed(t *testing.T) {
+	_, err := iface.GetSpeed("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+}
+
+func TestGetDNS(t *testing.T) {
+	_, err := iface.GetDNS("xyz0")
+	EqualError(t, err, "no such network interface: xyz0")
+
+	is, _ := net.Interfaces()
+	for _, i := range is {
+		c, err := iface.GetDNS(i.Name)
+		if err == nil {
+			NotNil(t, c.Servers, i.Name)
+			NotNil(t, c.Search, i.Name)
+		}
+	}
+}
+
+func TestLists(t *testing.T) {
+	Equal(t, "10.0.0.53 2001:db8::53", iface.IPList{net.ParseIP("10.0.0.53"), net.ParseIP("2001:db8::53")}.String())
+	Equal(t, "", iface.IPList{}.String())
+	Equal(t, "corp.example.com example.com", iface.StringList{"corp.example.com", "example.com"}.String())
+}
+
+func TestProperties(t *testing.T) {
+	m := iface.GetParams("eth0", net.ParseIP("192.168.0.1").To4(), net.CIDRMask(24, 32))
+	Len(t, iface.Properties, len(m))
+	for _, p := range iface.Properties {
+		Contains(t, m, p.Name)
+	}
 }
 
 func TestFindInterface(t *testing.T) {
@@ -70,6 +263,40 @@ func TestFindInterface(t *testing.T) {
 	Contains(t, ns, m[iface.Name])
 }
 
+func TestGetParamsOnLink(t *testing.T) {
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{
+		{Index: 1, Name: "eth0", Flags: []string{"up"}, Addrs: []string{"192.0.2.2/24", "198.51.100.2/24"}},
+		{Index: 2, Name: "tun0", Flags: []string{"up"}, Addrs: []string{"192.0.2.130/25", "fe80::1/64"}},
+	}}))
+	t.Cleanup(func() { _ = iface.UseSnapshot(nil) })
+
+	tests := []struct {
+		name   string
+		ip     string
+		onLink bool
+		local  string
+	}{
+		{"192.0.2.2", "192.0.2.2", true, "eth0"},
+		{"192.0.2.42", "192.0.2.42", true, "eth0"},
+		{"198.51.100.42", "198.51.100.42", true, "eth0"},
+		{"192.0.2.200/24", "192.0.2.200", true, "tun0"},
+		{"203.0.113.1", "203.0.113.1", false, ""},
+		{"fe80::42%tun0", "fe80::42", true, "tun0"},
+		{"fe80::42%tap9", "fe80::42", false, ""},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			m := iface.GetParamsOf(tt.name, net.ParseIP(tt.ip), net.CIDRMask(24, 32), iface.OnLink, iface.LocalInterface)
+			Equal(t, map[string]interface{}{iface.OnLink: tt.onLink, iface.LocalInterface: tt.local}, m)
+		})
+	}
+
+	m := iface.GetParamsOf("192.0.2.42", net.ParseIP("192.0.2.42"), net.CIDRMask(24, 32), iface.Name)
+	Empty(t, m[iface.Name])
+}
+
 func TestFindInterfaceNotExists(t *testing.T) {
 	ip, n, _ := net.ParseCIDR("127.255.255.255/8")
 	m := iface.GetParams(ip.String(), ip, n.Mask)
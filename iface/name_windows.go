@@ -0,0 +1,40 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// interfaceByAltName returns the interface, whose adapter name (GUID, with or without braces) or friendly name
+// (ignoring case) equals name.
+func interfaceByAltName(name string) (*net.Interface, error) {
+	b, err := adapterAddresses()
+	if err != nil {
+		return nil, err
+	}
+	guid := strings.Trim(name, "{}")
+	for a := (*windows.IpAdapterAddresses)(unsafe.Pointer(&b[0])); a != nil; a = a.Next {
+		if strings.EqualFold(strings.Trim(windows.BytePtrToString(a.AdapterName), "{}"), guid) ||
+			strings.EqualFold(windows.UTF16PtrToString(a.FriendlyName), name) {
+			return net.InterfaceByIndex(int(a.IfIndex))
+		}
+	}
+	return nil, ErrNoSuchInterface
+}
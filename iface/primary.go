@@ -0,0 +1,66 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"fmt"
+	"net"
+)
+
+// Primary returns the name of the primary network interface i.e., the interface of the IPv4 default route with the
+// lowest metric (or of the IPv6 default route if there is no IPv4 default route).
+// If there is no default route, the first interface, which is up and has a global unicast IPv4 address, is returned.
+func Primary() (string, error) {
+	rs, _ := Routes()
+	if name := primaryRoute(rs); name != "" {
+		return name, nil
+	}
+
+	is, err := Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, i := range is {
+		if i.Flags&net.FlagUp == 0 || i.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, _ := Addrs(&i)
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok && n.IP.To4() != nil && n.IP.IsGlobalUnicast() {
+				return i.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: no default route and no interface with a global unicast address", ErrNoSuchInterface)
+}
+
+// primaryRoute returns the interface of the IPv4 (or IPv6) default route with the lowest metric.
+func primaryRoute(rs []Route) string {
+	var best *Route
+	for i, r := range rs {
+		if !r.isDefault() || r.Interface == "" {
+			continue
+		}
+		v4 := r.Dst.IP.To4() != nil
+		if best == nil || v4 && best.Dst.IP.To4() == nil ||
+			v4 == (best.Dst.IP.To4() != nil) && r.Metric < best.Metric {
+			best = &rs[i]
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Interface
+}
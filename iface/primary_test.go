@@ -0,0 +1,48 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPrimary(t *testing.T) {
+	name, err := Primary()
+	if errors.Is(err, ErrNoSuchInterface) {
+		t.Skip(err)
+	}
+	NoError(t, err)
+	_, err = net.InterfaceByName(name)
+	NoError(t, err)
+}
+
+func TestPrimaryRoute(t *testing.T) {
+	route := func(cidr, name string, metric int) Route {
+		_, dst, _ := net.ParseCIDR(cidr)
+		return Route{Dst: dst, Interface: name, Metric: metric}
+	}
+
+	Equal(t, "", primaryRoute(nil))
+	Equal(t, "", primaryRoute([]Route{route("10.0.0.0/8", "eth0", 0)}))
+	Equal(t, "wlan0", primaryRoute([]Route{route("::/0", "eth0", 0), route("0.0.0.0/0", "wlan0", 600)}))
+	Equal(t, "eth0", primaryRoute([]Route{
+		route("0.0.0.0/0", "wlan0", 600), route("0.0.0.0/0", "eth0", 100), route("0.0.0.0/0", "", 0),
+	}))
+	Equal(t, "tun0", primaryRoute([]Route{route("::/0", "eth0", 1024), route("::/0", "tun0", 50)}))
+}
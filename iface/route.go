@@ -0,0 +1,124 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// Route is an entry of the routing table.
+type Route struct {
+	// Dst is the destination network.
+	Dst *net.IPNet
+	// Gateway is the next hop (nil if the destination is directly connected).
+	Gateway net.IP
+	// Interface is the name of the outgoing network interface.
+	Interface string
+	// Metric is the cost of the route.
+	Metric int
+}
+
+// routesTTL is the time, for which the routing table is cached, so that the routing table is not read again for
+// every input if many inputs are processed.
+const routesTTL = time.Second
+
+// routeCache holds the routing table read last.
+var routeCache struct {
+	sync.Mutex
+	rs   []Route
+	err  error
+	read time.Time
+}
+
+// Routes returns the IPv4 and IPv6 routes (IPv4 only on Windows) or the routes of the snapshot.
+// The IPv4 routes are the ones of the main routing table, whereas the IPv6 routes of all routing tables are returned
+// on Linux (/proc/net/ipv6_route does not tell the table). The routing table is read at most once per second.
+func Routes() ([]Route, error) {
+	if snapshot != nil {
+		return snapshot.routes()
+	}
+
+	routeCache.Lock()
+	defer routeCache.Unlock()
+	if routeCache.read.IsZero() || time.Since(routeCache.read) > routesTTL {
+		routeCache.rs, routeCache.err = routes()
+		routeCache.read = time.Now()
+	}
+	return append([]Route(nil), routeCache.rs...), routeCache.err
+}
+
+// GetGateway returns the default gateway of the interface specified by name for the address family of ip.
+// If ip is nil, IPv4 gateways take precedence over IPv6 gateways.
+func GetGateway(name string, ip net.IP) (net.IP, error) {
+	if _, err := InterfaceByName(name); err != nil {
+		return nil, err
+	}
+
+	rs, err := Routes()
+	if err != nil {
+		return nil, err
+	}
+	if ip != nil {
+		if gw := defaultGateway(rs, name, ip.To4() != nil); gw != nil {
+			return gw, nil
+		}
+	} else if gw := defaultGateway(rs, name, true); gw != nil {
+		return gw, nil
+	} else if gw = defaultGateway(rs, name, false); gw != nil {
+		return gw, nil
+	}
+	return nil, errors.New("no default gateway: " + name)
+}
+
+// LookupRoute returns the most specific route, which covers ip.
+// If multiple routes are equally specific, the one with the lowest metric is returned.
+func LookupRoute(rs []Route, ip net.IP) (r Route, err error) {
+	best := -1
+	for _, rt := range rs {
+		if !rt.Dst.Contains(ip) || (rt.Dst.IP.To4() == nil) != (ip.To4() == nil) {
+			continue
+		}
+		if size, _ := rt.Dst.Mask.Size(); size > best || size == best && rt.Metric < r.Metric {
+			r, best = rt, size
+		}
+	}
+	if best < 0 {
+		return r, errors.New("no route to host: " + ip.String())
+	}
+	return r, nil
+}
+
+// defaultGateway returns the gateway of the IPv4 (or IPv6) default route via the interface (or nil if there is none).
+func defaultGateway(rs []Route, name string, v4 bool) net.IP {
+	var gw net.IP
+	metric := 0
+	for _, r := range rs {
+		if r.Interface == name && r.isDefault() && r.Gateway != nil && (r.Dst.IP.To4() != nil) == v4 {
+			if gw == nil || r.Metric < metric {
+				gw, metric = r.Gateway, r.Metric
+			}
+		}
+	}
+	return gw
+}
+
+// isDefault reports whether the route covers all addresses of its family.
+func (r Route) isDefault() bool {
+	size, _ := r.Dst.Mask.Size()
+	return size == 0
+}
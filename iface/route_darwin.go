@@ -0,0 +1,76 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/net/route"
+)
+
+func routes() ([]Route, error) {
+	b, err := route.FetchRIB(syscall.AF_UNSPEC, route.RIBTypeRoute, 0)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := route.ParseRIB(route.RIBTypeRoute, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []Route
+	for _, msg := range msgs {
+		m, ok := msg.(*route.RouteMessage)
+		if !ok || m.Flags&syscall.RTF_UP == 0 || m.Flags&(syscall.RTF_REJECT|syscall.RTF_BLACKHOLE) != 0 ||
+			len(m.Addrs) <= syscall.RTAX_NETMASK {
+			continue
+		}
+
+		dst := addrIP(m.Addrs[syscall.RTAX_DST])
+		if dst == nil {
+			continue
+		}
+		bits := 8 * len(dst)
+		mask := net.CIDRMask(bits, bits)
+		if ip := addrIP(m.Addrs[syscall.RTAX_NETMASK]); ip != nil && m.Flags&syscall.RTF_HOST == 0 {
+			mask = net.IPMask(ip[len(ip)-len(dst):])
+		} else if ip == nil && m.Flags&syscall.RTF_HOST == 0 && dst.IsUnspecified() {
+			mask = net.CIDRMask(0, bits)
+		}
+
+		rt := Route{Dst: &net.IPNet{IP: dst.Mask(mask), Mask: mask}}
+		if m.Flags&syscall.RTF_GATEWAY != 0 {
+			rt.Gateway = addrIP(m.Addrs[syscall.RTAX_GATEWAY])
+		}
+		if i, err := net.InterfaceByIndex(m.Index); err == nil {
+			rt.Interface = i.Name
+		}
+		rs = append(rs, rt)
+	}
+	return rs, nil
+}
+
+// addrIP converts an IPv4 or IPv6 address of a routing message (or nil if a is a link-layer address).
+func addrIP(a route.Addr) net.IP {
+	switch a := a.(type) {
+	case *route.Inet4Addr:
+		return net.IP(a.IP[:]).To4()
+	case *route.Inet6Addr:
+		return append(net.IP(nil), a.IP[:]...)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	rtfUp      = 0x0001
+	rtfGateway = 0x0002
+	rtfReject  = 0x0200
+)
+
+func routes() ([]Route, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	rs, err := parseProcRoute(f)
+	if err != nil {
+		return nil, err
+	}
+
+	f6, err := os.Open("/proc/net/ipv6_route")
+	if os.IsNotExist(err) {
+		// IPv6 is disabled
+		return rs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f6.Close() }()
+
+	rs6, err := parseProcIPv6Route(f6)
+	return append(rs, rs6...), err
+}
+
+// parseProcRoute parses the IPv4 routing table in the format of /proc/net/route.
+// The addresses are hexadecimal numbers in host byte order i.e., little-endian on all supported architectures.
+func parseProcRoute(r io.Reader) (rs []Route, err error) {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fs := strings.Fields(sc.Text())
+		if line == 1 {
+			continue
+		} else if len(fs) < 8 {
+			return nil, fmt.Errorf("invalid route in line %d", line)
+		}
+
+		dst, err1 := parseHexIPv4(fs[1])
+		gw, err2 := parseHexIPv4(fs[2])
+		flags, err3 := strconv.ParseUint(fs[3], 16, 16)
+		metric, err4 := strconv.Atoi(fs[6])
+		mask, err5 := parseHexIPv4(fs[7])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil {
+			return nil, fmt.Errorf("invalid route in line %d", line)
+		}
+		if flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+
+		rt := Route{Dst: &net.IPNet{IP: dst, Mask: net.IPMask(mask)}, Interface: fs[0], Metric: metric}
+		if flags&rtfGateway != 0 {
+			rt.Gateway = gw
+		}
+		rs = append(rs, rt)
+	}
+	return rs, sc.Err()
+}
+
+// parseProcIPv6Route parses the IPv6 routing table in the format of /proc/net/ipv6_route.
+func parseProcIPv6Route(r io.Reader) (rs []Route, err error) {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fs := strings.Fields(sc.Text())
+		if len(fs) < 10 {
+			return nil, fmt.Errorf("invalid route in line %d", line)
+		}
+
+		dst, err1 := hex.DecodeString(fs[0])
+		size, err2 := strconv.ParseUint(fs[1], 16, 8)
+		gw, err3 := hex.DecodeString(fs[4])
+		metric, err4 := strconv.ParseUint(fs[5], 16, 32)
+		flags, err5 := strconv.ParseUint(fs[8], 16, 32)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil ||
+			len(dst) != net.IPv6len || len(gw) != net.IPv6len || size > 128 {
+			return nil, fmt.Errorf("invalid route in line %d", line)
+		}
+		if flags&rtfUp == 0 || flags&rtfReject != 0 {
+			continue
+		}
+
+		rt := Route{Dst: &net.IPNet{IP: dst, Mask: net.CIDRMask(int(size), 128)}, Interface: fs[9], Metric: int(metric)}
+		if flags&rtfGateway != 0 {
+			rt.Gateway = gw
+		}
+		rs = append(rs, rt)
+	}
+	return rs, sc.Err()
+}
+
+// parseHexIPv4 converts a little-endian hexadecimal number to an IPv4 address.
+func parseHexIPv4(s string) (net.IP, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != net.IPv4len {
+		return nil, fmt.Errorf("invalid IPv4 address: %s", s)
+	}
+	return net.IPv4(b[3], b[2], b[1], b[0]).To4(), nil
+}
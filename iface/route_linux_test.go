@@ -0,0 +1,64 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/stretchr/testify/require"
+)
+
+func TestParseProcRoute(t *testing.T) {
+	rs, err := parseProcRoute(strings.NewReader(
+		"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+			"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+			"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+			"eth1\t0000000A\t00000000\t0000\t0\t0\t0\t000000FF\t0\t0\t0\n"))
+	NoError(t, err)
+	Len(t, rs, 2)
+	Equal(t, "0.0.0.0/0", rs[0].Dst.String())
+	Equal(t, "192.168.1.1", rs[0].Gateway.String())
+	Equal(t, "eth0", rs[0].Interface)
+	Equal(t, 100, rs[0].Metric)
+	Equal(t, "192.168.1.0/24", rs[1].Dst.String())
+	Nil(t, rs[1].Gateway)
+	Equal(t, "192.168.1.1", defaultGateway(rs, "eth0", true).String())
+	Nil(t, defaultGateway(rs, "eth0", false))
+
+	_, err = parseProcRoute(strings.NewReader("Iface\nwlan0\tXYZ\n"))
+	EqualError(t, err, "invalid route in line 2")
+}
+
+func TestParseProcIPv6Route(t *testing.T) {
+	rs, err := parseProcIPv6Route(strings.NewReader(
+		"fe800000000000000000000000000000 40 00000000000000000000000000000000 00 " +
+			"00000000000000000000000000000000 00000100 00000002 00000000 00000001     eth0\n" +
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 " +
+			"fe800000000000000000000000000001 00000400 00000001 00000000 00000003     eth0\n" +
+			"00000000000000000000000000000000 00 00000000000000000000000000000000 00 " +
+			"00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo\n"))
+	NoError(t, err)
+	Len(t, rs, 2)
+	Equal(t, "fe80::/64", rs[0].Dst.String())
+	Nil(t, rs[0].Gateway)
+	Equal(t, 256, rs[0].Metric)
+	Equal(t, "::/0", rs[1].Dst.String())
+	Equal(t, "fe80::1", rs[1].Gateway.String())
+	Equal(t, "fe80::1", defaultGateway(rs, "eth0", false).String())
+
+	_, err = parseProcIPv6Route(strings.NewReader("fe80 40\n"))
+	EqualError(t, err, "invalid route in line 1")
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestLookupRoute(t *testing.T) {
+	route := func(cidr, gw, name string, metric int) iface.Route {
+		_, dst, _ := net.ParseCIDR(cidr)
+		return iface.Route{Dst: dst, Gateway: net.ParseIP(gw), Interface: name, Metric: metric}
+	}
+	rs := []iface.Route{
+		route("0.0.0.0/0", "192.168.1.1", "eth0", 600),
+		route("0.0.0.0/0", "10.0.0.1", "wlan0", 100),
+		route("192.168.1.0/24", "", "eth0", 0),
+		route("192.168.1.128/25", "192.168.1.2", "eth0", 0),
+		route("fe80::/64", "", "eth0", 256),
+	}
+
+	tests := []struct {
+		ip   string
+		want iface.Route
+	}{
+		{"1.1.1.1", rs[1]},
+		{"192.168.1.23", rs[2]},
+		{"192.168.1.200", rs[3]},
+		{"fe80::1", rs[4]},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			r, err := iface.LookupRoute(rs, net.ParseIP(tt.ip))
+			NoError(t, err)
+			Equal(t, tt.want, r)
+		})
+	}
+
+	_, err := iface.LookupRoute(rs, net.ParseIP("2001:db8::1"))
+	EqualError(t, err, "no route to host: 2001:db8::1")
+}
+
+func TestGetGateway(t *testing.T) {
+	_, err := iface.GetGateway("", nil)
+	EqualError(t, err, "invalid network interface name: ")
+
+	is, _ := net.Interfaces()
+	for _, i := range is {
+		if i.Flags&net.FlagLoopback != 0 {
+			_, err = iface.GetGateway(i.Name, nil)
+			EqualError(t, err, "no default gateway: "+i.Name)
+		}
+	}
+}
+
+func TestRoutesCached(t *testing.T) {
+	rs, err := iface.Routes()
+	if err != nil {
+		t.Skip(err)
+	}
+	rs2, err := iface.Routes()
+	NoError(t, err)
+	Equal(t, rs, rs2)
+
+	if len(rs) > 0 {
+		// the cached routing table is not affected by callers
+		rs[0] = iface.Route{}
+		rs2, _ = iface.Routes()
+		NotEqual(t, rs[0], rs2[0])
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	rs, err := iface.Routes()
+	if err != nil {
+		t.Skip(err)
+	}
+	_, err = iface.LookupRoute(rs, net.ParseIP("127.0.0.1"))
+	NoError(t, err)
+}
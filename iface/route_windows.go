@@ -0,0 +1,82 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetIPForwardTable = windows.NewLazySystemDLL("iphlpapi.dll").NewProc("GetIpForwardTable")
+
+// mibIPForwardRow is the MIB_IPFORWARDROW structure, which describes an IPv4 route.
+type mibIPForwardRow struct {
+	Dest      [4]byte
+	Mask      [4]byte
+	Policy    uint32
+	NextHop   [4]byte
+	IfIndex   uint32
+	Type      uint32
+	Proto     uint32
+	Age       uint32
+	NextHopAS uint32
+	Metric1   uint32
+	Metric2   uint32
+	Metric3   uint32
+	Metric4   uint32
+	Metric5   uint32
+}
+
+// mibIPRouteTypeIndirect denotes a route, where the next hop is not the final destination.
+const mibIPRouteTypeIndirect = 4
+
+// routes returns the IPv4 routing table (IPv6 routes are not supported on Windows).
+func routes() ([]Route, error) {
+	size := uint32(0)
+	b := make([]byte, 4)
+	for {
+		r, _, _ := procGetIPForwardTable.Call(uintptr(unsafe.Pointer(&b[0])), uintptr(unsafe.Pointer(&size)), 1)
+		if r == 0 {
+			break
+		} else if windows.Errno(r) != windows.ERROR_INSUFFICIENT_BUFFER {
+			return nil, errors.New("cannot read routing table: " + windows.Errno(r).Error())
+		}
+		b = make([]byte, size)
+	}
+
+	n := *(*uint32)(unsafe.Pointer(&b[0]))
+	if n == 0 {
+		return nil, nil
+	}
+	rows := unsafe.Slice((*mibIPForwardRow)(unsafe.Pointer(&b[4])), n)
+
+	rs := make([]Route, 0, n)
+	for _, row := range rows {
+		dst := net.IPv4(row.Dest[0], row.Dest[1], row.Dest[2], row.Dest[3]).To4()
+		mask := net.IPv4Mask(row.Mask[0], row.Mask[1], row.Mask[2], row.Mask[3])
+		rt := Route{Dst: &net.IPNet{IP: dst, Mask: mask}, Metric: int(row.Metric1)}
+		if row.Type == mibIPRouteTypeIndirect {
+			rt.Gateway = net.IPv4(row.NextHop[0], row.NextHop[1], row.NextHop[2], row.NextHop[3]).To4()
+		}
+		if i, err := net.InterfaceByIndex(int(row.IfIndex)); err == nil {
+			rt.Interface = i.Name
+		}
+		rs = append(rs, rt)
+	}
+	return rs, nil
+}
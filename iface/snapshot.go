@@ -0,0 +1,240 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Snapshot is the captured state of the network interfaces and routes of a host.
+// Once it is activated by UseSnapshot, all functions of this package report the snapshot instead of the live state.
+type Snapshot struct {
+	// Host is the name of the host, where the snapshot was captured.
+	Host string `json:"host,omitempty"`
+	// OS is the operating system of the host (GOOS).
+	OS string `json:"os,omitempty"`
+	// Captured is the time, when the snapshot was captured.
+	Captured time.Time `json:"captured"`
+	// Interfaces are the network interfaces by index.
+	Interfaces []InterfaceSnapshot `json:"interfaces"`
+	// Routes are the IPv4 and IPv6 routes as returned by Routes.
+	Routes []RouteSnapshot `json:"routes"`
+}
+
+// InterfaceSnapshot is the captured state of a network interface.
+type InterfaceSnapshot struct {
+	Index        int    `json:"index"`
+	Name         string `json:"name"`
+	MTU          int    `json:"mtu"`
+	HardwareAddr string `json:"hardwareAddr,omitempty"`
+	// Speed is the link speed in Mbit/s (0 if unknown).
+	Speed int `json:"speed,omitempty"`
+	// Flags are the names of the interface flags (e.g., "up" or "loopback").
+	Flags []string `json:"flags,omitempty"`
+	// Addrs are the IP addresses in CIDR notation.
+	Addrs []string `json:"addrs,omitempty"`
+	// DNS, DHCP and Stats are nil if they were not available.
+	DNS   *DNSConfig `json:"dns,omitempty"`
+	DHCP  *DHCPLease `json:"dhcp,omitempty"`
+	Stats *Stats     `json:"stats,omitempty"`
+}
+
+// RouteSnapshot is a captured route, which is given in CIDR notation.
+type RouteSnapshot struct {
+	Dst       string `json:"dst"`
+	Gateway   string `json:"gateway,omitempty"`
+	Interface string `json:"interface,omitempty"`
+	Metric    int    `json:"metric"`
+}
+
+// snapshot replaces the live state of the operating system unless it is nil.
+var snapshot *Snapshot
+
+// UseSnapshot replaces the live state of the operating system by the snapshot (or restores it if s is nil).
+// Invalid addresses, routes and flags are reported as errors, in which case the live state is kept.
+func UseSnapshot(s *Snapshot) error {
+	if s != nil {
+		for _, i := range s.Interfaces {
+			if _, err := i.addrs(); err != nil {
+				return err
+			} else if _, err := i.iface(); err != nil {
+				return err
+			}
+		}
+		if _, err := s.routes(); err != nil {
+			return err
+		}
+	}
+	snapshot = s
+	return nil
+}
+
+// Capture captures the live state of the network interfaces and routes.
+// Properties, which are not available on this platform, are omitted.
+func Capture() (*Snapshot, error) {
+	is, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{OS: runtime.GOOS, Captured: time.Now().UTC(), Interfaces: []InterfaceSnapshot{}}
+	s.Host, _ = os.Hostname()
+	for n := range is {
+		i := &is[n]
+		si := InterfaceSnapshot{Index: i.Index, Name: i.Name, MTU: i.MTU, HardwareAddr: i.HardwareAddr.String()}
+		if i.Flags != 0 {
+			si.Flags = strings.Split(i.Flags.String(), "|")
+		}
+		addrs, _ := i.Addrs()
+		for _, a := range addrs {
+			si.Addrs = append(si.Addrs, a.String())
+		}
+		if c, err := dnsConfig(i); err == nil && (len(c.Servers) > 0 || len(c.Search) > 0) {
+			si.DNS = &c
+		}
+		if l, err := dhcpLease(i); err == nil && l.Address != nil {
+			si.DHCP = &l
+		}
+		if st, err := stats(i); err == nil {
+			si.Stats = &st
+		}
+		si.Speed, _ = speed(i)
+		s.Interfaces = append(s.Interfaces, si)
+	}
+
+	rs, _ := routes()
+	s.Routes = make([]RouteSnapshot, len(rs))
+	for n, r := range rs {
+		s.Routes[n] = RouteSnapshot{Dst: r.Dst.String(), Interface: r.Interface, Metric: r.Metric}
+		if r.Gateway != nil {
+			s.Routes[n].Gateway = r.Gateway.String()
+		}
+	}
+	return s, nil
+}
+
+// Interfaces returns the network interfaces (of the snapshot, if any).
+func Interfaces() ([]net.Interface, error) {
+	if snapshot == nil {
+		return net.Interfaces()
+	}
+	is := make([]net.Interface, len(snapshot.Interfaces))
+	for n, si := range snapshot.Interfaces {
+		is[n], _ = si.iface()
+	}
+	return is, nil
+}
+
+// Addrs returns the IP addresses of the network interface (of the snapshot, if any).
+func Addrs(i *net.Interface) ([]net.Addr, error) {
+	if snapshot == nil {
+		return i.Addrs()
+	}
+	if si := snapshot.lookup(i.Name); si != nil {
+		return si.addrs()
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoSuchInterface, i.Name)
+}
+
+// snapshotOf returns the interface of the snapshot with the given name or nil if there is no snapshot.
+func snapshotOf(name string) *InterfaceSnapshot {
+	if snapshot == nil {
+		return nil
+	}
+	return snapshot.lookup(name)
+}
+
+// lookup returns the interface with the given name (or index) or nil if there is no such interface.
+func (s *Snapshot) lookup(name string) *InterfaceSnapshot {
+	for n := range s.Interfaces {
+		if s.Interfaces[n].Name == name {
+			return &s.Interfaces[n]
+		}
+	}
+	if idx, err := strconv.Atoi(name); err == nil && idx > 0 {
+		for n := range s.Interfaces {
+			if s.Interfaces[n].Index == idx {
+				return &s.Interfaces[n]
+			}
+		}
+	}
+	return nil
+}
+
+// routes parses the captured routes.
+func (s *Snapshot) routes() ([]Route, error) {
+	rs := make([]Route, len(s.Routes))
+	for n, r := range s.Routes {
+		_, dst, err := net.ParseCIDR(r.Dst)
+		if err != nil {
+			return nil, err
+		}
+		rs[n] = Route{Dst: dst, Interface: r.Interface, Metric: r.Metric}
+		if r.Gateway != "" {
+			if rs[n].Gateway = net.ParseIP(r.Gateway); rs[n].Gateway == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: r.Gateway}
+			}
+		}
+	}
+	return rs, nil
+}
+
+// iface converts the snapshot into a net.Interface.
+func (si InterfaceSnapshot) iface() (net.Interface, error) {
+	i := net.Interface{Index: si.Index, MTU: si.MTU, Name: si.Name}
+	if si.HardwareAddr != "" {
+		hw, err := net.ParseMAC(si.HardwareAddr)
+		if err != nil {
+			return i, err
+		}
+		i.HardwareAddr = hw
+	}
+
+next:
+	for _, name := range si.Flags {
+		for f := net.Flags(1); f != 0; f <<= 1 {
+			if f.String() == name {
+				i.Flags |= f
+				continue next
+			}
+		}
+		return i, errors.New("unknown interface flag: " + name)
+	}
+	return i, nil
+}
+
+// addrs parses the captured IP addresses.
+func (si InterfaceSnapshot) addrs() ([]net.Addr, error) {
+	as := make([]net.Addr, len(si.Addrs))
+	for n, s := range si.Addrs {
+		ip, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		ipNet.IP = ip
+		as[n] = ipNet
+	}
+	return as, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/iface"
+	. "github.com/stretchr/testify/require"
+)
+
+const fixture = `{
+  "host": "web1",
+  "os": "linux",
+  "captured": "2024-05-04T10:20:30Z",
+  "interfaces": [
+    {"index": 1, "name": "lo", "mtu": 65536, "flags": ["up", "loopback"], "addrs": ["127.0.0.1/8", "::1/128"]},
+    {"index": 2, "name": "eth0", "mtu": 1500, "hardwareAddr": "00:00:5e:00:53:01", "flags": ["up", "broadcast"],
+     "addrs": ["2001:db8::2/64", "192.0.2.2/24"],
+     "dns": {"servers": ["192.0.2.53"], "search": ["example.com"]},
+     "dhcp": {"address": "192.0.2.2", "server": "192.0.2.1"},
+     "stats": {"rxBytes": 42}}
+  ],
+  "routes": [
+    {"dst": "0.0.0.0/0", "gateway": "192.0.2.1", "interface": "eth0", "metric": 100},
+    {"dst": "192.0.2.0/24", "interface": "eth0", "metric": 100}
+  ]
+}`
+
+func useFixture(t *testing.T) {
+	s := &iface.Snapshot{}
+	NoError(t, json.Unmarshal([]byte(fixture), s))
+	NoError(t, iface.UseSnapshot(s))
+	t.Cleanup(func() { _ = iface.UseSnapshot(nil) })
+}
+
+func TestUseSnapshot(t *testing.T) {
+	useFixture(t)
+
+	is, err := iface.Interfaces()
+	NoError(t, err)
+	Len(t, is, 2)
+	Equal(t, net.Interface{Index: 2, MTU: 1500, Name: "eth0", HardwareAddr: net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1},
+		Flags: net.FlagUp | net.FlagBroadcast}, is[1])
+
+	ip, n, err := iface.GetAddr("eth0")
+	NoError(t, err)
+	Equal(t, "192.0.2.2", ip.String())
+	Equal(t, "192.0.2.0/24", n.String())
+
+	name, err := iface.Primary()
+	NoError(t, err)
+	Equal(t, "eth0", name)
+	gw, err := iface.GetGateway("eth0", nil)
+	NoError(t, err)
+	Equal(t, "192.0.2.1", gw.String())
+	gw, err = iface.GetGateway("eth0", net.ParseIP("192.0.2.2"))
+	NoError(t, err)
+	Equal(t, "192.0.2.1", gw.String())
+	_, err = iface.GetGateway("eth0", net.ParseIP("2001:db8::2"))
+	EqualError(t, err, "no default gateway: eth0")
+	m := iface.GetParamsOf("eth0", net.ParseIP("192.0.2.2"), net.CIDRMask(24, 32), iface.Gateway)
+	Equal(t, "192.0.2.1", fmt.Sprint(m[iface.Gateway]))
+
+	c, err := iface.GetDNS("eth0")
+	NoError(t, err)
+	Equal(t, "192.0.2.53", c.Servers.String())
+	Equal(t, "example.com", c.Search.String())
+	l, err := iface.GetDHCPLease("eth0")
+	NoError(t, err)
+	Equal(t, "192.0.2.2 from 192.0.2.1", l.String())
+	_, err = iface.GetDHCPLease("lo")
+	EqualError(t, err, "no DHCP lease: lo")
+
+	s, err := iface.GetStats("eth0")
+	NoError(t, err)
+	Equal(t, uint64(42), s.RxBytes)
+	_, err = iface.GetStats("lo")
+	EqualError(t, err, "no traffic counters in the snapshot: lo")
+
+	_, _, err = iface.GetAddr("wlan0")
+	ErrorIs(t, err, iface.ErrNoSuchInterface)
+	i, err := iface.InterfaceByName("2")
+	NoError(t, err)
+	Equal(t, "eth0", i.Name)
+	Equal(t, "eth0", iface.GetParams("192.0.2.2", ip, n.Mask)[iface.Name])
+}
+
+func TestGetAddrs(t *testing.T) {
+	useFixture(t)
+
+	ns, err := iface.GetAddrs("eth0")
+	NoError(t, err)
+	Len(t, ns, 2)
+	Equal(t, "2001:db8::2/64", ns[0].String())
+	Equal(t, "192.0.2.2/24", ns[1].String())
+
+	NoError(t, iface.UseSnapshot(&iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{{Name: "tun0"}}}))
+	_, err = iface.GetAddrs("tun0")
+	EqualError(t, err, "no IP address: tun0")
+	ErrorIs(t, err, iface.ErrNoAddress)
+}
+
+func TestUseSnapshotInvalid(t *testing.T) {
+	tests := []struct {
+		s    iface.Snapshot
+		want string
+	}{
+		{iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{{Name: "eth0", Addrs: []string{"192.0.2.2"}}}},
+			"invalid CIDR address: 192.0.2.2"},
+		{iface.Snapshot{Interfaces: []iface.InterfaceSnapshot{{Name: "eth0", Flags: []string{"up", "fast"}}}},
+			"unknown interface flag: fast"},
+		{iface.Snapshot{Routes: []iface.RouteSnapshot{{Dst: "0.0.0.0/0", Gateway: "gw"}}},
+			"invalid IP address: gw"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want, func(t *testing.T) {
+			EqualError(t, iface.UseSnapshot(&tt.s), tt.want)
+			is, err := iface.Interfaces()
+			NoError(t, err)
+			NotContains(t, is, net.Interface{Name: "eth0"})
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	s, err := iface.Capture()
+	NoError(t, err)
+	is, err := net.Interfaces()
+	NoError(t, err)
+	Len(t, s.Interfaces, len(is))
+
+	live := map[string]string{}
+	for _, i := range is {
+		if ip, _, err := iface.GetAddr(i.Name); err == nil {
+			live[i.Name] = ip.String()
+		}
+	}
+
+	// the captured state is reported as it is
+	NoError(t, iface.UseSnapshot(s))
+	defer func() { _ = iface.UseSnapshot(nil) }()
+	for name, want := range live {
+		ip, _, err := iface.GetAddr(name)
+		NoError(t, err)
+		Equal(t, want, ip.String())
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+// GetSpeed returns the link speed of the network interface with the given name in Mbit/s.
+// If the speed is unknown (e.g., for virtual interfaces or if the link is down), 0 is returned.
+func GetSpeed(name string) (int, error) {
+	i, err := InterfaceByName(name)
+	if err != nil {
+		return 0, err
+	}
+	if si := snapshotOf(name); si != nil {
+		return si.Speed, nil
+	}
+	return speed(i)
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// speed reads the link speed from /sys/class/net/NAME/speed.
+// Reading the file fails if the link is down, and virtual interfaces report -1 (both are reported as 0).
+func speed(i *net.Interface) (int, error) {
+	b, err := os.ReadFile(filepath.Join("/sys/class/net", i.Name, "speed"))
+	if err != nil {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || n < 0 {
+		return 0, err
+	}
+	return n, nil
+}
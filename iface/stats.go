@@ -0,0 +1,45 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import "errors"
+
+// Stats holds the traffic counters of a network interface.
+type Stats struct {
+	RxBytes   uint64 `json:"rxBytes"`
+	TxBytes   uint64 `json:"txBytes"`
+	RxPackets uint64 `json:"rxPackets"`
+	TxPackets uint64 `json:"txPackets"`
+	RxErrors  uint64 `json:"rxErrors"`
+	TxErrors  uint64 `json:"txErrors"`
+	RxDropped uint64 `json:"rxDropped"`
+	TxDropped uint64 `json:"txDropped"`
+}
+
+// GetStats returns the traffic counters of the network interface with the given name.
+// Note that the counters might wrap around, depending on the operating system (e.g., 32 bits on Windows).
+func GetStats(name string) (Stats, error) {
+	i, err := InterfaceByName(name)
+	if err != nil {
+		return Stats{}, err
+	}
+	if si := snapshotOf(name); si != nil {
+		if si.Stats == nil {
+			return Stats{}, errors.New("no traffic counters in the snapshot: " + name)
+		}
+		return *si.Stats, nil
+	}
+	return stats(i)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// stats returns the counters of the interface as reported by "netstat -ibdn".
+func stats(i *net.Interface) (Stats, error) {
+	out, err := exec.Command("/usr/sbin/netstat", "-ibdn", "-I", i.Name).Output()
+	if err != nil {
+		return Stats{}, err
+	}
+	return parseNetstat(strings.NewReader(string(out)), i.Name)
+}
+
+// parseNetstat parses the link-level line of the interface in the output of "netstat -ibdn".
+// The header determines the columns, since the address column is empty for some interfaces.
+func parseNetstat(r io.Reader, name string) (Stats, error) {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return Stats{}, errors.New("no interface statistics: " + name)
+	}
+	header := strings.Fields(sc.Text())
+
+	for sc.Scan() {
+		fs := strings.Fields(sc.Text())
+		if len(fs) < 3 || fs[0] != name || !strings.HasPrefix(fs[2], "<Link#") {
+			continue
+		}
+		if len(fs) == len(header)-1 {
+			// no address
+			fs = append(fs[:3], append([]string{""}, fs[3:]...)...)
+		}
+
+		var s Stats
+		cols := map[string]*uint64{
+			"Ibytes": &s.RxBytes, "Obytes": &s.TxBytes, "Ipkts": &s.RxPackets, "Opkts": &s.TxPackets,
+			"Ierrs": &s.RxErrors, "Oerrs": &s.TxErrors, "Drop": &s.RxDropped,
+		}
+		for c, h := range header {
+			if v, ok := cols[h]; ok && c < len(fs) {
+				*v, _ = strconv.ParseUint(fs[c], 10, 64)
+			}
+		}
+		return s, nil
+	}
+	return Stats{}, errors.New("no interface statistics: " + name)
+}
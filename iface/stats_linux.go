@@ -0,0 +1,41 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// stats reads the counters from /sys/class/net/NAME/statistics.
+func stats(i *net.Interface) (s Stats, err error) {
+	dir := filepath.Join("/sys/class/net", i.Name, "statistics")
+	for name, v := range map[string]*uint64{
+		"rx_bytes": &s.RxBytes, "tx_bytes": &s.TxBytes, "rx_packets": &s.RxPackets, "tx_packets": &s.TxPackets,
+		"rx_errors": &s.RxErrors, "tx_errors": &s.TxErrors, "rx_dropped": &s.RxDropped, "tx_dropped": &s.TxDropped,
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return Stats{}, err
+		}
+		if *v, err = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64); err != nil {
+			return Stats{}, err
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iface
+
+import (
+	"net"
+
+	"golang.org/x/sys/windows"
+)
+
+// stats returns the (32-bit) counters of the interface as reported by GetIfEntry.
+func stats(i *net.Interface) (Stats, error) {
+	row := windows.MibIfRow{Index: uint32(i.Index)}
+	if err := windows.GetIfEntry(&row); err != nil {
+		return Stats{}, err
+	}
+	return Stats{
+		RxBytes:   uint64(row.InOctets),
+		TxBytes:   uint64(row.OutOctets),
+		RxPackets: uint64(row.InUcastPkts) + uint64(row.InNUcastPkts),
+		TxPackets: uint64(row.OutUcastPkts) + uint64(row.OutNUcastPkts),
+		RxErrors:  uint64(row.InErrors),
+		TxErrors:  uint64(row.OutErrors),
+		RxDropped: uint64(row.InDiscards),
+		TxDropped: uint64(row.OutDiscards),
+	}, nil
+}
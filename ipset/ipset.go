@@ -0,0 +1,371 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipset provides sets of IP addresses, which are represented by non-overlapping ranges.
+package ipset
+
+import (
+	"errors"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Range is a contiguous range of IP addresses of the same family (From <= To).
+type Range struct {
+	From, To netip.Addr
+}
+
+// ParseRange parses an IP address, a network in CIDR notation or an IP range (FROM-TO).
+func ParseRange(s string) (Range, error) {
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		f, errFrom := netip.ParseAddr(strings.TrimSpace(from))
+		t, errTo := netip.ParseAddr(strings.TrimSpace(to))
+		r := Range{f.Unmap(), t.Unmap()}
+		if errFrom != nil || errTo != nil || r.From.Is4() != r.To.Is4() || r.To.Less(r.From) {
+			return Range{}, errors.New("invalid IP range: " + s)
+		}
+		return r, nil
+	}
+
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return Range{}, err
+		}
+		return PrefixRange(p), nil
+	}
+
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{a.Unmap(), a.Unmap()}, nil
+}
+
+// maxOctetRanges is the maximum number of ranges an octet range pattern may expand to.
+const maxOctetRanges = 1 << 16
+
+// ParseOctetRanges parses an nmap-style IPv4 address pattern, whose octets are numbers, ranges (N-M), lists
+// thereof (N,M-O) or "*" (0-255) e.g., 10.0.0-3.1-254. Open ranges (-N and N-) start at 0 or end at 255.
+// The ranges are returned in ascending order.
+func ParseOctetRanges(s string) ([]Range, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 4 {
+		return nil, errors.New("invalid octet range: " + s)
+	}
+
+	var octets [4][][2]int
+	for i, part := range parts {
+		spans, err := parseOctetSpans(part)
+		if err != nil {
+			return nil, errors.New("invalid octet range: " + s)
+		}
+		octets[i] = spans
+	}
+
+	// the first octets are enumerated, whereas the last octets form a range, if they are not restricted
+	last := 3
+	for last > 0 && len(octets[last]) == 1 && octets[last][0] == [2]int{0, 255} {
+		last--
+	}
+	count := len(octets[last])
+	for i := 0; i < last; i++ {
+		n := 0
+		for _, sp := range octets[i] {
+			n += sp[1] - sp[0] + 1
+		}
+		if count *= n; count > maxOctetRanges {
+			return nil, errors.New("too many ranges in octet range: " + s)
+		}
+	}
+
+	var rs []Range
+	var prefix [4]byte
+	var expand func(i int)
+	expand = func(i int) {
+		if i == last {
+			for _, sp := range octets[i] {
+				from, to := prefix, prefix
+				from[i], to[i] = byte(sp[0]), byte(sp[1])
+				for j := i + 1; j < 4; j++ {
+					to[j] = 0xff
+				}
+				rs = append(rs, Range{netip.AddrFrom4(from), netip.AddrFrom4(to)})
+			}
+			return
+		}
+		for _, sp := range octets[i] {
+			for v := sp[0]; v <= sp[1]; v++ {
+				prefix[i] = byte(v)
+				expand(i + 1)
+			}
+		}
+	}
+	expand(0)
+	return New(rs...).Ranges(), nil
+}
+
+// parseOctetSpans parses a comma-separated list of octet values and ranges.
+func parseOctetSpans(s string) ([][2]int, error) {
+	var spans [][2]int
+	for _, e := range strings.Split(s, ",") {
+		if e == "*" {
+			e = "-"
+		}
+		from, to, isRange := strings.Cut(e, "-")
+		if !isRange {
+			to = from
+		}
+		if from == "" && isRange {
+			from = "0"
+		}
+		if to == "" && isRange {
+			to = "255"
+		}
+
+		f, errFrom := strconv.ParseUint(from, 10, 8)
+		t, errTo := strconv.ParseUint(to, 10, 8)
+		if errFrom != nil || errTo != nil || f > t {
+			return nil, errors.New("invalid octet: " + e)
+		}
+		spans = append(spans, [2]int{int(f), int(t)})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, sp := range spans[1:] {
+		if prev := &merged[len(merged)-1]; sp[0] <= prev[1]+1 {
+			if sp[1] > prev[1] {
+				prev[1] = sp[1]
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	return merged, nil
+}
+
+// PrefixRange returns the range of IP addresses of the network p.
+func PrefixRange(p netip.Prefix) Range {
+	p = p.Masked()
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return Range{p.Addr(), LastAddr(p)}
+}
+
+// LastAddr returns the last IP address of the network p.
+func LastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := range b {
+		if rem := p.Bits() - i*8; rem <= 0 {
+			b[i] = 0xff
+		} else if rem < 8 {
+			b[i] |= 0xff >> rem
+		}
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// String returns the range in FROM-TO notation.
+func (r Range) String() string {
+	return r.From.String() + "-" + r.To.String()
+}
+
+// Prefixes returns the smallest list of networks, which cover exactly the range r.
+func (r Range) Prefixes() []netip.Prefix {
+	var ps []netip.Prefix
+	for from := r.From; ; {
+		bits := from.BitLen()
+		for bits > 0 {
+			p := netip.PrefixFrom(from, bits-1)
+			if p.Masked().Addr() != from || r.To.Less(LastAddr(p)) {
+				break
+			}
+			bits--
+		}
+
+		p := netip.PrefixFrom(from, bits)
+		ps = append(ps, p)
+		last := LastAddr(p)
+		if last == r.To {
+			return ps
+		}
+		from = last.Next()
+	}
+}
+
+// Set is a set of IP addresses.
+// The zero value is an empty set.
+type Set struct {
+	// ranges are sorted, non-overlapping and non-adjacent.
+	ranges []Range
+}
+
+// New creates a set, which contains all IP addresses of the given ranges.
+func New(rs ...Range) Set {
+	rs = append([]Range(nil), rs...)
+	sort.Slice(rs, func(i, j int) bool { return rs[i].From.Less(rs[j].From) })
+
+	var merged []Range
+	for _, r := range rs {
+		if n := len(merged); n > 0 && adjacent(merged[n-1], r) {
+			if merged[n-1].To.Less(r.To) {
+				merged[n-1].To = r.To
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return Set{ranges: merged}
+}
+
+// Parse creates a set from IP addresses, networks in CIDR notation and IP ranges (FROM-TO).
+func Parse(ss []string) (Set, error) {
+	rs := make([]Range, 0, len(ss))
+	for _, s := range ss {
+		r, err := ParseRange(s)
+		if err != nil {
+			return Set{}, err
+		}
+		rs = append(rs, r)
+	}
+	return New(rs...), nil
+}
+
+// adjacent reports whether r (which must not start before prev) overlaps or directly follows prev.
+func adjacent(prev, r Range) bool {
+	if prev.From.Is4() != r.From.Is4() {
+		return false
+	}
+	next := prev.To.Next()
+	return !next.IsValid() || !next.Less(r.From)
+}
+
+// Ranges returns the ranges of the set in ascending order.
+func (s Set) Ranges() []Range {
+	return append([]Range(nil), s.ranges...)
+}
+
+// Prefixes returns the smallest list of networks, which cover exactly the set.
+func (s Set) Prefixes() []netip.Prefix {
+	var ps []netip.Prefix
+	for _, r := range s.ranges {
+		ps = append(ps, r.Prefixes()...)
+	}
+	return ps
+}
+
+// Size returns the number of IP addresses in the range.
+func (r Range) Size() *big.Int {
+	from, to := new(big.Int).SetBytes(r.From.AsSlice()), new(big.Int).SetBytes(r.To.AsSlice())
+	return to.Sub(to, from).Add(to, big.NewInt(1))
+}
+
+// Size returns the number of IP addresses in the set.
+func (s Set) Size() *big.Int {
+	n := new(big.Int)
+	for _, r := range s.ranges {
+		n.Add(n, r.Size())
+	}
+	return n
+}
+
+// CountIn returns the number of IP addresses of the set, which are within the range r.
+func (s Set) CountIn(r Range) *big.Int {
+	n := new(big.Int)
+	i := sort.Search(len(s.ranges), func(i int) bool { return !s.ranges[i].To.Less(r.From) })
+	for ; i < len(s.ranges) && !r.To.Less(s.ranges[i].From); i++ {
+		if s.ranges[i].From.Is4() == r.From.Is4() {
+			n.Add(n, Range{maxAddr(r.From, s.ranges[i].From), minAddr(r.To, s.ranges[i].To)}.Size())
+		}
+	}
+	return n
+}
+
+// Contains reports whether the IP address a is part of the set.
+func (s Set) Contains(a netip.Addr) bool {
+	a = a.Unmap()
+	i := sort.Search(len(s.ranges), func(i int) bool { return !s.ranges[i].To.Less(a) })
+	return i < len(s.ranges) && !a.Less(s.ranges[i].From)
+}
+
+// Union returns the set of IP addresses, which are part of s or o.
+func (s Set) Union(o Set) Set {
+	return New(append(s.Ranges(), o.ranges...)...)
+}
+
+// Intersect returns the set of IP addresses, which are part of both s and o.
+func (s Set) Intersect(o Set) Set {
+	var rs []Range
+	for i, j := 0, 0; i < len(s.ranges) && j < len(o.ranges); {
+		a, b := s.ranges[i], o.ranges[j]
+		from, to := maxAddr(a.From, b.From), minAddr(a.To, b.To)
+		if a.From.Is4() == b.From.Is4() && !to.Less(from) {
+			rs = append(rs, Range{from, to})
+		}
+		if a.To.Less(b.To) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return Set{ranges: rs}
+}
+
+// Difference returns the set of IP addresses, which are part of s, but not of o.
+func (s Set) Difference(o Set) Set {
+	var rs []Range
+	j := 0
+	for _, r := range s.ranges {
+		for j < len(o.ranges) && o.ranges[j].To.Less(r.From) {
+			j++
+		}
+
+		from, covered := r.From, false
+		for k := j; k < len(o.ranges) && !r.To.Less(o.ranges[k].From); k++ {
+			b := o.ranges[k]
+			if from.Less(b.From) {
+				rs = append(rs, Range{from, b.From.Prev()})
+			}
+			if !b.To.Less(r.To) {
+				covered = true
+				break
+			}
+			from = b.To.Next()
+		}
+		if !covered {
+			rs = append(rs, Range{from, r.To})
+		}
+	}
+	return Set{ranges: rs}
+}
+
+func minAddr(a, b netip.Addr) netip.Addr {
+	if a.Less(b) {
+		return a
+	}
+	return b
+}
+
+func maxAddr(a, b netip.Addr) netip.Addr {
+	if a.Less(b) {
+		return b
+	}
+	return a
+}
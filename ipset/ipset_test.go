@@ -0,0 +1,175 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipset_test
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipset"
+	. "github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, ss ...string) ipset.Set {
+	s, err := ipset.Parse(ss)
+	NoError(t, err)
+	return s
+}
+
+func prefixes(s ipset.Set) string {
+	return fmt.Sprint(s.Prefixes())
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"10.0.0.1", "10.0.0.1-10.0.0.1"},
+		{"10.0.0.1/24", "10.0.0.0-10.0.0.255"},
+		{"10.0.0.10-10.0.0.20", "10.0.0.10-10.0.0.20"},
+		{"10.0.0.10 - 10.0.0.20", "10.0.0.10-10.0.0.20"},
+		{"::ffff:10.0.0.1", "10.0.0.1-10.0.0.1"},
+		{"2001:db8::/127", "2001:db8::-2001:db8::1"},
+		{"0.0.0.0/0", "0.0.0.0-255.255.255.255"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := ipset.ParseRange(tt.in)
+			NoError(t, err)
+			Equal(t, tt.want, r.String())
+		})
+	}
+
+	for _, in := range []string{"10.0.0.20-10.0.0.10", "10.0.0.1-::1", "10.0.0.256", "10.0.0.0/33"} {
+		_, err := ipset.ParseRange(in)
+		Error(t, err, in)
+	}
+}
+
+func TestParseOctetRanges(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"10.0.0.1", []string{"10.0.0.1-10.0.0.1"}},
+		{"10.0.0-3.1-254", []string{"10.0.0.1-10.0.0.254", "10.0.1.1-10.0.1.254", "10.0.2.1-10.0.2.254",
+			"10.0.3.1-10.0.3.254"}},
+		{"192.168.1.1,3,10-12", []string{"192.168.1.1-192.168.1.1", "192.168.1.3-192.168.1.3",
+			"192.168.1.10-192.168.1.12"}},
+		{"10.1-2.*.*", []string{"10.1.0.0-10.2.255.255"}},
+		{"10.0.0.-9,5-", []string{"10.0.0.0-10.0.0.255"}},
+		{"*.*.*.*", []string{"0.0.0.0-255.255.255.255"}},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.in, func(t *testing.T) {
+			rs, err := ipset.ParseOctetRanges(tt.in)
+			NoError(t, err)
+			var ss []string
+			for _, r := range rs {
+				ss = append(ss, r.String())
+			}
+			Equal(t, tt.want, ss)
+		})
+	}
+
+	for _, in := range []string{"10.0.0", "10.0.0.256", "10.0.0.5-1", "10.0..1", "10.0.0.a", "*.*.*.1"} {
+		_, err := ipset.ParseOctetRanges(in)
+		Error(t, err, in)
+	}
+}
+
+func TestRangePrefixes(t *testing.T) {
+	r, _ := ipset.ParseRange("10.0.0.1-10.0.0.10")
+	Equal(t, "[10.0.0.1/32 10.0.0.2/31 10.0.0.4/30 10.0.0.8/31 10.0.0.10/32]", fmt.Sprint(r.Prefixes()))
+	r, _ = ipset.ParseRange("0.0.0.0-255.255.255.255")
+	Equal(t, "[0.0.0.0/0]", fmt.Sprint(r.Prefixes()))
+	r, _ = ipset.ParseRange("::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")
+	Equal(t, "[::/0]", fmt.Sprint(r.Prefixes()))
+}
+
+func TestLastAddr(t *testing.T) {
+	Equal(t, "10.0.3.255", ipset.LastAddr(netip.MustParsePrefix("10.0.1.1/22")).String())
+	Equal(t, "2001:db8::ffff", ipset.LastAddr(netip.MustParsePrefix("2001:db8::/112")).String())
+}
+
+func TestNew(t *testing.T) {
+	s := parse(t, "10.0.1.0/24", "10.0.0.0/24", "10.0.0.128/25", "2001:db8::/64", "255.255.255.255", "::")
+	Equal(t, "[10.0.0.0/23 255.255.255.255/32 ::/128 2001:db8::/64]", prefixes(s))
+	Len(t, s.Ranges(), 4)
+}
+
+func TestSize(t *testing.T) {
+	Equal(t, "0", ipset.Set{}.Size().String())
+	Equal(t, "4294967296", parse(t, "0.0.0.0/0").Size().String())
+	Equal(t, "18446744073709551873", parse(t, "10.0.0.0/24", "10.0.0.100", "10.0.1.0", "2001:db8::/64").Size().String())
+}
+
+func TestCountIn(t *testing.T) {
+	s := parse(t, "10.0.0.0/25", "10.0.0.200-10.0.1.10", "2001:db8::/64")
+	r, _ := ipset.ParseRange("10.0.0.0/24")
+	Equal(t, "184", s.CountIn(r).String())
+	r, _ = ipset.ParseRange("10.0.1.0/24")
+	Equal(t, "11", s.CountIn(r).String())
+	r, _ = ipset.ParseRange("10.0.2.0/24")
+	Equal(t, "0", s.CountIn(r).String())
+	r, _ = ipset.ParseRange("2001:db8::/112")
+	Equal(t, "65536", s.CountIn(r).String())
+}
+
+func TestContains(t *testing.T) {
+	s := parse(t, "10.0.0.0/24", "192.168.0.1-192.168.0.5", "2001:db8::/64")
+	True(t, s.Contains(netip.MustParseAddr("10.0.0.42")))
+	True(t, s.Contains(netip.MustParseAddr("192.168.0.5")))
+	True(t, s.Contains(netip.MustParseAddr("::ffff:10.0.0.1")))
+	True(t, s.Contains(netip.MustParseAddr("2001:db8::1")))
+	False(t, s.Contains(netip.MustParseAddr("192.168.0.6")))
+	False(t, s.Contains(netip.MustParseAddr("10.0.1.0")))
+	False(t, ipset.Set{}.Contains(netip.MustParseAddr("10.0.1.0")))
+}
+
+func TestUnion(t *testing.T) {
+	a := parse(t, "10.0.0.0/25", "2001:db8::/64")
+	b := parse(t, "10.0.0.128/25", "10.0.2.0/24")
+	Equal(t, "[10.0.0.0/24 10.0.2.0/24 2001:db8::/64]", prefixes(a.Union(b)))
+}
+
+func TestIntersect(t *testing.T) {
+	a := parse(t, "10.0.0.0/23", "10.1.0.0/24", "2001:db8::/32")
+	b := parse(t, "10.0.1.128/25", "10.0.2.0/24", "10.1.0.0-10.1.0.9", "2001:db8:1::/48")
+	Equal(t, "[10.0.1.128/25 10.1.0.0/29 10.1.0.8/31 2001:db8:1::/48]", prefixes(a.Intersect(b)))
+	Empty(t, a.Intersect(ipset.Set{}).Prefixes())
+}
+
+func TestDifference(t *testing.T) {
+	a := parse(t, "10.0.0.0/24", "10.0.2.0/24", "2001:db8::/64")
+	b := parse(t, "10.0.0.64/26", "10.0.0.192/26", "10.0.2.0/23")
+	Equal(t, "[10.0.0.0/26 10.0.0.128/26 2001:db8::/64]", prefixes(a.Difference(b)))
+	Equal(t, "[10.0.3.0/24]", prefixes(b.Difference(a)))
+	Empty(t, a.Difference(a).Prefixes())
+	Equal(t, prefixes(a), prefixes(a.Difference(ipset.Set{})))
+
+	all := parse(t, "0.0.0.0/0")
+	Equal(t, "[0.0.0.0/1 128.0.0.0/2 192.0.0.0/3 224.0.0.0/4 240.0.0.0/5 248.0.0.0/6 252.0.0.0/7 254.0.0.0/8 "+
+		"255.0.0.0/9 255.128.0.0/10 255.192.0.0/11 255.224.0.0/12 255.240.0.0/13 255.248.0.0/14 255.252.0.0/15 "+
+		"255.254.0.0/16 255.255.0.0/17 255.255.128.0/18 255.255.192.0/19 255.255.224.0/20 255.255.240.0/21 "+
+		"255.255.248.0/22 255.255.252.0/23 255.255.254.0/24 255.255.255.0/25 255.255.255.128/26 255.255.255.192/27 "+
+		"255.255.255.224/28 255.255.255.240/29 255.255.255.248/30 255.255.255.252/31 255.255.255.254/32]",
+		prefixes(all.Difference(parse(t, "255.255.255.255"))))
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// addr converts ip to a netip.Addr, if it is an IPv6 address (but not an IPv4(-mapped) address).
+func addr(ip net.IP) (netip.Addr, error) {
+	if ip.To4() != nil || len(ip) != net.IPv6len {
+		return netip.Addr{}, errors.New("not an IPv6 address: " + ip.String())
+	}
+	a, _ := netip.AddrFromSlice(ip)
+	return a, nil
+}
+
+// Expand returns the full form of an IPv6 address, i.e., eight groups of four hex digits (39 characters).
+func Expand(ip net.IP) (string, error) {
+	a, err := addr(ip)
+	if err != nil {
+		return "", err
+	}
+	return a.StringExpanded(), nil
+}
+
+// Canonical returns the canonical text representation of an IPv6 address (see RFC 5952, section 4):
+// hex digits are lowercase, leading zeros are omitted and the first longest run of (at least two) groups of zeros
+// is replaced by "::".
+func Canonical(ip net.IP) (string, error) {
+	a, err := addr(ip)
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+// Compress returns the shortest text representation of an IPv6 address.
+// Unlike Canonical, a single group of zeros is compressed as well (e.g., 2001:db8::1:1:1:1:1), like some vendors do.
+func Compress(ip net.IP) (string, error) {
+	if _, err := addr(ip); err != nil {
+		return "", err
+	}
+
+	// find the first longest run of groups of zeros
+	groups := make([]string, 8)
+	start, length, run := -1, 0, 0
+	for i := range groups {
+		g := uint16(ip[2*i])<<8 | uint16(ip[2*i+1])
+		groups[i] = fmt.Sprintf("%x", g)
+		if g != 0 {
+			run = 0
+			continue
+		}
+		if run++; run > length {
+			start, length = i-run+1, run
+		}
+	}
+	if start < 0 {
+		return strings.Join(groups, ":"), nil
+	}
+	return strings.Join(groups[:start], ":") + "::" + strings.Join(groups[start+length:], ":"), nil
+}
+
+// Arpa returns the reverse DNS name of an IPv6 address in nibble format below ip6.arpa (see RFC 3596, section 2.5).
+func Arpa(ip net.IP) (string, error) {
+	if _, err := addr(ip); err != nil {
+		return "", err
+	}
+
+	const digits = "0123456789abcdef"
+	b := &strings.Builder{}
+	for i := net.IPv6len - 1; i >= 0; i-- {
+		b.WriteByte(digits[ip[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(digits[ip[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa")
+	return b.String(), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipv6"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		ip         string
+		expanded   string
+		canonical  string
+		compressed string
+	}{
+		{"2001:DB8::1", "2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1", "2001:db8::1"},
+		{"2001:db8:0:1:1:1:1:1", "2001:0db8:0000:0001:0001:0001:0001:0001", "2001:db8:0:1:1:1:1:1",
+			"2001:db8::1:1:1:1:1"},
+		{"2001:0:0:1:0:0:0:1", "2001:0000:0000:0001:0000:0000:0000:0001", "2001:0:0:1::1", "2001:0:0:1::1"},
+		{"2001:db8:0:0:1:0:0:1", "2001:0db8:0000:0000:0001:0000:0000:0001", "2001:db8::1:0:0:1", "2001:db8::1:0:0:1"},
+		{"::", "0000:0000:0000:0000:0000:0000:0000:0000", "::", "::"},
+		{"::1", "0000:0000:0000:0000:0000:0000:0000:0001", "::1", "::1"},
+		{"fe80::", "fe80:0000:0000:0000:0000:0000:0000:0000", "fe80::", "fe80::"},
+		{"1:2:3:4:5:6:7:8", "0001:0002:0003:0004:0005:0006:0007:0008", "1:2:3:4:5:6:7:8", "1:2:3:4:5:6:7:8"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			s, err := ipv6.Expand(ip)
+			NoError(t, err)
+			Equal(t, tt.expanded, s)
+			s, err = ipv6.Canonical(ip)
+			NoError(t, err)
+			Equal(t, tt.canonical, s)
+			s, err = ipv6.Compress(ip)
+			NoError(t, err)
+			Equal(t, tt.compressed, s)
+		})
+	}
+}
+
+func TestArpa(t *testing.T) {
+	s, err := ipv6.Arpa(net.ParseIP("2001:db8::567:89ab"))
+	NoError(t, err)
+	Equal(t, "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", s)
+}
+
+func TestFormatIPv4(t *testing.T) {
+	ip := net.ParseIP("192.0.2.1")
+	for _, fn := range []func(net.IP) (string, error){ipv6.Expand, ipv6.Canonical, ipv6.Compress, ipv6.Arpa} {
+		_, err := fn(ip)
+		EqualError(t, err, "not an IPv6 address: 192.0.2.1")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6
+
+import (
+	"errors"
+	"net"
+)
+
+// SolicitedNode returns the solicited-node multicast address of an IPv6 unicast address (see RFC 4291, section 2.7.1).
+func SolicitedNode(ip net.IP) (net.IP, error) {
+	if ip.To4() != nil || len(ip) != net.IPv6len || ip.IsMulticast() {
+		return nil, errors.New("not an IPv6 unicast address: " + ip.String())
+	}
+
+	sn := net.ParseIP("ff02::1:ff00:0")
+	copy(sn[13:], ip[13:])
+	return sn, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipv6"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestSolicitedNode(t *testing.T) {
+	sn, err := ipv6.SolicitedNode(net.ParseIP("2001:db8::211:22ff:fe33:4455"))
+	NoError(t, err)
+	Equal(t, "ff02::1:ff33:4455", sn.String())
+
+	_, err = ipv6.SolicitedNode(net.ParseIP("10.0.0.1"))
+	EqualError(t, err, "not an IPv6 unicast address: 10.0.0.1")
+	_, err = ipv6.SolicitedNode(net.ParseIP("ff02::1"))
+	EqualError(t, err, "not an IPv6 unicast address: ff02::1")
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// Transition mechanisms, which embed an IPv4 address in an IPv6 address.
+const (
+	// SixToFour is a 6to4 address (2002::/16, RFC 3056).
+	SixToFour = "6to4"
+	// ISATAP is an ISATAP address (::0:5efe:a.b.c.d or ::200:5efe:a.b.c.d, RFC 5214).
+	ISATAP = "isatap"
+	// IPv4Mapped is an IPv4-mapped address (::ffff:0:0/96, RFC 4291).
+	IPv4Mapped = "ipv4-mapped"
+	// NAT64 is an address of the NAT64 well-known prefix (64:ff9b::/96, RFC 6052).
+	NAT64 = "nat64"
+	// Teredo is a Teredo address (2001::/32, RFC 4380).
+	Teredo = "teredo"
+)
+
+var (
+	nat64Prefix  = &net.IPNet{IP: net.ParseIP("64:ff9b::"), Mask: net.CIDRMask(96, 128)}
+	teredoPrefix = &net.IPNet{IP: net.ParseIP("2001::"), Mask: net.CIDRMask(32, 128)}
+	sixToFour    = &net.IPNet{IP: net.ParseIP("2002::"), Mask: net.CIDRMask(16, 128)}
+)
+
+// TransitionAddr holds the information embedded in an IPv6 transition address.
+type TransitionAddr struct {
+	// Type is the transition mechanism.
+	Type string
+	// IPv4 is the embedded IPv4 address (the client address in case of Teredo).
+	IPv4 net.IP
+	// Server is the Teredo server address.
+	Server net.IP
+	// Port is the (de-obfuscated) external UDP port of a Teredo client.
+	Port uint16
+	// Flags are the Teredo flags.
+	Flags uint16
+}
+
+// DecodeTransition recognizes IPv6 transition addresses and extracts the embedded IPv4 address.
+func DecodeTransition(ip net.IP) (*TransitionAddr, error) {
+	if len(ip) != net.IPv6len {
+		return nil, errors.New("not an IPv6 address: " + ip.String())
+	}
+
+	switch {
+	case ip.To4() != nil:
+		return &TransitionAddr{Type: IPv4Mapped, IPv4: ip.To4()}, nil
+	case nat64Prefix.Contains(ip):
+		return &TransitionAddr{Type: NAT64, IPv4: ipv4(ip[12:])}, nil
+	case sixToFour.Contains(ip):
+		return &TransitionAddr{Type: SixToFour, IPv4: ipv4(ip[2:6])}, nil
+	case teredoPrefix.Contains(ip):
+		client := make(net.IP, net.IPv4len)
+		for i := range client {
+			client[i] = ^ip[12+i]
+		}
+		return &TransitionAddr{
+			Type:   Teredo,
+			IPv4:   client,
+			Server: ipv4(ip[4:8]),
+			Flags:  binary.BigEndian.Uint16(ip[8:10]),
+			Port:   ^binary.BigEndian.Uint16(ip[10:12]),
+		}, nil
+	case ip[8]&^0x02 == 0 && ip[9] == 0 && ip[10] == 0x5e && ip[11] == 0xfe:
+		return &TransitionAddr{Type: ISATAP, IPv4: ipv4(ip[12:])}, nil
+	}
+	return nil, errors.New("not an IPv6 transition address: " + ip.String())
+}
+
+func ipv4(b []byte) net.IP {
+	return net.IPv4(b[0], b[1], b[2], b[3]).To4()
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/ipv6"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransition(t *testing.T) {
+	tests := []struct {
+		ip   string
+		typ  string
+		ipv4 string
+	}{
+		{"2002:c000:0204::1", ipv6.SixToFour, "192.0.2.4"},
+		{"::ffff:192.0.2.33", ipv6.IPv4Mapped, "192.0.2.33"},
+		{"64:ff9b::c000:221", ipv6.NAT64, "192.0.2.33"},
+		{"fe80::5efe:c000:221", ipv6.ISATAP, "192.0.2.33"},
+		{"2001:db8::200:5efe:c000:221", ipv6.ISATAP, "192.0.2.33"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			ta, err := ipv6.DecodeTransition(net.ParseIP(tt.ip))
+			NoError(t, err)
+			Equal(t, tt.typ, ta.Type)
+			Equal(t, tt.ipv4, ta.IPv4.String())
+		})
+	}
+}
+
+func TestDecodeTransitionTeredo(t *testing.T) {
+	// example from RFC 4380, section 4
+	ta, err := ipv6.DecodeTransition(net.ParseIP("2001:0:4136:e378:8000:63bf:3fff:fdd2"))
+	NoError(t, err)
+	Equal(t, ipv6.Teredo, ta.Type)
+	Equal(t, "192.0.2.45", ta.IPv4.String())
+	Equal(t, "65.54.227.120", ta.Server.String())
+	Equal(t, uint16(40000), ta.Port)
+	Equal(t, uint16(0x8000), ta.Flags)
+}
+
+func TestDecodeTransitionInvalid(t *testing.T) {
+	_, err := ipv6.DecodeTransition(net.ParseIP("2001:db8::1"))
+	EqualError(t, err, "not an IPv6 transition address: 2001:db8::1")
+	_, err = ipv6.DecodeTransition(net.ParseIP("192.0.2.1").To4())
+	EqualError(t, err, "not an IPv6 address: 192.0.2.1")
+}
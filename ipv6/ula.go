@@ -0,0 +1,72 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipv6 provides calculations, which are specific to IPv6 addresses.
+package ipv6
+
+import (
+	"crypto/sha1" //nolint:gosec // mandated by RFC 4193
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch (1970).
+const ntpEpochOffset = 2208988800
+
+// ULA returns a Unique Local IPv6 Unicast Address prefix (/48) generated according to RFC 4193, section 3.2.2.
+// The global ID is derived from the time t and the EUI-64 identifier eui64.
+func ULA(t time.Time, eui64 []byte) (*net.IPNet, error) {
+	if len(eui64) != 8 {
+		return nil, errors.New("not an EUI-64 identifier")
+	}
+
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, ntpTime(t))
+	copy(key[8:], eui64)
+	sum := sha1.Sum(key) //nolint:gosec // mandated by RFC 4193
+
+	ip := make(net.IP, net.IPv6len)
+	ip[0] = 0xfd
+	copy(ip[1:6], sum[len(sum)-5:])
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(48, 128)}, nil
+}
+
+// Subnets returns the first count subnets with the given prefix length within the network n.
+func Subnets(n *net.IPNet, prefix, count int) ([]*net.IPNet, error) {
+	size, bits := n.Mask.Size()
+	if prefix < size || prefix > bits {
+		return nil, errors.New("invalid prefix length for subnetting " + n.String())
+	}
+
+	var ns []*net.IPNet
+	start := new(big.Int).SetBytes(n.IP.Mask(n.Mask))
+	step := new(big.Int).Lsh(big.NewInt(1), uint(bits-prefix))
+	total := new(big.Int).Lsh(big.NewInt(1), uint(prefix-size))
+	for i := int64(0); i < int64(count) && big.NewInt(i).Cmp(total) < 0; i++ {
+		ip := new(big.Int).Add(start, new(big.Int).Mul(step, big.NewInt(i)))
+		b := make(net.IP, len(n.IP.Mask(n.Mask)))
+		ns = append(ns, &net.IPNet{IP: ip.FillBytes(b), Mask: net.CIDRMask(prefix, bits)})
+	}
+	return ns, nil
+}
+
+// ntpTime returns t in 64-bit NTP timestamp format.
+func ntpTime(t time.Time) uint64 {
+	sec := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return sec<<32 | frac
+}
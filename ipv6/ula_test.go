@@ -0,0 +1,63 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipv6_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/ipv6"
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestULA(t *testing.T) {
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	id, _ := mac.EUI64(hw)
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	n, err := ipv6.ULA(ts, id)
+	NoError(t, err)
+	Equal(t, byte(0xfd), n.IP[0])
+	size, _ := n.Mask.Size()
+	Equal(t, 48, size)
+
+	// deterministic for the same input, different for another time
+	n2, _ := ipv6.ULA(ts, id)
+	Equal(t, n.String(), n2.String())
+	n3, _ := ipv6.ULA(ts.Add(time.Millisecond), id)
+	NotEqual(t, n.String(), n3.String())
+
+	_, err = ipv6.ULA(ts, hw)
+	EqualError(t, err, "not an EUI-64 identifier")
+}
+
+func TestSubnets(t *testing.T) {
+	_, n, _ := net.ParseCIDR("fd12:3456:789a::/48")
+	ns, err := ipv6.Subnets(n, 64, 3)
+	NoError(t, err)
+	Len(t, ns, 3)
+	Equal(t, "fd12:3456:789a::/64", ns[0].String())
+	Equal(t, "fd12:3456:789a:1::/64", ns[1].String())
+	Equal(t, "fd12:3456:789a:2::/64", ns[2].String())
+
+	ns, err = ipv6.Subnets(n, 49, 3)
+	NoError(t, err)
+	Len(t, ns, 2)
+
+	_, err = ipv6.Subnets(n, 47, 3)
+	EqualError(t, err, "invalid prefix length for subnetting fd12:3456:789a::/48")
+}
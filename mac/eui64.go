@@ -0,0 +1,65 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mac provides calculations for MAC addresses.
+package mac
+
+import (
+	"errors"
+	"net"
+)
+
+var (
+	errNotEUI48 = errors.New("not an EUI-48 MAC address")
+	errNotEUI64 = errors.New("not an EUI-64 based IPv6 address")
+)
+
+// LinkLocal is the link-local prefix fe80::/64.
+var LinkLocal = &net.IPNet{IP: net.ParseIP("fe80::"), Mask: net.CIDRMask(64, 128)}
+
+// EUI64 returns the modified EUI-64 interface identifier of the MAC address hw (see RFC 4291, Appendix A).
+func EUI64(hw net.HardwareAddr) ([]byte, error) {
+	if len(hw) != 6 {
+		return nil, errNotEUI48
+	}
+
+	id := []byte{hw[0] ^ 0x02, hw[1], hw[2], 0xff, 0xfe, hw[3], hw[4], hw[5]}
+	return id, nil
+}
+
+// EUI64Addr returns the IPv6 address, which consists of the /64 prefix n and the
+// modified EUI-64 interface identifier of the MAC address hw.
+func EUI64Addr(n *net.IPNet, hw net.HardwareAddr) (net.IP, error) {
+	if size, bits := n.Mask.Size(); size != 64 || bits != 128 {
+		return nil, errors.New("not an IPv6 /64 prefix: " + n.String())
+	}
+
+	id, err := EUI64(hw)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, n.IP.Mask(n.Mask))
+	copy(ip[8:], id)
+	return ip, nil
+}
+
+// FromEUI64 extracts the MAC address from an IPv6 address with modified EUI-64 interface identifier.
+func FromEUI64(ip net.IP) (net.HardwareAddr, error) {
+	if ip.To4() != nil || len(ip) != net.IPv6len || ip[11] != 0xff || ip[12] != 0xfe {
+		return nil, errNotEUI64
+	}
+	return net.HardwareAddr{ip[8] ^ 0x02, ip[9], ip[10], ip[13], ip[14], ip[15]}, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestEUI64(t *testing.T) {
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	id, err := mac.EUI64(hw)
+	NoError(t, err)
+	Equal(t, []byte{0x02, 0x11, 0x22, 0xff, 0xfe, 0x33, 0x44, 0x55}, id)
+
+	hw, _ = net.ParseMAC("00:11:22:33:44:55:66:77")
+	_, err = mac.EUI64(hw)
+	EqualError(t, err, "not an EUI-48 MAC address")
+}
+
+func TestEUI64Addr(t *testing.T) {
+	hw, _ := net.ParseMAC("02:11:22:33:44:55")
+	ip, err := mac.EUI64Addr(mac.LinkLocal, hw)
+	NoError(t, err)
+	Equal(t, "fe80::11:22ff:fe33:4455", ip.String())
+
+	_, n, _ := net.ParseCIDR("2001:db8:1:2::/64")
+	ip, err = mac.EUI64Addr(n, hw)
+	NoError(t, err)
+	Equal(t, "2001:db8:1:2:11:22ff:fe33:4455", ip.String())
+
+	_, n, _ = net.ParseCIDR("2001:db8::/48")
+	_, err = mac.EUI64Addr(n, hw)
+	EqualError(t, err, "not an IPv6 /64 prefix: 2001:db8::/48")
+}
+
+func TestFromEUI64(t *testing.T) {
+	hw, err := mac.FromEUI64(net.ParseIP("fe80::211:22ff:fe33:4455"))
+	NoError(t, err)
+	Equal(t, "00:11:22:33:44:55", hw.String())
+
+	_, err = mac.FromEUI64(net.ParseIP("fe80::1"))
+	EqualError(t, err, "not an EUI-64 based IPv6 address")
+	_, err = mac.FromEUI64(net.ParseIP("10.0.0.1"))
+	EqualError(t, err, "not an EUI-64 based IPv6 address")
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Broadcast is the Ethernet broadcast address ff:ff:ff:ff:ff:ff.
+var Broadcast = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// Format returns the MAC address hw in the given notation:
+// colon (00:11:22:33:44:55), dash (00-11-22-33-44-55), dot (0011.2233.4455) or bare (001122334455).
+func Format(hw net.HardwareAddr, notation string) (string, error) {
+	hex := fmt.Sprintf("%x", []byte(hw))
+	switch notation {
+	case "colon":
+		return hw.String(), nil
+	case "dash":
+		return strings.ReplaceAll(hw.String(), ":", "-"), nil
+	case "dot":
+		if len(hex)%4 != 0 {
+			return "", errors.New("dot notation requires an even number of octets: " + hw.String())
+		}
+		var parts []string
+		for i := 0; i < len(hex); i += 4 {
+			parts = append(parts, hex[i:i+4])
+		}
+		return strings.Join(parts, "."), nil
+	case "bare":
+		return hex, nil
+	default:
+		return "", errors.New("unsupported MAC address notation: " + notation)
+	}
+}
+
+// IsMulticast reports whether the individual/group bit of hw is set (the broadcast address included).
+func IsMulticast(hw net.HardwareAddr) bool {
+	return len(hw) > 0 && hw[0]&0x01 != 0
+}
+
+// IsLocal reports whether the universal/local bit of hw is set i.e., the address is locally administered.
+func IsLocal(hw net.HardwareAddr) bool {
+	return len(hw) > 0 && hw[0]&0x02 != 0
+}
+
+// IsBroadcast reports whether hw is the Ethernet broadcast address.
+func IsBroadcast(hw net.HardwareAddr) bool {
+	return hw.String() == Broadcast.String()
+}
+
+// Random returns a random locally administered unicast EUI-48 MAC address.
+// If r is nil, a cryptographically secure random number generator is used.
+func Random(r io.Reader) (net.HardwareAddr, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+
+	hw := make(net.HardwareAddr, 6)
+	if _, err := io.ReadFull(r, hw); err != nil {
+		return nil, err
+	}
+	hw[0] = hw[0]&^0x01 | 0x02
+	return hw, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	hw, _ := net.ParseMAC("0011.2233.44AA")
+	tests := []struct {
+		notation string
+		want     string
+	}{
+		{"colon", "00:11:22:33:44:aa"},
+		{"dash", "00-11-22-33-44-aa"},
+		{"dot", "0011.2233.44aa"},
+		{"bare", "0011223344aa"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.notation, func(t *testing.T) {
+			s, err := mac.Format(hw, tt.notation)
+			NoError(t, err)
+			Equal(t, tt.want, s)
+		})
+	}
+
+	_, err := mac.Format(hw, "cisco")
+	EqualError(t, err, "unsupported MAC address notation: cisco")
+}
+
+func TestBits(t *testing.T) {
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	False(t, mac.IsMulticast(hw))
+	False(t, mac.IsLocal(hw))
+
+	hw, _ = net.ParseMAC("01:00:5e:00:00:fb")
+	True(t, mac.IsMulticast(hw))
+	False(t, mac.IsBroadcast(hw))
+
+	hw, _ = net.ParseMAC("ff:ff:ff:ff:ff:ff")
+	True(t, mac.IsMulticast(hw))
+	True(t, mac.IsLocal(hw))
+	True(t, mac.IsBroadcast(hw))
+}
+
+func TestRandom(t *testing.T) {
+	hw, err := mac.Random(bytes.NewReader([]byte{0xff, 1, 2, 3, 4, 5}))
+	NoError(t, err)
+	Equal(t, "fe:01:02:03:04:05", hw.String())
+
+	hw, err = mac.Random(nil)
+	NoError(t, err)
+	True(t, mac.IsLocal(hw))
+	False(t, mac.IsMulticast(hw))
+
+	_, err = mac.Random(bytes.NewReader([]byte{1}))
+	Error(t, err)
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac
+
+import (
+	"errors"
+	"net"
+)
+
+// Multicast returns the Ethernet multicast MAC address of an IPv4 (RFC 1112) or IPv6 (RFC 2464) multicast group.
+func Multicast(ip net.IP) (net.HardwareAddr, error) {
+	if !ip.IsMulticast() {
+		return nil, errors.New("not a multicast address: " + ip.String())
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.HardwareAddr{0x01, 0x00, 0x5e, ip4[1] & 0x7f, ip4[2], ip4[3]}, nil
+	}
+	return net.HardwareAddr{0x33, 0x33, ip[12], ip[13], ip[14], ip[15]}, nil
+}
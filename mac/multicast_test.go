@@ -0,0 +1,47 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestMulticast(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"224.0.0.251", "01:00:5e:00:00:fb"},
+		{"239.255.255.250", "01:00:5e:7f:ff:fa"},
+		{"ff02::1", "33:33:00:00:00:01"},
+		{"ff02::1:ff33:4455", "33:33:ff:33:44:55"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.ip, func(t *testing.T) {
+			hw, err := mac.Multicast(net.ParseIP(tt.ip))
+			NoError(t, err)
+			Equal(t, tt.want, hw.String())
+		})
+	}
+
+	_, err := mac.Multicast(net.ParseIP("10.0.0.1"))
+	EqualError(t, err, "not a multicast address: 10.0.0.1")
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Vendors maps IEEE assignments (MA-L, MA-M and MA-S) in uppercase hexadecimal digits to organization names.
+type Vendors map[string]string
+
+// ParseVendors reads an IEEE registry in CSV format (e.g., oui.csv, mam.csv or oui36.csv).
+// The columns are "Registry", "Assignment", "Organization Name" and "Organization Address".
+func ParseVendors(r io.Reader) (Vendors, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	v := Vendors{}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return v, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("invalid IEEE registry entry in line %d", line)
+		}
+		if line == 1 && rec[1] == "Assignment" {
+			continue
+		}
+		v[strings.ToUpper(rec[1])] = strings.TrimSpace(rec[2])
+	}
+}
+
+// Lookup returns the organization, to which the longest matching assignment of hw is registered.
+func (v Vendors) Lookup(hw net.HardwareAddr) (string, bool) {
+	hex := fmt.Sprintf("%X", []byte(hw))
+	for _, n := range []int{9, 7, 6} {
+		if len(hex) >= n {
+			if org, ok := v[hex[:n]]; ok {
+				return org, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/mac"
+	. "github.com/stretchr/testify/require"
+)
+
+const registry = `Registry,Assignment,Organization Name,Organization Address
+MA-L,001122,"CIMSYS Inc","#301,Sinsung-clean BLDG,140, Nongseo-Ri,Kiheung-Eup Yongin-City Kyunggi-Do 449-711 KR "
+MA-L,70B3D5,IEEE Registration Authority,445 Hoes Lane Piscataway NJ US 08554
+MA-S,70B3D5F2F,"Acme, Inc.",Somewhere US
+`
+
+func TestVendors(t *testing.T) {
+	v, err := mac.ParseVendors(strings.NewReader(registry))
+	NoError(t, err)
+	Len(t, v, 3)
+
+	tests := []struct {
+		mac  string
+		want string
+		ok   bool
+	}{
+		{"00:11:22:33:44:55", "CIMSYS Inc", true},
+		{"70:b3:d5:f2:f0:01", "Acme, Inc.", true},
+		{"70:b3:d5:01:00:01", "IEEE Registration Authority", true},
+		{"02:00:00:00:00:01", "", false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.mac, func(t *testing.T) {
+			hw, _ := net.ParseMAC(tt.mac)
+			org, ok := v.Lookup(hw)
+			Equal(t, tt.ok, ok)
+			Equal(t, tt.want, org)
+		})
+	}
+
+	_, err = mac.ParseVendors(strings.NewReader("MA-L,001122\n"))
+	EqualError(t, err, "invalid IEEE registry entry in line 1")
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mtu calculates the usable payload sizes of tunnels and the MTU they require.
+// The payload is the largest inner packet (including its IP header), which can be sent without fragmentation.
+package mtu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// IPv4Header is the size of the outer IPv4 header without options.
+	IPv4Header = 20
+	// IPv6Header is the size of the outer IPv6 header without extension headers.
+	IPv6Header = 40
+)
+
+// Encapsulation describes the overhead, which a tunnel adds to the inner packet (apart from the outer IP header).
+type Encapsulation struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Header is the number of bytes added to every packet (headers, IVs, authentication tags, etc.).
+	Header int `json:"header"`
+	// Block is the block size, to which the inner packet plus Trailer is padded (0 if there is no padding).
+	Block int `json:"block,omitempty"`
+	// Trailer is the number of bytes appended to the inner packet before padding.
+	Trailer int `json:"trailer,omitempty"`
+}
+
+// Encapsulations lists the supported tunnels.
+var Encapsulations = []Encapsulation{
+	{"vxlan", "VXLAN (UDP 8 + VXLAN 8 + inner Ethernet 14)", 30, 0, 0},
+	{"geneve", "Geneve without options (UDP 8 + Geneve 8 + inner Ethernet 14)", 30, 0, 0},
+	{"gre", "GRE without key and sequence number", 4, 0, 0},
+	{"gretap", "GRE with inner Ethernet (GRE 4 + inner Ethernet 14)", 18, 0, 0},
+	{"ipip", "IP in IP", 0, 0, 0},
+	{"ipsec", "IPsec ESP tunnel mode with AES-GCM (ESP 8 + IV 8 + ICV 16, 4 byte alignment)", 32, 4, 2},
+	{"ipsec-nat-t", "IPsec ESP tunnel mode with AES-GCM and NAT traversal (UDP 8)", 40, 4, 2},
+	{"ipsec-cbc", "IPsec ESP tunnel mode with AES-CBC and HMAC-SHA-256 (ESP 8 + IV 16 + ICV 16)", 40, 16, 2},
+	{"wireguard", "WireGuard (UDP 8 + WireGuard 16 + tag 16, padding never exceeds the MTU)", 40, 0, 0},
+}
+
+// Lookup returns the Encapsulation with the given name.
+func Lookup(name string) (Encapsulation, error) {
+	for _, e := range Encapsulations {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return Encapsulation{}, errors.New("unsupported encapsulation: " + name)
+}
+
+// ipHeader returns the size of the outer IP header.
+func ipHeader(ipv6 bool) int {
+	if ipv6 {
+		return IPv6Header
+	}
+	return IPv4Header
+}
+
+// Payload returns the maximum size of the inner packet, which fits into a packet of the given MTU.
+func (e Encapsulation) Payload(mtu int, ipv6 bool) (int, error) {
+	n := mtu - ipHeader(ipv6) - e.Header
+	if e.Block > 0 {
+		n -= n % e.Block
+	}
+	if n -= e.Trailer; n <= 0 {
+		return 0, fmt.Errorf("MTU %d is too small for %s", mtu, e.Name)
+	}
+	return n, nil
+}
+
+// MTU returns the minimum MTU required for sending inner packets of the given size without fragmentation.
+func (e Encapsulation) MTU(payload int, ipv6 bool) (int, error) {
+	if payload <= 0 {
+		return 0, fmt.Errorf("invalid payload size: %d", payload)
+	}
+	n := payload + e.Trailer
+	if e.Block > 0 && n%e.Block != 0 {
+		n += e.Block - n%e.Block
+	}
+	return n + e.Header + ipHeader(ipv6), nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtu_test
+
+import (
+	"testing"
+
+	"github.com/abc-inc/terminus/mtu"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		ipv6 bool
+		mtu  int
+		want int
+	}{
+		{"vxlan", false, 1500, 1450},
+		{"vxlan", true, 1500, 1430},
+		{"vxlan", false, 9000, 8950},
+		{"geneve", false, 1500, 1450},
+		{"gre", false, 1500, 1476},
+		{"gre", true, 1500, 1456},
+		{"gretap", false, 1500, 1462},
+		{"ipip", false, 1500, 1480},
+		{"ipsec", false, 1500, 1446},
+		{"ipsec-nat-t", false, 1500, 1438},
+		{"ipsec-cbc", false, 1500, 1438},
+		{"ipsec-cbc", true, 1500, 1406},
+		{"wireguard", false, 1500, 1440},
+		{"wireguard", true, 1500, 1420},
+		{"wireguard", true, 1492, 1412},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := mtu.Lookup(tt.name)
+			NoError(t, err)
+			p, err := e.Payload(tt.mtu, tt.ipv6)
+			NoError(t, err)
+			Equal(t, tt.want, p)
+
+			// the inverse must not require more than the given MTU, and one byte more must not fit
+			m, err := e.MTU(p, tt.ipv6)
+			NoError(t, err)
+			LessOrEqual(t, m, tt.mtu)
+			m, err = e.MTU(p+1, tt.ipv6)
+			NoError(t, err)
+			Greater(t, m, tt.mtu)
+		})
+	}
+}
+
+func TestMTU(t *testing.T) {
+	e, _ := mtu.Lookup("VXLAN")
+	m, err := e.MTU(1500, false)
+	NoError(t, err)
+	Equal(t, 1550, m)
+
+	e, _ = mtu.Lookup("wireguard")
+	m, err = e.MTU(1500, true)
+	NoError(t, err)
+	Equal(t, 1580, m)
+
+	_, err = e.MTU(0, true)
+	EqualError(t, err, "invalid payload size: 0")
+}
+
+func TestErrors(t *testing.T) {
+	_, err := mtu.Lookup("pptp")
+	EqualError(t, err, "unsupported encapsulation: pptp")
+
+	e, _ := mtu.Lookup("ipsec")
+	_, err = e.Payload(54, false)
+	EqualError(t, err, "MTU 54 is too small for ipsec")
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan loads address plans, which are the single source of truth for the networks, reservations and
+// device addresses of an organization.
+//
+// An address plan is a YAML document with the following schema (all keys are optional):
+//
+//	name: campus                     # name of the plan
+//	description: Campus network      # human-readable description
+//	metadata: {owner: netops}        # arbitrary key-value pairs (at every level)
+//	supernets:                       # disjoint top-level networks
+//	  - cidr: 10.0.0.0/16            # network address in CIDR notation (required)
+//	    name: campus
+//	    description: ...
+//	    metadata: {site: vienna}
+//	    reservations:                # addresses, which must not be assigned
+//	      - cidr: 10.0.0.0/29        # CIDR or IP range (FROM-TO), within the network
+//	        name: gateways
+//	    children:                    # disjoint subnets, in the same format as supernets
+//	      - cidr: 10.0.1.0/24
+//	        name: servers
+//	devices:                         # planned addresses per device and interface
+//	  rtr1:
+//	    GigabitEthernet0/0: 10.0.1.1/24
+//	    Loopback0: [10.0.255.1/32, 2001:db8::1/128]
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+
+	"github.com/abc-inc/terminus/ipset"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan is an address plan.
+type Plan struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Supernets are the disjoint top-level networks.
+	Supernets []Network `json:"supernets,omitempty"`
+	// Devices are the planned addresses per device and interface.
+	Devices Devices `json:"devices,omitempty"`
+}
+
+// Network is a network of the plan, which can be subdivided into named children.
+type Network struct {
+	Prefix      netip.Prefix      `json:"cidr"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Reservations are the addresses of the network, which must not be assigned.
+	Reservations []Reservation `json:"reservations,omitempty"`
+	// Children are the disjoint subnets of the network in ascending order.
+	Children []Network `json:"children,omitempty"`
+}
+
+// Reservation is a range of addresses, which must not be assigned (e.g., gateways or DHCP pools).
+type Reservation struct {
+	Range       ipset.Range       `json:"range"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Devices maps the device names to the planned addresses of their interfaces.
+type Devices map[string]map[string][]netip.Prefix
+
+// document is the YAML representation of a plan.
+type document struct {
+	Name        string                          `yaml:"name"`
+	Description string                          `yaml:"description"`
+	Metadata    map[string]string               `yaml:"metadata"`
+	Supernets   []network                       `yaml:"supernets"`
+	Devices     map[string]map[string]yaml.Node `yaml:"devices"`
+}
+
+// network is the YAML representation of a network.
+type network struct {
+	CIDR         string            `yaml:"cidr"`
+	Name         string            `yaml:"name"`
+	Description  string            `yaml:"description"`
+	Metadata     map[string]string `yaml:"metadata"`
+	Reservations []reservation     `yaml:"reservations"`
+	Children     []network         `yaml:"children"`
+}
+
+// reservation is the YAML representation of a reservation.
+type reservation struct {
+	CIDR        string            `yaml:"cidr"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Metadata    map[string]string `yaml:"metadata"`
+}
+
+// Parse parses an address plan in YAML and validates it i.e., networks must be given by their network address,
+// children and reservations must be within their network, and siblings must not overlap.
+// Unknown keys are ignored, so that plans written for newer versions can still be loaded.
+func Parse(b []byte) (*Plan, error) {
+	return parse(b, false)
+}
+
+// ParseStrict is like Parse, but rejects unknown keys (e.g., typos).
+func ParseStrict(b []byte) (*Plan, error) {
+	return parse(b, true)
+}
+
+func parse(b []byte, strict bool) (*Plan, error) {
+	var doc document
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(strict)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	p := &Plan{Name: doc.Name, Description: doc.Description, Metadata: doc.Metadata}
+	ns, err := convertNetworks(doc.Supernets, netip.Prefix{})
+	if err != nil {
+		return nil, err
+	}
+	p.Supernets = ns
+
+	if p.Devices, err = convertDevices(doc.Devices); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// convertNetworks converts and validates the (sub)networks of the parent (invalid for supernets).
+func convertNetworks(ns []network, parent netip.Prefix) ([]Network, error) {
+	res := make([]Network, len(ns))
+	for i, n := range ns {
+		pfx, err := netip.ParsePrefix(n.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network: %q", n.CIDR)
+		} else if pfx != pfx.Masked() {
+			return nil, fmt.Errorf("%s: not a network address (%s)", pfx, pfx.Masked())
+		} else if parent.IsValid() && (!parent.Contains(pfx.Addr()) || pfx.Bits() <= parent.Bits()) {
+			return nil, fmt.Errorf("%s: not a subnet of %s", pfx, parent)
+		}
+
+		res[i] = Network{Prefix: pfx, Name: n.Name, Description: n.Description, Metadata: n.Metadata}
+		if res[i].Reservations, err = convertReservations(n.Reservations, pfx); err != nil {
+			return nil, fmt.Errorf("%s: %w", pfx, err)
+		}
+		if res[i].Children, err = convertNetworks(n.Children, pfx); err != nil {
+			return nil, fmt.Errorf("%s: %w", pfx, err)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return less(res[i].Prefix, res[j].Prefix) })
+	for i := 1; i < len(res); i++ {
+		if res[i-1].Prefix.Overlaps(res[i].Prefix) {
+			return nil, fmt.Errorf("%s overlaps %s", res[i].Prefix, res[i-1].Prefix)
+		}
+	}
+	return res, nil
+}
+
+// convertReservations converts the reservations of the network and validates that they are part of it.
+func convertReservations(ns []reservation, parent netip.Prefix) ([]Reservation, error) {
+	res := make([]Reservation, len(ns))
+	for i, n := range ns {
+		r, err := ipset.ParseRange(n.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reservation: %q", n.CIDR)
+		} else if !parent.Contains(r.From) || !parent.Contains(r.To) {
+			return nil, fmt.Errorf("reservation %s: not part of %s", n.CIDR, parent)
+		}
+		res[i] = Reservation{Range: r, Name: n.Name, Description: n.Description, Metadata: n.Metadata}
+	}
+	return res, nil
+}
+
+// convertDevices parses the planned addresses of the devices.
+// Addresses can be given as a single string or a list of strings.
+func convertDevices(devs map[string]map[string]yaml.Node) (Devices, error) {
+	ds := Devices{}
+	for dev, ifs := range devs {
+		ds[dev] = map[string][]netip.Prefix{}
+		for name, n := range ifs {
+			var ss []string
+			if n.Kind == yaml.ScalarNode {
+				ss = []string{n.Value}
+			} else if err := n.Decode(&ss); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", dev, name, err)
+			}
+			for _, s := range ss {
+				p, err := netip.ParsePrefix(s)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: invalid address: %s", dev, name, s)
+				}
+				ds[dev][name] = append(ds[dev][name], p)
+			}
+		}
+	}
+	return ds, nil
+}
+
+// Walk calls fn for every network of the plan in depth-first order.
+// The depth of supernets is 0. If fn returns false, the children of the network are skipped.
+func (p *Plan) Walk(fn func(n *Network, depth int) bool) {
+	var walk func(ns []Network, depth int)
+	walk = func(ns []Network, depth int) {
+		for i := range ns {
+			if fn(&ns[i], depth) {
+				walk(ns[i].Children, depth+1)
+			}
+		}
+	}
+	walk(p.Supernets, 0)
+}
+
+// Lookup returns the most specific network of the plan, which contains the IP address a.
+func (p *Plan) Lookup(a netip.Addr) (n *Network, ok bool) {
+	p.Walk(func(c *Network, _ int) bool {
+		if !c.Prefix.Contains(a) {
+			return false
+		}
+		n, ok = c, true
+		return true
+	})
+	return n, ok
+}
+
+// less orders IPv4 before IPv6 networks, then by address and prefix length.
+func less(a, b netip.Prefix) bool {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c < 0
+	}
+	return a.Bits() < b.Bits()
+}
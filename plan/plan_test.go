@@ -0,0 +1,141 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/plan"
+	. "github.com/stretchr/testify/require"
+)
+
+const campusYAML = `name: campus
+description: Campus network
+metadata: {owner: netops}
+supernets:
+  - cidr: 2001:db8::/48
+    name: campus-v6
+  - cidr: 10.0.0.0/16
+    name: campus
+    metadata: {site: vienna, vlan: 1}
+    reservations:
+      - cidr: 10.0.0.0/29
+        name: gateways
+      - cidr: 10.0.0.100-10.0.0.199
+        name: dhcp
+    children:
+      - cidr: 10.0.2.0/24
+        name: clients
+      - cidr: 10.0.1.0/24
+        name: servers
+        children:
+          - cidr: 10.0.1.0/26
+            name: db
+devices:
+  rtr1:
+    GigabitEthernet0/0: 10.0.1.1/24
+    Loopback0: [10.0.255.1/32, 2001:db8::1/128]
+`
+
+func TestParse(t *testing.T) {
+	p, err := plan.Parse([]byte(campusYAML))
+	NoError(t, err)
+	Equal(t, "campus", p.Name)
+	Equal(t, map[string]string{"owner": "netops"}, p.Metadata)
+
+	Len(t, p.Supernets, 2)
+	n := p.Supernets[0]
+	Equal(t, netip.MustParsePrefix("10.0.0.0/16"), n.Prefix)
+	Equal(t, map[string]string{"site": "vienna", "vlan": "1"}, n.Metadata)
+	Len(t, n.Reservations, 2)
+	Equal(t, "10.0.0.100-10.0.0.199", n.Reservations[1].Range.String())
+	Equal(t, []string{"servers", "clients"}, []string{n.Children[0].Name, n.Children[1].Name})
+	Equal(t, "db", n.Children[0].Children[0].Name)
+
+	Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.255.1/32"), netip.MustParsePrefix("2001:db8::1/128")},
+		p.Devices["rtr1"]["Loopback0"])
+}
+
+func TestParseEmpty(t *testing.T) {
+	p, err := plan.Parse(nil)
+	NoError(t, err)
+	Empty(t, p.Supernets)
+	Empty(t, p.Devices)
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{"supernets:\n  - cidr: 10.0.0.0/33\n", `invalid network: "10.0.0.0/33"`},
+		{"supernets:\n  - cidr: 10.0.0.1/8\n", "10.0.0.1/8: not a network address (10.0.0.0/8)"},
+		{"supernets:\n  - cidr: 10.0.0.0/8\n  - cidr: 10.1.0.0/16\n", "10.1.0.0/16 overlaps 10.0.0.0/8"},
+		{"supernets:\n  - cidr: 10.0.0.0/16\n    children:\n      - cidr: 10.1.0.0/24\n",
+			"10.0.0.0/16: 10.1.0.0/24: not a subnet of 10.0.0.0/16"},
+		{"supernets:\n  - cidr: 10.0.0.0/16\n    children:\n      - cidr: 10.0.0.0/16\n",
+			"10.0.0.0/16: 10.0.0.0/16: not a subnet of 10.0.0.0/16"},
+		{"supernets:\n  - cidr: 10.0.0.0/24\n    reservations:\n      - cidr: 10.0.0.250-10.0.1.5\n",
+			"10.0.0.0/24: reservation 10.0.0.250-10.0.1.5: not part of 10.0.0.0/24"},
+		{"supernets:\n  - cidr: 10.0.0.0/24\n    reservations:\n      - cidr: gateway\n",
+			`10.0.0.0/24: invalid reservation: "gateway"`},
+		{"devices:\n  rtr1:\n    eth0: 192.0.2.1\n", "rtr1 eth0: invalid address: 192.0.2.1"},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := plan.Parse([]byte(tt.yaml))
+			EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseUnknownKeys(t *testing.T) {
+	yml := []byte("supernet:\n  - cidr: 10.0.0.0/8\n")
+	p, err := plan.Parse(yml)
+	NoError(t, err)
+	Empty(t, p.Supernets)
+
+	_, err = plan.ParseStrict(yml)
+	EqualError(t, err, "yaml: unmarshal errors:\n  line 1: field supernet not found in type plan.document")
+
+	yml = []byte("supernets:\n  - cidr: 10.0.0.0/24\n    reservations:\n      - cidr: 10.0.0.0/29\n" +
+		"        children:\n          - cidr: 10.0.0.0/30\n")
+	_, err = plan.ParseStrict(yml)
+	EqualError(t, err, "yaml: unmarshal errors:\n  line 5: field children not found in type plan.reservation")
+}
+
+func TestWalkAndLookup(t *testing.T) {
+	p, err := plan.Parse([]byte(campusYAML))
+	NoError(t, err)
+
+	var names []string
+	p.Walk(func(n *plan.Network, depth int) bool {
+		names = append(names, n.Name)
+		return depth < 1
+	})
+	Equal(t, []string{"campus", "servers", "clients", "campus-v6"}, names)
+
+	n, ok := p.Lookup(netip.MustParseAddr("10.0.1.10"))
+	True(t, ok)
+	Equal(t, "db", n.Name)
+	n, ok = p.Lookup(netip.MustParseAddr("10.0.3.1"))
+	True(t, ok)
+	Equal(t, "campus", n.Name)
+	_, ok = p.Lookup(netip.MustParseAddr("192.0.2.1"))
+	False(t, ok)
+}
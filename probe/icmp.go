@@ -0,0 +1,147 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// ErrNotPermitted is returned if no ICMP socket can be opened (e.g., without root privileges).
+var ErrNotPermitted = errors.New("ICMP not permitted")
+
+// seq is the sequence number of the last echo request.
+var seq atomic.Uint32
+
+// echoData is the payload of echo requests.
+var echoData = []byte("terminus")
+
+// Ping sends an ICMP echo request to ip and returns the round-trip time of the reply.
+// Raw sockets are used if the process is privileged. Otherwise, unprivileged ICMP sockets are tried (e.g., on Linux
+// if permitted by net.ipv4.ping_group_range). If neither is available, the error wraps ErrNotPermitted.
+// If there is no reply before ctx is done, a timeout error (or the error of ctx, if it is canceled) is returned.
+func Ping(ctx context.Context, ip netip.Addr) (time.Duration, error) {
+	ip = ip.Unmap()
+	c, dst, err := listen(ip)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = c.Close() }()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = c.SetDeadline(dl)
+	}
+
+	// closing the socket unblocks ReadFrom if ctx is canceled before the deadline
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = c.Close()
+		case <-done:
+		}
+	}()
+
+	var typ, reply icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	proto := 1
+	if ip.Is6() {
+		typ, reply, proto = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, 58
+	}
+	id, sq := os.Getpid()&0xffff, int(seq.Add(1)&0xffff)
+	b, err := (&icmp.Message{Type: typ, Body: &icmp.Echo{ID: id, Seq: sq, Data: echoData}}).Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := c.WriteTo(b, dst); err != nil {
+		return 0, err
+	}
+
+	_, datagram := dst.(*net.UDPAddr)
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := c.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+				// the socket was closed, because ctx is canceled
+				return 0, ctx.Err()
+			}
+			return 0, err
+		}
+		m, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || m.Type != reply || !isFrom(peer, ip) {
+			continue
+		}
+		// unprivileged sockets replace the ID by the local port and receive their own replies only
+		if echo, ok := m.Body.(*icmp.Echo); ok && echo.Seq == sq && (datagram || echo.ID == id) {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// CanPing reports whether ICMP echo requests can be sent to IPv4 or IPv6 addresses.
+func CanPing(ipv6 bool) bool {
+	ip := netip.IPv6Unspecified()
+	if !ipv6 {
+		ip = netip.IPv4Unspecified()
+	}
+	c, _, err := listen(ip)
+	if err == nil {
+		_ = c.Close()
+	}
+	return err == nil
+}
+
+// listen opens a raw ICMP socket, or an unprivileged one if the former is not permitted, and returns it along with
+// the destination address.
+func listen(ip netip.Addr) (*icmp.PacketConn, net.Addr, error) {
+	network, datagram, laddr := "ip4:icmp", "udp4", "0.0.0.0"
+	if ip.Is6() {
+		network, datagram, laddr = "ip6:ipv6-icmp", "udp6", "::"
+	}
+
+	if c, err := icmp.ListenPacket(network, laddr); err == nil {
+		return c, &net.IPAddr{IP: ip.AsSlice(), Zone: ip.Zone()}, nil
+	}
+	c, err := icmp.ListenPacket(datagram, laddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrNotPermitted, err)
+	}
+	return c, &net.UDPAddr{IP: ip.AsSlice(), Zone: ip.Zone()}, nil
+}
+
+// isFrom reports whether the peer address refers to ip.
+func isFrom(peer net.Addr, ip netip.Addr) bool {
+	var b net.IP
+	switch a := peer.(type) {
+	case *net.IPAddr:
+		b = a.IP
+	case *net.UDPAddr:
+		b = a.IP
+	}
+	a, ok := netip.AddrFromSlice(b)
+	return ok && a.Unmap() == ip.WithZone("")
+}
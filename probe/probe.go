@@ -0,0 +1,98 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probe checks the reachability of hosts and ports via ICMP echo requests, TCP connections and UDP datagrams.
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// State is the result of a reachability check of a port.
+type State string
+
+const (
+	// Open means that the port accepted the connection (or responded to the UDP datagram).
+	Open State = "open"
+	// Closed means that the host refused the connection (or sent an ICMP port unreachable), which proves that the
+	// host is reachable.
+	Closed State = "closed"
+	// Filtered means that there was no response within the timeout.
+	Filtered State = "filtered"
+	// OpenFiltered means that a UDP port did not respond, which is the case for filtered ports as well as for open
+	// ports of many services, which ignore unexpected datagrams.
+	OpenFiltered State = "open|filtered"
+)
+
+// TCP connects to addr and returns the state of the port along with the round-trip time of the handshake.
+// If ctx is done before the handshake completes, the port is Filtered.
+// Other errors (e.g., no route to host) are returned along with Filtered.
+func TCP(ctx context.Context, addr netip.AddrPort) (State, time.Duration, error) {
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr.String())
+	rtt := time.Since(start)
+	switch {
+	case err == nil:
+		_ = conn.Close()
+		return Open, rtt, nil
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return Closed, rtt, nil
+	case isTimeout(err) || ctx.Err() != nil:
+		return Filtered, 0, nil
+	default:
+		return Filtered, 0, err
+	}
+}
+
+// UDP sends the payload to addr and waits for a response until ctx is done.
+// It returns the state of the port along with the round-trip time of the response.
+// Other errors (e.g., no route to host) are returned along with Filtered.
+func UDP(ctx context.Context, addr netip.AddrPort, payload []byte) (State, time.Duration, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "udp", addr.String())
+	if err != nil {
+		return Filtered, 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+
+	start := time.Now()
+	if _, err = conn.Write(payload); err == nil {
+		_, err = conn.Read(make([]byte, 1500))
+	}
+	rtt := time.Since(start)
+	switch {
+	case err == nil:
+		return Open, rtt, nil
+	case errors.Is(err, syscall.ECONNREFUSED):
+		// ICMP port unreachable
+		return Closed, rtt, nil
+	case isTimeout(err) || ctx.Err() != nil:
+		return OpenFiltered, 0, nil
+	default:
+		return Filtered, 0, err
+	}
+}
+
+// isTimeout reports whether err is caused by a timeout.
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &ne) && ne.Timeout()
+}
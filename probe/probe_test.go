@@ -0,0 +1,113 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/abc-inc/terminus/probe"
+	. "github.com/stretchr/testify/require"
+)
+
+func TestTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	NoError(t, err)
+	addr := netip.MustParseAddrPort(l.Addr().String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	state, rtt, err := probe.TCP(ctx, addr)
+	NoError(t, err)
+	Equal(t, probe.Open, state)
+	Positive(t, rtt)
+
+	// without listener, the connection is refused
+	NoError(t, l.Close())
+	state, _, err = probe.TCP(ctx, addr)
+	NoError(t, err)
+	Equal(t, probe.Closed, state)
+}
+
+func TestTCPTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	state, _, err := probe.TCP(ctx, netip.MustParseAddrPort("192.0.2.1:443"))
+	NoError(t, err)
+	Equal(t, probe.Filtered, state)
+}
+
+func TestUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	NoError(t, err)
+	addr := netip.MustParseAddrPort(conn.LocalAddr().String())
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, peer, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if string(buf[:n]) == "ping" {
+				_, _ = conn.WriteTo([]byte("pong"), peer)
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	state, rtt, err := probe.UDP(ctx, addr, []byte("ping"))
+	NoError(t, err)
+	Equal(t, probe.Open, state)
+	Positive(t, rtt)
+
+	state, _, err = probe.UDP(ctx, addr, []byte("unexpected"))
+	NoError(t, err)
+	Equal(t, probe.OpenFiltered, state)
+
+	// without listener, the host responds with ICMP port unreachable
+	NoError(t, conn.Close())
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	state, _, err = probe.UDP(ctx, addr, nil)
+	NoError(t, err)
+	Equal(t, probe.Closed, state)
+}
+
+func TestPing(t *testing.T) {
+	if !probe.CanPing(false) {
+		t.Skip("ICMP not permitted")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rtt, err := probe.Ping(ctx, netip.MustParseAddr("127.0.0.1"))
+	NoError(t, err)
+	Positive(t, rtt)
+}
+
+func TestPingCanceled(t *testing.T) {
+	if !probe.CanPing(false) {
+		t.Skip("ICMP not permitted")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := probe.Ping(ctx, netip.MustParseAddr("198.51.100.1"))
+	ErrorIs(t, err, context.Canceled)
+	Less(t, time.Since(start), 5*time.Second)
+}
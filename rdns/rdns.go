@@ -0,0 +1,176 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rdns derives the reverse DNS zones, which are needed to cover a set of networks.
+package rdns
+
+import (
+	"bufio"
+	"bytes"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Zone is a reverse DNS zone.
+type Zone struct {
+	// Name is the domain name of the zone (without trailing dot).
+	Name string `json:"name"`
+	// Prefix is the network covered by the zone.
+	Prefix netip.Prefix `json:"prefix"`
+	// Parent is the zone, which delegates a classless zone according to RFC 2317 (empty otherwise).
+	Parent string `json:"parent,omitempty"`
+}
+
+// Zones returns the reverse DNS zones, which cover the given (disjoint) networks.
+// IPv4 networks are split at octet boundaries and networks longer than /24 become RFC 2317 delegations
+// (e.g., 0/26.2.0.192.in-addr.arpa). IPv6 networks are split at nibble boundaries.
+func Zones(ps []netip.Prefix) []Zone {
+	var zs []Zone
+	for _, p := range ps {
+		p = p.Masked()
+		step := 4
+		if p.Addr().Is4() {
+			step = 8
+		}
+
+		if p.Addr().Is4() && p.Bits() > 24 {
+			parent := netip.PrefixFrom(p.Addr(), 24).Masked()
+			last := p.Addr().As4()[3]
+			name := strconv.Itoa(int(last)) + "/" + strconv.Itoa(p.Bits()) + "." + Name(parent)
+			zs = append(zs, Zone{Name: name, Prefix: p, Parent: Name(parent)})
+			continue
+		}
+
+		bits := (p.Bits() + step - 1) / step * step
+		if bits == 0 {
+			bits = step
+		}
+		for _, sub := range split(p, bits) {
+			zs = append(zs, Zone{Name: Name(sub), Prefix: sub})
+		}
+	}
+	return zs
+}
+
+// Name returns the name of the reverse DNS zone of p, whose length must be a multiple of 8 (IPv4) or 4 (IPv6).
+func Name(p netip.Prefix) string {
+	a := p.Addr()
+	var labels []string
+	if a.Is4() {
+		b := a.As4()
+		for i := p.Bits()/8 - 1; i >= 0; i-- {
+			labels = append(labels, strconv.Itoa(int(b[i])))
+		}
+		return strings.Join(append(labels, "in-addr", "arpa"), ".")
+	}
+
+	b := a.As16()
+	for i := p.Bits()/4 - 1; i >= 0; i-- {
+		n := b[i/2] >> 4
+		if i%2 == 1 {
+			n = b[i/2] & 0x0f
+		}
+		labels = append(labels, strconv.FormatUint(uint64(n), 16))
+	}
+	return strings.Join(append(labels, "ip6", "arpa"), ".")
+}
+
+// split returns the subnets of p with the given prefix length.
+func split(p netip.Prefix, bits int) []netip.Prefix {
+	start := new(big.Int).SetBytes(p.Addr().AsSlice())
+	step := new(big.Int).Lsh(big.NewInt(1), uint(p.Addr().BitLen()-bits))
+	ps := make([]netip.Prefix, 1<<(bits-p.Bits()))
+	for i := range ps {
+		buf := new(big.Int).Add(start, new(big.Int).Mul(step, big.NewInt(int64(i)))).
+			FillBytes(make([]byte, p.Addr().BitLen()/8))
+		a, _ := netip.AddrFromSlice(buf)
+		ps[i] = netip.PrefixFrom(a, bits)
+	}
+	return ps
+}
+
+// zoneStmt matches the zone statements of a named.conf file.
+var zoneStmt = regexp.MustCompile(`(?m)^\s*zone\s+"([^"]+)"`)
+
+// ParseInventory returns the names of the zones declared in a named.conf file.
+// If there is no zone statement, every line is considered a zone name (skipping empty lines and comments).
+// Names are converted to lower case and trailing dots are removed.
+func ParseInventory(b []byte) []string {
+	var names []string
+	for _, m := range zoneStmt.FindAllSubmatch(b, -1) {
+		names = append(names, normalize(string(m[1])))
+	}
+	if names != nil {
+		return names
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		l := sc.Text()
+		if i := strings.IndexAny(l, "#;"); i >= 0 {
+			l = l[:i]
+		}
+		if fs := strings.Fields(l); len(fs) > 0 {
+			names = append(names, normalize(fs[0]))
+		}
+	}
+	return names
+}
+
+// Diff compares the required zones with the zones of an inventory.
+// A zone is missing unless the inventory contains the zone itself or a parent zone.
+// A reverse zone of the inventory is obsolete if it is neither a parent nor a child of any required zone.
+func Diff(zs []Zone, inventory []string) (missing []Zone, obsolete []string) {
+	for _, z := range zs {
+		found := false
+		for _, n := range inventory {
+			found = found || isSubdomain(z.Name, n)
+		}
+		if !found {
+			missing = append(missing, z)
+		}
+	}
+
+	for _, n := range inventory {
+		if !IsReverse(n) {
+			continue
+		}
+		used := false
+		for _, z := range zs {
+			used = used || isSubdomain(z.Name, n) || isSubdomain(n, z.Name)
+		}
+		if !used {
+			obsolete = append(obsolete, n)
+		}
+	}
+	return missing, obsolete
+}
+
+// IsReverse reports whether name is a reverse DNS zone (in-addr.arpa or ip6.arpa).
+func IsReverse(name string) bool {
+	name = normalize(name)
+	return isSubdomain(name, "in-addr.arpa") || isSubdomain(name, "ip6.arpa")
+}
+
+// isSubdomain reports whether name is equal to or below the domain parent.
+func isSubdomain(name, parent string) bool {
+	return name == parent || strings.HasSuffix(name, "."+parent)
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdns_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/rdns"
+	. "github.com/stretchr/testify/require"
+)
+
+func names(zs []rdns.Zone) (ns []string) {
+	for _, z := range zs {
+		ns = append(ns, z.Name)
+	}
+	return ns
+}
+
+func TestZones(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"10.0.0.0/8", []string{"10.in-addr.arpa"}},
+		{"192.0.2.0/24", []string{"2.0.192.in-addr.arpa"}},
+		{"198.51.100.0/23", []string{"100.51.198.in-addr.arpa", "101.51.198.in-addr.arpa"}},
+		{"192.0.2.64/26", []string{"64/26.2.0.192.in-addr.arpa"}},
+		{"192.0.2.9/32", []string{"9/32.2.0.192.in-addr.arpa"}},
+		{"2001:db8::/32", []string{"8.b.d.0.1.0.0.2.ip6.arpa"}},
+		{"2001:db8:aa00::/39", []string{"a.a.8.b.d.0.1.0.0.2.ip6.arpa", "b.a.8.b.d.0.1.0.0.2.ip6.arpa"}},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.prefix, func(t *testing.T) {
+			Equal(t, tt.want, names(rdns.Zones([]netip.Prefix{netip.MustParsePrefix(tt.prefix)})))
+		})
+	}
+}
+
+func TestZonesDelegation(t *testing.T) {
+	zs := rdns.Zones([]netip.Prefix{netip.MustParsePrefix("192.0.2.128/25")})
+	Equal(t, []rdns.Zone{{
+		Name:   "128/25.2.0.192.in-addr.arpa",
+		Prefix: netip.MustParsePrefix("192.0.2.128/25"),
+		Parent: "2.0.192.in-addr.arpa",
+	}}, zs)
+}
+
+func TestParseInventory(t *testing.T) {
+	conf := `options { directory "/var/named"; };
+zone "2.0.192.IN-ADDR.ARPA." {
+	type master;
+	file "db.192.0.2";
+};
+  zone "example.com" { type master; file "db.example.com"; };
+`
+	Equal(t, []string{"2.0.192.in-addr.arpa", "example.com"}, rdns.ParseInventory([]byte(conf)))
+	Equal(t, []string{"10.in-addr.arpa", "2.0.192.in-addr.arpa"},
+		rdns.ParseInventory([]byte("# zones\n10.in-addr.arpa.\n\n2.0.192.in-addr.arpa ; lab\n")))
+}
+
+func TestDiff(t *testing.T) {
+	zs := rdns.Zones([]netip.Prefix{
+		netip.MustParsePrefix("10.1.0.0/16"),
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("198.51.100.0/26"),
+	})
+	inv := []string{"10.in-addr.arpa", "0/26.100.51.198.in-addr.arpa", "113.0.203.in-addr.arpa", "example.com"}
+
+	missing, obsolete := rdns.Diff(zs, inv)
+	Equal(t, []string{"2.0.192.in-addr.arpa"}, names(missing))
+	Equal(t, []string{"113.0.203.in-addr.arpa"}, obsolete)
+}
+
+func TestIsReverse(t *testing.T) {
+	True(t, rdns.IsReverse("2.0.192.in-addr.arpa."))
+	True(t, rdns.IsReverse("8.B.D.0.1.0.0.2.ip6.arpa"))
+	False(t, rdns.IsReverse("example.com"))
+	False(t, rdns.IsReverse("notin-addr.arpa"))
+}
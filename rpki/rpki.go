@@ -0,0 +1,343 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpki performs the route origin validation (RFC 6811) of BGP routes.
+// The validated ROA payloads (VRPs) are fetched from an RPKI cache via the RPKI-to-Router protocol (RFC 8210)
+// or read from a JSON export (as produced by Routinator, rpki-client, etc.). Alternatively, the validity of a route
+// can be queried from the HTTP API of Routinator.
+package rpki
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/abc-inc/terminus/asn"
+)
+
+// State is the validation state of a route.
+type State string
+
+// Validation states as defined in RFC 6811 (and named by Routinator).
+const (
+	Valid    State = "valid"
+	Invalid  State = "invalid"
+	NotFound State = "not-found"
+)
+
+// VRP is a validated ROA payload i.e., an AS, which is authorized to originate a prefix up to a maximum length.
+type VRP struct {
+	ASN       uint32       `json:"asn"`
+	Prefix    netip.Prefix `json:"prefix"`
+	MaxLength int          `json:"maxLength"`
+}
+
+func (v VRP) String() string {
+	return fmt.Sprintf("%s-%d AS%d", v.Prefix, v.MaxLength, v.ASN)
+}
+
+// covers reports whether the prefix of the VRP covers (i.e., is equal to or less specific than) p.
+func (v VRP) covers(p netip.Prefix) bool {
+	return v.Prefix.Bits() <= p.Bits() && v.Prefix.Contains(p.Addr())
+}
+
+// Result is the outcome of the route origin validation.
+type Result struct {
+	Prefix netip.Prefix `json:"prefix"`
+	ASN    uint32       `json:"asn"`
+	State  State        `json:"state"`
+	// Reason is "as" or "length" if the route is invalid.
+	Reason string `json:"reason,omitempty"`
+	// Matched lists the VRPs, which the route matches.
+	Matched []VRP `json:"matched,omitempty"`
+	// Unmatched lists the covering VRPs, which authorize a different AS or a shorter prefix.
+	Unmatched []VRP `json:"unmatched,omitempty"`
+}
+
+// Validate determines the validation state of the route to p originated by asn.
+// A route is valid if a covering VRP authorizes the origin AS (other than AS 0) and the prefix length.
+// It is invalid if there are covering VRPs, but none of them matches, and not found otherwise.
+func Validate(vrps []VRP, p netip.Prefix, asn uint32) Result {
+	p = p.Masked()
+	r := Result{Prefix: p, ASN: asn, State: NotFound}
+	asMatch := false
+	for _, v := range vrps {
+		if !v.covers(p) {
+			continue
+		}
+		if v.ASN == asn && asn != 0 {
+			asMatch = true
+			if p.Bits() <= v.MaxLength {
+				r.Matched = append(r.Matched, v)
+				continue
+			}
+		}
+		r.Unmatched = append(r.Unmatched, v)
+	}
+
+	switch {
+	case len(r.Matched) > 0:
+		r.State = Valid
+	case asMatch:
+		r.State, r.Reason = Invalid, "length"
+	case len(r.Unmatched) > 0:
+		r.State, r.Reason = Invalid, "as"
+	}
+	return r
+}
+
+// ReadJSON reads VRPs from a JSON export, which contains an array "roas" of objects with the keys "asn"
+// (e.g., "AS64496" or 64496), "prefix" and "maxLength" (such as the jsonext format of Routinator).
+func ReadJSON(r io.Reader) ([]VRP, error) {
+	var doc struct {
+		ROAs []struct {
+			ASN       json.RawMessage `json:"asn"`
+			Prefix    string          `json:"prefix"`
+			MaxLength int             `json:"maxLength"`
+		} `json:"roas"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	vrps := make([]VRP, len(doc.ROAs))
+	for i, roa := range doc.ROAs {
+		n, err := asn.ParseASN(strings.Trim(string(roa.ASN), `"`))
+		if err != nil {
+			return nil, err
+		}
+		p, err := netip.ParsePrefix(roa.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		vrps[i] = VRP{n, p.Masked(), roa.MaxLength}
+	}
+	return vrps, nil
+}
+
+// PDU types of the RPKI-to-Router protocol.
+const (
+	pduCacheResponse = 3
+	pduIPv4Prefix    = 4
+	pduIPv6Prefix    = 6
+	pduEndOfData     = 7
+	pduCacheReset    = 8
+	pduRouterKey     = 9
+	pduErrorReport   = 10
+	pduResetQuery    = 2
+)
+
+// errUnsupportedVersion is reported by caches, which do not support the requested protocol version.
+const errUnsupportedVersion = 4
+
+// FetchRTR retrieves all VRPs from an RPKI cache (e.g., "rtr.example.com:323") via the RPKI-to-Router protocol.
+// Version 1 (RFC 8210) is requested and version 0 (RFC 6810) is used if the cache does not support version 1.
+func FetchRTR(ctx context.Context, addr string) ([]VRP, error) {
+	vrps, err := fetchRTR(ctx, addr, 1)
+	var re *rtrError
+	if errors.As(err, &re) && re.code == errUnsupportedVersion {
+		return fetchRTR(ctx, addr, 0)
+	}
+	return vrps, err
+}
+
+// rtrError is an Error Report PDU sent by the cache.
+type rtrError struct {
+	code uint16
+	text string
+}
+
+func (e *rtrError) Error() string {
+	return fmt.Sprintf("RTR error %d: %s", e.code, e.text)
+}
+
+func fetchRTR(ctx context.Context, addr string, version byte) ([]VRP, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+
+	if _, err := conn.Write([]byte{version, pduResetQuery, 0, 0, 0, 0, 0, 8}); err != nil {
+		return nil, err
+	}
+	return readRTR(bufio.NewReader(conn))
+}
+
+// readRTR reads the PDUs of a cache response up to the End of Data PDU.
+func readRTR(r io.Reader) ([]VRP, error) {
+	var vrps []VRP
+	hdr := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			return nil, err
+		}
+		n := binary.BigEndian.Uint32(hdr[4:])
+		if n < 8 || n > 1<<16 {
+			return nil, fmt.Errorf("invalid RTR PDU length: %d", n)
+		}
+		body := make([]byte, n-8)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, err
+		}
+
+		switch hdr[1] {
+		case pduCacheResponse, pduRouterKey:
+		case pduIPv4Prefix, pduIPv6Prefix:
+			v, announce, err := parsePrefixPDU(hdr[1], body)
+			if err != nil {
+				return nil, err
+			}
+			if announce {
+				vrps = append(vrps, v)
+			}
+		case pduEndOfData:
+			return vrps, nil
+		case pduCacheReset:
+			return nil, errors.New("RTR cache has no data available")
+		case pduErrorReport:
+			return nil, parseErrorPDU(binary.BigEndian.Uint16(hdr[2:]), body)
+		default:
+			return nil, fmt.Errorf("unexpected RTR PDU type: %d", hdr[1])
+		}
+	}
+}
+
+// parsePrefixPDU parses the body of an IPv4 or IPv6 Prefix PDU.
+func parsePrefixPDU(typ byte, b []byte) (VRP, bool, error) {
+	addrLen := 4
+	if typ == pduIPv6Prefix {
+		addrLen = 16
+	}
+	if len(b) != 4+addrLen+4 {
+		return VRP{}, false, errors.New("invalid RTR prefix PDU")
+	}
+
+	a, _ := netip.AddrFromSlice(b[4 : 4+addrLen])
+	p, err := a.Prefix(int(b[1]))
+	if err != nil || int(b[2]) < p.Bits() || int(b[2]) > a.BitLen() {
+		return VRP{}, false, errors.New("invalid RTR prefix PDU")
+	}
+	return VRP{binary.BigEndian.Uint32(b[4+addrLen:]), p, int(b[2])}, b[0]&1 == 1, nil
+}
+
+// parseErrorPDU parses the body of an Error Report PDU.
+func parseErrorPDU(code uint16, b []byte) error {
+	e := &rtrError{code: code}
+	if len(b) >= 4 {
+		if n := int(binary.BigEndian.Uint32(b)); len(b) >= 8+n {
+			if m := int(binary.BigEndian.Uint32(b[4+n:])); len(b) >= 8+n+m {
+				e.text = string(b[8+n : 8+n+m])
+			}
+		}
+	}
+	return e
+}
+
+// LookupRoutinator queries the validity of a route from the HTTP API of Routinator (e.g., "http://localhost:8323").
+func LookupRoutinator(ctx context.Context, client *http.Client, baseURL string, p netip.Prefix, asn uint32) (
+	Result, error,
+) {
+	p = p.Masked()
+	u := fmt.Sprintf("%s/api/v1/validity/AS%d/%s", strings.TrimSuffix(baseURL, "/"), asn, p)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return Result{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("cannot fetch %s: %s", u, resp.Status)
+	}
+	return parseRoutinator(resp.Body, p, asn)
+}
+
+// routinatorVRP is a VRP in the validity response of Routinator.
+type routinatorVRP struct {
+	ASN    string `json:"asn"`
+	Prefix string `json:"prefix"`
+	// MaxLength is a string (but a number is accepted as well).
+	MaxLength json.Number `json:"max_length"`
+}
+
+// parseRoutinator parses the validity response of Routinator.
+func parseRoutinator(r io.Reader, p netip.Prefix, asn uint32) (Result, error) {
+	var doc struct {
+		ValidatedRoute struct {
+			Validity struct {
+				State  string `json:"state"`
+				Reason string `json:"reason"`
+				VRPs   struct {
+					Matched         []routinatorVRP `json:"matched"`
+					UnmatchedAS     []routinatorVRP `json:"unmatched_as"`
+					UnmatchedLength []routinatorVRP `json:"unmatched_length"`
+				} `json:"VRPs"`
+			} `json:"validity"`
+		} `json:"validated_route"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Result{}, err
+	}
+
+	v := doc.ValidatedRoute.Validity
+	res := Result{Prefix: p, ASN: asn, State: State(v.State), Reason: v.Reason}
+	switch res.State {
+	case Valid, Invalid, NotFound:
+	default:
+		return Result{}, errors.New("invalid validation state: " + v.State)
+	}
+
+	var err error
+	if res.Matched, err = toVRPs(v.VRPs.Matched); err != nil {
+		return Result{}, err
+	}
+	if res.Unmatched, err = toVRPs(append(v.VRPs.UnmatchedAS, v.VRPs.UnmatchedLength...)); err != nil {
+		return Result{}, err
+	}
+	return res, nil
+}
+
+func toVRPs(rvs []routinatorVRP) ([]VRP, error) {
+	var vrps []VRP
+	for _, rv := range rvs {
+		n, err := asn.ParseASN(rv.ASN)
+		if err != nil {
+			return nil, err
+		}
+		p, err := netip.ParsePrefix(rv.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		l, err := rv.MaxLength.Int64()
+		if err != nil {
+			return nil, err
+		}
+		vrps = append(vrps, VRP{n, p, int(l)})
+	}
+	return vrps, nil
+}
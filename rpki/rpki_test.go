@@ -0,0 +1,179 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpki_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/rpki"
+	. "github.com/stretchr/testify/require"
+)
+
+var vrps = []rpki.VRP{
+	{64500, netip.MustParsePrefix("203.0.113.0/24"), 24},
+	{64501, netip.MustParsePrefix("198.51.100.0/22"), 24},
+	{0, netip.MustParsePrefix("192.0.2.0/24"), 24},
+	{64500, netip.MustParsePrefix("2001:db8::/32"), 48},
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		route  string
+		asn    uint32
+		state  rpki.State
+		reason string
+	}{
+		{"203.0.113.0/24", 64500, rpki.Valid, ""},
+		{"203.0.113.0/24", 64501, rpki.Invalid, "as"},
+		{"203.0.113.128/25", 64500, rpki.Invalid, "length"},
+		{"198.51.100.0/23", 64501, rpki.Valid, ""},
+		{"198.51.100.0/21", 64501, rpki.NotFound, ""},
+		{"192.0.2.0/24", 0, rpki.Invalid, "as"},
+		{"2001:db8:1::/48", 64500, rpki.Valid, ""},
+		{"2001:db8:1::/64", 64500, rpki.Invalid, "length"},
+		{"::ffff:203.0.113.0/120", 64500, rpki.NotFound, ""},
+		{"10.0.0.0/8", 64500, rpki.NotFound, ""},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.route, func(t *testing.T) {
+			r := rpki.Validate(vrps, netip.MustParsePrefix(tt.route), tt.asn)
+			Equal(t, tt.state, r.State)
+			Equal(t, tt.reason, r.Reason)
+			Equal(t, tt.state == rpki.Valid, len(r.Matched) > 0)
+		})
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	in := `{"metadata":{},"roas":[
+		{"asn":"AS64500","prefix":"203.0.113.0/24","maxLength":24,"ta":"ripe"},
+		{"asn":64500,"prefix":"2001:db8::/32","maxLength":48}]}`
+	vs, err := rpki.ReadJSON(strings.NewReader(in))
+	NoError(t, err)
+	Equal(t, []rpki.VRP{vrps[0], vrps[3]}, vs)
+
+	_, err = rpki.ReadJSON(strings.NewReader(`{"roas":[{"asn":"ASX","prefix":"203.0.113.0/24"}]}`))
+	Error(t, err)
+}
+
+// pdu returns an RTR PDU with the given header fields and body.
+func pdu(version, typ byte, session uint16, body ...byte) []byte {
+	b := []byte{version, typ}
+	b = binary.BigEndian.AppendUint16(b, session)
+	b = binary.BigEndian.AppendUint32(b, uint32(8+len(body)))
+	return append(b, body...)
+}
+
+// prefixPDU returns an announcing IPv4 or IPv6 Prefix PDU of v.
+func prefixPDU(version byte, v rpki.VRP) []byte {
+	typ := byte(4)
+	if v.Prefix.Addr().Is6() {
+		typ = 6
+	}
+	body := append([]byte{1, byte(v.Prefix.Bits()), byte(v.MaxLength), 0}, v.Prefix.Addr().AsSlice()...)
+	return pdu(version, typ, 0, binary.BigEndian.AppendUint32(body, v.ASN)...)
+}
+
+// serveRTR starts an RPKI cache, which answers a Reset Query using respond.
+func serveRTR(t *testing.T, respond func(version byte) []byte) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			q := make([]byte, 8)
+			if _, err := io.ReadFull(c, q); err == nil && q[1] == 2 {
+				_, _ = c.Write(respond(q[0]))
+			}
+			_ = c.Close()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestFetchRTR(t *testing.T) {
+	addr := serveRTR(t, func(version byte) []byte {
+		b := pdu(version, 3, 42)
+		for _, v := range vrps {
+			b = append(b, prefixPDU(version, v)...)
+		}
+		// withdrawn prefixes and router keys are ignored
+		w := prefixPDU(version, rpki.VRP{64511, netip.MustParsePrefix("10.0.0.0/8"), 8})
+		w[8] = 0
+		b = append(b, w...)
+		b = append(b, pdu(version, 9, 0, make([]byte, 24)...)...)
+		return append(b, pdu(version, 7, 42, make([]byte, 16)...)...)
+	})
+
+	vs, err := rpki.FetchRTR(context.Background(), addr)
+	NoError(t, err)
+	Equal(t, vrps, vs)
+}
+
+func TestFetchRTRVersion0(t *testing.T) {
+	addr := serveRTR(t, func(version byte) []byte {
+		if version != 0 {
+			return pdu(version, 10, 4, make([]byte, 8)...)
+		}
+		return append(prefixPDU(0, vrps[0]), pdu(0, 7, 0, 0, 0, 0, 1)...)
+	})
+
+	vs, err := rpki.FetchRTR(context.Background(), addr)
+	NoError(t, err)
+	Equal(t, vrps[:1], vs)
+}
+
+func TestFetchRTRError(t *testing.T) {
+	addr := serveRTR(t, func(version byte) []byte {
+		return pdu(version, 10, 2, append(make([]byte, 4), 0, 0, 0, 7, 'n', 'o', ' ', 'd', 'a', 't', 'a')...)
+	})
+
+	_, err := rpki.FetchRTR(context.Background(), addr)
+	EqualError(t, err, "RTR error 2: no data")
+}
+
+func TestLookupRoutinator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equal(t, "/api/v1/validity/AS64501/203.0.113.0/24", r.URL.Path)
+		_, _ = io.WriteString(w, `{"validated_route":{"route":{"origin_asn":"AS64501","prefix":"203.0.113.0/24"},
+			"validity":{"state":"invalid","reason":"as","description":"At least one VRP Covers the Route Prefix",
+			"VRPs":{"matched":[],"unmatched_as":[{"asn":"AS64500","prefix":"203.0.113.0/24","max_length":"24"}],
+			"unmatched_length":[]}}}}`)
+	}))
+	defer srv.Close()
+
+	r, err := rpki.LookupRoutinator(context.Background(), srv.Client(), srv.URL+"/",
+		netip.MustParsePrefix("203.0.113.1/24"), 64501)
+	NoError(t, err)
+	Equal(t, rpki.Invalid, r.State)
+	Equal(t, "as", r.Reason)
+	Empty(t, r.Matched)
+	Equal(t, vrps[:1], r.Unmatched)
+}
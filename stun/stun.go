@@ -0,0 +1,163 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stun implements a minimal STUN client for discovering the public address of a host (see RFC 5389).
+package stun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"time"
+)
+
+const (
+	magicCookie         = 0x2112a442
+	bindingRequest      = 0x0001
+	bindingSuccess      = 0x0101
+	attrMappedAddr      = 0x0001
+	attrXORMappedAddr   = 0x0020
+	headerLen           = 20
+	familyIPv4          = 0x01
+	familyIPv6          = 0x02
+	defaultQueryTimeout = 5 * time.Second
+)
+
+var (
+	errInvalidResponse = errors.New("invalid STUN response")
+	errTxMismatch      = errors.New("STUN transaction ID mismatch")
+)
+
+// Query sends a binding request to the STUN server via UDP and returns the reflexive transport address.
+// The network must be "udp", "udp4" or "udp6". Unless ctx has a deadline, the query times out after 5 seconds.
+func Query(ctx context.Context, network, server string) (netip.AddrPort, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultQueryTimeout)
+		defer cancel()
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, network, server)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	defer func() { _ = conn.Close() }()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+
+	var txID [12]byte
+	if _, err = rand.Read(txID[:]); err != nil {
+		return netip.AddrPort{}, err
+	}
+	if _, err = conn.Write(NewBindingRequest(txID)); err != nil {
+		return netip.AddrPort{}, err
+	}
+
+	b := make([]byte, 1500)
+	for {
+		n, err := conn.Read(b)
+		if err != nil {
+			return netip.AddrPort{}, err
+		}
+		// ignore stray datagrams of other transactions
+		if ap, err := ParseBindingResponse(b[:n], txID); err == nil || !errors.Is(err, errTxMismatch) {
+			return ap, err
+		}
+	}
+}
+
+// NewBindingRequest returns a binding request without attributes.
+func NewBindingRequest(txID [12]byte) []byte {
+	b := make([]byte, headerLen)
+	binary.BigEndian.PutUint16(b[0:], bindingRequest)
+	binary.BigEndian.PutUint32(b[4:], magicCookie)
+	copy(b[8:], txID[:])
+	return b
+}
+
+// ParseBindingResponse returns the (XOR-)MAPPED-ADDRESS of a binding success response.
+func ParseBindingResponse(b []byte, txID [12]byte) (netip.AddrPort, error) {
+	if len(b) < headerLen || binary.BigEndian.Uint32(b[4:]) != magicCookie {
+		return netip.AddrPort{}, errInvalidResponse
+	}
+	if string(b[8:headerLen]) != string(txID[:]) {
+		return netip.AddrPort{}, errTxMismatch
+	}
+	if binary.BigEndian.Uint16(b[0:]) != bindingSuccess {
+		return netip.AddrPort{}, errors.New("STUN binding request failed")
+	}
+
+	length := int(binary.BigEndian.Uint16(b[2:]))
+	if len(b) < headerLen+length {
+		return netip.AddrPort{}, errInvalidResponse
+	}
+
+	var mapped netip.AddrPort
+	attrs := b[headerLen : headerLen+length]
+	for len(attrs) >= 4 {
+		typ, n := binary.BigEndian.Uint16(attrs[0:]), int(binary.BigEndian.Uint16(attrs[2:]))
+		if len(attrs) < 4+n {
+			return netip.AddrPort{}, errInvalidResponse
+		}
+		switch v := attrs[4 : 4+n]; typ {
+		case attrXORMappedAddr:
+			return parseAddr(v, b[4:headerLen])
+		case attrMappedAddr:
+			ap, err := parseAddr(v, nil)
+			if err != nil {
+				return ap, err
+			}
+			mapped = ap
+		}
+		// attributes are padded to a multiple of 4 bytes
+		attrs = attrs[4+(n+3)&^3:]
+	}
+
+	if !mapped.IsValid() {
+		return mapped, errors.New("STUN response does not contain a mapped address")
+	}
+	return mapped, nil
+}
+
+// parseAddr decodes a (XOR-)MAPPED-ADDRESS attribute. If xor is not nil, it contains the magic cookie and
+// the transaction ID, which the address is obfuscated with.
+func parseAddr(v, xor []byte) (netip.AddrPort, error) {
+	if len(v) < 4 {
+		return netip.AddrPort{}, errInvalidResponse
+	}
+
+	port := binary.BigEndian.Uint16(v[2:])
+	ip := append([]byte(nil), v[4:]...)
+	if xor != nil {
+		port ^= magicCookie >> 16
+		for i := range ip {
+			ip[i] ^= xor[i]
+		}
+	}
+
+	var a netip.Addr
+	switch {
+	case v[1] == familyIPv4 && len(ip) == net.IPv4len:
+		a = netip.AddrFrom4(*(*[4]byte)(ip))
+	case v[1] == familyIPv6 && len(ip) == net.IPv6len:
+		a = netip.AddrFrom16(*(*[16]byte)(ip))
+	default:
+		return netip.AddrPort{}, errInvalidResponse
+	}
+	return netip.AddrPortFrom(a, port), nil
+}
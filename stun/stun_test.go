@@ -0,0 +1,88 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stun_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/stun"
+	. "github.com/stretchr/testify/require"
+)
+
+// response returns a binding success response with the given attribute.
+func response(txID []byte, typ uint16, v []byte) []byte {
+	b := make([]byte, 20, 24+len(v))
+	binary.BigEndian.PutUint16(b[0:], 0x0101)
+	binary.BigEndian.PutUint16(b[2:], uint16(4+len(v)))
+	binary.BigEndian.PutUint32(b[4:], 0x2112a442)
+	copy(b[8:], txID)
+	b = binary.BigEndian.AppendUint16(b, typ)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(v)))
+	return append(b, v...)
+}
+
+func TestParseBindingResponse(t *testing.T) {
+	txID := [12]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	req := stun.NewBindingRequest(txID)
+	Equal(t, []byte{0, 1, 0, 0, 0x21, 0x12, 0xa4, 0x42}, req[:8])
+	Equal(t, txID[:], req[8:])
+
+	// 192.0.2.1:32853 XOR 0x2112a442
+	ap, err := stun.ParseBindingResponse(response(txID[:], 0x0020, []byte{0, 1, 0xa1, 0x47, 0xe1, 0x12, 0xa6, 0x43}), txID)
+	NoError(t, err)
+	Equal(t, netip.MustParseAddrPort("192.0.2.1:32853"), ap)
+
+	ap, err = stun.ParseBindingResponse(response(txID[:], 0x0001, []byte{0, 1, 0x80, 0x55, 192, 0, 2, 1}), txID)
+	NoError(t, err)
+	Equal(t, netip.MustParseAddrPort("192.0.2.1:32853"), ap)
+
+	_, err = stun.ParseBindingResponse(response(make([]byte, 12), 0x0020, nil), txID)
+	EqualError(t, err, "STUN transaction ID mismatch")
+	_, err = stun.ParseBindingResponse(req[:10], txID)
+	EqualError(t, err, "invalid STUN response")
+	_, err = stun.ParseBindingResponse(response(txID[:], 0x8022, []byte("test")), txID)
+	EqualError(t, err, "STUN response does not contain a mapped address")
+}
+
+func TestQuery(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		b := make([]byte, 1500)
+		n, addr, err := conn.ReadFrom(b)
+		if err != nil || n < 20 {
+			return
+		}
+		ua := addr.(*net.UDPAddr)
+		v := []byte{0, 1, 0, 0}
+		binary.BigEndian.PutUint16(v[2:], uint16(ua.Port)^0x2112)
+		for i, x := range ua.IP.To4() {
+			v = append(v, x^b[4+i])
+		}
+		_, _ = conn.WriteTo(response(make([]byte, 12), 0x0020, nil), addr)
+		_, _ = conn.WriteTo(response(b[8:20], 0x0020, v), addr)
+	}()
+
+	ap, err := stun.Query(context.Background(), "udp4", conn.LocalAddr().String())
+	NoError(t, err)
+	Equal(t, "127.0.0.1", ap.Addr().String())
+	NotZero(t, ap.Port())
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+
+	"github.com/abc-inc/terminus/asn"
+)
+
+// RouteObject is a route or route6 object registered in an Internet Routing Registry (IRR).
+type RouteObject struct {
+	Route  netip.Prefix `json:"route"`
+	Origin uint32       `json:"origin"`
+	Descr  string       `json:"descr,omitempty"`
+	MntBy  []string     `json:"mntBy,omitempty"`
+	Source string       `json:"source,omitempty"`
+}
+
+// Match selects the route objects returned by LookupRoutes.
+type Match string
+
+// Supported matches, which correspond to the RIPE-style query flags -x, -L and -M.
+const (
+	// Exact matches route objects of the prefix only.
+	Exact Match = "exact"
+	// Less matches route objects of the prefix and all less specific ones.
+	Less Match = "less"
+	// More matches route objects of the prefix and all more specific ones.
+	More Match = "more"
+)
+
+var matchFlags = map[Match]string{Exact: "-x", Less: "-L", More: "-M"}
+
+// LookupRoutes queries an IRRd or RIPE-style WHOIS server (HOST:PORT, e.g., "whois.radb.net:43") for the route
+// objects of p. If sources are given, only the route objects of these databases (e.g., "RIPE", "RADB") are returned.
+func LookupRoutes(ctx context.Context, server string, p netip.Prefix, m Match, sources ...string) (
+	[]RouteObject, error,
+) {
+	flag, ok := matchFlags[m]
+	if !ok {
+		return nil, errors.New("unsupported match: " + string(m))
+	}
+
+	q := "-T route,route6 " + flag
+	if len(sources) > 0 {
+		q += " -s " + strings.Join(sources, ",")
+	}
+	text, err := queryWHOIS(ctx, server, q+" "+p.Masked().String())
+	if err != nil {
+		return nil, err
+	}
+	return ParseRoutes(text)
+}
+
+// ParseRoutes extracts the route and route6 objects from a WHOIS response in RPSL (RFC 2622).
+// Other objects are skipped.
+func ParseRoutes(text string) ([]RouteObject, error) {
+	var ros []RouteObject
+	for _, obj := range objects(text) {
+		if len(obj) == 0 || obj[0][0] != "route" && obj[0][0] != "route6" {
+			continue
+		}
+
+		p, err := netip.ParsePrefix(obj[0][1])
+		if err != nil {
+			return nil, err
+		}
+		ro := RouteObject{Route: p}
+		for _, kv := range obj[1:] {
+			switch kv[0] {
+			case "origin":
+				if ro.Origin, err = asn.ParseASN(strings.ToUpper(kv[1])); err != nil {
+					return nil, err
+				}
+			case "descr":
+				if ro.Descr == "" {
+					ro.Descr = kv[1]
+				}
+			case "mnt-by":
+				ro.MntBy = append(ro.MntBy, kv[1])
+			case "source":
+				ro.Source = strings.ToUpper(kv[1])
+			}
+		}
+		ros = append(ros, ro)
+	}
+	return ros, nil
+}
+
+// objects splits a WHOIS response into RPSL objects (separated by empty lines) of lower-case "key: value" pairs.
+// Comments and continuation lines are skipped.
+func objects(text string) (objs [][][2]string) {
+	var obj [][2]string
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		if l := sc.Text(); strings.TrimSpace(l) == "" {
+			if len(obj) > 0 {
+				objs, obj = append(objs, obj), nil
+			}
+		} else if kvs := fields(l); len(kvs) > 0 {
+			obj = append(obj, [2]string{strings.ToLower(kvs[0][0]), kvs[0][1]})
+		}
+	}
+	if len(obj) > 0 {
+		objs = append(objs, obj)
+	}
+	return objs
+}
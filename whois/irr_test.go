@@ -0,0 +1,81 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package whois_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/abc-inc/terminus/whois"
+	. "github.com/stretchr/testify/require"
+)
+
+const routeResponse = `% This is the RADb IRR
+
+route:          203.0.113.0/24
+descr:          Example Network
+                continued
+descr:          Vienna
+origin:         AS64500
+mnt-by:         MAINT-EXAMPLE
+mnt-by:         MAINT-NOC
+source:         RADB
+
+route:          203.0.113.0/24
+origin:         as64501
+source:         ripe
+
+mntner:         MAINT-EXAMPLE
+source:         RADB
+`
+
+func TestParseRoutes(t *testing.T) {
+	ros, err := whois.ParseRoutes(routeResponse)
+	NoError(t, err)
+	p := netip.MustParsePrefix("203.0.113.0/24")
+	Equal(t, []whois.RouteObject{
+		{Route: p, Origin: 64500, Descr: "Example Network", MntBy: []string{"MAINT-EXAMPLE", "MAINT-NOC"}, Source: "RADB"},
+		{Route: p, Origin: 64501, Source: "RIPE"},
+	}, ros)
+
+	ros, err = whois.ParseRoutes("%  No entries found for the selected source(s).\n")
+	NoError(t, err)
+	Empty(t, ros)
+
+	_, err = whois.ParseRoutes("route6: 2001:db8::/32\norigin: ASX\n")
+	Error(t, err)
+}
+
+func TestLookupRoutes(t *testing.T) {
+	srv := serveWHOIS(t, func(q string) string {
+		if q != "-T route,route6 -L -s RADB,RIPE 203.0.113.0/24" {
+			return "%ERROR:101: no entries found\n"
+		}
+		return routeResponse
+	})
+
+	ros, err := whois.LookupRoutes(context.Background(), srv, netip.MustParsePrefix("203.0.113.1/24"), whois.Less,
+		"RADB", "RIPE")
+	NoError(t, err)
+	Len(t, ros, 2)
+
+	ros, err = whois.LookupRoutes(context.Background(), srv, netip.MustParsePrefix("203.0.113.0/24"), whois.Exact)
+	NoError(t, err)
+	Empty(t, ros)
+
+	_, err = whois.LookupRoutes(context.Background(), srv, netip.MustParsePrefix("203.0.113.0/24"), "all")
+	EqualError(t, err, "unsupported match: all")
+}
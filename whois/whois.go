@@ -0,0 +1,298 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package whois looks up the registration data of IP addresses via RDAP (RFC 9082, RFC 9083) and WHOIS (RFC 3912).
+package whois
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// Record is the registration data of an IP network.
+type Record struct {
+	// Allocation is the address range e.g., "192.0.2.0 - 192.0.2.255".
+	Allocation string `json:"allocation,omitempty"`
+	// Name is the name of the network.
+	Name string `json:"name,omitempty"`
+	// Org is the organization, which the network is registered to.
+	Org string `json:"org,omitempty"`
+	// Country is the ISO 3166 code of the country.
+	Country string `json:"country,omitempty"`
+	// Abuse is the e-mail address for reporting abuse.
+	Abuse string `json:"abuse,omitempty"`
+	// Source is the URL (RDAP) or the server (WHOIS), which the record was obtained from.
+	Source string `json:"source,omitempty"`
+}
+
+// Bootstrap maps IP networks to the base URLs of their RDAP services (see RFC 9224).
+type Bootstrap map[netip.Prefix][]string
+
+// ParseBootstrap parses an IANA bootstrap file like https://data.iana.org/rdap/ipv4.json.
+func ParseBootstrap(b []byte) (Bootstrap, error) {
+	var doc struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("invalid RDAP bootstrap file: %w", err)
+	}
+
+	bs := Bootstrap{}
+	for _, svc := range doc.Services {
+		if len(svc) != 2 {
+			return nil, errors.New("invalid RDAP bootstrap file: malformed service")
+		}
+		for _, s := range svc[0] {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RDAP bootstrap file: %w", err)
+			}
+			bs[p] = svc[1]
+		}
+	}
+	return bs, nil
+}
+
+// Lookup returns the base URLs of the most specific network, which contains ip.
+func (bs Bootstrap) Lookup(ip netip.Addr) []string {
+	var best netip.Prefix
+	for p := range bs {
+		if p.Contains(ip) && (!best.IsValid() || p.Bits() > best.Bits()) {
+			best = p
+		}
+	}
+	return bs[best]
+}
+
+// LookupRDAP queries the RDAP service at baseURL for the network containing ip.
+// Redirects to other registries are followed by the client.
+func LookupRDAP(ctx context.Context, client *http.Client, baseURL string, ip netip.Addr) (Record, error) {
+	u := strings.TrimSuffix(baseURL, "/") + "/ip/" + ip.String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return Record{}, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Record{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return Record{}, fmt.Errorf("cannot query %s: %s", u, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return Record{}, err
+	}
+
+	r, err := ParseRDAP(b)
+	r.Source = resp.Request.URL.String()
+	return r, err
+}
+
+// entity is an RDAP entity like the registrant or the abuse contact.
+type entity struct {
+	Roles    []string          `json:"roles"`
+	VCard    []json.RawMessage `json:"vcardArray"`
+	Entities []entity          `json:"entities"`
+}
+
+// vcard returns the first value of the vCard property (e.g., "fn" or "email").
+func (e entity) vcard(prop string) string {
+	if len(e.VCard) != 2 {
+		return ""
+	}
+	var props [][]interface{}
+	if json.Unmarshal(e.VCard[1], &props) != nil {
+		return ""
+	}
+	for _, p := range props {
+		if len(p) == 4 && p[0] == prop {
+			if s, ok := p[3].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// find returns the first entity (including nested ones), which has the given role.
+func find(es []entity, role string) *entity {
+	for i := range es {
+		for _, r := range es[i].Roles {
+			if r == role {
+				return &es[i]
+			}
+		}
+		if e := find(es[i].Entities, role); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// ParseRDAP extracts the registration data from an RDAP response of an IP network.
+func ParseRDAP(b []byte) (Record, error) {
+	var nw struct {
+		ObjectClassName string   `json:"objectClassName"`
+		StartAddress    string   `json:"startAddress"`
+		EndAddress      string   `json:"endAddress"`
+		Name            string   `json:"name"`
+		Country         string   `json:"country"`
+		Entities        []entity `json:"entities"`
+	}
+	if err := json.Unmarshal(b, &nw); err != nil {
+		return Record{}, fmt.Errorf("invalid RDAP response: %w", err)
+	} else if nw.ObjectClassName != "ip network" {
+		return Record{}, errors.New("invalid RDAP response: not an IP network")
+	}
+
+	r := Record{Name: nw.Name, Country: nw.Country}
+	if nw.StartAddress != "" {
+		r.Allocation = nw.StartAddress + " - " + nw.EndAddress
+	}
+	if e := find(nw.Entities, "registrant"); e != nil {
+		r.Org = e.vcard("fn")
+	}
+	if e := find(nw.Entities, "abuse"); e != nil {
+		r.Abuse = e.vcard("email")
+	}
+	return r, nil
+}
+
+// maxReferrals is the maximum number of WHOIS referrals, which are followed.
+const maxReferrals = 3
+
+// LookupWHOIS queries the WHOIS server (HOST:PORT) for ip and follows referrals to other servers.
+func LookupWHOIS(ctx context.Context, server string, ip netip.Addr) (Record, error) {
+	for i := 0; ; i++ {
+		text, err := queryWHOIS(ctx, server, ip.String())
+		if err != nil {
+			return Record{}, err
+		}
+
+		if ref := referral(text); ref != "" && i < maxReferrals {
+			server = ref
+			continue
+		}
+		r := ParseWHOIS(text)
+		r.Source = server
+		return r, nil
+	}
+}
+
+func queryWHOIS(ctx context.Context, server, query string) (string, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+
+	if _, err = io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", err
+	}
+	b, err := io.ReadAll(io.LimitReader(conn, 1<<20))
+	return string(b), err
+}
+
+// referral returns the WHOIS server (HOST:PORT), which the response refers to (or an empty string).
+func referral(text string) string {
+	for _, kv := range fields(text) {
+		switch strings.ToLower(kv[0]) {
+		case "refer", "whois", "referralserver":
+			host := strings.TrimPrefix(strings.TrimPrefix(kv[1], "rwhois://"), "whois://")
+			if host == "" || strings.Contains(kv[1], "rwhois://") {
+				continue
+			}
+			if _, _, err := net.SplitHostPort(host); err != nil {
+				host = net.JoinHostPort(host, "43")
+			}
+			return host
+		}
+	}
+	return ""
+}
+
+var abuseComment = regexp.MustCompile(`(?i)abuse contact for .* is '([^']+)'`)
+
+// ParseWHOIS extracts the registration data from the WHOIS response of an RIR.
+// The first occurrence of each attribute wins, because RIRs list the most relevant object first.
+func ParseWHOIS(text string) Record {
+	r := Record{}
+	set := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	for _, kv := range fields(text) {
+		switch strings.ToLower(kv[0]) {
+		case "inetnum", "inet6num", "netrange":
+			set(&r.Allocation, strings.Join(strings.Fields(kv[1]), " "))
+		case "netname":
+			set(&r.Name, kv[1])
+		case "org-name", "orgname", "owner":
+			set(&r.Org, kv[1])
+		case "country":
+			set(&r.Country, strings.ToUpper(kv[1]))
+		case "abuse-mailbox", "orgabuseemail":
+			set(&r.Abuse, kv[1])
+		}
+	}
+
+	if m := abuseComment.FindStringSubmatch(text); m != nil {
+		set(&r.Abuse, m[1])
+	}
+	if r.Org == "" {
+		// RIPE and APNIC describe the network instead
+		for _, kv := range fields(text) {
+			if strings.EqualFold(kv[0], "descr") {
+				r.Org = kv[1]
+				break
+			}
+		}
+	}
+	return r
+}
+
+// fields returns the "key: value" pairs of a WHOIS response, skipping comments.
+func fields(text string) (kvs [][2]string) {
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		l := sc.Text()
+		if strings.HasPrefix(l, "%") || strings.HasPrefix(l, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(l, ":"); ok && k != "" && !strings.ContainsAny(k, " \t") {
+			if v = strings.TrimSpace(v); v != "" {
+				kvs = append(kvs, [2]string{k, v})
+			}
+		}
+	}
+	return kvs
+}
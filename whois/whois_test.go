@@ -0,0 +1,193 @@
+// Copyright 2020 The Terminus authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package whois_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/abc-inc/terminus/whois"
+	. "github.com/stretchr/testify/require"
+)
+
+const bootstrap = `{
+  "version": "1.0",
+  "services": [
+    [["192.0.0.0/8"], ["https://rdap.example.net/"]],
+    [["192.0.2.0/24", "198.51.100.0/24"], ["https://rdap.example.org/rdap/", "http://rdap.example.org/rdap/"]]
+  ]
+}`
+
+const rdapResponse = `{
+  "objectClassName": "ip network",
+  "handle": "NET-192-0-2-0-1",
+  "startAddress": "192.0.2.0",
+  "endAddress": "192.0.2.255",
+  "name": "TEST-NET-1",
+  "country": "US",
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["registrant"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Org"]]],
+      "entities": [
+        {
+          "objectClassName": "entity",
+          "roles": ["technical", "abuse"],
+          "vcardArray": ["vcard", [["fn", {}, "text", "Abuse"], ["email", {}, "text", "abuse@example.org"]]]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestBootstrap(t *testing.T) {
+	bs, err := whois.ParseBootstrap([]byte(bootstrap))
+	NoError(t, err)
+	Equal(t, []string{"https://rdap.example.org/rdap/", "http://rdap.example.org/rdap/"},
+		bs.Lookup(netip.MustParseAddr("192.0.2.1")))
+	Equal(t, []string{"https://rdap.example.net/"}, bs.Lookup(netip.MustParseAddr("192.0.3.1")))
+	Empty(t, bs.Lookup(netip.MustParseAddr("2001:db8::1")))
+
+	_, err = whois.ParseBootstrap([]byte(`{"services": [[["192.0.2.0/33"], []]]}`))
+	ErrorContains(t, err, "invalid RDAP bootstrap file")
+}
+
+func TestParseRDAP(t *testing.T) {
+	r, err := whois.ParseRDAP([]byte(rdapResponse))
+	NoError(t, err)
+	Equal(t, whois.Record{
+		Allocation: "192.0.2.0 - 192.0.2.255",
+		Name:       "TEST-NET-1",
+		Org:        "Example Org",
+		Country:    "US",
+		Abuse:      "abuse@example.org",
+	}, r)
+
+	_, err = whois.ParseRDAP([]byte(`{"objectClassName": "autnum"}`))
+	EqualError(t, err, "invalid RDAP response: not an IP network")
+}
+
+func TestLookupRDAP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ip/", func(w http.ResponseWriter, r *http.Request) {
+		// refer to the authoritative registry
+		http.Redirect(w, r, "/registry"+r.URL.Path, http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/registry/ip/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/registry/ip/192.0.2.1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		_, _ = io.WriteString(w, rdapResponse)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	r, err := whois.LookupRDAP(context.Background(), srv.Client(), srv.URL+"/", netip.MustParseAddr("192.0.2.1"))
+	NoError(t, err)
+	Equal(t, "Example Org", r.Org)
+	Equal(t, srv.URL+"/registry/ip/192.0.2.1", r.Source)
+
+	_, err = whois.LookupRDAP(context.Background(), srv.Client(), srv.URL, netip.MustParseAddr("192.0.2.2"))
+	EqualError(t, err, "cannot query "+srv.URL+"/ip/192.0.2.2: 404 Not Found")
+}
+
+const ripeResponse = `% This is the RIPE Database query service.
+% Abuse contact for '192.0.2.0 - 192.0.2.255' is 'abuse@example.net'
+
+inetnum:        192.0.2.0 - 192.0.2.255
+netname:        TEST-NET-1
+descr:          Example Network
+country:        nl
+admin-c:        EX1-RIPE
+`
+
+const arinResponse = `#
+# ARIN WHOIS data and services are subject to the Terms of Use
+#
+
+NetRange:       198.51.100.0 - 198.51.100.255
+CIDR:           198.51.100.0/24
+NetName:        TEST-NET-2
+Organization:   Example Org (EO-1)
+
+OrgName:        Example Org
+Country:        US
+OrgAbuseEmail:  abuse@example.com
+`
+
+func TestParseWHOIS(t *testing.T) {
+	Equal(t, whois.Record{
+		Allocation: "192.0.2.0 - 192.0.2.255",
+		Name:       "TEST-NET-1",
+		Org:        "Example Network",
+		Country:    "NL",
+		Abuse:      "abuse@example.net",
+	}, whois.ParseWHOIS(ripeResponse))
+
+	Equal(t, whois.Record{
+		Allocation: "198.51.100.0 - 198.51.100.255",
+		Name:       "TEST-NET-2",
+		Org:        "Example Org",
+		Country:    "US",
+		Abuse:      "abuse@example.com",
+	}, whois.ParseWHOIS(arinResponse))
+}
+
+// serveWHOIS starts a WHOIS server, which responds with the result of f, and returns its address.
+func serveWHOIS(t *testing.T, f func(query string) string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			q, _ := bufio.NewReader(conn).ReadString('\n')
+			_, _ = io.WriteString(conn, f(strings.TrimSpace(q)))
+			_ = conn.Close()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestLookupWHOIS(t *testing.T) {
+	rir := serveWHOIS(t, func(q string) string {
+		if q != "198.51.100.1" {
+			return "% no entries found\n"
+		}
+		return arinResponse
+	})
+	iana := serveWHOIS(t, func(string) string {
+		return "% IANA WHOIS server\n\nrefer:        " + rir + "\n\ninetnum:      198.0.0.0 - 198.255.255.255\n"
+	})
+
+	r, err := whois.LookupWHOIS(context.Background(), iana, netip.MustParseAddr("198.51.100.1"))
+	NoError(t, err)
+	Equal(t, "Example Org", r.Org)
+	Equal(t, rir, r.Source)
+}